@@ -1,9 +1,14 @@
 package main
 
 import (
+	"context"
+	"github.com/jmoiron/sqlx"
 	ddsqlx "gopkg.in/DataDog/dd-trace-go.v1/contrib/jmoiron/sqlx"
+	"lowerthirdsapi/internal/auth"
 	"lowerthirdsapi/internal/config"
+	"lowerthirdsapi/internal/events"
 	"lowerthirdsapi/internal/logger"
+	"lowerthirdsapi/internal/mail"
 	"lowerthirdsapi/internal/server"
 	"lowerthirdsapi/internal/storage"
 	"net/http/fcgi"
@@ -17,14 +22,36 @@ func main() {
 	log := logger.New()
 	cfg := config.New(os.Getenv("ENV_FILES_DIR"))
 
-	db := ddsqlx.MustConnect("mysql", cfg.MySQLConfig.ConnectionString())
-	db.SetMaxOpenConns(cfg.MySQLConfig.MaxOpenConns)
-	db.SetMaxIdleConns(cfg.MySQLConfig.MaxOpenConns)
-	db.SetConnMaxLifetime(time.Hour)
+	var db *sqlx.DB
+	if cfg.Storage.Backend == storage.BackendMySQL {
+		db = ddsqlx.MustConnect("mysql", cfg.MySQLConfig.ConnectionString())
+		db.SetMaxOpenConns(cfg.MySQLConfig.MaxOpenConns)
+		db.SetMaxIdleConns(cfg.MySQLConfig.MaxOpenConns)
+		db.SetConnMaxLifetime(time.Hour)
+	} else {
+		var err error
+		db, err = storage.OpenLocal(context.Background(), cfg.Storage, log)
+		if err != nil {
+			log.Fatal("failed to open storage: ", err)
+		}
+	}
 	defer db.Close()
 
-	lowerThirdsService := storage.New(db, log)
-	srvr := server.New(cfg, db, lowerThirdsService, log)
+	broker := events.NewBroker(cfg.Events.HistorySize)
+	lowerThirdsService := storage.New(db, log, storage.WithEvents(broker))
+
+	verifier, err := auth.NewFirebaseVerifier(cfg.Firebase)
+	if err != nil {
+		log.Fatal("failed to get JWKS: ", err)
+	}
+	defer verifier.Close()
+
+	mailer, err := mail.New(cfg.Mail, log)
+	if err != nil {
+		log.Fatal("failed to set up mail: ", err)
+	}
+
+	srvr := server.New(cfg, db, lowerThirdsService, verifier, mailer, log)
 
 	// Serve using FastCGI (or replace with cgi.Serve if you want classic CGI)
 	if err := fcgi.Serve(nil, srvr.Router); err != nil {
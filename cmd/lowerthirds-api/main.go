@@ -2,11 +2,16 @@ package main
 
 import (
 	"context"
+	"github.com/jmoiron/sqlx"
 	"github.com/sirupsen/logrus"
 	ddsqlx "gopkg.in/DataDog/dd-trace-go.v1/contrib/jmoiron/sqlx"
+	"lowerthirdsapi/internal/auth"
 	"lowerthirdsapi/internal/config"
+	"lowerthirdsapi/internal/events"
 	"lowerthirdsapi/internal/helpers"
 	"lowerthirdsapi/internal/logger"
+	"lowerthirdsapi/internal/mail"
+	"lowerthirdsapi/internal/scheduler"
 	"lowerthirdsapi/internal/server"
 	"lowerthirdsapi/internal/storage"
 	"os"
@@ -27,19 +32,47 @@ func startApp(ctx context.Context, log *logrus.Entry) {
 	log.Info("Starting up LowerThirds API")
 	cfg := config.New(os.Getenv("ENV_FILES_DIR"))
 
-	db := ddsqlx.MustConnect("mysql", cfg.MySQLConfig.ConnectionString())
-	db.SetMaxOpenConns(cfg.MySQLConfig.MaxOpenConns)
-	db.SetMaxIdleConns(cfg.MySQLConfig.MaxOpenConns)
-	db.SetConnMaxLifetime(time.Hour)
+	db := connectDB(ctx, cfg, log)
 	defer db.Close()
 
-	lowerThirdsService := storage.New(db, log)
+	broker := events.NewBroker(cfg.Events.HistorySize)
+	lowerThirdsService := storage.New(db, log, storage.WithEvents(broker))
 
-	srvr := server.New(cfg, db, lowerThirdsService, log)
+	verifier, err := auth.NewFirebaseVerifier(cfg.Firebase)
+	if err != nil {
+		log.Fatal("failed to get JWKS: ", err)
+	}
+	defer verifier.Close()
+
+	mailer, err := mail.New(cfg.Mail, log)
+	if err != nil {
+		log.Fatal("failed to set up mail: ", err)
+	}
+
+	srvr := server.New(cfg, db, lowerThirdsService, verifier, mailer, log)
 	defer helpers.ShutdownServer(srvr, log)
 	go helpers.RunServer(srvr, log)
+	go scheduler.Run(ctx, cfg.Scheduler, lowerThirdsService, log)
 
 	// Exit safely
 	<-ctx.Done()
 	log.Info("exiting")
 }
+
+// connectDB connects to MySQL, or opens and migrates the SQLite or memory database
+func connectDB(ctx context.Context, cfg *config.Config, log *logrus.Entry) *sqlx.DB {
+	if cfg.Storage.Backend != storage.BackendMySQL {
+		log.Info("Using ", cfg.Storage.Backend, " storage")
+		db, err := storage.OpenLocal(ctx, cfg.Storage, log)
+		if err != nil {
+			log.Fatal("failed to open storage: ", err)
+		}
+		return db
+	}
+
+	db := ddsqlx.MustConnect("mysql", cfg.MySQLConfig.ConnectionString())
+	db.SetMaxOpenConns(cfg.MySQLConfig.MaxOpenConns)
+	db.SetMaxIdleConns(cfg.MySQLConfig.MaxOpenConns)
+	db.SetConnMaxLifetime(time.Hour)
+	return db
+}
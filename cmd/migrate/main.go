@@ -0,0 +1,128 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"github.com/sirupsen/logrus"
+	"lowerthirdsapi/internal/config"
+	"lowerthirdsapi/internal/helpers"
+	"lowerthirdsapi/internal/logger"
+	"lowerthirdsapi/internal/migrations"
+	"lowerthirdsapi/internal/sqlitedb"
+	"lowerthirdsapi/internal/storage"
+	"os"
+	"strconv"
+
+	"github.com/jmoiron/sqlx"
+
+	_ "github.com/go-sql-driver/mysql"
+)
+
+const usage = `usage: migrate <command>
+
+  up [n]           apply the next n migrations, or all of them
+  down [n]         revert the last n migrations, or the last one
+  status           list the migrations and which are applied
+  force <version>  record the database as being at version without running anything`
+
+func main() {
+	var log = logger.New()
+	// Setup context that will cancel on signalled termination
+	ctx := helpers.GetOsSignalContext(log)
+
+	flag.Usage = func() { fmt.Fprintln(os.Stderr, usage) }
+	flag.Parse()
+	switch flag.Arg(0) {
+	case "up", "down", "status", "force":
+	default:
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	if err := migrate(ctx, log, flag.Arg(0), flag.Args()[1:]); err != nil {
+		log.Fatal("migrate failed: ", err)
+	}
+}
+
+func migrate(ctx context.Context, log *logrus.Entry, command string, args []string) error {
+	cfg := config.New(os.Getenv("ENV_FILES_DIR"))
+
+	var db *sqlx.DB
+	var err error
+	switch cfg.Storage.Backend {
+	case storage.BackendMySQL:
+		db, err = sqlx.Connect("mysql", cfg.MySQLConfig.ConnectionString())
+	case storage.BackendSQLite:
+		db, err = sqlitedb.Open(cfg.Storage.SQLitePath)
+	default:
+		// A memory database is migrated when the API starts, and is gone when it stops
+		return fmt.Errorf("can't migrate %s storage", cfg.Storage.Backend)
+	}
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	m, err := migrations.New(db, log)
+	if err != nil {
+		return err
+	}
+
+	switch command {
+	case "up":
+		steps, err := stepsArg(args, 0)
+		if err != nil {
+			return err
+		}
+		applied, err := m.Up(ctx, steps)
+		log.Info("applied migrations: ", applied)
+		return err
+	case "down":
+		steps, err := stepsArg(args, 1)
+		if err != nil {
+			return err
+		}
+		reverted, err := m.Down(ctx, steps)
+		log.Info("reverted migrations: ", reverted)
+		return err
+	case "status":
+		statuses, err := m.Status(ctx)
+		if err != nil {
+			return err
+		}
+		for _, status := range statuses {
+			state := "pending"
+			if status.Dirty {
+				state = "dirty"
+			} else if status.Applied {
+				state = "applied " + status.AppliedDT.Format("2006-01-02 15:04:05")
+			}
+			fmt.Printf("%04d_%-30s %s\n", status.Version, status.Name, state)
+		}
+		return nil
+	case "force":
+		if len(args) != 1 {
+			return fmt.Errorf("force needs a version")
+		}
+		version, err := strconv.Atoi(args[0])
+		if err != nil {
+			return fmt.Errorf("invalid version %q", args[0])
+		}
+		return m.Force(ctx, version)
+	default:
+		return fmt.Errorf("unknown command %q", command)
+	}
+}
+
+// stepsArg reads the optional count of migrations to run
+func stepsArg(args []string, fallback int) (int, error) {
+	if len(args) == 0 {
+		return fallback, nil
+	}
+	steps, err := strconv.Atoi(args[0])
+	if err != nil || steps < 1 {
+		return 0, fmt.Errorf("invalid count %q", args[0])
+	}
+	return steps, nil
+}
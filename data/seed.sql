@@ -0,0 +1,22 @@
+-- Demo users, orgs and meetings for trying the API out. Load it into a database that's been migrated; it works for
+-- MySQL and SQLite. The hymn catalog comes with the baseline migration.
+
+INSERT INTO `Users` (`id`, `email`, `first_name`, `full_name`, `last_name`) VALUES ('3cd5fe4e-9ecb-4ec2-b7c7-0d19288c08e0', 'pendenga@gmail.com', 'Grant', 'Grant Anderson', 'Anderson');
+INSERT INTO `Users` (`id`, `email`, `first_name`, `full_name`, `last_name`) VALUES ('a5659535-43a8-486d-9b68-1da5d3fdee06', 'rskabelund@gmail.com', 'Randy', 'Randy Skabelund', 'Skabelund');
+INSERT INTO Meetings (id, org_id, conference, meeting, meeting_date, duration) VALUES ('6cd5b59a-413a-4815-b3a9-e99a5dc91b50', 'd65ad59c-216c-11f0-a191-ac1f6bbcd39a', 'Stake Conference', 'General Session', '2025-06-01 10:00:00.000000', '120');
+INSERT INTO Meetings (id, org_id, meeting, meeting_date, duration) VALUES ('958a87d5-19b8-4e97-8016-dc9ca23072c5', 'e7d7a025-5bcd-43c8-ba35-e80d91ead4b2', 'Sacrament Meeting', '2025-04-27 09:00:00.000000', '120');
+INSERT INTO Meetings (id, org_id, meeting, meeting_date, duration) VALUES ('f7c65b79-1d5a-45fc-a935-f3ed1bef75f9', '1b951e53-89d4-403c-b7e4-23984ac8aa15', 'Sacrament Meeting', '2025-04-27 09:00:00.000000', '120');
+INSERT INTO AgendaItems (id, meeting_id, item_type, item_order, meeting_role, details) VALUES ('c4ce7194-0f38-4b7b-89d1-09be87b902fd', '6cd5b59a-413a-4815-b3a9-e99a5dc91b50', 'blank', 0, 'Pre-meeting', '{}');
+INSERT INTO AgendaItems (id, meeting_id, item_type, item_order, meeting_role, details) VALUES ('b68d7dfa-d318-4fde-9381-f4992393c981', '6cd5b59a-413a-4815-b3a9-e99a5dc91b50', 'timer', 1, 'Meeting Countdown', '{"show_meeting_details": false}');
+INSERT INTO AgendaItems (id, meeting_id, item_type, item_order, meeting_role, details) VALUES ('5535277e-4192-4872-9320-c0f7a52569b0', '6cd5b59a-413a-4815-b3a9-e99a5dc91b50', 'lyrics', 4, 'Opening Hymn', '{"hymn_id": "bb125745-55eb-448c-b255-dac7ef6444cc", "show_translation": true}');
+INSERT INTO `Organization` (`id`, `name`) VALUES ('d65ad59c-216c-11f0-a191-ac1f6bbcd39a', 'Mesa Flatiron Stake');
+INSERT INTO `Organization` (`id`, `name`) VALUES ('e7d7a025-5bcd-43c8-ba35-e80d91ead4b2', 'Boulder Mountain Ward');
+INSERT INTO `Organization` (`id`, `name`) VALUES ('6b11edd1-1d25-4268-a6a2-6e564ebde510', 'Ironwood Ward');
+INSERT INTO `Organization` (`id`, `name`) VALUES ('62a29b38-5255-43bc-b857-2480bf7b0de5', 'Ocotillo Ward');
+INSERT INTO `Organization` (`id`, `name`) VALUES ('37a98239-bf69-430f-bc28-7af63edd52c7', 'Twin Knolls Ward');
+INSERT INTO `Organization` (`id`, `name`) VALUES ('1b951e53-89d4-403c-b7e4-23984ac8aa15', 'Signal Butte 1st Ward');
+INSERT INTO `Organization` (`id`, `name`) VALUES ('bf6b6624-71a5-49dd-8c89-59938606577b', 'Adobe Branch');
+INSERT INTO `OrgUsers` (`org_id`, `user_id`, `role`) VALUES ('d65ad59c-216c-11f0-a191-ac1f6bbcd39a', '3cd5fe4e-9ecb-4ec2-b7c7-0d19288c08e0', 'owner');
+INSERT INTO `OrgUsers` (`org_id`, `user_id`, `role`) VALUES ('e7d7a025-5bcd-43c8-ba35-e80d91ead4b2', '3cd5fe4e-9ecb-4ec2-b7c7-0d19288c08e0', 'owner');
+INSERT INTO `OrgUsers` (`org_id`, `user_id`, `role`) VALUES ('d65ad59c-216c-11f0-a191-ac1f6bbcd39a', 'a5659535-43a8-486d-9b68-1da5d3fdee06', 'owner');
+INSERT INTO `OrgUsers` (`org_id`, `user_id`, `role`) VALUES ('e7d7a025-5bcd-43c8-ba35-e80d91ead4b2', 'a5659535-43a8-486d-9b68-1da5d3fdee06', 'owner');
@@ -0,0 +1,55 @@
+package auth
+
+import (
+	"time"
+
+	"github.com/MicahParks/keyfunc"
+	"github.com/golang-jwt/jwt/v4"
+)
+
+// firebaseJWKSURL publishes the keys used to sign Firebase ID tokens, in JWK format
+const firebaseJWKSURL = "https://www.googleapis.com/service_accounts/v1/jwk/securetoken@system.gserviceaccount.com"
+
+type FirebaseConfig struct {
+	ProjectID string `envconfig:"FIREBASE_PROJECT_ID" default:"lower3-d26f2"`
+}
+
+// Issuer returns the expected `iss` claim for the project's ID tokens
+func (cfg FirebaseConfig) Issuer() string {
+	return "https://securetoken.google.com/" + cfg.ProjectID
+}
+
+// FirebaseVerifier verifies Firebase ID tokens against Google's published signing keys
+type FirebaseVerifier struct {
+	*verifier
+	jwks *keyfunc.JWKS
+}
+
+// NewFirebaseVerifier fetches the Firebase signing keys and keeps them refreshed in the background
+func NewFirebaseVerifier(cfg FirebaseConfig) (*FirebaseVerifier, error) {
+	jwks, err := keyfunc.Get(firebaseJWKSURL, keyfunc.Options{
+		RefreshInterval:   time.Hour,
+		RefreshRateLimit:  time.Minute * 5,
+		RefreshTimeout:    10 * time.Second,
+		RefreshUnknownKID: true,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &FirebaseVerifier{
+		verifier: &verifier{
+			keyfunc:  jwks.Keyfunc,
+			methods:  []string{jwt.SigningMethodRS256.Alg()},
+			audience: cfg.ProjectID,
+			issuer:   cfg.Issuer(),
+			now:      time.Now,
+		},
+		jwks: jwks,
+	}, nil
+}
+
+// Close stops the background refresh of the signing keys
+func (v *FirebaseVerifier) Close() {
+	v.jwks.EndBackground()
+}
@@ -0,0 +1,109 @@
+package auth
+
+import (
+	"context"
+	"crypto/rsa"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+)
+
+var (
+	ErrInvalidToken    = errors.New("invalid token")
+	ErrTokenExpired    = errors.New("token is expired")
+	ErrInvalidAudience = errors.New("invalid audience")
+	ErrInvalidIssuer   = errors.New("invalid issuer")
+	ErrInvalidAuthTime = errors.New("invalid auth_time")
+	ErrInvalidSubject  = errors.New("user_id claim missing or invalid")
+)
+
+// clockSkew is the leeway allowed when comparing token timestamps against the local clock
+const clockSkew = time.Minute
+
+// Claims holds the verified claims of a Firebase ID token
+type Claims struct {
+	jwt.RegisteredClaims
+	UserID        string `json:"user_id"`
+	AuthTime      int64  `json:"auth_time"`
+	Email         string `json:"email,omitempty"`
+	EmailVerified bool   `json:"email_verified,omitempty"`
+	Name          string `json:"name,omitempty"`
+	Picture       string `json:"picture,omitempty"`
+}
+
+// TokenVerifier verifies a bearer token and returns its claims
+type TokenVerifier interface {
+	Verify(ctx context.Context, tokenStr string) (*Claims, error)
+}
+
+// verifier checks the signature, expiry, audience, issuer and auth_time of a token
+type verifier struct {
+	keyfunc  jwt.Keyfunc
+	methods  []string
+	audience string
+	issuer   string
+	now      func() time.Time
+}
+
+func (v *verifier) Verify(ctx context.Context, tokenStr string) (*Claims, error) {
+	var claims Claims
+	parser := jwt.NewParser(jwt.WithValidMethods(v.methods), jwt.WithoutClaimsValidation())
+	token, err := parser.ParseWithClaims(tokenStr, &claims, v.keyfunc)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidToken, err)
+	}
+	if !token.Valid {
+		return nil, ErrInvalidToken
+	}
+
+	now := v.now()
+	if claims.ExpiresAt == nil || !now.Before(claims.ExpiresAt.Add(clockSkew)) {
+		return nil, ErrTokenExpired
+	}
+	if claims.IssuedAt != nil && claims.IssuedAt.After(now.Add(clockSkew)) {
+		return nil, fmt.Errorf("%w: issued in the future", ErrInvalidToken)
+	}
+	if !claims.VerifyAudience(v.audience, true) {
+		return nil, ErrInvalidAudience
+	}
+	if !claims.VerifyIssuer(v.issuer, true) {
+		return nil, ErrInvalidIssuer
+	}
+	if claims.AuthTime <= 0 || time.Unix(claims.AuthTime, 0).After(now.Add(clockSkew)) {
+		return nil, ErrInvalidAuthTime
+	}
+	if claims.Subject == "" || (claims.UserID != "" && claims.UserID != claims.Subject) {
+		return nil, ErrInvalidSubject
+	}
+	if claims.UserID == "" {
+		claims.UserID = claims.Subject
+	}
+
+	return &claims, nil
+}
+
+// NewStaticKeyVerifier creates a verifier for tokens signed with a single known key.
+// An *rsa.PublicKey accepts RS256 tokens and a []byte secret accepts HS256 tokens.
+func NewStaticKeyVerifier(key interface{}, audience string, issuer string) (TokenVerifier, error) {
+	var methods []string
+	switch key.(type) {
+	case []byte:
+		methods = []string{jwt.SigningMethodHS256.Alg()}
+	case *rsa.PublicKey:
+		methods = []string{jwt.SigningMethodRS256.Alg()}
+	default:
+		return nil, fmt.Errorf("unsupported verification key type %T", key)
+	}
+
+	return &verifier{
+		keyfunc: func(*jwt.Token) (interface{}, error) {
+			return key, nil
+		},
+		methods:  methods,
+		audience: audience,
+		issuer:   issuer,
+		now:      time.Now,
+	}, nil
+}
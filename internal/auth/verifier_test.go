@@ -0,0 +1,193 @@
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+)
+
+const (
+	testProjectID = "test-project"
+	testIssuer    = "https://securetoken.google.com/test-project"
+)
+
+func validClaims() Claims {
+	now := time.Now()
+	return Claims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			Subject:   "firebase-uid",
+			Audience:  jwt.ClaimStrings{testProjectID},
+			Issuer:    testIssuer,
+			IssuedAt:  jwt.NewNumericDate(now.Add(-time.Minute)),
+			ExpiresAt: jwt.NewNumericDate(now.Add(time.Hour)),
+		},
+		UserID:   "firebase-uid",
+		AuthTime: now.Add(-time.Minute).Unix(),
+		Email:    "test@example.com",
+	}
+}
+
+func signRS256(t *testing.T, key *rsa.PrivateKey, claims Claims) string {
+	t.Helper()
+	tokenStr, err := jwt.NewWithClaims(jwt.SigningMethodRS256, claims).SignedString(key)
+	if err != nil {
+		t.Fatalf("failed to sign token: %v", err)
+	}
+	return tokenStr
+}
+
+func TestStaticKeyVerifier(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+	otherKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+
+	verifier, err := NewStaticKeyVerifier(&key.PublicKey, testProjectID, testIssuer)
+	if err != nil {
+		t.Fatalf("NewStaticKeyVerifier failed: %v", err)
+	}
+
+	unsigned, err := jwt.NewWithClaims(jwt.SigningMethodNone, validClaims()).SignedString(jwt.UnsafeAllowNoneSignatureType)
+	if err != nil {
+		t.Fatalf("failed to build unsigned token: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		token   string
+		wantErr error
+	}{
+		{
+			name:  "valid token",
+			token: signRS256(t, key, validClaims()),
+		},
+		{
+			name:    "unsigned token",
+			token:   unsigned,
+			wantErr: ErrInvalidToken,
+		},
+		{
+			name:    "signed by another key",
+			token:   signRS256(t, otherKey, validClaims()),
+			wantErr: ErrInvalidToken,
+		},
+		{
+			name: "expired",
+			token: signRS256(t, key, func() Claims {
+				c := validClaims()
+				c.ExpiresAt = jwt.NewNumericDate(time.Now().Add(-time.Hour))
+				return c
+			}()),
+			wantErr: ErrTokenExpired,
+		},
+		{
+			name: "wrong audience",
+			token: signRS256(t, key, func() Claims {
+				c := validClaims()
+				c.Audience = jwt.ClaimStrings{"another-project"}
+				return c
+			}()),
+			wantErr: ErrInvalidAudience,
+		},
+		{
+			name: "wrong issuer",
+			token: signRS256(t, key, func() Claims {
+				c := validClaims()
+				c.Issuer = "https://securetoken.google.com/another-project"
+				return c
+			}()),
+			wantErr: ErrInvalidIssuer,
+		},
+		{
+			name: "missing auth_time",
+			token: signRS256(t, key, func() Claims {
+				c := validClaims()
+				c.AuthTime = 0
+				return c
+			}()),
+			wantErr: ErrInvalidAuthTime,
+		},
+		{
+			name: "auth_time in the future",
+			token: signRS256(t, key, func() Claims {
+				c := validClaims()
+				c.AuthTime = time.Now().Add(time.Hour).Unix()
+				return c
+			}()),
+			wantErr: ErrInvalidAuthTime,
+		},
+		{
+			name: "mismatched user_id",
+			token: signRS256(t, key, func() Claims {
+				c := validClaims()
+				c.UserID = "someone-else"
+				return c
+			}()),
+			wantErr: ErrInvalidSubject,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			claims, err := verifier.Verify(context.Background(), tt.token)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("expected error %v, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Verify failed: %v", err)
+			}
+			if claims.UserID != "firebase-uid" {
+				t.Errorf("unexpected UserID: %v", claims.UserID)
+			}
+			if claims.Email != "test@example.com" {
+				t.Errorf("unexpected Email: %v", claims.Email)
+			}
+		})
+	}
+}
+
+func TestStaticKeyVerifier_HMAC(t *testing.T) {
+	secret := []byte("test-secret")
+	verifier, err := NewStaticKeyVerifier(secret, testProjectID, testIssuer)
+	if err != nil {
+		t.Fatalf("NewStaticKeyVerifier failed: %v", err)
+	}
+
+	tokenStr, err := jwt.NewWithClaims(jwt.SigningMethodHS256, validClaims()).SignedString(secret)
+	if err != nil {
+		t.Fatalf("failed to sign token: %v", err)
+	}
+	if _, err := verifier.Verify(context.Background(), tokenStr); err != nil {
+		t.Errorf("Verify failed: %v", err)
+	}
+
+	// A subject-only token gets the user ID from `sub`
+	c := validClaims()
+	c.UserID = ""
+	tokenStr, _ = jwt.NewWithClaims(jwt.SigningMethodHS256, c).SignedString(secret)
+	claims, err := verifier.Verify(context.Background(), tokenStr)
+	if err != nil {
+		t.Fatalf("Verify failed: %v", err)
+	}
+	if claims.UserID != "firebase-uid" {
+		t.Errorf("unexpected UserID: %v", claims.UserID)
+	}
+}
+
+func TestNewStaticKeyVerifier_UnsupportedKey(t *testing.T) {
+	if _, err := NewStaticKeyVerifier("not a key", testProjectID, testIssuer); err == nil {
+		t.Error("expected an error for an unsupported key type")
+	}
+}
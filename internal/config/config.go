@@ -1,13 +1,24 @@
 package config
 
 import (
+	"lowerthirdsapi/internal/auth"
+	"lowerthirdsapi/internal/events"
 	"lowerthirdsapi/internal/helpers"
+	"lowerthirdsapi/internal/invitations"
+	"lowerthirdsapi/internal/mail"
+	"lowerthirdsapi/internal/scheduler"
 	"lowerthirdsapi/internal/storage"
 )
 
 type Config struct {
 	Environment string `envconfig:"ENVIRONMENT"`
 	MySQLConfig storage.MySQLConfig
+	Storage     storage.BackendConfig
+	Firebase    auth.FirebaseConfig
+	Invitations invitations.Config
+	Mail        mail.Config
+	Events      events.Config
+	Scheduler   scheduler.Config
 }
 
 func New(envDir string) *Config {
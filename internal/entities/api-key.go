@@ -0,0 +1,58 @@
+package entities
+
+import (
+	"github.com/google/uuid"
+	"gopkg.in/guregu/null.v4"
+	"time"
+)
+
+// APIKeyPrefix starts every API key, so bearer tokens that are keys can be told apart from ID tokens
+const APIKeyPrefix = "ltk_"
+
+// APIKeyScope limits what an API key may do in its org
+type APIKeyScope string
+
+const (
+	APIKeyScopeRead     APIKeyScope = "read"     // read meetings, agendas and live state
+	APIKeyScopeOperator APIKeyScope = "operator" // also run meetings live
+)
+
+// Role is the org role the scope grants, or an empty role for an unknown scope
+func (s APIKeyScope) Role() Role {
+	switch s {
+	case APIKeyScopeRead:
+		return RoleViewer
+	case APIKeyScopeOperator:
+		return RoleOperator
+	default:
+		return ""
+	}
+}
+
+// Valid reports whether the scope is one of the known scopes
+func (s APIKeyScope) Valid() bool {
+	return s.Role() != ""
+}
+
+// APIKey lets a device like an overlay machine or Stream Deck call the API for one org without signing in. It acts
+// as its own member of the org, UserID, with the role of its scope. The key itself is only shown when it's made;
+// Prefix is its start, to tell keys apart.
+type APIKey struct {
+	KeyID      uuid.UUID   `db:"id" json:"id"`
+	OrgID      uuid.UUID   `db:"org_id" json:"org_id"`
+	UserID     uuid.UUID   `db:"user_id" json:"user_id"`
+	Name       string      `db:"name" json:"name"`
+	Scope      APIKeyScope `db:"scope" json:"scope"`
+	Prefix     string      `db:"prefix" json:"prefix"`
+	KeyHash    string      `db:"key_hash" json:"-"`
+	CreatedBy  uuid.UUID   `db:"created_by" json:"created_by"`
+	LastUsedDT null.Time   `db:"last_used_dt" json:"last_used_dt"`
+	DeletedDT  null.Time   `db:"deleted_dt" json:"deleted_dt,omitempty"`
+	InsertedDT time.Time   `db:"inserted_dt" json:"inserted_dt"`
+	UpdatedDT  time.Time   `db:"updated_dt" json:"updated_dt"`
+}
+
+// SocialID is the social ID of the key's user, which no sign-in account can have
+func (k APIKey) SocialID() string {
+	return "apikey:" + k.KeyID.String()
+}
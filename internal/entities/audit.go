@@ -0,0 +1,146 @@
+package entities
+
+import (
+	"bytes"
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"github.com/google/uuid"
+	"gopkg.in/guregu/null.v4"
+	"time"
+)
+
+// AuditAction is what a change did to an entity
+type AuditAction string
+
+const (
+	AuditCreate AuditAction = "create"
+	AuditUpdate AuditAction = "update"
+	AuditDelete AuditAction = "delete"
+)
+
+// Valid reports whether the action is one of the known actions
+func (a AuditAction) Valid() bool {
+	return a == AuditCreate || a == AuditUpdate || a == AuditDelete
+}
+
+// AuditEntity is the kind of entity a change was made to
+type AuditEntity string
+
+const (
+	AuditAPIKey       AuditEntity = "api_key"
+	AuditInvitation   AuditEntity = "invitation"
+	AuditItem         AuditEntity = "item"
+	AuditLiveState    AuditEntity = "live_state" // ID is the meeting's
+	AuditMeeting      AuditEntity = "meeting"
+	AuditMeetingTheme AuditEntity = "meeting_theme" // ID is the meeting's
+	AuditMember       AuditEntity = "member"        // ID is the member's user ID
+	AuditOrg          AuditEntity = "org"
+	AuditOrgTheme     AuditEntity = "org_theme" // ID is the org's
+	AuditOverlayToken AuditEntity = "overlay_token"
+	AuditSchedule     AuditEntity = "schedule"
+	AuditTemplate     AuditEntity = "template"
+	AuditTimer        AuditEntity = "timer" // ID is the item's
+	AuditUser         AuditEntity = "user"
+)
+
+// auditIgnoredKeys are bookkeeping fields that change with every write, so they're left out of diffs
+var auditIgnoredKeys = map[string]bool{"inserted_dt": true, "updated_dt": true}
+
+// AuditRecord is one change to an entity: who made it, in which org and request, and what it changed. OrgID is null
+// for entities outside any org, like users, and ActorID for changes made without a user, like scheduled meetings.
+type AuditRecord struct {
+	AuditID    int64         `db:"id" json:"id"`
+	OrgID      uuid.NullUUID `db:"org_id" json:"org_id"`
+	ActorID    uuid.NullUUID `db:"actor_id" json:"actor_id"`
+	EntityType AuditEntity   `db:"entity_type" json:"entity_type"`
+	EntityID   string        `db:"entity_id" json:"entity_id"`
+	Action     AuditAction   `db:"action" json:"action"`
+	Diff       AuditDiff     `db:"diff" json:"diff"`
+	RequestID  null.String   `db:"request_id" json:"request_id"`
+	InsertedDT time.Time     `db:"inserted_dt" json:"inserted_dt"`
+}
+
+// AuditChange is a field's JSON before and after a change. A field that didn't exist before, as in a create, has
+// no before, and one that's gone after, as in a delete, has no after.
+type AuditChange struct {
+	Before json.RawMessage `json:"before,omitempty"`
+	After  json.RawMessage `json:"after,omitempty"`
+}
+
+// AuditDiff is the fields a change touched, by their JSON names
+type AuditDiff map[string]AuditChange
+
+// NewAuditDiff compares the JSON of an entity before and after a change, either of which may be nil, and keeps the
+// fields that differ
+func NewAuditDiff(before interface{}, after interface{}) (AuditDiff, error) {
+	beforeFields, err := auditFields(before)
+	if err != nil {
+		return nil, err
+	}
+	afterFields, err := auditFields(after)
+	if err != nil {
+		return nil, err
+	}
+
+	diff := AuditDiff{}
+	for key, value := range beforeFields {
+		if auditIgnoredKeys[key] || bytes.Equal(value, afterFields[key]) {
+			continue
+		}
+		diff[key] = AuditChange{Before: value, After: afterFields[key]}
+	}
+	for key, value := range afterFields {
+		if _, ok := beforeFields[key]; ok || auditIgnoredKeys[key] {
+			continue
+		}
+		diff[key] = AuditChange{After: value}
+	}
+	return diff, nil
+}
+
+// auditFields splits an entity's JSON object into its fields, or none for nil
+func auditFields(entity interface{}) (map[string]json.RawMessage, error) {
+	if entity == nil {
+		return nil, nil
+	}
+	data, err := json.Marshal(entity)
+	if err != nil {
+		return nil, err
+	}
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, fmt.Errorf("can't audit %T: %w", entity, err)
+	}
+	return fields, nil
+}
+
+// Value stores the diff as a JSON object
+func (d AuditDiff) Value() (driver.Value, error) {
+	if d == nil {
+		return []byte("{}"), nil
+	}
+	return json.Marshal(map[string]AuditChange(d))
+}
+
+// Scan reads the diff from a JSON object
+func (d *AuditDiff) Scan(src any) error {
+	var data []byte
+	switch src := src.(type) {
+	case []byte:
+		data = src
+	case string:
+		data = []byte(src)
+	case nil:
+		*d = AuditDiff{}
+		return nil
+	default:
+		return fmt.Errorf("can't scan %T into an audit diff", src)
+	}
+	diff := AuditDiff{}
+	if err := json.Unmarshal(data, &diff); err != nil {
+		return err
+	}
+	*d = diff
+	return nil
+}
@@ -0,0 +1,87 @@
+package entities
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"gopkg.in/guregu/null.v4"
+)
+
+func TestNewAuditDiff(t *testing.T) {
+	before := Meeting{
+		MeetingID:  uuid.New(),
+		Meeting:    "Sacrament Meeting",
+		Conference: null.StringFrom("Ward"),
+		InsertedDT: time.Now(),
+		UpdatedDT:  time.Now(),
+	}
+	after := before
+	after.Meeting = "Stake Conference"
+	after.Conference = null.String{}
+	after.UpdatedDT = before.UpdatedDT.Add(time.Minute)
+
+	diff, err := NewAuditDiff(before, after)
+	if err != nil {
+		t.Fatalf("NewAuditDiff failed: %v", err)
+	}
+	if len(diff) != 2 {
+		t.Fatalf("expected meeting and conference to change, got %v", diff)
+	}
+	if string(diff["meeting"].Before) != `"Sacrament Meeting"` || string(diff["meeting"].After) != `"Stake Conference"` {
+		t.Errorf("unexpected meeting change %s -> %s", diff["meeting"].Before, diff["meeting"].After)
+	}
+	if string(diff["conference"].After) != "null" {
+		t.Errorf("expected conference to be cleared, got %s", diff["conference"].After)
+	}
+
+	created, err := NewAuditDiff(nil, after)
+	if err != nil {
+		t.Fatalf("NewAuditDiff failed: %v", err)
+	}
+	if created["id"].Before != nil || string(created["id"].After) != `"`+after.MeetingID.String()+`"` {
+		t.Errorf("expected a create to have only afters, got %+v", created["id"])
+	}
+	if _, ok := created["inserted_dt"]; ok {
+		t.Error("expected bookkeeping fields to be left out")
+	}
+
+	deleted, err := NewAuditDiff(before, nil)
+	if err != nil {
+		t.Fatalf("NewAuditDiff failed: %v", err)
+	}
+	if deleted["meeting"].After != nil || deleted["meeting"].Before == nil {
+		t.Errorf("expected a delete to have only befores, got %+v", deleted["meeting"])
+	}
+
+	if _, err := NewAuditDiff("not an object", nil); err == nil {
+		t.Error("expected an error for an entity that isn't a JSON object")
+	}
+}
+
+func TestAuditDiffScan(t *testing.T) {
+	diff := AuditDiff{"name": {Before: json.RawMessage(`"a"`), After: json.RawMessage(`"b"`)}}
+	value, err := diff.Value()
+	if err != nil {
+		t.Fatalf("Value failed: %v", err)
+	}
+
+	for _, src := range []any{value, string(value.([]byte))} {
+		var scanned AuditDiff
+		if err := scanned.Scan(src); err != nil {
+			t.Fatalf("Scan(%T) failed: %v", src, err)
+		}
+		if string(scanned["name"].After) != `"b"` {
+			t.Errorf("Scan(%T) = %v", src, scanned)
+		}
+	}
+
+	var empty AuditDiff
+	if err := empty.Scan(nil); err != nil || empty == nil {
+		t.Errorf("expected an empty diff for NULL, got %v, %v", empty, err)
+	}
+	if err := empty.Scan(42); err == nil {
+		t.Error("expected an error scanning an int")
+	}
+}
@@ -0,0 +1,30 @@
+package entities
+
+import (
+	"github.com/google/uuid"
+	"gopkg.in/guregu/null.v4"
+	"time"
+)
+
+type Hymn struct {
+	HymnID        uuid.UUID     `db:"id" json:"id"`
+	Page          int           `db:"page" json:"page"`
+	Language      string        `db:"language" json:"language"`
+	Name          string        `db:"name" json:"name"`
+	TranslationID uuid.NullUUID `db:"translation_id" json:"translation_id"`
+	Verses        []HymnVerse   `json:"verses,omitempty"`
+	Translation   *Hymn         `json:"translation,omitempty"`
+	DeletedDT     null.Time     `db:"deleted_dt" json:"deleted_dt"`
+	InsertedDT    time.Time     `db:"inserted_dt" json:"inserted_dt"`
+	UpdatedDT     time.Time     `db:"updated_dt" json:"updated_dt"`
+}
+
+type HymnVerse struct {
+	HymnID      uuid.UUID   `db:"hymn_id" json:"hymn_id"`
+	VerseNumber int         `db:"verse_number" json:"verse_number"`
+	VerseLines  null.String `db:"verse_lines" json:"verse_lines"`
+	Optional    bool        `db:"optional" json:"optional"`
+	DeletedDT   null.Time   `db:"deleted_dt" json:"deleted_dt"`
+	InsertedDT  time.Time   `db:"inserted_dt" json:"inserted_dt"`
+	UpdatedDT   time.Time   `db:"updated_dt" json:"updated_dt"`
+}
@@ -0,0 +1,28 @@
+package entities
+
+import (
+	"github.com/google/uuid"
+	"gopkg.in/guregu/null.v4"
+	"time"
+)
+
+// Invitation asks someone, by email, to join an organization with a role. It's accepted once, before it expires,
+// with the signed token emailed to them. Revoked invitations are deleted.
+type Invitation struct {
+	InvitationID uuid.UUID     `db:"id" json:"id"`
+	OrgID        uuid.UUID     `db:"org_id" json:"org_id"`
+	Email        string        `db:"email" json:"email"`
+	Role         Role          `db:"role" json:"role"`
+	InvitedBy    uuid.UUID     `db:"invited_by" json:"invited_by"`
+	ExpiresDT    time.Time     `db:"expires_dt" json:"expires_dt"`
+	AcceptedBy   uuid.NullUUID `db:"accepted_by" json:"accepted_by"`
+	AcceptedDT   null.Time     `db:"accepted_dt" json:"accepted_dt"`
+	DeletedDT    null.Time     `db:"deleted_dt" json:"deleted_dt,omitempty"`
+	InsertedDT   time.Time     `db:"inserted_dt" json:"inserted_dt"`
+	UpdatedDT    time.Time     `db:"updated_dt" json:"updated_dt"`
+}
+
+// Pending reports whether the invitation can still be accepted at now
+func (i Invitation) Pending(now time.Time) bool {
+	return !i.AcceptedDT.Valid && !i.DeletedDT.Valid && now.Before(i.ExpiresDT)
+}
@@ -94,6 +94,59 @@ func ParseItemJSON(data []byte) (Item, error) {
     }
 }
 
+// CopyItem returns a copy of item with a new ID, in meetingID at order. The copy hasn't been saved, so it has no
+// timestamps.
+func CopyItem(item Item, meetingID uuid.UUID, order int) (Item, error) {
+    switch v := item.(type) {
+    case *BlankItem:
+        c := *v
+        c.BlankItemID, c.MeetingID, c.ItemOrder = uuid.New(), meetingID, order
+        c.DeletedDT, c.InsertedDT, c.UpdatedDT = null.Time{}, time.Time{}, time.Time{}
+        return &c, nil
+    case *LyricsItem:
+        c := *v
+        c.LyricsItemID, c.MeetingID, c.ItemOrder = uuid.New(), meetingID, order
+        c.DeletedDT, c.InsertedDT, c.UpdatedDT = null.Time{}, time.Time{}, time.Time{}
+        return &c, nil
+    case *MessageItem:
+        c := *v
+        c.MessageItemID, c.MeetingID, c.ItemOrder = uuid.New(), meetingID, order
+        c.DeletedDT, c.InsertedDT, c.UpdatedDT = null.Time{}, time.Time{}, time.Time{}
+        return &c, nil
+    case *SpeakerItem:
+        c := *v
+        c.SpeakerItemID, c.MeetingID, c.ItemOrder = uuid.New(), meetingID, order
+        c.DeletedDT, c.InsertedDT, c.UpdatedDT = null.Time{}, time.Time{}, time.Time{}
+        return &c, nil
+    case *TimerItem:
+        c := *v
+        c.TimerItemID, c.MeetingID, c.ItemOrder = uuid.New(), meetingID, order
+        c.DeletedDT, c.InsertedDT, c.UpdatedDT = null.Time{}, time.Time{}, time.Time{}
+        return &c, nil
+    default:
+        return nil, ErrUnknownItemType
+    }
+}
+
+// SetItemID gives item the ID id
+func SetItemID(item Item, id uuid.UUID) error {
+    switch v := item.(type) {
+    case *BlankItem:
+        v.BlankItemID = id
+    case *LyricsItem:
+        v.LyricsItemID = id
+    case *MessageItem:
+        v.MessageItemID = id
+    case *SpeakerItem:
+        v.SpeakerItemID = id
+    case *TimerItem:
+        v.TimerItemID = id
+    default:
+        return ErrUnknownItemType
+    }
+    return nil
+}
+
 type BlankItem struct {
     BlankItemID uuid.UUID `db:"id" json:"id,omitempty"`
     MeetingID   uuid.UUID `db:"meeting_id" json:"meeting_id"`
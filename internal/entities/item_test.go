@@ -3,6 +3,7 @@ package entities
 import (
 	"encoding/json"
 	"testing"
+	"time"
 
 	"github.com/google/uuid"
 )
@@ -125,3 +126,33 @@ func TestParseItemJSON_BlankItem(t *testing.T) {
 		t.Errorf("unexpected MeetingRole: %v", blank.MeetingRole)
 	}
 }
+
+func TestCopyItem(t *testing.T) {
+	original := &LyricsItem{
+		LyricsItemID: uuid.New(),
+		MeetingID:    uuid.New(),
+		ItemType:     "lyrics",
+		ItemOrder:    4,
+		HymnID:       "hymn-1",
+		InsertedDT:   time.Now(),
+	}
+	meetingID := uuid.New()
+
+	item, err := CopyItem(original, meetingID, 2)
+	if err != nil {
+		t.Fatalf("CopyItem failed: %v", err)
+	}
+	lyrics, ok := item.(*LyricsItem)
+	if !ok {
+		t.Fatalf("expected a *LyricsItem, got %T", item)
+	}
+	if lyrics.LyricsItemID == original.LyricsItemID || lyrics.MeetingID != meetingID || lyrics.ItemOrder != 2 {
+		t.Errorf("expected a new ID in the new meeting, got %+v", lyrics)
+	}
+	if lyrics.HymnID != "hymn-1" || !lyrics.InsertedDT.IsZero() {
+		t.Errorf("expected the fields but not the timestamps to be copied, got %+v", lyrics)
+	}
+	if original.ItemOrder != 4 {
+		t.Errorf("expected the original to be unchanged, got %+v", original)
+	}
+}
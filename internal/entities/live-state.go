@@ -0,0 +1,183 @@
+package entities
+
+import (
+	"errors"
+	"github.com/google/uuid"
+	"gopkg.in/guregu/null.v4"
+	"time"
+)
+
+var (
+	ErrUnknownLiveAction = errors.New("unknown live action")
+	ErrNotInAgenda       = errors.New("item is not in the meeting agenda")
+	ErrNothingCued       = errors.New("no item is cued in preview")
+	ErrEndOfAgenda       = errors.New("already at the end of the agenda")
+	ErrStartOfAgenda     = errors.New("already at the start of the agenda")
+	ErrNoProgram         = errors.New("no item is on air")
+	ErrLastVerse         = errors.New("already at the last verse")
+	ErrFirstVerse        = errors.New("already at the first verse")
+)
+
+// LiveAction is an operator command that changes what a meeting has on air
+type LiveAction string
+
+const (
+	LiveActionTake     LiveAction = "take"     // put the given item, or the preview item, on air
+	LiveActionCue      LiveAction = "cue"      // put the given item in preview
+	LiveActionNext     LiveAction = "next"     // put the item after the program item on air
+	LiveActionPrevious LiveAction = "previous" // put the item before the program item on air
+	LiveActionClear    LiveAction = "clear"    // take everything off air
+	LiveActionShow     LiveAction = "show"     // show the program item
+	LiveActionHide     LiveAction = "hide"     // hide the program item without losing its place
+
+	LiveActionNextVerse     LiveAction = "next_verse"     // show the next verse of the program item
+	LiveActionPreviousVerse LiveAction = "previous_verse" // show the previous verse of the program item
+)
+
+// LiveState records what a meeting currently has on screen. Version goes up with every applied action, so clients
+// can tell which of two states is newer.
+type LiveState struct {
+	MeetingID        uuid.UUID     `db:"meeting_id" json:"meeting_id"`
+	Version          int64         `db:"version" json:"version"`
+	ProgramItemID    uuid.NullUUID `db:"program_item_id" json:"program_item_id"`
+	PreviewItemID    uuid.NullUUID `db:"preview_item_id" json:"preview_item_id"`
+	Visible          bool          `db:"visible" json:"visible"`
+	VerseIndex       int           `db:"verse_index" json:"verse_index"`
+	VerseCount       int           `db:"verse_count" json:"verse_count"`
+	ProgramChangedDT null.Time     `db:"program_changed_dt" json:"program_changed_dt"`
+	PreviewChangedDT null.Time     `db:"preview_changed_dt" json:"preview_changed_dt"`
+	VisibleChangedDT null.Time     `db:"visible_changed_dt" json:"visible_changed_dt"`
+	InsertedDT       time.Time     `db:"inserted_dt" json:"inserted_dt"`
+	UpdatedDT        time.Time     `db:"updated_dt" json:"updated_dt"`
+}
+
+// Apply changes the state for an action. agenda is the meeting's item IDs in order, and itemID is the target of
+// take and cue (take falls back to the preview item when it's uuid.Nil). After a take, next or previous the item
+// following the program item is cued in preview. A new program item starts at its first verse; the caller sets
+// VerseCount for it.
+func (ls *LiveState) Apply(action LiveAction, itemID uuid.UUID, agenda []uuid.UUID, now time.Time) error {
+	err := ls.apply(action, itemID, agenda, now)
+	if err != nil {
+		return err
+	}
+	ls.Version++
+	return nil
+}
+
+func (ls *LiveState) apply(action LiveAction, itemID uuid.UUID, agenda []uuid.UUID, now time.Time) error {
+	switch action {
+	case LiveActionTake:
+		if itemID == uuid.Nil {
+			if !ls.PreviewItemID.Valid {
+				return ErrNothingCued
+			}
+			itemID = ls.PreviewItemID.UUID
+		}
+		if indexOf(agenda, itemID) < 0 {
+			return ErrNotInAgenda
+		}
+		ls.setProgram(itemID, agenda, now)
+	case LiveActionCue:
+		if indexOf(agenda, itemID) < 0 {
+			return ErrNotInAgenda
+		}
+		ls.setPreview(uuid.NullUUID{UUID: itemID, Valid: true}, now)
+	case LiveActionNext:
+		i := 0
+		if ls.ProgramItemID.Valid {
+			i = indexOf(agenda, ls.ProgramItemID.UUID) + 1
+		}
+		if i >= len(agenda) {
+			return ErrEndOfAgenda
+		}
+		ls.setProgram(agenda[i], agenda, now)
+	case LiveActionPrevious:
+		i := -1
+		if ls.ProgramItemID.Valid {
+			i = indexOf(agenda, ls.ProgramItemID.UUID) - 1
+		}
+		if i < 0 {
+			return ErrStartOfAgenda
+		}
+		ls.setProgram(agenda[i], agenda, now)
+	case LiveActionClear:
+		if ls.ProgramItemID.Valid {
+			ls.ProgramItemID = uuid.NullUUID{}
+			ls.ProgramChangedDT = null.TimeFrom(now)
+			ls.resetProgress()
+		}
+		ls.setVisible(false, now)
+	case LiveActionShow:
+		ls.setVisible(true, now)
+	case LiveActionHide:
+		ls.setVisible(false, now)
+	case LiveActionNextVerse:
+		if !ls.ProgramItemID.Valid {
+			return ErrNoProgram
+		}
+		if ls.VerseIndex+1 >= ls.VerseCount {
+			return ErrLastVerse
+		}
+		ls.VerseIndex++
+	case LiveActionPreviousVerse:
+		if !ls.ProgramItemID.Valid {
+			return ErrNoProgram
+		}
+		if ls.VerseIndex <= 0 {
+			return ErrFirstVerse
+		}
+		ls.VerseIndex--
+	default:
+		return ErrUnknownLiveAction
+	}
+	return nil
+}
+
+// ProgramChanged reports whether the program item is different from the one in an earlier state
+func (ls *LiveState) ProgramChanged(previous LiveState) bool {
+	return ls.ProgramItemID != previous.ProgramItemID
+}
+
+func (ls *LiveState) setProgram(itemID uuid.UUID, agenda []uuid.UUID, now time.Time) {
+	if !ls.ProgramItemID.Valid || ls.ProgramItemID.UUID != itemID {
+		ls.ProgramItemID = uuid.NullUUID{UUID: itemID, Valid: true}
+		ls.ProgramChangedDT = null.TimeFrom(now)
+		ls.resetProgress()
+	}
+	ls.setVisible(true, now)
+
+	var preview uuid.NullUUID
+	if i := indexOf(agenda, itemID) + 1; i < len(agenda) {
+		preview = uuid.NullUUID{UUID: agenda[i], Valid: true}
+	}
+	ls.setPreview(preview, now)
+}
+
+// resetProgress starts a new program item from its first verse
+func (ls *LiveState) resetProgress() {
+	ls.VerseIndex = 0
+	ls.VerseCount = 0
+}
+
+func (ls *LiveState) setPreview(itemID uuid.NullUUID, now time.Time) {
+	if ls.PreviewItemID != itemID {
+		ls.PreviewItemID = itemID
+		ls.PreviewChangedDT = null.TimeFrom(now)
+	}
+}
+
+func (ls *LiveState) setVisible(visible bool, now time.Time) {
+	if ls.Visible != visible {
+		ls.Visible = visible
+		ls.VisibleChangedDT = null.TimeFrom(now)
+	}
+}
+
+func indexOf(agenda []uuid.UUID, itemID uuid.UUID) int {
+	for i, id := range agenda {
+		if id == itemID {
+			return i
+		}
+	}
+	return -1
+}
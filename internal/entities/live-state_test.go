@@ -0,0 +1,140 @@
+package entities
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestLiveStateApply(t *testing.T) {
+	agenda := []uuid.UUID{uuid.New(), uuid.New(), uuid.New()}
+	now := time.Date(2025, 5, 4, 10, 0, 0, 0, time.UTC)
+	program := func(ls LiveState) uuid.UUID { return ls.ProgramItemID.UUID }
+	preview := func(ls LiveState) uuid.UUID { return ls.PreviewItemID.UUID }
+
+	ls := LiveState{}
+
+	// Nothing to take before anything is cued
+	if err := ls.Apply(LiveActionTake, uuid.Nil, agenda, now); !errors.Is(err, ErrNothingCued) {
+		t.Fatalf("expected ErrNothingCued, got %v", err)
+	}
+
+	// Next from an empty state starts at the top of the agenda
+	if err := ls.Apply(LiveActionNext, uuid.Nil, agenda, now); err != nil {
+		t.Fatalf("next failed: %v", err)
+	}
+	if program(ls) != agenda[0] || preview(ls) != agenda[1] || !ls.Visible {
+		t.Errorf("unexpected state after next: %+v", ls)
+	}
+	if !ls.ProgramChangedDT.Valid || !ls.ProgramChangedDT.Time.Equal(now) {
+		t.Errorf("expected program change time %v, got %v", now, ls.ProgramChangedDT)
+	}
+
+	// Cue and take the last item
+	later := now.Add(time.Minute)
+	if err := ls.Apply(LiveActionCue, agenda[2], agenda, later); err != nil {
+		t.Fatalf("cue failed: %v", err)
+	}
+	if program(ls) != agenda[0] || preview(ls) != agenda[2] {
+		t.Errorf("unexpected state after cue: %+v", ls)
+	}
+	if err := ls.Apply(LiveActionTake, uuid.Nil, agenda, later); err != nil {
+		t.Fatalf("take failed: %v", err)
+	}
+	if program(ls) != agenda[2] || ls.PreviewItemID.Valid {
+		t.Errorf("unexpected state after take: %+v", ls)
+	}
+	if err := ls.Apply(LiveActionNext, uuid.Nil, agenda, later); !errors.Is(err, ErrEndOfAgenda) {
+		t.Errorf("expected ErrEndOfAgenda, got %v", err)
+	}
+
+	// Previous steps back and cues the item after it
+	if err := ls.Apply(LiveActionPrevious, uuid.Nil, agenda, later); err != nil {
+		t.Fatalf("previous failed: %v", err)
+	}
+	if program(ls) != agenda[1] || preview(ls) != agenda[2] {
+		t.Errorf("unexpected state after previous: %+v", ls)
+	}
+
+	// Hiding keeps the program item, clearing drops it
+	if err := ls.Apply(LiveActionHide, uuid.Nil, agenda, later); err != nil {
+		t.Fatalf("hide failed: %v", err)
+	}
+	if ls.Visible || program(ls) != agenda[1] {
+		t.Errorf("unexpected state after hide: %+v", ls)
+	}
+	if !ls.VisibleChangedDT.Time.Equal(later) {
+		t.Errorf("expected visible change time %v, got %v", later, ls.VisibleChangedDT)
+	}
+	if err := ls.Apply(LiveActionShow, uuid.Nil, agenda, later); err != nil || !ls.Visible {
+		t.Fatalf("show failed: %v", err)
+	}
+	if err := ls.Apply(LiveActionClear, uuid.Nil, agenda, later); err != nil {
+		t.Fatalf("clear failed: %v", err)
+	}
+	if ls.ProgramItemID.Valid || ls.Visible {
+		t.Errorf("unexpected state after clear: %+v", ls)
+	}
+	if err := ls.Apply(LiveActionPrevious, uuid.Nil, agenda, later); !errors.Is(err, ErrStartOfAgenda) {
+		t.Errorf("expected ErrStartOfAgenda, got %v", err)
+	}
+}
+
+func TestLiveStateApplyErrors(t *testing.T) {
+	agenda := []uuid.UUID{uuid.New()}
+	ls := LiveState{}
+
+	if err := ls.Apply(LiveActionCue, uuid.New(), agenda, time.Now()); !errors.Is(err, ErrNotInAgenda) {
+		t.Errorf("expected ErrNotInAgenda for cue, got %v", err)
+	}
+	if err := ls.Apply(LiveActionTake, uuid.New(), agenda, time.Now()); !errors.Is(err, ErrNotInAgenda) {
+		t.Errorf("expected ErrNotInAgenda for take, got %v", err)
+	}
+	if err := ls.Apply("rewind", uuid.Nil, agenda, time.Now()); !errors.Is(err, ErrUnknownLiveAction) {
+		t.Errorf("expected ErrUnknownLiveAction, got %v", err)
+	}
+	if ls.ProgramItemID.Valid || ls.PreviewItemID.Valid || ls.Version != 0 {
+		t.Errorf("expected failed actions to leave the state alone, got %+v", ls)
+	}
+}
+
+func TestLiveStateVerses(t *testing.T) {
+	agenda := []uuid.UUID{uuid.New(), uuid.New()}
+	start := time.Date(2024, 1, 7, 10, 0, 0, 0, time.UTC)
+	ls := LiveState{}
+
+	if err := ls.Apply(LiveActionNextVerse, uuid.Nil, agenda, start); !errors.Is(err, ErrNoProgram) {
+		t.Errorf("expected ErrNoProgram, got %v", err)
+	}
+	if err := ls.Apply(LiveActionTake, agenda[0], agenda, start); err != nil {
+		t.Fatalf("take failed: %v", err)
+	}
+	ls.VerseCount = 2
+
+	if err := ls.Apply(LiveActionNextVerse, uuid.Nil, agenda, start); err != nil || ls.VerseIndex != 1 {
+		t.Fatalf("next_verse failed: %v, index %d", err, ls.VerseIndex)
+	}
+	if err := ls.Apply(LiveActionNextVerse, uuid.Nil, agenda, start); !errors.Is(err, ErrLastVerse) {
+		t.Errorf("expected ErrLastVerse, got %v", err)
+	}
+	if err := ls.Apply(LiveActionPreviousVerse, uuid.Nil, agenda, start); err != nil || ls.VerseIndex != 0 {
+		t.Fatalf("previous_verse failed: %v, index %d", err, ls.VerseIndex)
+	}
+	if err := ls.Apply(LiveActionPreviousVerse, uuid.Nil, agenda, start); !errors.Is(err, ErrFirstVerse) {
+		t.Errorf("expected ErrFirstVerse, got %v", err)
+	}
+	if ls.Version != 3 {
+		t.Errorf("expected version 3 after three actions, got %d", ls.Version)
+	}
+
+	// A new program item starts over
+	before := ls
+	if err := ls.Apply(LiveActionNext, uuid.Nil, agenda, start); err != nil {
+		t.Fatalf("next failed: %v", err)
+	}
+	if !ls.ProgramChanged(before) || ls.VerseIndex != 0 || ls.VerseCount != 0 {
+		t.Errorf("expected progress to reset for a new program item, got %+v", ls)
+	}
+}
@@ -0,0 +1,116 @@
+package entities
+
+import (
+	"bytes"
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
+	"gopkg.in/guregu/null.v4"
+	"time"
+)
+
+var (
+	ErrTemplateName    = errors.New("name is required")
+	ErrTemplateMeeting = errors.New("meeting is required")
+)
+
+// MeetingTemplate is an org's reusable agenda. Meeting, Conference and Duration are copied to each meeting made from
+// it, and Items are the item prototypes in agenda order.
+type MeetingTemplate struct {
+	TemplateID uuid.UUID     `db:"id" json:"id"`
+	OrgID      uuid.UUID     `db:"org_id" json:"org_id"`
+	Name       string        `db:"name" json:"name"`
+	Conference null.String   `db:"conference" json:"conference"`
+	Meeting    string        `db:"meeting" json:"meeting"`
+	Duration   null.Int      `db:"duration" json:"duration"`
+	Items      TemplateItems `db:"items" json:"items"`
+	DeletedDT  null.Time     `db:"deleted_dt" json:"deleted_dt"`
+	InsertedDT time.Time     `db:"inserted_dt" json:"inserted_dt"`
+	UpdatedDT  time.Time     `db:"updated_dt" json:"updated_dt"`
+}
+
+// TemplateItems are item prototypes, in the same JSON as items. Their id, meeting_id and order are ignored; each
+// meeting made from the template gets new items numbered in the order they're listed.
+type TemplateItems []json.RawMessage
+
+// Value stores the prototypes as a JSON array
+func (items TemplateItems) Value() (driver.Value, error) {
+	if items == nil {
+		return []byte("[]"), nil
+	}
+	return json.Marshal([]json.RawMessage(items))
+}
+
+// Scan reads the prototypes from a JSON array
+func (items *TemplateItems) Scan(src any) error {
+	var data []byte
+	switch src := src.(type) {
+	case []byte:
+		data = src
+	case string:
+		data = []byte(src)
+	case nil:
+		*items = TemplateItems{}
+		return nil
+	default:
+		return fmt.Errorf("can't scan %T into template items", src)
+	}
+	var raw []json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	*items = raw
+	return nil
+}
+
+// Validate checks the template has a name and meeting, and that each prototype is an item of a known type
+func (t *MeetingTemplate) Validate() error {
+	if t.Name == "" {
+		return ErrTemplateName
+	}
+	if t.Meeting == "" {
+		return ErrTemplateMeeting
+	}
+	for i, raw := range t.Items {
+		if _, err := ParseItemJSON(raw); err != nil {
+			return fmt.Errorf("item %d: %w", i, err)
+		}
+		var compacted bytes.Buffer
+		if err := json.Compact(&compacted, raw); err != nil {
+			return fmt.Errorf("item %d: %w", i, err)
+		}
+		t.Items[i] = compacted.Bytes()
+	}
+	if t.Items == nil {
+		t.Items = TemplateItems{}
+	}
+	return nil
+}
+
+// Instantiate makes a meeting on date from the template, with new items from its prototypes
+func (t MeetingTemplate) Instantiate(meetingID uuid.UUID, date time.Time) (Meeting, []Item, error) {
+	meeting := Meeting{
+		MeetingID:   meetingID,
+		OrgID:       t.OrgID,
+		Conference:  t.Conference,
+		Meeting:     t.Meeting,
+		MeetingDate: date,
+		Duration:    t.Duration,
+	}
+
+	items := make([]Item, 0, len(t.Items))
+	for i, raw := range t.Items {
+		item, err := ParseItemJSON(raw)
+		if err != nil {
+			return Meeting{}, nil, fmt.Errorf("item %d: %w", i, err)
+		}
+		item, err = CopyItem(item, meetingID, i+1)
+		if err != nil {
+			return Meeting{}, nil, fmt.Errorf("item %d: %w", i, err)
+		}
+		items = append(items, item)
+	}
+	return meeting, items, nil
+}
@@ -0,0 +1,73 @@
+package entities
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestMeetingTemplateValidate(t *testing.T) {
+	template := MeetingTemplate{Name: "Sacrament Meeting", Meeting: "Sacrament Meeting"}
+	if err := template.Validate(); err != nil || template.Items == nil {
+		t.Errorf("expected a template without items to be valid, got %v, %+v", err, template.Items)
+	}
+
+	template.Items = TemplateItems{json.RawMessage(`{"type": "speaker",  "name": "Speaker"}`)}
+	if err := template.Validate(); err != nil {
+		t.Fatalf("Validate failed: %v", err)
+	}
+	if string(template.Items[0]) != `{"type":"speaker","name":"Speaker"}` {
+		t.Errorf("expected the item to be compacted, got %s", template.Items[0])
+	}
+
+	template.Items = append(template.Items, json.RawMessage(`{"type": "video"}`))
+	if err := template.Validate(); !errors.Is(err, ErrUnknownItemType) {
+		t.Errorf("expected ErrUnknownItemType, got %v", err)
+	}
+	if err := (&MeetingTemplate{Meeting: "Sacrament Meeting"}).Validate(); !errors.Is(err, ErrTemplateName) {
+		t.Errorf("expected ErrTemplateName, got %v", err)
+	}
+	if err := (&MeetingTemplate{Name: "Sacrament Meeting"}).Validate(); !errors.Is(err, ErrTemplateMeeting) {
+		t.Errorf("expected ErrTemplateMeeting, got %v", err)
+	}
+}
+
+func TestMeetingTemplateInstantiate(t *testing.T) {
+	template := MeetingTemplate{
+		OrgID:   uuid.New(),
+		Name:    "Sacrament Meeting",
+		Meeting: "Sacrament Meeting",
+		Items: TemplateItems{
+			json.RawMessage(`{"type": "blank", "meeting_role": "Pre-meeting"}`),
+			json.RawMessage(`{"type": "timer", "meeting_role": "Countdown", "show_meeting_details": true}`),
+			json.RawMessage(`{"id": "6cd5b59a-413a-4815-b3a9-e99a5dc91b50", "type": "speaker", "order": 9, "name": "Speaker"}`),
+		},
+	}
+	meetingID := uuid.New()
+	date := time.Date(2025, 6, 1, 9, 0, 0, 0, time.UTC)
+
+	meeting, items, err := template.Instantiate(meetingID, date)
+	if err != nil {
+		t.Fatalf("Instantiate failed: %v", err)
+	}
+	if meeting.MeetingID != meetingID || meeting.OrgID != template.OrgID || !meeting.MeetingDate.Equal(date) {
+		t.Errorf("unexpected meeting: %+v", meeting)
+	}
+	if len(items) != 3 {
+		t.Fatalf("expected 3 items, got %d", len(items))
+	}
+	for i, item := range items {
+		if item.GetMeetingID() != meetingID || item.GetOrder() != i+1 || item.GetID() == uuid.Nil {
+			t.Errorf("unexpected item %d: %+v", i, item)
+		}
+	}
+	if items[2].GetID().String() == "6cd5b59a-413a-4815-b3a9-e99a5dc91b50" {
+		t.Error("expected the prototype's id to be replaced")
+	}
+	if timer, ok := items[1].(*TimerItem); !ok || !timer.ShowMeetingDetails {
+		t.Errorf("expected the timer prototype's fields to be kept, got %+v", items[1])
+	}
+}
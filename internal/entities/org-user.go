@@ -6,9 +6,38 @@ import (
 	"time"
 )
 
+// Role is a user's level of access within an organization
+type Role string
+
+const (
+	RoleViewer   Role = "viewer"   // read meetings and agendas
+	RoleOperator Role = "operator" // run meetings live
+	RoleEditor   Role = "editor"   // create and change meetings and agenda items
+	RoleOwner    Role = "owner"    // manage the organization and its members
+)
+
+var roleRank = map[Role]int{
+	RoleViewer:   1,
+	RoleOperator: 2,
+	RoleEditor:   3,
+	RoleOwner:    4,
+}
+
+// Valid reports whether the role is one of the known roles
+func (r Role) Valid() bool {
+	_, ok := roleRank[r]
+	return ok
+}
+
+// Includes reports whether the role grants at least the access of the required role
+func (r Role) Includes(required Role) bool {
+	return r.Valid() && roleRank[r] >= roleRank[required]
+}
+
 type OrgUser struct {
 	OrgID      uuid.UUID `db:"org_id" json:"org_id"`
 	UserID     uuid.UUID `db:"user_id" json:"user_id"`
+	Role       Role      `db:"role" json:"role"`
 	DeletedDT  null.Time `db:"deleted_dt" json:"deleted_dt"`
 	InsertedDT time.Time `db:"inserted_dt" json:"inserted_dt"`
 }
@@ -17,3 +46,9 @@ type OrgUserMap struct {
 	OrgID  uuid.UUID   `json:"org_id"`
 	UserID []uuid.UUID `json:"user_ids"`
 }
+
+// Membership is an org a user belongs to, with the user's role in it
+type Membership struct {
+	Organization
+	Role Role `db:"role" json:"role"`
+}
@@ -0,0 +1,36 @@
+package entities
+
+import "testing"
+
+func TestRoleIncludes(t *testing.T) {
+	tests := []struct {
+		role     Role
+		required Role
+		want     bool
+	}{
+		{RoleOwner, RoleEditor, true},
+		{RoleEditor, RoleEditor, true},
+		{RoleEditor, RoleOwner, false},
+		{RoleOperator, RoleViewer, true},
+		{RoleOperator, RoleEditor, false},
+		{RoleViewer, RoleOperator, false},
+		{Role(""), RoleViewer, false},
+		{Role("admin"), RoleViewer, false},
+	}
+	for _, tt := range tests {
+		if got := tt.role.Includes(tt.required); got != tt.want {
+			t.Errorf("%q.Includes(%q) = %v, want %v", tt.role, tt.required, got, tt.want)
+		}
+	}
+}
+
+func TestRoleValid(t *testing.T) {
+	for _, role := range []Role{RoleViewer, RoleOperator, RoleEditor, RoleOwner} {
+		if !role.Valid() {
+			t.Errorf("expected %q to be valid", role)
+		}
+	}
+	if Role("admin").Valid() {
+		t.Error("expected unknown role to be invalid")
+	}
+}
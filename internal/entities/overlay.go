@@ -0,0 +1,28 @@
+package entities
+
+import (
+	"github.com/google/uuid"
+	"gopkg.in/guregu/null.v4"
+	"time"
+)
+
+// OverlayToken grants unauthenticated, read-only access to a meeting's on-air graphic, for use in a browser source
+type OverlayToken struct {
+	Token      string    `db:"token" json:"token"`
+	MeetingID  uuid.UUID `db:"meeting_id" json:"meeting_id"`
+	DeletedDT  null.Time `db:"deleted_dt" json:"deleted_dt,omitempty"`
+	InsertedDT time.Time `db:"inserted_dt" json:"inserted_dt"`
+	UpdatedDT  time.Time `db:"updated_dt" json:"updated_dt"`
+}
+
+// Overlay is what a meeting's browser source should draw. Item is the program item, or nil when nothing is on air
+// or it's hidden. Slide is the current slide of a lyrics item, Timer is the program item's timer, and Theme is the
+// meeting's theme.
+type Overlay struct {
+	Meeting   Meeting   `json:"meeting"`
+	LiveState LiveState `json:"live_state"`
+	Item      Item      `json:"item"`
+	Slide     *Slide    `json:"slide"`
+	Timer     *Timer    `json:"timer"`
+	Theme     Theme     `json:"theme"`
+}
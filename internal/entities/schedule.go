@@ -0,0 +1,144 @@
+package entities
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
+	"gopkg.in/guregu/null.v4"
+	"lowerthirdsapi/internal/recurrence"
+	"time"
+)
+
+// DefaultLeadDays is how far ahead a schedule's meetings are made when it doesn't say
+const DefaultLeadDays = 14
+
+var (
+	ErrScheduleName     = errors.New("name is required")
+	ErrScheduleTemplate = errors.New("template_id is required")
+	ErrScheduleStart    = errors.New("start is required")
+	ErrScheduleLeadDays = errors.New("lead_days must be between 1 and 366")
+)
+
+// Schedule makes an org's meetings from a template on a recurrence rule. Start is the first meeting's local time
+// in TimeZone; the rule's times of day and weekdays are in that zone too, so a 9:00 meeting stays at 9:00 across
+// daylight saving changes. Exceptions are local dates that are skipped, and meetings are made LeadDays ahead.
+type Schedule struct {
+	ScheduleID uuid.UUID     `db:"id" json:"id"`
+	OrgID      uuid.UUID     `db:"org_id" json:"org_id"`
+	TemplateID uuid.UUID     `db:"template_id" json:"template_id"`
+	Name       string        `db:"name" json:"name"`
+	RRule      string        `db:"rrule" json:"rrule"`
+	TimeZone   string        `db:"time_zone" json:"time_zone"`
+	StartDT    time.Time     `db:"start_dt" json:"start"`
+	Exceptions ScheduleDates `db:"exceptions" json:"exceptions"`
+	LeadDays   int           `db:"lead_days" json:"lead_days"`
+	DeletedDT  null.Time     `db:"deleted_dt" json:"deleted_dt"`
+	InsertedDT time.Time     `db:"inserted_dt" json:"inserted_dt"`
+	UpdatedDT  time.Time     `db:"updated_dt" json:"updated_dt"`
+}
+
+// ScheduleDates are local dates in 2006-01-02 format
+type ScheduleDates []string
+
+// Value stores the dates as a JSON array
+func (dates ScheduleDates) Value() (driver.Value, error) {
+	if dates == nil {
+		return []byte("[]"), nil
+	}
+	return json.Marshal([]string(dates))
+}
+
+// Scan reads the dates from a JSON array
+func (dates *ScheduleDates) Scan(src any) error {
+	var data []byte
+	switch src := src.(type) {
+	case []byte:
+		data = src
+	case string:
+		data = []byte(src)
+	case nil:
+		*dates = ScheduleDates{}
+		return nil
+	default:
+		return fmt.Errorf("can't scan %T into schedule dates", src)
+	}
+	var list []string
+	if err := json.Unmarshal(data, &list); err != nil {
+		return err
+	}
+	*dates = list
+	return nil
+}
+
+// Validate checks the schedule's fields and rule, and fills in the default time zone and lead days. Start keeps its
+// wall-clock time but is stored without an offset, since the database would otherwise shift it to UTC.
+func (sc *Schedule) Validate() error {
+	if sc.Name == "" {
+		return ErrScheduleName
+	}
+	if sc.TemplateID == uuid.Nil {
+		return ErrScheduleTemplate
+	}
+	if sc.StartDT.IsZero() {
+		return ErrScheduleStart
+	}
+	sc.StartDT = wallClock(sc.StartDT, time.UTC)
+	if _, err := recurrence.Parse(sc.RRule); err != nil {
+		return err
+	}
+	if sc.TimeZone == "" {
+		sc.TimeZone = "UTC"
+	}
+	if _, err := time.LoadLocation(sc.TimeZone); err != nil {
+		return fmt.Errorf("unknown time_zone %q", sc.TimeZone)
+	}
+	if sc.LeadDays == 0 {
+		sc.LeadDays = DefaultLeadDays
+	}
+	if sc.LeadDays < 1 || sc.LeadDays > 366 {
+		return ErrScheduleLeadDays
+	}
+	for _, date := range sc.Exceptions {
+		if _, err := time.Parse(time.DateOnly, date); err != nil {
+			return fmt.Errorf("exception %q is not a date in 2006-01-02 format", date)
+		}
+	}
+	if sc.Exceptions == nil {
+		sc.Exceptions = ScheduleDates{}
+	}
+	return nil
+}
+
+// Occurrences lists the schedule's meeting times in [after, before), in UTC, leaving out its exceptions.
+// Start is read as a wall-clock time in the schedule's zone, whatever zone it was stored in.
+func (sc Schedule) Occurrences(after, before time.Time) ([]time.Time, error) {
+	rule, err := recurrence.Parse(sc.RRule)
+	if err != nil {
+		return nil, err
+	}
+	loc, err := time.LoadLocation(sc.TimeZone)
+	if err != nil {
+		return nil, err
+	}
+	skip := make(map[string]bool, len(sc.Exceptions))
+	for _, date := range sc.Exceptions {
+		skip[date] = true
+	}
+
+	start := wallClock(sc.StartDT, loc)
+	var times []time.Time
+	for _, t := range rule.Between(start, after, before) {
+		if skip[t.Format(time.DateOnly)] {
+			continue
+		}
+		times = append(times, t.UTC())
+	}
+	return times, nil
+}
+
+// wallClock moves t to loc without changing its date or time of day
+func wallClock(t time.Time, loc *time.Location) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), 0, loc)
+}
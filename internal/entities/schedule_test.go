@@ -0,0 +1,85 @@
+package entities
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"lowerthirdsapi/internal/recurrence"
+)
+
+func TestScheduleValidate(t *testing.T) {
+	denver, _ := time.LoadLocation("America/Denver")
+	schedule := Schedule{
+		TemplateID: uuid.New(),
+		Name:       "Sacrament Meeting",
+		RRule:      "FREQ=WEEKLY;BYDAY=SU",
+		TimeZone:   "America/Denver",
+		StartDT:    time.Date(2025, 1, 5, 9, 0, 0, 0, denver),
+	}
+	if err := schedule.Validate(); err != nil {
+		t.Fatalf("Validate failed: %v", err)
+	}
+	if schedule.LeadDays != DefaultLeadDays || schedule.Exceptions == nil {
+		t.Errorf("expected defaults to be filled in, got %+v", schedule)
+	}
+	if schedule.StartDT.Location() != time.UTC || schedule.StartDT.Hour() != 9 {
+		t.Errorf("expected start to keep its wall-clock time, got %v", schedule.StartDT)
+	}
+
+	for _, tc := range []struct {
+		change func(*Schedule)
+		err    error
+	}{
+		{func(sc *Schedule) { sc.Name = "" }, ErrScheduleName},
+		{func(sc *Schedule) { sc.TemplateID = uuid.Nil }, ErrScheduleTemplate},
+		{func(sc *Schedule) { sc.StartDT = time.Time{} }, ErrScheduleStart},
+		{func(sc *Schedule) { sc.RRule = "FREQ=SOMETIMES" }, recurrence.ErrInvalidRule},
+		{func(sc *Schedule) { sc.LeadDays = 400 }, ErrScheduleLeadDays},
+	} {
+		sc := schedule
+		tc.change(&sc)
+		if err := sc.Validate(); !errors.Is(err, tc.err) {
+			t.Errorf("expected %v, got %v", tc.err, err)
+		}
+	}
+
+	sc := schedule
+	sc.TimeZone = "Mars/Olympus_Mons"
+	if err := sc.Validate(); err == nil {
+		t.Error("expected an unknown time zone to be invalid")
+	}
+	sc = schedule
+	sc.Exceptions = ScheduleDates{"Christmas"}
+	if err := sc.Validate(); err == nil {
+		t.Error("expected a bad exception date to be invalid")
+	}
+}
+
+func TestScheduleOccurrences(t *testing.T) {
+	schedule := Schedule{
+		RRule:      "FREQ=WEEKLY;BYDAY=SU",
+		TimeZone:   "America/Denver",
+		StartDT:    time.Date(2025, 3, 2, 9, 0, 0, 0, time.UTC),
+		Exceptions: ScheduleDates{"2025-03-09"},
+	}
+
+	got, err := schedule.Occurrences(time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC), time.Date(2025, 3, 20, 0, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatalf("Occurrences failed: %v", err)
+	}
+	// 9:00 MST, then 9:00 MDT after the clocks change, skipping the exception
+	want := []time.Time{
+		time.Date(2025, 3, 2, 16, 0, 0, 0, time.UTC),
+		time.Date(2025, 3, 16, 15, 0, 0, 0, time.UTC),
+	}
+	if len(got) != len(want) {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+	for i := range want {
+		if !got[i].Equal(want[i]) {
+			t.Errorf("occurrence %d: expected %v, got %v", i, want[i], got[i])
+		}
+	}
+}
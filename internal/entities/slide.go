@@ -0,0 +1,86 @@
+package entities
+
+import (
+	"strings"
+)
+
+// Slide is one screen of lyrics, optionally paired line-by-line with a translation
+type Slide struct {
+	Number           int      `json:"number"`
+	VerseNumber      int      `json:"verse_number"`
+	Optional         bool     `json:"optional"`
+	Lines            []string `json:"lines"`
+	TranslationLines []string `json:"translation_lines,omitempty"`
+}
+
+type SlideOptions struct {
+	MaxLines        int  // split verses longer than this; zero keeps whole verses
+	IncludeOptional bool // include verses marked optional
+	ShowTranslation bool // pair each line with the translation's line
+}
+
+// Lines splits the verse text into its non-empty lines
+func (v HymnVerse) Lines() []string {
+	var lines []string
+	for _, line := range strings.Split(strings.ReplaceAll(v.VerseLines.String, "\r\n", "\n"), "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return lines
+}
+
+// Slides expands the hymn's verses into an ordered list of on-screen slides. Translated lines come from the verse
+// with the same number in h.Translation, so it needs its verses loaded when opts.ShowTranslation is set.
+func (h Hymn) Slides(opts SlideOptions) []Slide {
+	translated := make(map[int][]string)
+	if opts.ShowTranslation && h.Translation != nil {
+		for _, verse := range h.Translation.Verses {
+			translated[verse.VerseNumber] = verse.Lines()
+		}
+	}
+
+	slides := []Slide{}
+	for _, verse := range h.Verses {
+		if verse.Optional && !opts.IncludeOptional {
+			continue
+		}
+
+		lines := verse.Lines()
+		translation := translated[verse.VerseNumber]
+
+		size := opts.MaxLines
+		if size <= 0 || size > len(lines) {
+			size = len(lines)
+		}
+		for start := 0; start < len(lines); start += size {
+			end := min(start+size, len(lines))
+			slide := Slide{
+				Number:      len(slides) + 1,
+				VerseNumber: verse.VerseNumber,
+				Optional:    verse.Optional,
+				Lines:       lines[start:end],
+			}
+			if opts.ShowTranslation {
+				slide.TranslationLines = pairLines(translation, start, end, end == len(lines))
+			}
+			slides = append(slides, slide)
+		}
+	}
+	return slides
+}
+
+// pairLines returns the translated lines matching lines[start:end], padding with blanks when the translation is short.
+// The last slide of a verse also takes any extra translated lines.
+func pairLines(translation []string, start int, end int, last bool) []string {
+	if last && len(translation) > end {
+		end = len(translation)
+	}
+	paired := make([]string, end-start)
+	for i := range paired {
+		if start+i < len(translation) {
+			paired[i] = translation[start+i]
+		}
+	}
+	return paired
+}
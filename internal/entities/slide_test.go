@@ -0,0 +1,92 @@
+package entities
+
+import (
+	"reflect"
+	"testing"
+
+	"gopkg.in/guregu/null.v4"
+)
+
+func testHymn() Hymn {
+	return Hymn{
+		Name: "Redeemer of Israel",
+		Verses: []HymnVerse{
+			{VerseNumber: 1, VerseLines: null.StringFrom("one\ntwo\nthree\nfour")},
+			{VerseNumber: 2, VerseLines: null.StringFrom("five\r\nsix\n\n")},
+			{VerseNumber: 3, VerseLines: null.StringFrom("seven\neight"), Optional: true},
+		},
+		Translation: &Hymn{
+			Name: "Redentor de Israel",
+			Verses: []HymnVerse{
+				{VerseNumber: 1, VerseLines: null.StringFrom("uno\ndos\ntres")},
+				{VerseNumber: 2, VerseLines: null.StringFrom("cinco\nseis\nextra")},
+				{VerseNumber: 3, VerseLines: null.StringFrom("siete\nocho")},
+			},
+		},
+	}
+}
+
+func TestHymnSlides(t *testing.T) {
+	tests := []struct {
+		name string
+		opts SlideOptions
+		want []Slide
+	}{
+		{
+			name: "whole verses",
+			opts: SlideOptions{},
+			want: []Slide{
+				{Number: 1, VerseNumber: 1, Lines: []string{"one", "two", "three", "four"}},
+				{Number: 2, VerseNumber: 2, Lines: []string{"five", "six"}},
+			},
+		},
+		{
+			name: "include optional",
+			opts: SlideOptions{IncludeOptional: true},
+			want: []Slide{
+				{Number: 1, VerseNumber: 1, Lines: []string{"one", "two", "three", "four"}},
+				{Number: 2, VerseNumber: 2, Lines: []string{"five", "six"}},
+				{Number: 3, VerseNumber: 3, Optional: true, Lines: []string{"seven", "eight"}},
+			},
+		},
+		{
+			name: "split long verses",
+			opts: SlideOptions{MaxLines: 3},
+			want: []Slide{
+				{Number: 1, VerseNumber: 1, Lines: []string{"one", "two", "three"}},
+				{Number: 2, VerseNumber: 1, Lines: []string{"four"}},
+				{Number: 3, VerseNumber: 2, Lines: []string{"five", "six"}},
+			},
+		},
+		{
+			name: "paired with translation",
+			opts: SlideOptions{MaxLines: 2, ShowTranslation: true},
+			want: []Slide{
+				{Number: 1, VerseNumber: 1, Lines: []string{"one", "two"}, TranslationLines: []string{"uno", "dos"}},
+				{Number: 2, VerseNumber: 1, Lines: []string{"three", "four"}, TranslationLines: []string{"tres", ""}},
+				{Number: 3, VerseNumber: 2, Lines: []string{"five", "six"}, TranslationLines: []string{"cinco", "seis", "extra"}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := testHymn().Slides(tt.opts)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Slides() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHymnSlides_NoTranslation(t *testing.T) {
+	hymn := testHymn()
+	hymn.Translation = nil
+	slides := hymn.Slides(SlideOptions{ShowTranslation: true})
+	if len(slides) != 2 {
+		t.Fatalf("expected 2 slides, got %d", len(slides))
+	}
+	if !reflect.DeepEqual(slides[1].TranslationLines, []string{"", ""}) {
+		t.Errorf("expected blank translation lines, got %q", slides[1].TranslationLines)
+	}
+}
@@ -0,0 +1,224 @@
+package entities
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"image/color"
+	"image/png"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// DefaultThemeName is the theme used when none is chosen
+const DefaultThemeName = "classic"
+
+// maxLogoDataURL is the longest logo that can be stored in a theme as a data URL
+const maxLogoDataURL = 512 << 10
+
+// Fonts a theme can use. They're embedded in the renderers, so stills look the same on every machine.
+var ThemeFonts = []string{"go", "go-medium", "go-mono", "go-smallcaps"}
+
+// Where a theme's logo is drawn: in a top corner of the safe area, or at the right end of the lower third
+var LogoPositions = []string{"none", "top-left", "top-right", "band-right"}
+
+// Theme is the look of a rendered lower third. Colors are hex, as #RRGGBB or #RRGGBBAA, and sizes are pixels on a
+// 1920x1080 frame. The margins are the safe area the graphic stays inside.
+type Theme struct {
+	Name            string `json:"name"`
+	Font            string `json:"font"`
+	BackgroundColor string `json:"background_color"`
+	AccentColor     string `json:"accent_color"`
+	PrimaryColor    string `json:"primary_color"`
+	SecondaryColor  string `json:"secondary_color"`
+	PrimarySize     int    `json:"primary_size"`
+	SecondarySize   int    `json:"secondary_size"`
+	MarginX         int    `json:"margin_x"`
+	MarginTop       int    `json:"margin_top"`
+	MarginBottom    int    `json:"margin_bottom"`
+	Padding         int    `json:"padding"`
+	AccentWidth     int    `json:"accent_width"`
+	LogoURL         string `json:"logo_url"`
+	LogoPosition    string `json:"logo_position"`
+	LogoHeight      int    `json:"logo_height"`
+}
+
+// ThemePresets are the built-in themes by name
+var ThemePresets = map[string]Theme{
+	"classic": {
+		Name:            "classic",
+		Font:            "go",
+		BackgroundColor: "#000000A6",
+		AccentColor:     "#C9A227",
+		PrimaryColor:    "#FFFFFF",
+		SecondaryColor:  "#FFFFFFD9",
+		PrimarySize:     56,
+		SecondarySize:   38,
+		MarginX:         96,
+		MarginTop:       54,
+		MarginBottom:    86,
+		Padding:         28,
+		AccentWidth:     12,
+		LogoPosition:    "none",
+		LogoHeight:      96,
+	},
+	"light": {
+		Name:            "light",
+		Font:            "go",
+		BackgroundColor: "#FFFFFFE6",
+		AccentColor:     "#1F4E79",
+		PrimaryColor:    "#1A1A1A",
+		SecondaryColor:  "#404040",
+		PrimarySize:     56,
+		SecondarySize:   38,
+		MarginX:         96,
+		MarginTop:       54,
+		MarginBottom:    86,
+		Padding:         28,
+		AccentWidth:     12,
+		LogoPosition:    "none",
+		LogoHeight:      96,
+	},
+	"minimal": {
+		Name:            "minimal",
+		Font:            "go-medium",
+		BackgroundColor: "#00000000",
+		AccentColor:     "#00000000",
+		PrimaryColor:    "#FFFFFF",
+		SecondaryColor:  "#FFFFFFCC",
+		PrimarySize:     60,
+		SecondarySize:   40,
+		MarginX:         96,
+		MarginTop:       54,
+		MarginBottom:    72,
+		Padding:         0,
+		AccentWidth:     0,
+		LogoPosition:    "none",
+		LogoHeight:      96,
+	},
+}
+
+// ThemeByName finds a preset theme, using the default theme when name is empty
+func ThemeByName(name string) (Theme, bool) {
+	if name == "" {
+		name = DefaultThemeName
+	}
+	theme, ok := ThemePresets[name]
+	return theme, ok
+}
+
+// ParseTheme builds a theme from JSON. Fields left out are taken from the preset named by name, or from the
+// default preset when name isn't a preset.
+func ParseTheme(data []byte) (Theme, error) {
+	var named struct {
+		Name string `json:"name"`
+	}
+	if err := json.Unmarshal(data, &named); err != nil {
+		return Theme{}, err
+	}
+	base, ok := ThemeByName(named.Name)
+	if !ok {
+		base, _ = ThemeByName("")
+	}
+	return base.Merge(data)
+}
+
+// Merge returns the theme with the fields set in the JSON override replaced. Fields that aren't part of a theme
+// are an error.
+func (t Theme) Merge(override []byte) (Theme, error) {
+	if !json.Valid(override) {
+		return Theme{}, errors.New("theme isn't valid JSON")
+	}
+	dec := json.NewDecoder(bytes.NewReader(override))
+	dec.DisallowUnknownFields()
+	merged := t
+	if err := dec.Decode(&merged); err != nil {
+		return Theme{}, err
+	}
+	return merged, nil
+}
+
+// Validate checks every field of the theme, returning the first problem found
+func (t Theme) Validate() error {
+	if !slices.Contains(ThemeFonts, t.Font) {
+		return fmt.Errorf("font must be one of %s", strings.Join(ThemeFonts, ", "))
+	}
+	for _, c := range []struct {
+		name  string
+		value string
+	}{
+		{"background_color", t.BackgroundColor},
+		{"accent_color", t.AccentColor},
+		{"primary_color", t.PrimaryColor},
+		{"secondary_color", t.SecondaryColor},
+	} {
+		if _, err := ParseColor(c.value); err != nil {
+			return fmt.Errorf("%s: %w", c.name, err)
+		}
+	}
+	for _, r := range []struct {
+		name     string
+		value    int
+		min, max int
+	}{
+		{"primary_size", t.PrimarySize, 12, 200},
+		{"secondary_size", t.SecondarySize, 12, 200},
+		{"margin_x", t.MarginX, 0, 480},
+		{"margin_top", t.MarginTop, 0, 270},
+		{"margin_bottom", t.MarginBottom, 0, 540},
+		{"padding", t.Padding, 0, 120},
+		{"accent_width", t.AccentWidth, 0, 60},
+		{"logo_height", t.LogoHeight, 0, 400},
+	} {
+		if r.value < r.min || r.value > r.max {
+			return fmt.Errorf("%s must be between %d and %d", r.name, r.min, r.max)
+		}
+	}
+	if !slices.Contains(LogoPositions, t.LogoPosition) {
+		return fmt.Errorf("logo_position must be one of %s", strings.Join(LogoPositions, ", "))
+	}
+	if t.LogoPosition != "none" {
+		if t.LogoURL == "" {
+			return fmt.Errorf("logo_url is required to show a logo")
+		}
+		if t.LogoHeight == 0 {
+			return fmt.Errorf("logo_height is required to show a logo")
+		}
+	}
+	if data, ok := strings.CutPrefix(t.LogoURL, "data:image/png;base64,"); ok {
+		if len(t.LogoURL) > maxLogoDataURL {
+			return fmt.Errorf("logo_url data URLs can be at most %d bytes", maxLogoDataURL)
+		}
+		_, err := png.DecodeConfig(base64.NewDecoder(base64.StdEncoding, strings.NewReader(data)))
+		if err != nil {
+			return fmt.Errorf("logo_url isn't a valid PNG: %w", err)
+		}
+	} else if t.LogoURL != "" && !strings.HasPrefix(t.LogoURL, "https://") {
+		return fmt.Errorf("logo_url must be an https URL or a base64 PNG data URL")
+	}
+	return nil
+}
+
+// ShowsLogo reports whether the theme draws a logo
+func (t Theme) ShowsLogo() bool {
+	return t.LogoPosition != "" && t.LogoPosition != "none" && t.LogoURL != ""
+}
+
+// ParseColor parses a #RRGGBB or #RRGGBBAA hex color
+func ParseColor(s string) (color.NRGBA, error) {
+	hex, ok := strings.CutPrefix(s, "#")
+	if len(hex) == 6 {
+		hex += "FF"
+	}
+	if !ok || len(hex) != 8 {
+		return color.NRGBA{}, fmt.Errorf("invalid color %q, expected #RRGGBB or #RRGGBBAA", s)
+	}
+	v, err := strconv.ParseUint(hex, 16, 32)
+	if err != nil {
+		return color.NRGBA{}, fmt.Errorf("invalid color %q, expected #RRGGBB or #RRGGBBAA", s)
+	}
+	return color.NRGBA{R: uint8(v >> 24), G: uint8(v >> 16), B: uint8(v >> 8), A: uint8(v)}, nil
+}
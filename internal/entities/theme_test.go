@@ -0,0 +1,94 @@
+package entities
+
+import (
+	"bytes"
+	"encoding/base64"
+	"image"
+	"image/color"
+	"image/png"
+	"strings"
+	"testing"
+)
+
+func TestParseColor(t *testing.T) {
+	c, err := ParseColor("#C9A227")
+	if err != nil || c != (color.NRGBA{R: 0xC9, G: 0xA2, B: 0x27, A: 0xFF}) {
+		t.Errorf("unexpected color %v, %v", c, err)
+	}
+	c, err = ParseColor("#00000080")
+	if err != nil || c.A != 0x80 {
+		t.Errorf("unexpected color %v, %v", c, err)
+	}
+	for _, bad := range []string{"", "C9A227", "#C9A", "#GGGGGG"} {
+		if _, err := ParseColor(bad); err == nil {
+			t.Errorf("expected an error for %q", bad)
+		}
+	}
+}
+
+func TestParseThemeAndMerge(t *testing.T) {
+	for name, preset := range ThemePresets {
+		if err := preset.Validate(); err != nil {
+			t.Errorf("preset %s is invalid: %v", name, err)
+		}
+	}
+
+	// Fields left out come from the named preset
+	theme, err := ParseTheme([]byte(`{"name": "light", "accent_color": "#8B0000", "font": "go-mono"}`))
+	if err != nil {
+		t.Fatalf("ParseTheme failed: %v", err)
+	}
+	light := ThemePresets["light"]
+	if theme.AccentColor != "#8B0000" || theme.Font != "go-mono" || theme.BackgroundColor != light.BackgroundColor {
+		t.Errorf("unexpected theme %+v", theme)
+	}
+
+	// A name that isn't a preset starts from the default theme
+	theme, err = ParseTheme([]byte(`{"name": "Ward 3"}`))
+	if err != nil || theme.Name != "Ward 3" || theme.PrimaryColor != ThemePresets[DefaultThemeName].PrimaryColor {
+		t.Errorf("unexpected theme %+v, %v", theme, err)
+	}
+
+	merged, err := theme.Merge([]byte(`{"margin_bottom": 120}`))
+	if err != nil || merged.MarginBottom != 120 || merged.Name != "Ward 3" {
+		t.Errorf("unexpected merged theme %+v, %v", merged, err)
+	}
+	if _, err := theme.Merge([]byte(`{"colour": "#FFFFFF"}`)); err == nil {
+		t.Error("expected an error for an unknown field")
+	}
+	if _, err := theme.Merge([]byte(`{"padding": "wide"}`)); err == nil {
+		t.Error("expected an error for a field of the wrong type")
+	}
+}
+
+func TestThemeValidate(t *testing.T) {
+	var buf bytes.Buffer
+	_ = png.Encode(&buf, image.NewNRGBA(image.Rect(0, 0, 4, 2)))
+	logo := "data:image/png;base64," + base64.StdEncoding.EncodeToString(buf.Bytes())
+
+	valid := ThemePresets[DefaultThemeName]
+	valid.LogoPosition = "top-right"
+	valid.LogoURL = logo
+	if err := valid.Validate(); err != nil {
+		t.Fatalf("expected a valid theme, got %v", err)
+	}
+
+	for field, change := range map[string]func(*Theme){
+		"font":             func(t *Theme) { t.Font = "comic-sans" },
+		"primary_color":    func(t *Theme) { t.PrimaryColor = "white" },
+		"primary_size":     func(t *Theme) { t.PrimarySize = 4 },
+		"margin_bottom":    func(t *Theme) { t.MarginBottom = -1 },
+		"logo_position":    func(t *Theme) { t.LogoPosition = "center" },
+		"logo_url is":      func(t *Theme) { t.LogoURL = "" },
+		"logo_height":      func(t *Theme) { t.LogoHeight = 0 },
+		"logo_url must":    func(t *Theme) { t.LogoURL = "http://example.com/logo.png" },
+		"logo_url isn't a": func(t *Theme) { t.LogoURL = "data:image/png;base64,bm90IGEgcG5n" },
+	} {
+		theme := valid
+		change(&theme)
+		err := theme.Validate()
+		if err == nil || !strings.HasPrefix(err.Error(), field) {
+			t.Errorf("expected a %s error, got %v", field, err)
+		}
+	}
+}
@@ -0,0 +1,176 @@
+package entities
+
+import (
+	"errors"
+	"github.com/google/uuid"
+	"gopkg.in/guregu/null.v4"
+	"time"
+)
+
+var (
+	ErrUnknownTimerAction = errors.New("unknown timer action")
+	ErrTimerRunning       = errors.New("the timer is already running")
+	ErrTimerNotRunning    = errors.New("the timer isn't running")
+	ErrTimerFixed         = errors.New("the timer counts down to a fixed time and can't be started or paused")
+)
+
+// TimerKind is how a timer counts
+type TimerKind string
+
+const (
+	TimerKindCountdown TimerKind = "countdown" // counts down from a duration while running
+	TimerKindStopwatch TimerKind = "stopwatch" // counts up while running
+	TimerKindUntil     TimerKind = "until"     // counts down to a time of day, and is always running
+)
+
+// TimerAction is an operator command for a timer
+type TimerAction string
+
+const (
+	TimerActionStart   TimerAction = "start"    // run the timer from the beginning
+	TimerActionPause   TimerAction = "pause"    // stop the timer, keeping its time
+	TimerActionResume  TimerAction = "resume"   // run a paused timer from where it stopped
+	TimerActionReset   TimerAction = "reset"    // stop the timer and re-arm it from its item
+	TimerActionAddTime TimerAction = "add_time" // give a timer more time, or less with a negative amount
+)
+
+// Timer is the timer of an agenda item. It's stored as wall-clock anchors rather than a running count, so every
+// client works out the same time: ElapsedMS is how long it ran before StartedDT, and it's running from StartedDT.
+// AddedMS is the time added to it since it was armed.
+type Timer struct {
+	ItemID     uuid.UUID     `db:"item_id" json:"item_id"`
+	MeetingID  uuid.UUID     `db:"meeting_id" json:"meeting_id"`
+	Version    int64         `db:"version" json:"version"`
+	Kind       TimerKind     `db:"kind" json:"kind"`
+	DurationMS int64         `db:"duration_ms" json:"duration_ms"`
+	TargetDT   null.Time     `db:"target_dt" json:"target_dt"`
+	AddedMS    int64         `db:"added_ms" json:"added_ms"`
+	ElapsedMS  int64         `db:"elapsed_ms" json:"elapsed_ms"`
+	StartedDT  null.Time     `db:"started_dt" json:"started_dt"`
+	Reading    *TimerReading `db:"-" json:"reading,omitempty"`
+	InsertedDT time.Time     `db:"inserted_dt" json:"inserted_dt"`
+	UpdatedDT  time.Time     `db:"updated_dt" json:"updated_dt"`
+}
+
+// TimerReading is a timer's value at ServerDT. Clients can compare ServerDT with their own clock to correct for skew.
+type TimerReading struct {
+	ServerDT    time.Time `json:"server_dt"`
+	Running     bool      `json:"running"`
+	ElapsedMS   int64     `json:"elapsed_ms"`
+	RemainingMS int64     `json:"remaining_ms"` // negative once a countdown runs over; zero for stopwatches
+	EndsDT      null.Time `json:"ends_dt"`      // when a running countdown reaches zero
+}
+
+// ArmTimer sets up a stopped timer for an item. Speakers count down from their expected duration and timer items
+// count down to the start of the meeting. Everything else gets a stopwatch.
+func ArmTimer(item Item, meeting Meeting) Timer {
+	timer := Timer{ItemID: item.GetID(), MeetingID: item.GetMeetingID(), Kind: TimerKindStopwatch}
+	switch item := item.(type) {
+	case *SpeakerItem:
+		if item.ExpectedDuration.Valid && item.ExpectedDuration.Int64 > 0 {
+			timer.Kind = TimerKindCountdown
+			timer.DurationMS = (time.Duration(item.ExpectedDuration.Int64) * time.Minute).Milliseconds()
+		}
+	case *TimerItem:
+		timer.Kind = TimerKindUntil
+		timer.TargetDT = null.TimeFrom(meeting.MeetingDate)
+	}
+	return timer
+}
+
+// Apply changes the timer for an action. amount is only used by add_time. armed is a freshly armed timer for the
+// item, which reset takes its kind and duration from so changes to the item show up.
+func (t *Timer) Apply(action TimerAction, amount time.Duration, armed Timer, now time.Time) error {
+	err := t.apply(action, amount, armed, now)
+	if err != nil {
+		return err
+	}
+	t.Version++
+	return nil
+}
+
+func (t *Timer) apply(action TimerAction, amount time.Duration, armed Timer, now time.Time) error {
+	switch action {
+	case TimerActionStart, TimerActionPause, TimerActionResume:
+		if t.Kind == TimerKindUntil {
+			return ErrTimerFixed
+		}
+	}
+
+	switch action {
+	case TimerActionStart:
+		t.ElapsedMS = 0
+		t.StartedDT = null.TimeFrom(now)
+	case TimerActionPause:
+		if !t.StartedDT.Valid {
+			return ErrTimerNotRunning
+		}
+		t.ElapsedMS = t.Elapsed(now).Milliseconds()
+		t.StartedDT = null.Time{}
+	case TimerActionResume:
+		if t.StartedDT.Valid {
+			return ErrTimerRunning
+		}
+		t.StartedDT = null.TimeFrom(now)
+	case TimerActionReset:
+		t.Kind = armed.Kind
+		t.DurationMS = armed.DurationMS
+		t.TargetDT = armed.TargetDT
+		t.AddedMS = 0
+		t.ElapsedMS = 0
+		t.StartedDT = null.Time{}
+	case TimerActionAddTime:
+		t.AddedMS += amount.Milliseconds()
+	default:
+		return ErrUnknownTimerAction
+	}
+	return nil
+}
+
+// Running reports whether the timer is counting. Timers counting down to a time of day always are.
+func (t Timer) Running() bool {
+	return t.Kind == TimerKindUntil || t.StartedDT.Valid
+}
+
+// Elapsed is how long the timer has run, including any time before it was last paused. Time added to a stopwatch
+// counts as elapsed.
+func (t Timer) Elapsed(now time.Time) time.Duration {
+	elapsed := time.Duration(t.ElapsedMS) * time.Millisecond
+	if t.StartedDT.Valid {
+		elapsed += now.Sub(t.StartedDT.Time)
+	}
+	if t.Kind == TimerKindStopwatch {
+		elapsed += time.Duration(t.AddedMS) * time.Millisecond
+	}
+	return elapsed
+}
+
+// Remaining is how long a countdown has left, which goes negative once it runs over. Stopwatches have no end.
+func (t Timer) Remaining(now time.Time) time.Duration {
+	added := time.Duration(t.AddedMS) * time.Millisecond
+	switch t.Kind {
+	case TimerKindCountdown:
+		return time.Duration(t.DurationMS)*time.Millisecond + added - t.Elapsed(now)
+	case TimerKindUntil:
+		return t.TargetDT.Time.Add(added).Sub(now)
+	default:
+		return 0
+	}
+}
+
+// Read fills in the timer's reading at now
+func (t *Timer) Read(now time.Time) {
+	reading := &TimerReading{
+		ServerDT:    now,
+		Running:     t.Running(),
+		ElapsedMS:   t.Elapsed(now).Milliseconds(),
+		RemainingMS: t.Remaining(now).Milliseconds(),
+	}
+	if t.Kind == TimerKindUntil {
+		reading.ElapsedMS = 0
+	}
+	if t.Kind != TimerKindStopwatch && reading.Running {
+		reading.EndsDT = null.TimeFrom(now.Add(t.Remaining(now)))
+	}
+	t.Reading = reading
+}
@@ -0,0 +1,111 @@
+package entities
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"gopkg.in/guregu/null.v4"
+)
+
+func TestArmTimer(t *testing.T) {
+	meeting := Meeting{MeetingID: uuid.New(), MeetingDate: time.Date(2024, 1, 7, 10, 0, 0, 0, time.UTC)}
+
+	speaker := &SpeakerItem{SpeakerItemID: uuid.New(), MeetingID: meeting.MeetingID, ExpectedDuration: null.IntFrom(5)}
+	timer := ArmTimer(speaker, meeting)
+	if timer.Kind != TimerKindCountdown || timer.DurationMS != 300000 || timer.ItemID != speaker.SpeakerItemID {
+		t.Errorf("expected a 5 minute countdown, got %+v", timer)
+	}
+
+	timer = ArmTimer(&TimerItem{TimerItemID: uuid.New(), MeetingID: meeting.MeetingID}, meeting)
+	if timer.Kind != TimerKindUntil || !timer.TargetDT.Time.Equal(meeting.MeetingDate) {
+		t.Errorf("expected a countdown to the meeting, got %+v", timer)
+	}
+
+	timer = ArmTimer(&SpeakerItem{SpeakerItemID: uuid.New(), MeetingID: meeting.MeetingID}, meeting)
+	if timer.Kind != TimerKindStopwatch {
+		t.Errorf("expected a stopwatch for a speaker without a duration, got %+v", timer)
+	}
+}
+
+func TestTimerApply(t *testing.T) {
+	start := time.Date(2024, 1, 7, 10, 0, 0, 0, time.UTC)
+	armed := Timer{Kind: TimerKindCountdown, DurationMS: 60000}
+	timer := armed
+
+	if err := timer.Apply(TimerActionPause, 0, armed, start); !errors.Is(err, ErrTimerNotRunning) {
+		t.Errorf("expected ErrTimerNotRunning, got %v", err)
+	}
+	if err := timer.Apply(TimerActionStart, 0, armed, start); err != nil {
+		t.Fatalf("start failed: %v", err)
+	}
+	if err := timer.Apply(TimerActionResume, 0, armed, start); !errors.Is(err, ErrTimerRunning) {
+		t.Errorf("expected ErrTimerRunning, got %v", err)
+	}
+
+	// 20 seconds in, pause, then resume 5 minutes later
+	if err := timer.Apply(TimerActionPause, 0, armed, start.Add(20*time.Second)); err != nil {
+		t.Fatalf("pause failed: %v", err)
+	}
+	later := start.Add(5 * time.Minute)
+	if got := timer.Remaining(later); got != 40*time.Second {
+		t.Errorf("expected 40s left while paused, got %v", got)
+	}
+	if err := timer.Apply(TimerActionResume, 0, armed, later); err != nil {
+		t.Fatalf("resume failed: %v", err)
+	}
+	if err := timer.Apply(TimerActionAddTime, 30*time.Second, armed, later); err != nil {
+		t.Fatalf("add_time failed: %v", err)
+	}
+	timer.Read(later.Add(10 * time.Second))
+	if !timer.Reading.Running || timer.Reading.ElapsedMS != 30000 || timer.Reading.RemainingMS != 60000 {
+		t.Errorf("unexpected reading: %+v", timer.Reading)
+	}
+	if !timer.Reading.EndsDT.Time.Equal(later.Add(70 * time.Second)) {
+		t.Errorf("expected the countdown to end at %v, got %v", later.Add(70*time.Second), timer.Reading.EndsDT)
+	}
+
+	// Countdowns run over rather than stopping at zero
+	if got := timer.Remaining(later.Add(2 * time.Minute)); got != -50*time.Second {
+		t.Errorf("expected to be 50s over, got %v", got)
+	}
+
+	// Reset takes the armed timer, so a changed duration shows up
+	armed.DurationMS = 120000
+	if err := timer.Apply(TimerActionReset, 0, armed, later); err != nil {
+		t.Fatalf("reset failed: %v", err)
+	}
+	if timer.Running() || timer.AddedMS != 0 || timer.Remaining(later) != 2*time.Minute {
+		t.Errorf("unexpected timer after reset: %+v", timer)
+	}
+	if timer.Version != 5 {
+		t.Errorf("expected version 5 after five successful actions, got %d", timer.Version)
+	}
+}
+
+func TestTimerUntil(t *testing.T) {
+	target := time.Date(2024, 1, 7, 10, 0, 0, 0, time.UTC)
+	timer := Timer{Kind: TimerKindUntil, TargetDT: null.TimeFrom(target)}
+
+	for _, action := range []TimerAction{TimerActionStart, TimerActionPause, TimerActionResume} {
+		if err := timer.Apply(action, 0, timer, target); !errors.Is(err, ErrTimerFixed) {
+			t.Errorf("expected ErrTimerFixed for %s, got %v", action, err)
+		}
+	}
+	if err := timer.Apply("rewind", 0, timer, target); !errors.Is(err, ErrUnknownTimerAction) {
+		t.Errorf("expected ErrUnknownTimerAction, got %v", err)
+	}
+	if timer.Version != 0 {
+		t.Errorf("expected failed actions to leave the timer alone, got %+v", timer)
+	}
+
+	// Adding time moves the target
+	if err := timer.Apply(TimerActionAddTime, time.Minute, timer, target); err != nil {
+		t.Fatalf("add_time failed: %v", err)
+	}
+	timer.Read(target.Add(-time.Minute))
+	if !timer.Reading.Running || timer.Reading.RemainingMS != 120000 || timer.Reading.ElapsedMS != 0 {
+		t.Errorf("unexpected reading: %+v", timer.Reading)
+	}
+}
@@ -0,0 +1,197 @@
+package events
+
+import (
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Event types published for a meeting
+const (
+	ItemCreated    = "item.created"
+	ItemUpdated    = "item.updated"
+	ItemDeleted    = "item.deleted"
+	ItemsReordered = "items.reordered"
+	LiveChanged    = "live.changed"
+	ThemeChanged   = "theme.changed"
+	TimerChanged   = "timer.changed"
+)
+
+const (
+	defaultHistorySize    = 100
+	defaultSubscriberSize = 32
+	// defaultHistoryTTL is how long a meeting's history is kept after its last event once nobody is subscribed
+	defaultHistoryTTL = 10 * time.Minute
+)
+
+type Config struct {
+	HistorySize int `envconfig:"EVENTS_HISTORY_SIZE" default:"100"`
+}
+
+// Event is a change to a meeting, delivered to every subscriber of that meeting
+type Event struct {
+	ID        uint64          `json:"id"`
+	MeetingID uuid.UUID       `json:"meeting_id"`
+	Type      string          `json:"type"`
+	Data      json.RawMessage `json:"data"`
+}
+
+// Broker fans out meeting events to in-process subscribers. It keeps a short history per meeting so reconnecting
+// subscribers can resume from the last event they saw. A meeting nobody is subscribed to is forgotten once its
+// history is older than historyTTL.
+type Broker struct {
+	mu             sync.Mutex
+	lastID         uint64
+	historySize    int
+	subscriberSize int
+	historyTTL     time.Duration
+	lastSweep      time.Time
+	now            func() time.Time
+	meetings       map[uuid.UUID]*meetingTopic
+}
+
+type meetingTopic struct {
+	history     []Event // ring buffer of the latest events, oldest at start
+	start       int
+	updated     time.Time // when the topic was made or last published to
+	subscribers map[*Subscription]struct{}
+}
+
+// Subscription receives a meeting's events. Replay holds the missed events when resuming from an event ID.
+// C is closed when the subscription is closed, or when the subscriber falls too far behind; it should then
+// reconnect from the last event it received.
+type Subscription struct {
+	Replay []Event
+	C      <-chan Event
+
+	ch        chan Event
+	meetingID uuid.UUID
+	broker    *Broker
+	closeOnce sync.Once
+}
+
+// NewBroker creates a broker that keeps historySize events per meeting for resuming subscribers
+func NewBroker(historySize int) *Broker {
+	if historySize <= 0 {
+		historySize = defaultHistorySize
+	}
+	now := time.Now()
+	return &Broker{
+		// Start IDs from the clock so they keep increasing across restarts
+		lastID:         uint64(now.UnixMicro()),
+		historySize:    historySize,
+		subscriberSize: defaultSubscriberSize,
+		historyTTL:     defaultHistoryTTL,
+		lastSweep:      now,
+		now:            time.Now,
+		meetings:       make(map[uuid.UUID]*meetingTopic),
+	}
+}
+
+// Publish sends an event with the JSON-encoded data to every subscriber of the meeting
+func (b *Broker) Publish(meetingID uuid.UUID, eventType string, data interface{}) (Event, error) {
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return Event{}, err
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.sweep()
+
+	b.lastID++
+	event := Event{ID: b.lastID, MeetingID: meetingID, Type: eventType, Data: raw}
+
+	topic := b.topic(meetingID)
+	topic.updated = b.now()
+	if len(topic.history) < b.historySize {
+		topic.history = append(topic.history, event)
+	} else {
+		topic.history[topic.start] = event
+		topic.start = (topic.start + 1) % b.historySize
+	}
+
+	for sub := range topic.subscribers {
+		select {
+		case sub.ch <- event:
+		default:
+			// Too slow to keep up; it can resume from its last event
+			b.remove(sub)
+		}
+	}
+	return event, nil
+}
+
+// Subscribe starts receiving a meeting's events. If lastEventID is set, events after it that are still in the
+// history are returned in Replay.
+func (b *Broker) Subscribe(meetingID uuid.UUID, lastEventID uint64) *Subscription {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.sweep()
+
+	ch := make(chan Event, b.subscriberSize)
+	sub := &Subscription{C: ch, ch: ch, meetingID: meetingID, broker: b}
+
+	topic := b.topic(meetingID)
+	if lastEventID > 0 {
+		for i := range topic.history {
+			event := topic.history[(topic.start+i)%len(topic.history)]
+			if event.ID > lastEventID {
+				sub.Replay = append(sub.Replay, event)
+			}
+		}
+	}
+	topic.subscribers[sub] = struct{}{}
+	return sub
+}
+
+// Close stops the subscription and closes its channel
+func (sub *Subscription) Close() {
+	sub.broker.mu.Lock()
+	defer sub.broker.mu.Unlock()
+	sub.broker.remove(sub)
+}
+
+// topic returns the meeting's topic, creating it if needed. The caller must hold b.mu.
+func (b *Broker) topic(meetingID uuid.UUID) *meetingTopic {
+	topic, ok := b.meetings[meetingID]
+	if !ok {
+		topic = &meetingTopic{updated: b.now(), subscribers: make(map[*Subscription]struct{})}
+		b.meetings[meetingID] = topic
+	}
+	return topic
+}
+
+// remove drops a subscriber and closes its channel, and the meeting's topic if it was the last subscriber and the
+// history is stale. The caller must hold b.mu.
+func (b *Broker) remove(sub *Subscription) {
+	if topic, ok := b.meetings[sub.meetingID]; ok {
+		delete(topic.subscribers, sub)
+		if b.stale(topic, b.now()) {
+			delete(b.meetings, sub.meetingID)
+		}
+	}
+	sub.closeOnce.Do(func() { close(sub.ch) })
+}
+
+// sweep drops the stale topics of meetings nobody is subscribed to, at most once every historyTTL. The caller must
+// hold b.mu.
+func (b *Broker) sweep() {
+	now := b.now()
+	if now.Sub(b.lastSweep) < b.historyTTL {
+		return
+	}
+	b.lastSweep = now
+	for meetingID, topic := range b.meetings {
+		if b.stale(topic, now) {
+			delete(b.meetings, meetingID)
+		}
+	}
+}
+
+// stale reports whether nobody is subscribed to the topic and its history is too old to resume from
+func (b *Broker) stale(topic *meetingTopic, now time.Time) bool {
+	return len(topic.subscribers) == 0 && now.Sub(topic.updated) >= b.historyTTL
+}
@@ -0,0 +1,155 @@
+package events
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestBrokerPublishSubscribe(t *testing.T) {
+	broker := NewBroker(10)
+	meetingID := uuid.New()
+
+	sub := broker.Subscribe(meetingID, 0)
+	defer sub.Close()
+	other := broker.Subscribe(uuid.New(), 0)
+	defer other.Close()
+
+	published, err := broker.Publish(meetingID, ItemCreated, map[string]string{"id": "1"})
+	if err != nil {
+		t.Fatalf("Publish failed: %v", err)
+	}
+
+	event := <-sub.C
+	if event.ID != published.ID || event.Type != ItemCreated || string(event.Data) != `{"id":"1"}` {
+		t.Errorf("unexpected event: %+v", event)
+	}
+	select {
+	case event := <-other.C:
+		t.Errorf("unexpected event for another meeting: %+v", event)
+	default:
+	}
+}
+
+func TestBrokerReplay(t *testing.T) {
+	broker := NewBroker(3)
+	meetingID := uuid.New()
+
+	var ids []uint64
+	for i := 0; i < 5; i++ {
+		event, err := broker.Publish(meetingID, ItemUpdated, i)
+		if err != nil {
+			t.Fatalf("Publish failed: %v", err)
+		}
+		ids = append(ids, event.ID)
+	}
+
+	// Resume after the second event; only the last three are kept
+	sub := broker.Subscribe(meetingID, ids[1])
+	defer sub.Close()
+	if len(sub.Replay) != 3 || sub.Replay[0].ID != ids[2] || sub.Replay[2].ID != ids[4] {
+		t.Errorf("unexpected replay: %+v", sub.Replay)
+	}
+
+	// Up to date subscribers get nothing to replay
+	current := broker.Subscribe(meetingID, ids[4])
+	defer current.Close()
+	if len(current.Replay) != 0 {
+		t.Errorf("expected no replay, got %+v", current.Replay)
+	}
+
+	// IDs from before a restart replay everything that's kept
+	restarted := NewBroker(3)
+	if _, err := restarted.Publish(meetingID, ItemUpdated, 0); err != nil {
+		t.Fatalf("Publish failed: %v", err)
+	}
+	resumed := restarted.Subscribe(meetingID, ids[4])
+	defer resumed.Close()
+	if len(resumed.Replay) != 1 {
+		t.Errorf("expected 1 replayed event after restart, got %d", len(resumed.Replay))
+	}
+}
+
+func TestBrokerSlowSubscriber(t *testing.T) {
+	broker := NewBroker(10)
+	meetingID := uuid.New()
+	sub := broker.Subscribe(meetingID, 0)
+
+	for i := 0; i <= defaultSubscriberSize; i++ {
+		if _, err := broker.Publish(meetingID, ItemUpdated, i); err != nil {
+			t.Fatalf("Publish failed: %v", err)
+		}
+	}
+
+	// The buffered events drain, then the channel is closed
+	count := 0
+	for range sub.C {
+		count++
+	}
+	if count != defaultSubscriberSize {
+		t.Errorf("expected %d buffered events, got %d", defaultSubscriberSize, count)
+	}
+
+	// Closing again is safe
+	sub.Close()
+}
+
+func TestBrokerForgetsStaleMeetings(t *testing.T) {
+	broker := NewBroker(10)
+	now := time.Now()
+	broker.now = func() time.Time { return now }
+	watched, idle := uuid.New(), uuid.New()
+
+	sub := broker.Subscribe(watched, 0)
+	for _, meetingID := range []uuid.UUID{watched, idle} {
+		if _, err := broker.Publish(meetingID, ItemUpdated, 0); err != nil {
+			t.Fatalf("Publish failed: %v", err)
+		}
+	}
+
+	// History is kept while it's fresh, and while someone is subscribed
+	now = now.Add(broker.historyTTL / 2)
+	resumed := broker.Subscribe(idle, 1)
+	resumed.Close()
+	if len(resumed.Replay) != 1 || len(broker.meetings) != 2 {
+		t.Errorf("expected both meetings kept, got %d with %d replayed", len(broker.meetings), len(resumed.Replay))
+	}
+	now = now.Add(broker.historyTTL)
+	if _, err := broker.Publish(uuid.New(), ItemUpdated, 0); err != nil {
+		t.Fatalf("Publish failed: %v", err)
+	}
+	if _, ok := broker.meetings[idle]; ok {
+		t.Error("expected the idle meeting forgotten")
+	}
+	if _, ok := broker.meetings[watched]; !ok {
+		t.Error("expected the watched meeting kept")
+	}
+
+	// The last subscriber leaving a stale meeting forgets it
+	sub.Close()
+	if _, ok := broker.meetings[watched]; ok {
+		t.Error("expected the watched meeting forgotten once unwatched")
+	}
+}
+
+func TestBrokerConcurrent(t *testing.T) {
+	broker := NewBroker(10)
+	meetingID := uuid.New()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			sub := broker.Subscribe(meetingID, 0)
+			sub.Close()
+		}()
+		go func() {
+			defer wg.Done()
+			_, _ = broker.Publish(meetingID, LiveChanged, nil)
+		}()
+	}
+	wg.Wait()
+}
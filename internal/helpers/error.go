@@ -7,7 +7,7 @@ import (
 )
 
 // WriteError pulls an error response from the context, merges it with the provided error (if applicable),
-// and writes the error to the http response writer
+// and writes the error to the http response writer with the request's ID
 func WriteError(ctx context.Context, err error, w http.ResponseWriter) {
 	errResp, ok := ctx.Value(ErrorsResponseKey).(*apierrors.Response)
 	if ok {
@@ -15,5 +15,8 @@ func WriteError(ctx context.Context, err error, w http.ResponseWriter) {
 	} else {
 		errResp = apierrors.NewResponse(err)
 	}
+	if requestID, ok := ctx.Value(RequestIDKey).(string); ok && errResp.RequestID == "" {
+		errResp.RequestID = requestID
+	}
 	_ = errResp.Write(w)
 }
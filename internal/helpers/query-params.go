@@ -3,17 +3,30 @@ package helpers
 import (
 	"context"
 	"github.com/google/uuid"
+	"math"
 	"time"
 )
 
+// MaxOffset is the most rows a page may skip, so Page*PageSize can't overflow on any platform or database
+const MaxOffset = math.MaxInt32
+
 type QueryParams struct {
-	Page     int
-	PageSize int
-	DateFrom *time.Time
-	DateTo   *time.Time
-	Language string
-	UserID   uuid.UUID
-	OrgID    uuid.UUID
+	Page            int
+	PageSize        int
+	DateFrom        *time.Time
+	DateTo          *time.Time
+	Language        string
+	Search          string
+	MaxLines        int
+	IncludeOptional bool
+	Theme           string
+	Slide           int
+	Format          string
+	UserID          uuid.UUID
+	OrgID           uuid.UUID
+	EntityType      string
+	EntityID        string
+	Action          string
 }
 
 func DefaultQueryParams() QueryParams {
@@ -21,7 +34,17 @@ func DefaultQueryParams() QueryParams {
 		Page:     0,
 		PageSize: 100,
 		Language: "eng",
+		MaxLines: 4,
+		Format:   "png",
+	}
+}
+
+// Offset is how many rows come before the page. It's false if the page starts past MaxOffset.
+func (qp QueryParams) Offset() (int, bool) {
+	if qp.Page < 0 || qp.PageSize < 0 || (qp.PageSize > 0 && qp.Page > MaxOffset/qp.PageSize) {
+		return 0, false
 	}
+	return qp.Page * qp.PageSize, true
 }
 
 func GetQueryParams(ctx context.Context) QueryParams {
@@ -3,6 +3,7 @@ package helpers
 // ContextKey defines a unique type for a request context key, to ensure type unique-ness
 type ContextKey string
 
+const APIKeyKey = ContextKey("apiKey")
 const AuthKey = ContextKey("authorization")
 const ErrorsResponseKey = ContextKey("errorResponse")
 const QueryParametersKey = ContextKey("queryParameters")
@@ -0,0 +1,76 @@
+// Package invitations signs the tokens in invitation links, so they can't be guessed or changed and stop working
+// when the invitation expires
+package invitations
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrNoSecret     = errors.New("INVITATION_SECRET isn't set")
+	ErrInvalidToken = errors.New("invalid invitation token")
+	ErrExpiredToken = errors.New("invitation token is expired")
+)
+
+type Config struct {
+	Secret string        `envconfig:"INVITATION_SECRET"`
+	TTL    time.Duration `envconfig:"INVITATION_TTL" default:"168h"`
+	URL    string        `envconfig:"INVITATION_URL" default:"http://localhost:5173/invitations/"`
+}
+
+// payloadSize is an invitation ID and its expiry in Unix seconds
+const payloadSize = 16 + 8
+
+// Signer makes and checks invitation tokens. A token is the invitation's ID and expiry followed by their
+// HMAC-SHA256, in unpadded URL-safe base64.
+type Signer struct {
+	secret []byte
+}
+
+func NewSigner(secret string) *Signer {
+	return &Signer{secret: []byte(secret)}
+}
+
+// Sign makes the token for an invitation
+func (s *Signer) Sign(invitationID uuid.UUID, expires time.Time) (string, error) {
+	if len(s.secret) == 0 {
+		return "", ErrNoSecret
+	}
+	payload := make([]byte, payloadSize, payloadSize+sha256.Size)
+	copy(payload, invitationID[:])
+	binary.BigEndian.PutUint64(payload[16:], uint64(expires.Unix()))
+	return base64.RawURLEncoding.EncodeToString(append(payload, s.mac(payload)...)), nil
+}
+
+// Verify checks a token's signature and expiry at now, and returns the invitation it's for
+func (s *Signer) Verify(token string, now time.Time) (uuid.UUID, error) {
+	if len(s.secret) == 0 {
+		return uuid.Nil, ErrNoSecret
+	}
+	raw, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil || len(raw) != payloadSize+sha256.Size {
+		return uuid.Nil, ErrInvalidToken
+	}
+	payload, mac := raw[:payloadSize], raw[payloadSize:]
+	if !hmac.Equal(mac, s.mac(payload)) {
+		return uuid.Nil, ErrInvalidToken
+	}
+	if !now.Before(time.Unix(int64(binary.BigEndian.Uint64(payload[16:])), 0)) {
+		return uuid.Nil, ErrExpiredToken
+	}
+	invitationID, _ := uuid.FromBytes(payload[:16])
+	return invitationID, nil
+}
+
+func (s *Signer) mac(payload []byte) []byte {
+	h := hmac.New(sha256.New, s.secret)
+	h.Write(payload)
+	return h.Sum(nil)
+}
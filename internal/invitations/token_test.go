@@ -0,0 +1,52 @@
+package invitations
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestSigner(t *testing.T) {
+	signer := NewSigner("test-secret")
+	now := time.Now()
+	invitationID := uuid.New()
+
+	token, err := signer.Sign(invitationID, now.Add(time.Hour))
+	if err != nil {
+		t.Fatalf("Sign failed: %v", err)
+	}
+	verifiedID, err := signer.Verify(token, now)
+	if err != nil || verifiedID != invitationID {
+		t.Fatalf("Expected %v, got %v, %v", invitationID, verifiedID, err)
+	}
+
+	// Expired tokens are refused even with a good signature
+	if _, err := signer.Verify(token, now.Add(2*time.Hour)); !errors.Is(err, ErrExpiredToken) {
+		t.Errorf("Expected an expired token, got %v", err)
+	}
+
+	// So are tokens signed with another secret, changed tokens and garbage
+	other, _ := NewSigner("other-secret").Sign(invitationID, now.Add(time.Hour))
+	tampered := []byte(token)
+	tampered[0] ^= 'A' ^ 'B'
+	for name, bad := range map[string]string{
+		"other secret": other,
+		"tampered":     string(tampered),
+		"truncated":    token[:len(token)-4],
+		"not base64":   "not a token!",
+	} {
+		if _, err := signer.Verify(bad, now); !errors.Is(err, ErrInvalidToken) {
+			t.Errorf("%s: expected an invalid token, got %v", name, err)
+		}
+	}
+
+	// Without a secret nothing is signed or accepted
+	if _, err := NewSigner("").Sign(invitationID, now.Add(time.Hour)); !errors.Is(err, ErrNoSecret) {
+		t.Errorf("Expected no secret, got %v", err)
+	}
+	if _, err := NewSigner("").Verify(token, now); !errors.Is(err, ErrNoSecret) {
+		t.Errorf("Expected no secret, got %v", err)
+	}
+}
@@ -3,6 +3,7 @@ package logger
 import (
 	"context"
 	"github.com/sirupsen/logrus"
+	"lowerthirdsapi/internal/entities"
 	"lowerthirdsapi/internal/helpers"
 	"os"
 )
@@ -41,8 +42,11 @@ func New() *logrus.Entry {
 }
 
 func WithContext(ctx context.Context, log *logrus.Entry) *logrus.Entry {
-	if userID, ok := ctx.Value(helpers.UserIDKey).(string); ok {
-		log = log.WithField("userID", userID)
+	if requestID, ok := ctx.Value(helpers.RequestIDKey).(string); ok {
+		log = log.WithField("requestID", requestID)
+	}
+	if user, ok := ctx.Value(helpers.UserIDKey).(*entities.User); ok {
+		log = log.WithField("userID", user.UserID)
 	}
 	if socialID, ok := ctx.Value(helpers.SocialIDKey).(string); ok {
 		log = log.WithField("socialID", socialID)
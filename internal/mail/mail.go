@@ -0,0 +1,96 @@
+// Package mail sends the emails the API writes, like invitations. Mailers are pluggable; the ones here log messages
+// or write them to files, for running locally.
+package mail
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	MailerLog  = "log"
+	MailerFile = "file"
+)
+
+type Config struct {
+	Mailer string `envconfig:"MAILER" default:"log"`
+	Dir    string `envconfig:"MAIL_DIR" default:"mail"`
+	From   string `envconfig:"MAIL_FROM" default:"lowerthirds@localhost"`
+}
+
+// Message is a plain text email
+type Message struct {
+	From    string
+	To      string
+	Subject string
+	Body    string
+}
+
+// Mailer sends messages
+type Mailer interface {
+	Send(ctx context.Context, msg Message) error
+}
+
+// New makes the mailer cfg names
+func New(cfg Config, log *logrus.Entry) (Mailer, error) {
+	switch cfg.Mailer {
+	case MailerLog:
+		return LogMailer{log: log, from: cfg.From}, nil
+	case MailerFile:
+		return FileMailer{Dir: cfg.Dir, from: cfg.From}, nil
+	default:
+		return nil, fmt.Errorf("unknown mailer %q, expected %s or %s", cfg.Mailer, MailerLog, MailerFile)
+	}
+}
+
+// LogMailer logs messages instead of sending them
+type LogMailer struct {
+	log  *logrus.Entry
+	from string
+}
+
+func (m LogMailer) Send(ctx context.Context, msg Message) error {
+	if msg.From == "" {
+		msg.From = m.from
+	}
+	m.log.WithFields(logrus.Fields{
+		"from":    msg.From,
+		"to":      msg.To,
+		"subject": msg.Subject,
+	}).Info("mail: ", msg.Body)
+	return nil
+}
+
+// FileMailer writes each message to a new .eml file in Dir, which mail clients can open
+type FileMailer struct {
+	Dir  string
+	from string
+}
+
+func (m FileMailer) Send(ctx context.Context, msg Message) error {
+	if msg.From == "" {
+		msg.From = m.from
+	}
+	if err := os.MkdirAll(m.Dir, 0o755); err != nil {
+		return err
+	}
+
+	now := time.Now().UTC()
+	var b strings.Builder
+	fmt.Fprintf(&b, "From: %s\r\n", msg.From)
+	fmt.Fprintf(&b, "To: %s\r\n", msg.To)
+	fmt.Fprintf(&b, "Subject: %s\r\n", msg.Subject)
+	fmt.Fprintf(&b, "Date: %s\r\n", now.Format(time.RFC1123Z))
+	b.WriteString("Content-Type: text/plain; charset=utf-8\r\n\r\n")
+	b.WriteString(strings.ReplaceAll(msg.Body, "\n", "\r\n"))
+
+	name := now.Format("20060102T150405") + "-" + uuid.NewString()[:8] + ".eml"
+	return os.WriteFile(filepath.Join(m.Dir, name), []byte(b.String()), 0o644)
+}
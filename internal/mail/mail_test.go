@@ -0,0 +1,44 @@
+package mail
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+)
+
+func TestFileMailer(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "mail")
+	mailer, err := New(Config{Mailer: MailerFile, Dir: dir, From: "from@example.com"}, logrus.NewEntry(logrus.New()))
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+
+	err = mailer.Send(context.Background(), Message{To: "to@example.com", Subject: "Hello", Body: "line one\nline two"})
+	if err != nil {
+		t.Fatalf("Send failed: %v", err)
+	}
+
+	files, err := filepath.Glob(filepath.Join(dir, "*.eml"))
+	if err != nil || len(files) != 1 {
+		t.Fatalf("Expected one message file, got %v, %v", files, err)
+	}
+	data, err := os.ReadFile(files[0])
+	if err != nil {
+		t.Fatalf("ReadFile failed: %v", err)
+	}
+	for _, want := range []string{"From: from@example.com\r\n", "To: to@example.com\r\n", "Subject: Hello\r\n", "\r\n\r\nline one\r\nline two"} {
+		if !strings.Contains(string(data), want) {
+			t.Errorf("Expected the message to contain %q, got %q", want, data)
+		}
+	}
+}
+
+func TestNewUnknownMailer(t *testing.T) {
+	if _, err := New(Config{Mailer: "smtp"}, logrus.NewEntry(logrus.New())); err == nil {
+		t.Error("Expected an error for an unknown mailer")
+	}
+}
@@ -0,0 +1,340 @@
+// Package migrations moves the database schema up and down through the numbered scripts embedded from scripts/,
+// which has a directory of scripts for each kind of database. Each version has a NNNN_name.up.sql and a
+// NNNN_name.down.sql, and the versions applied to a database are recorded in its schema_migrations table.
+package migrations
+
+import (
+	"context"
+	"database/sql"
+	"embed"
+	"errors"
+	"fmt"
+	"io/fs"
+	"regexp"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/sirupsen/logrus"
+)
+
+//go:embed scripts/mysql/*.sql scripts/sqlite/*.sql
+var scripts embed.FS
+
+// lockName is the advisory lock held while migrating MySQL, so two instances can't migrate the same database at once.
+// SQLite databases are migrated in a write transaction instead.
+const lockName = "lowerthirds_schema_migrations"
+
+var (
+	ErrLocked = errors.New("another migration is running")
+	ErrDirty  = errors.New("a migration failed part way through; fix the schema by hand, then force its version")
+)
+
+var scriptName = regexp.MustCompile(`^(\d+)_(\w+)\.(up|down)\.sql$`)
+
+// Migration is one version of the schema
+type Migration struct {
+	Version int
+	Name    string
+	Up      string
+	Down    string
+}
+
+// Status is a migration and whether it's been applied to the database
+type Status struct {
+	Migration
+	Applied   bool
+	Dirty     bool
+	AppliedDT time.Time
+}
+
+// record is a row of schema_migrations
+type record struct {
+	Version   int       `db:"version"`
+	Name      string    `db:"name"`
+	Dirty     bool      `db:"dirty"`
+	AppliedDT time.Time `db:"applied_dt"`
+}
+
+// Load reads the migrations in fsys, ordered by version. Every version needs both an up and a down script.
+func Load(fsys fs.FS) ([]Migration, error) {
+	entries, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		return nil, err
+	}
+
+	byVersion := map[int]*Migration{}
+	for _, entry := range entries {
+		match := scriptName.FindStringSubmatch(entry.Name())
+		if match == nil {
+			return nil, fmt.Errorf("migration %s isn't named NNNN_name.up.sql or NNNN_name.down.sql", entry.Name())
+		}
+		version, _ := strconv.Atoi(match[1])
+		if version < 1 {
+			return nil, fmt.Errorf("migration %s has to have a version above 0", entry.Name())
+		}
+		data, err := fs.ReadFile(fsys, entry.Name())
+		if err != nil {
+			return nil, err
+		}
+
+		m, ok := byVersion[version]
+		if !ok {
+			m = &Migration{Version: version, Name: match[2]}
+			byVersion[version] = m
+		}
+		if m.Name != match[2] {
+			return nil, fmt.Errorf("migrations %s and %s share version %d", m.Name, match[2], version)
+		}
+		if match[3] == "up" {
+			m.Up = string(data)
+		} else {
+			m.Down = string(data)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, m := range byVersion {
+		if m.Up == "" || m.Down == "" {
+			return nil, fmt.Errorf("migration %04d_%s needs both an up and a down script", m.Version, m.Name)
+		}
+		migrations = append(migrations, *m)
+	}
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].Version < migrations[j].Version })
+	return migrations, nil
+}
+
+// Migrator applies migrations to a database. Scripts hold several statements, so a MySQL connection has to allow
+// multiple statements.
+type Migrator struct {
+	db          *sqlx.DB
+	logger      *logrus.Entry
+	migrations  []Migration
+	sqlite      bool
+	lockTimeout time.Duration
+}
+
+// New creates a Migrator for the embedded migrations of the database's driver, which is "sqlite" or "mysql"
+func New(db *sqlx.DB, logger *logrus.Entry) (*Migrator, error) {
+	sqlite := db.DriverName() == "sqlite"
+	migrations, err := embedded(sqlite)
+	if err != nil {
+		return nil, err
+	}
+	return &Migrator{db: db, logger: logger, migrations: migrations, sqlite: sqlite, lockTimeout: time.Minute}, nil
+}
+
+// embedded loads the embedded migrations for SQLite or MySQL
+func embedded(sqlite bool) ([]Migration, error) {
+	dir := "scripts/mysql"
+	if sqlite {
+		dir = "scripts/sqlite"
+	}
+	sub, err := fs.Sub(scripts, dir)
+	if err != nil {
+		return nil, err
+	}
+	return Load(sub)
+}
+
+// Up applies the next steps migrations that haven't been applied, or all of them when steps is 0, and returns how
+// many it applied
+func (m *Migrator) Up(ctx context.Context, steps int) (int, error) {
+	applied := 0
+	err := m.withLock(ctx, func(conn *sqlx.Conn, records map[int]record) error {
+		if err := checkClean(records); err != nil {
+			return err
+		}
+		for _, migration := range m.migrations {
+			if steps > 0 && applied == steps {
+				break
+			}
+			if _, ok := records[migration.Version]; ok {
+				continue
+			}
+
+			m.logger.Infof("[migrations] applying %04d_%s", migration.Version, migration.Name)
+			_, err := conn.ExecContext(ctx, `INSERT INTO schema_migrations (version, name, dirty) VALUES (?, ?, 1)`,
+				migration.Version, migration.Name)
+			if err != nil {
+				return err
+			}
+			_, err = conn.ExecContext(ctx, migration.Up)
+			if err != nil {
+				return fmt.Errorf("migration %04d_%s: %w", migration.Version, migration.Name, err)
+			}
+			_, err = conn.ExecContext(ctx, `UPDATE schema_migrations SET dirty = 0 WHERE version = ?`, migration.Version)
+			if err != nil {
+				return err
+			}
+			applied++
+		}
+		return nil
+	})
+	return applied, err
+}
+
+// Down reverts the last steps applied migrations, newest first, and returns how many it reverted
+func (m *Migrator) Down(ctx context.Context, steps int) (int, error) {
+	reverted := 0
+	err := m.withLock(ctx, func(conn *sqlx.Conn, records map[int]record) error {
+		if err := checkClean(records); err != nil {
+			return err
+		}
+		for i := len(m.migrations) - 1; i >= 0 && reverted < steps; i-- {
+			migration := m.migrations[i]
+			if _, ok := records[migration.Version]; !ok {
+				continue
+			}
+
+			m.logger.Infof("[migrations] reverting %04d_%s", migration.Version, migration.Name)
+			_, err := conn.ExecContext(ctx, `UPDATE schema_migrations SET dirty = 1 WHERE version = ?`, migration.Version)
+			if err != nil {
+				return err
+			}
+			_, err = conn.ExecContext(ctx, migration.Down)
+			if err != nil {
+				return fmt.Errorf("migration %04d_%s: %w", migration.Version, migration.Name, err)
+			}
+			_, err = conn.ExecContext(ctx, `DELETE FROM schema_migrations WHERE version = ?`, migration.Version)
+			if err != nil {
+				return err
+			}
+			reverted++
+		}
+		return nil
+	})
+	return reverted, err
+}
+
+// Force records the database as being at version, with every migration up to it applied and none after it,
+// without running any scripts. It's for adopting existing databases and recovering from failed migrations.
+func (m *Migrator) Force(ctx context.Context, version int) error {
+	known := version == 0
+	for _, migration := range m.migrations {
+		known = known || migration.Version == version
+	}
+	if !known {
+		return fmt.Errorf("there's no migration %d", version)
+	}
+
+	return m.withLock(ctx, func(conn *sqlx.Conn, _ map[int]record) error {
+		_, err := conn.ExecContext(ctx, `DELETE FROM schema_migrations`)
+		if err != nil {
+			return err
+		}
+		for _, migration := range m.migrations {
+			if migration.Version > version {
+				break
+			}
+			_, err = conn.ExecContext(ctx, `INSERT INTO schema_migrations (version, name, dirty) VALUES (?, ?, 0)`,
+				migration.Version, migration.Name)
+			if err != nil {
+				return err
+			}
+		}
+		m.logger.Info("[migrations] forced version ", version)
+		return nil
+	})
+}
+
+// Status lists every migration and whether it's been applied
+func (m *Migrator) Status(ctx context.Context) ([]Status, error) {
+	var statuses []Status
+	err := m.withLock(ctx, func(conn *sqlx.Conn, records map[int]record) error {
+		for _, migration := range m.migrations {
+			r, ok := records[migration.Version]
+			statuses = append(statuses, Status{Migration: migration, Applied: ok, Dirty: r.Dirty, AppliedDT: r.AppliedDT})
+		}
+		return nil
+	})
+	return statuses, err
+}
+
+// withLock runs fn on one connection while holding the migration lock, with the recorded migrations. Up and Down
+// refuse to run while a migration is dirty, but Force and Status don't check.
+func (m *Migrator) withLock(ctx context.Context, fn func(conn *sqlx.Conn, records map[int]record) error) error {
+	conn, err := m.db.Connx(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	unlock, err := m.lock(ctx, conn)
+	if err != nil {
+		return err
+	}
+
+	_, err = conn.ExecContext(ctx, `CREATE TABLE IF NOT EXISTS schema_migrations (
+		version BIGINT NOT NULL,
+		name VARCHAR(200) NOT NULL,
+		dirty TINYINT(1) NOT NULL DEFAULT 0,
+		applied_dt DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+		PRIMARY KEY (version)
+	)`)
+	if err != nil {
+		return unlock(err)
+	}
+
+	var rows []record
+	err = conn.SelectContext(ctx, &rows, `SELECT version, name, dirty, applied_dt FROM schema_migrations`)
+	if err != nil {
+		return unlock(err)
+	}
+	records := make(map[int]record, len(rows))
+	for _, r := range rows {
+		records[r.Version] = r
+	}
+	return unlock(fn(conn, records))
+}
+
+// lock takes the migration lock on conn, waiting up to the lock timeout. The unlock func it returns releases the
+// lock and passes on the error it's given. SQLite holds its lock with a write transaction, which unlock commits,
+// or rolls back when there's an error, so a failed SQLite migration leaves nothing behind.
+func (m *Migrator) lock(ctx context.Context, conn *sqlx.Conn) (func(error) error, error) {
+	if m.sqlite {
+		_, err := conn.ExecContext(ctx, fmt.Sprintf(`PRAGMA busy_timeout = %d`, m.lockTimeout.Milliseconds()))
+		if err != nil {
+			return nil, err
+		}
+		_, err = conn.ExecContext(ctx, `BEGIN IMMEDIATE`)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrLocked, err)
+		}
+		return func(err error) error {
+			if err != nil {
+				_, _ = conn.ExecContext(context.Background(), `ROLLBACK`)
+				return err
+			}
+			_, err = conn.ExecContext(ctx, `COMMIT`)
+			return err
+		}, nil
+	}
+
+	var locked sql.NullInt64
+	err := conn.GetContext(ctx, &locked, `SELECT GET_LOCK(?, ?)`, lockName, int(m.lockTimeout.Seconds()))
+	if err != nil {
+		return nil, err
+	}
+	if !locked.Valid || locked.Int64 != 1 {
+		return nil, ErrLocked
+	}
+	return func(err error) error {
+		_, releaseErr := conn.ExecContext(context.Background(), `SELECT RELEASE_LOCK(?)`, lockName)
+		if releaseErr != nil {
+			m.logger.Error("[migrations] release lock error ", releaseErr)
+		}
+		return err
+	}, nil
+}
+
+// checkClean returns ErrDirty if a migration was left part way through
+func checkClean(records map[int]record) error {
+	for _, r := range records {
+		if r.Dirty {
+			return fmt.Errorf("%w: %04d_%s", ErrDirty, r.Version, r.Name)
+		}
+	}
+	return nil
+}
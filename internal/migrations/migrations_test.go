@@ -0,0 +1,320 @@
+package migrations
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+
+	_ "github.com/go-sql-driver/mysql"
+	"github.com/jmoiron/sqlx"
+	"github.com/sirupsen/logrus"
+	_ "modernc.org/sqlite"
+)
+
+func TestLoad(t *testing.T) {
+	migrations, err := Load(fstest.MapFS{
+		"0002_second.up.sql":   {Data: []byte("CREATE TABLE B (id INT);")},
+		"0002_second.down.sql": {Data: []byte("DROP TABLE B;")},
+		"0001_first.up.sql":    {Data: []byte("CREATE TABLE A (id INT);")},
+		"0001_first.down.sql":  {Data: []byte("DROP TABLE A;")},
+	})
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if len(migrations) != 2 || migrations[0].Name != "first" || migrations[1].Version != 2 {
+		t.Errorf("expected first and second in order, got %+v", migrations)
+	}
+
+	for name, fsys := range map[string]fstest.MapFS{
+		"missing down": {"0001_first.up.sql": {Data: []byte("SELECT 1;")}},
+		"bad name":     {"first.up.sql": {Data: []byte("SELECT 1;")}, "first.down.sql": {Data: []byte("SELECT 1;")}},
+		"shared version": {
+			"0001_first.up.sql":   {Data: []byte("SELECT 1;")},
+			"0001_first.down.sql": {Data: []byte("SELECT 1;")},
+			"0001_other.up.sql":   {Data: []byte("SELECT 1;")},
+		},
+	} {
+		if _, err := Load(fsys); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+
+	// The embedded migrations load too, with the same versions for each kind of database
+	mysqlMigrations, err := embedded(false)
+	if err != nil {
+		t.Fatalf("Loading the MySQL migrations failed: %v", err)
+	}
+	sqliteMigrations, err := embedded(true)
+	if err != nil {
+		t.Fatalf("Loading the SQLite migrations failed: %v", err)
+	}
+	if len(mysqlMigrations) != len(sqliteMigrations) {
+		t.Fatalf("Expected the same migrations, got %d for MySQL and %d for SQLite", len(mysqlMigrations), len(sqliteMigrations))
+	}
+	for i := range mysqlMigrations {
+		if mysqlMigrations[i].Version != sqliteMigrations[i].Version || mysqlMigrations[i].Name != sqliteMigrations[i].Name {
+			t.Errorf("Expected matching migrations, got %04d_%s and %04d_%s", mysqlMigrations[i].Version,
+				mysqlMigrations[i].Name, sqliteMigrations[i].Version, sqliteMigrations[i].Name)
+		}
+	}
+}
+
+func TestMigrator(t *testing.T) {
+	ctx := context.Background()
+	db := testDB(t)
+	log := logrus.New()
+	log.SetOutput(io.Discard)
+	m, err := New(db, logrus.NewEntry(log))
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+
+	applied, err := m.Up(ctx, 0)
+	if err != nil || applied != len(m.migrations) {
+		t.Fatalf("Expected every migration to be applied, got %d, %v", applied, err)
+	}
+	var hymns int
+	if err := db.Get(&hymns, `SELECT COUNT(*) FROM Hymns`); err != nil || hymns != 6 {
+		t.Errorf("Expected the 6 hymns in the catalog, got %d, %v", hymns, err)
+	}
+	applied, err = m.Up(ctx, 0)
+	if err != nil || applied != 0 {
+		t.Errorf("Expected nothing left to apply, got %d, %v", applied, err)
+	}
+
+	// Only one instance migrates at a time
+	other, err := db.Connx(ctx)
+	if err != nil {
+		t.Fatalf("Connx failed: %v", err)
+	}
+	defer other.Close()
+	if _, err := other.ExecContext(ctx, `SELECT GET_LOCK(?, 0)`, lockName); err != nil {
+		t.Fatalf("GET_LOCK failed: %v", err)
+	}
+	m.lockTimeout = 0
+	if _, err := m.Down(ctx, 1); !errors.Is(err, ErrLocked) {
+		t.Errorf("Expected ErrLocked, got %v", err)
+	}
+	if _, err := other.ExecContext(ctx, `SELECT RELEASE_LOCK(?)`, lockName); err != nil {
+		t.Fatalf("RELEASE_LOCK failed: %v", err)
+	}
+
+	reverted, err := m.Down(ctx, len(m.migrations))
+	if err != nil || reverted != len(m.migrations) {
+		t.Fatalf("Expected every migration to be reverted, got %d, %v", reverted, err)
+	}
+	if err := db.Get(&hymns, `SELECT COUNT(*) FROM Hymns`); err == nil {
+		t.Error("Expected Hymns to be dropped")
+	}
+
+	// Forcing a version records it without running anything
+	if err := m.Force(ctx, 1); err != nil {
+		t.Fatalf("Force failed: %v", err)
+	}
+	statuses, err := m.Status(ctx)
+	if err != nil || !statuses[0].Applied || statuses[0].Dirty {
+		t.Errorf("Expected the baseline to be applied, got %+v, %v", statuses, err)
+	}
+	if err := m.Force(ctx, 9999); err == nil {
+		t.Error("Expected an error forcing an unknown version")
+	}
+
+	// A dirty migration blocks everything but Force
+	if _, err := db.Exec(`UPDATE schema_migrations SET dirty = 1 WHERE version = 1`); err != nil {
+		t.Fatalf("UPDATE failed: %v", err)
+	}
+	if _, err := m.Up(ctx, 0); !errors.Is(err, ErrDirty) {
+		t.Errorf("Expected ErrDirty, got %v", err)
+	}
+	if err := m.Force(ctx, 0); err != nil {
+		t.Fatalf("Force failed: %v", err)
+	}
+}
+
+func TestMigratorSQLite(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "lowerthirds.db")
+	db, err := sqlx.Connect("sqlite", "file:"+path+"?_pragma=busy_timeout(10000)")
+	if err != nil {
+		t.Fatalf("Failed to open SQLite: %v", err)
+	}
+	defer db.Close()
+	log := logrus.New()
+	log.SetOutput(io.Discard)
+	m, err := New(db, logrus.NewEntry(log))
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	if !m.sqlite {
+		t.Fatal("Expected the SQLite migrations")
+	}
+
+	applied, err := m.Up(ctx, 0)
+	if err != nil || applied != len(m.migrations) {
+		t.Fatalf("Expected every migration to be applied, got %d, %v", applied, err)
+	}
+	var hymns int
+	if err := db.Get(&hymns, `SELECT COUNT(*) FROM Hymns`); err != nil || hymns != 6 {
+		t.Errorf("Expected the 6 hymns in the catalog, got %d, %v", hymns, err)
+	}
+
+	// Only one instance migrates at a time
+	other, err := db.Connx(ctx)
+	if err != nil {
+		t.Fatalf("Connx failed: %v", err)
+	}
+	defer other.Close()
+	if _, err := other.ExecContext(ctx, `BEGIN IMMEDIATE`); err != nil {
+		t.Fatalf("BEGIN failed: %v", err)
+	}
+	m.lockTimeout = 0
+	if _, err := m.Down(ctx, 1); !errors.Is(err, ErrLocked) {
+		t.Errorf("Expected ErrLocked, got %v", err)
+	}
+	if _, err := other.ExecContext(ctx, `ROLLBACK`); err != nil {
+		t.Fatalf("ROLLBACK failed: %v", err)
+	}
+
+	// A failed migration is rolled back with its record
+	m.migrations = append(m.migrations, Migration{Version: 9999, Name: "broken", Up: "CREATE TABLE Broken (id INT); SELECT * FROM Missing;", Down: "DROP TABLE Broken;"})
+	if _, err := m.Up(ctx, 0); err == nil {
+		t.Fatal("Expected the broken migration to fail")
+	}
+	statuses, err := m.Status(ctx)
+	if err != nil || statuses[len(statuses)-1].Applied {
+		t.Errorf("Expected the broken migration to be left unapplied, got %+v, %v", statuses, err)
+	}
+	if err := db.Get(&hymns, `SELECT COUNT(*) FROM Broken`); err == nil {
+		t.Error("Expected Broken to be rolled back")
+	}
+	m.migrations = m.migrations[:len(m.migrations)-1]
+
+	reverted, err := m.Down(ctx, len(m.migrations))
+	if err != nil || reverted != len(m.migrations) {
+		t.Fatalf("Expected every migration to be reverted, got %d, %v", reverted, err)
+	}
+	if err := db.Get(&hymns, `SELECT COUNT(*) FROM Hymns`); err == nil {
+		t.Error("Expected Hymns to be dropped")
+	}
+}
+
+// TestMigratingOldData moves the rows of a database made from the old setup.sql through the migrations that change
+// them
+func TestMigratingOldData(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "lowerthirds.db")
+	sqliteDB, err := sqlx.Connect("sqlite", "file:"+path+"?_pragma=busy_timeout(10000)")
+	if err != nil {
+		t.Fatalf("Failed to open SQLite: %v", err)
+	}
+	defer sqliteDB.Close()
+
+	for name, db := range map[string]*sqlx.DB{"mysql": testDB(t), "sqlite": sqliteDB} {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			log := logrus.New()
+			log.SetOutput(io.Discard)
+			m, err := New(db, logrus.NewEntry(log))
+			if err != nil {
+				t.Fatalf("New failed: %v", err)
+			}
+			if _, err := m.Up(ctx, 1); err != nil {
+				t.Fatalf("Up failed: %v", err)
+			}
+			for _, stmt := range []string{
+				`INSERT INTO OrgUsers (org_id, user_id) VALUES ('org', 'member')`,
+				`INSERT INTO SpeakerItems (id, meeting_id, meeting_role, item_order, speaker_name, expected_duration)
+				VALUES ('speaker', 'meeting', 'Speaker', 1, 'Sister Jones', 5)`,
+				`INSERT INTO LyricsItems (id, meeting_id, meeting_role, item_order, hymn_id, show_translation)
+				VALUES ('lyrics', 'meeting', 'Opening Hymn', 2, 'hymn', 1)`,
+			} {
+				if _, err := db.Exec(stmt); err != nil {
+					t.Fatalf("Failed to add old items: %v", err)
+				}
+			}
+
+			// Existing members keep doing everything as owners
+			if _, err := m.Up(ctx, 1); err != nil {
+				t.Fatalf("Up failed: %v", err)
+			}
+			var role string
+			if err := db.Get(&role, `SELECT role FROM OrgUsers WHERE user_id = 'member'`); err != nil || role != "owner" {
+				t.Errorf("Expected the member to own the org, got %q, %v", role, err)
+			}
+
+			// Items move into AgendaItems with their type's columns as details
+			steps := 0
+			for _, migration := range m.migrations {
+				if migration.Name == "agenda_items" {
+					break
+				}
+				steps++
+			}
+			if _, err := m.Up(ctx, steps-1); err != nil {
+				t.Fatalf("Up failed: %v", err)
+			}
+			var items []struct {
+				ID       string `db:"id"`
+				ItemType string `db:"item_type"`
+				Details  string `db:"details"`
+			}
+			err = db.Select(&items, `SELECT id, item_type, details FROM AgendaItems WHERE meeting_id = 'meeting' ORDER BY item_order`)
+			if err != nil || len(items) != 2 {
+				t.Fatalf("Expected both items moved, got %+v, %v", items, err)
+			}
+			var speaker, lyrics map[string]interface{}
+			_ = json.Unmarshal([]byte(items[0].Details), &speaker)
+			_ = json.Unmarshal([]byte(items[1].Details), &lyrics)
+			if items[0].ItemType != "speaker" || speaker["name"] != "Sister Jones" || speaker["title"] != nil || speaker["expected_duration"] != 5.0 {
+				t.Errorf("Unexpected speaker %+v", items[0])
+			}
+			if items[1].ItemType != "lyrics" || lyrics["hymn_id"] != "hymn" || lyrics["show_translation"] != true {
+				t.Errorf("Unexpected lyrics %+v", items[1])
+			}
+
+			// And back again
+			if _, err := m.Down(ctx, 1); err != nil {
+				t.Fatalf("Down failed: %v", err)
+			}
+			var title *string
+			var duration *int
+			err = db.QueryRow(`SELECT title, expected_duration FROM SpeakerItems WHERE id = 'speaker'`).Scan(&title, &duration)
+			if err != nil || title != nil || duration == nil || *duration != 5 {
+				t.Errorf("Expected the speaker moved back, got %v, %v, %v", title, duration, err)
+			}
+			var showTranslation bool
+			err = db.Get(&showTranslation, `SELECT show_translation FROM LyricsItems WHERE id = 'lyrics'`)
+			if err != nil || !showTranslation {
+				t.Errorf("Expected the lyrics moved back, got %v, %v", showTranslation, err)
+			}
+		})
+	}
+}
+
+// testDB connects to an empty database for migrating, which allows multiple statements like the app's connection
+func testDB(t *testing.T) *sqlx.DB {
+	t.Helper()
+	db, err := sqlx.Connect("mysql", "root:@tcp(localhost:3306)/")
+	if err != nil {
+		t.Fatalf("Failed to connect to MySQL: %v", err)
+	}
+	for _, stmt := range []string{
+		"DROP DATABASE IF EXISTS lowerthirds_migrations_test",
+		"CREATE DATABASE lowerthirds_migrations_test",
+	} {
+		if _, err := db.Exec(stmt); err != nil {
+			t.Fatalf("Failed to create migrations database: %v", err)
+		}
+	}
+	db.Close()
+
+	db, err = sqlx.Connect("mysql", "root:@tcp(localhost:3306)/lowerthirds_migrations_test?parseTime=true&multiStatements=true")
+	if err != nil {
+		t.Fatalf("Failed to connect to migrations database: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return db
+}
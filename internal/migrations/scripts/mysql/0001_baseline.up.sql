@@ -1,5 +1,121 @@
-DROP TABLE HymnVerses;
-DROP TABLE Hymns;
+-- The schema data/setup.sql and data/hymns.sql made before migrations existed. Databases made from them are marked
+-- as being at this version with `migrate force 1` instead of running it, and the later migrations bring them up to
+-- date.
+
+CREATE TABLE BlankItems (
+    id CHAR(36) NOT NULL,
+    meeting_id CHAR(36) NOT NULL,
+    meeting_role VARCHAR(50) NOT NULL,
+    item_type VARCHAR(20) NOT NULL DEFAULT 'blank',
+    item_order INT NOT NULL,
+    deleted_dt DATETIME NULL,
+    inserted_dt DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_dt DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    PRIMARY KEY (id)
+);
+
+CREATE TABLE LyricsItems (
+    id CHAR(36) NOT NULL,
+    meeting_id CHAR(36) NOT NULL,
+    meeting_role VARCHAR(50) NOT NULL,
+    item_type VARCHAR(20) NOT NULL DEFAULT 'blank',
+    item_order INT NOT NULL,
+    hymn_id CHAR(36) NULL,
+    show_translation TINYINT(0) NOT NULL DEFAULT 0,
+    deleted_dt DATETIME NULL,
+    inserted_dt DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_dt DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    PRIMARY KEY (id)
+);
+
+CREATE TABLE MessageItems (
+    id CHAR(36) NOT NULL,
+    meeting_id CHAR(36) NOT NULL,
+    meeting_role VARCHAR(50) NOT NULL,
+    item_type VARCHAR(20) NOT NULL DEFAULT 'blank',
+    item_order INT NOT NULL,
+    primary_text CHAR(200) NOT NULL,
+    secondary_text CHAR(200) NULL,
+    deleted_dt DATETIME NULL,
+    inserted_dt DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_dt DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    PRIMARY KEY (id)
+);
+
+CREATE TABLE SpeakerItems (
+    id CHAR(36) NOT NULL,
+    meeting_id CHAR(36) NOT NULL,
+    meeting_role VARCHAR(50) NOT NULL,
+    item_type VARCHAR(20) NOT NULL DEFAULT 'blank',
+    item_order INT NOT NULL,
+    speaker_name CHAR(200) NOT NULL,
+    title CHAR(200) NULL,
+    expected_duration INT NULL,
+    deleted_dt DATETIME NULL,
+    inserted_dt DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_dt DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    PRIMARY KEY (id)
+);
+
+CREATE TABLE TimerItems (
+    id CHAR(36) NOT NULL,
+    meeting_id CHAR(36) NOT NULL,
+    meeting_role VARCHAR(50) NOT NULL,
+    item_type VARCHAR(20) NOT NULL DEFAULT 'blank',
+    item_order INT NOT NULL,
+    show_meeting_details TINYINT(1) NOT NULL DEFAULT 0,
+    deleted_dt DATETIME NULL,
+    inserted_dt DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_dt DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    PRIMARY KEY (id)
+);
+
+CREATE TABLE Users (
+    id CHAR(36) NOT NULL,
+    social_id VARCHAR(60) NULL,
+    email VARCHAR(60) NOT NULL,
+    first_name VARCHAR(50) NULL,
+    full_name VARCHAR(50) NULL,
+    last_name VARCHAR(50) NULL,
+    photo_url VARCHAR(2000) NULL,
+    deleted_dt DATETIME NULL,
+    inserted_dt DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_dt DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    PRIMARY KEY (id),
+    UNIQUE KEY unique_email (email),
+    UNIQUE KEY unique_social (social_id)
+);
+
+CREATE TABLE Meetings (
+    id CHAR(36) NOT NULL,
+    org_id CHAR(36) NOT NULL,
+    conference VARCHAR(200) NULL,
+    meeting VARCHAR(200) NOT NULL,
+    meeting_date DATETIME NOT NULL,
+    duration INT,
+    deleted_dt DATETIME NULL,
+    inserted_dt DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_dt DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    PRIMARY KEY (id)
+);
+
+CREATE TABLE Organization (
+    id CHAR(36) NOT NULL,
+    name CHAR(200) NOT NULL,
+    deleted_dt DATETIME NULL,
+    inserted_dt DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_dt DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    PRIMARY KEY (id),
+    UNIQUE KEY unique_org_name (name)
+);
+
+CREATE TABLE OrgUsers (
+    org_id CHAR(36) NOT NULL,
+    user_id CHAR(36) NOT NULL,
+    deleted_dt DATETIME NULL,
+    inserted_dt DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE KEY (org_id, user_id, deleted_dt)
+);
 
 CREATE TABLE Hymns (
    id CHAR(36) NOT NULL,
@@ -12,14 +128,6 @@ CREATE TABLE Hymns (
    updated_dt DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
    PRIMARY KEY (id)
 );
-INSERT INTO Hymns (id, language, page, name) VALUES ('fd5905bb-35a4-4a2f-9e29-041f58f3d1a9', 'eng', 243, 'Let Us All Press On');
-INSERT INTO Hymns (id, language, page, name) VALUES ('3549ebe2-b6cc-4433-a0dd-365ec4113d38', 'spa', 158, 'Trabajemos hoy en la obra');
-INSERT INTO Hymns (id, language, page, name) VALUES ('bb125745-55eb-448c-b255-dac7ef6444cc', 'eng', 66, 'Rejoice, the Lord is King!');
-INSERT INTO Hymns (id, language, page, name) VALUES ('339dee9c-e944-4eb1-bdb8-bf7e1b9c411f', 'eng', 3, 'Now Let Us Rejoice');
-INSERT INTO Hymns (id, language, page, name) VALUES ('dbb6cabf-9466-46f2-9cfd-f0e06aa62869', 'eng', 6, 'Redeemer of Israel');
-INSERT INTO Hymns (id, language, page, name) VALUES ('a4d02b9f-bef8-47db-8765-4e8cee76bb64', 'spa', 5, 'Redentor de Israel');
-UPDATE Hymns SET translation_id = 'a4d02b9f-bef8-47db-8765-4e8cee76bb64' WHERE id = 'dbb6cabf-9466-46f2-9cfd-f0e06aa62869';
-UPDATE Hymns SET translation_id = '3549ebe2-b6cc-4433-a0dd-365ec4113d38' WHERE id = 'fd5905bb-35a4-4a2f-9e29-041f58f3d1a9';
 
 CREATE TABLE HymnVerses (
     hymn_id CHAR(36) NOT NULL,
@@ -31,6 +139,16 @@ CREATE TABLE HymnVerses (
     updated_dt DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
     UNIQUE KEY (hymn_id, verse_number, deleted_dt)
 );
+
+-- The hymn catalog from data/hymns.sql
+INSERT INTO Hymns (id, language, page, name) VALUES ('fd5905bb-35a4-4a2f-9e29-041f58f3d1a9', 'eng', 243, 'Let Us All Press On');
+INSERT INTO Hymns (id, language, page, name) VALUES ('3549ebe2-b6cc-4433-a0dd-365ec4113d38', 'spa', 158, 'Trabajemos hoy en la obra');
+INSERT INTO Hymns (id, language, page, name) VALUES ('bb125745-55eb-448c-b255-dac7ef6444cc', 'eng', 66, 'Rejoice, the Lord is King!');
+INSERT INTO Hymns (id, language, page, name) VALUES ('339dee9c-e944-4eb1-bdb8-bf7e1b9c411f', 'eng', 3, 'Now Let Us Rejoice');
+INSERT INTO Hymns (id, language, page, name) VALUES ('dbb6cabf-9466-46f2-9cfd-f0e06aa62869', 'eng', 6, 'Redeemer of Israel');
+INSERT INTO Hymns (id, language, page, name) VALUES ('a4d02b9f-bef8-47db-8765-4e8cee76bb64', 'spa', 5, 'Redentor de Israel');
+UPDATE Hymns SET translation_id = 'a4d02b9f-bef8-47db-8765-4e8cee76bb64' WHERE id = 'dbb6cabf-9466-46f2-9cfd-f0e06aa62869';
+UPDATE Hymns SET translation_id = '3549ebe2-b6cc-4433-a0dd-365ec4113d38' WHERE id = 'fd5905bb-35a4-4a2f-9e29-041f58f3d1a9';
 INSERT INTO HymnVerses (hymn_id, verse_number, verse_lines)
 VALUES ('dbb6cabf-9466-46f2-9cfd-f0e06aa62869', 1, 'Redeemer of Israel, our only delight,
 On whom for a blessing we call.
@@ -61,7 +179,6 @@ VALUES ('dbb6cabf-9466-46f2-9cfd-f0e06aa62869', 6, 'He looks! and ten thousands
 And myriads wait for his word;
 He speaks! and eternity, Filled with his voice,
 Re-echoes the praise of the Lord', 1);
-
 INSERT INTO HymnVerses (hymn_id, verse_number, verse_lines)
 VALUES ('a4d02b9f-bef8-47db-8765-4e8cee76bb64', 1, 'Oh Dios de Israel, te rendimos loor
 a ti, nuestro gran Redentor,
@@ -82,7 +199,6 @@ VALUES ('a4d02b9f-bef8-47db-8765-4e8cee76bb64', 4, 'Nos regocijamos, oh hijos de
 las señas presentes están.
 Seamos valientes y fieles al Rey;
 se vislumbra la gran redención.');
-
 INSERT INTO HymnVerses (hymn_id, verse_number, verse_lines)
 VALUES ('fd5905bb-35a4-4a2f-9e29-041f58f3d1a9', 1, 'Let us all press on in the work of the Lord,
 That when life is o’er we may gain a reward;
@@ -110,7 +226,6 @@ Fear not, though the enemy deride;
 Courage, for the Lord is on our side.
 We will heed not what the wicked may say,
 But the Lord alone we will obey.');
-
 INSERT INTO HymnVerses (hymn_id, verse_number, verse_lines)
 VALUES ('3549ebe2-b6cc-4433-a0dd-365ec4113d38', 1, 'Trabajemos hoy en la obra del Señor,
 y ganemos así un hogar celestial.
@@ -138,8 +253,3 @@ Firmes y valientes en la lid,
 todo enemigo confundid.
 Lucharemos a vencer el error;
 seguiremos sólo al Señor.');
-
-/*
-SELECT * FROM HymnVerses;
-SELECT * FROM Hymns;
-*/
\ No newline at end of file
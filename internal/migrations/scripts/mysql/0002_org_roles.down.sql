@@ -0,0 +1 @@
+ALTER TABLE OrgUsers DROP COLUMN role;
@@ -0,0 +1,4 @@
+-- Each org member gets a role. Everyone who was a member could already do everything, so they all become owners.
+
+ALTER TABLE OrgUsers ADD COLUMN role VARCHAR(20) NOT NULL DEFAULT 'viewer' AFTER user_id;
+UPDATE OrgUsers SET role = 'owner';
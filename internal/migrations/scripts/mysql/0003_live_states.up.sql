@@ -0,0 +1,14 @@
+-- The program and preview state of each meeting being run live
+
+CREATE TABLE LiveStates (
+    meeting_id CHAR(36) NOT NULL,
+    program_item_id CHAR(36) NULL,
+    preview_item_id CHAR(36) NULL,
+    visible TINYINT(1) NOT NULL DEFAULT 0,
+    program_changed_dt DATETIME(3) NULL,
+    preview_changed_dt DATETIME(3) NULL,
+    visible_changed_dt DATETIME(3) NULL,
+    inserted_dt DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_dt DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    PRIMARY KEY (meeting_id)
+);
@@ -0,0 +1,6 @@
+ALTER TABLE LiveStates
+    DROP COLUMN timer_elapsed_ms,
+    DROP COLUMN timer_started_dt,
+    DROP COLUMN verse_count,
+    DROP COLUMN verse_index,
+    DROP COLUMN version;
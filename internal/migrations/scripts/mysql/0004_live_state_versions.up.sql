@@ -0,0 +1,8 @@
+-- Versions live state for the control channel, and adds the lyrics verse and a timer to it
+
+ALTER TABLE LiveStates
+    ADD COLUMN version BIGINT NOT NULL DEFAULT 0 AFTER meeting_id,
+    ADD COLUMN verse_index INT NOT NULL DEFAULT 0 AFTER `visible`,
+    ADD COLUMN verse_count INT NOT NULL DEFAULT 0 AFTER verse_index,
+    ADD COLUMN timer_started_dt DATETIME(3) NULL AFTER verse_count,
+    ADD COLUMN timer_elapsed_ms BIGINT NOT NULL DEFAULT 0 AFTER timer_started_dt;
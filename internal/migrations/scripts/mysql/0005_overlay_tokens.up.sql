@@ -0,0 +1,11 @@
+-- Tokens that let a browser source show a meeting's overlay without signing in
+
+CREATE TABLE OverlayTokens (
+    token VARCHAR(64) NOT NULL,
+    meeting_id CHAR(36) NOT NULL,
+    deleted_dt DATETIME NULL,
+    inserted_dt DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_dt DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    PRIMARY KEY (token),
+    INDEX idx_overlay_tokens_meeting (meeting_id)
+);
@@ -0,0 +1,17 @@
+-- Org themes, and the overrides of a meeting's theme
+
+CREATE TABLE OrgThemes (
+    org_id CHAR(36) NOT NULL,
+    theme JSON NOT NULL,
+    inserted_dt DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_dt DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    PRIMARY KEY (org_id)
+);
+
+CREATE TABLE MeetingThemes (
+    meeting_id CHAR(36) NOT NULL,
+    overrides JSON NOT NULL,
+    inserted_dt DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_dt DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    PRIMARY KEY (meeting_id)
+);
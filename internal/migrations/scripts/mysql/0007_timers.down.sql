@@ -0,0 +1,5 @@
+DROP TABLE Timers;
+
+ALTER TABLE LiveStates
+    ADD COLUMN timer_started_dt DATETIME(3) NULL AFTER verse_count,
+    ADD COLUMN timer_elapsed_ms BIGINT NOT NULL DEFAULT 0 AFTER timer_started_dt;
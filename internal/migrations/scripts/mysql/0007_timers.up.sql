@@ -0,0 +1,21 @@
+-- Moves timers out of live state into a row per timer item. Running live state timers aren't carried over.
+
+ALTER TABLE LiveStates
+    DROP COLUMN timer_started_dt,
+    DROP COLUMN timer_elapsed_ms;
+
+CREATE TABLE Timers (
+    item_id CHAR(36) NOT NULL,
+    meeting_id CHAR(36) NOT NULL,
+    version BIGINT NOT NULL DEFAULT 0,
+    kind VARCHAR(16) NOT NULL,
+    duration_ms BIGINT NOT NULL DEFAULT 0,
+    target_dt DATETIME(3) NULL,
+    added_ms BIGINT NOT NULL DEFAULT 0,
+    elapsed_ms BIGINT NOT NULL DEFAULT 0,
+    started_dt DATETIME(3) NULL,
+    inserted_dt DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_dt DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    PRIMARY KEY (item_id),
+    INDEX idx_timers_meeting (meeting_id)
+);
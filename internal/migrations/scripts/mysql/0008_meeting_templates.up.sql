@@ -0,0 +1,16 @@
+-- Templates new meetings are made from
+
+CREATE TABLE MeetingTemplates (
+    id CHAR(36) NOT NULL,
+    org_id CHAR(36) NOT NULL,
+    name VARCHAR(200) NOT NULL,
+    conference VARCHAR(200) NULL,
+    meeting VARCHAR(200) NOT NULL,
+    duration INT,
+    items JSON NOT NULL,
+    deleted_dt DATETIME NULL,
+    inserted_dt DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_dt DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    PRIMARY KEY (id),
+    INDEX idx_meeting_templates_org (org_id)
+);
@@ -0,0 +1,2 @@
+DROP TABLE ScheduledMeetings;
+DROP TABLE Schedules;
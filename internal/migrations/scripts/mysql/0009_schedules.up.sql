@@ -0,0 +1,26 @@
+-- Recurring schedules of meetings made from a template, and the meetings made for each occurrence
+
+CREATE TABLE Schedules (
+    id CHAR(36) NOT NULL,
+    org_id CHAR(36) NOT NULL,
+    template_id CHAR(36) NOT NULL,
+    name VARCHAR(200) NOT NULL,
+    rrule VARCHAR(500) NOT NULL,
+    time_zone VARCHAR(64) NOT NULL,
+    start_dt DATETIME NOT NULL,
+    exceptions JSON NOT NULL,
+    lead_days INT NOT NULL DEFAULT 14,
+    deleted_dt DATETIME NULL,
+    inserted_dt DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_dt DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    PRIMARY KEY (id),
+    INDEX idx_schedules_org (org_id)
+);
+
+CREATE TABLE ScheduledMeetings (
+    schedule_id CHAR(36) NOT NULL,
+    occurrence_dt DATETIME NOT NULL,
+    meeting_id CHAR(36) NOT NULL,
+    inserted_dt DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (schedule_id, occurrence_dt)
+);
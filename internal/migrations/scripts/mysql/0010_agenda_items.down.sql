@@ -0,0 +1,103 @@
+-- Moves agenda items back into the per-type item tables. Each type's details become the columns of its table.
+
+CREATE TABLE BlankItems (
+    id CHAR(36) NOT NULL,
+    meeting_id CHAR(36) NOT NULL,
+    meeting_role VARCHAR(50) NOT NULL,
+    item_type VARCHAR(20) NOT NULL DEFAULT 'blank',
+    item_order INT NOT NULL,
+    deleted_dt DATETIME NULL,
+    inserted_dt DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_dt DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    PRIMARY KEY (id)
+);
+
+CREATE TABLE LyricsItems (
+    id CHAR(36) NOT NULL,
+    meeting_id CHAR(36) NOT NULL,
+    meeting_role VARCHAR(50) NOT NULL,
+    item_type VARCHAR(20) NOT NULL DEFAULT 'blank',
+    item_order INT NOT NULL,
+    hymn_id CHAR(36) NULL,
+    show_translation TINYINT(0) NOT NULL DEFAULT 0,
+    deleted_dt DATETIME NULL,
+    inserted_dt DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_dt DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    PRIMARY KEY (id)
+);
+
+CREATE TABLE MessageItems (
+    id CHAR(36) NOT NULL,
+    meeting_id CHAR(36) NOT NULL,
+    meeting_role VARCHAR(50) NOT NULL,
+    item_type VARCHAR(20) NOT NULL DEFAULT 'blank',
+    item_order INT NOT NULL,
+    primary_text CHAR(200) NOT NULL,
+    secondary_text CHAR(200) NULL,
+    deleted_dt DATETIME NULL,
+    inserted_dt DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_dt DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    PRIMARY KEY (id)
+);
+
+CREATE TABLE SpeakerItems (
+    id CHAR(36) NOT NULL,
+    meeting_id CHAR(36) NOT NULL,
+    meeting_role VARCHAR(50) NOT NULL,
+    item_type VARCHAR(20) NOT NULL DEFAULT 'blank',
+    item_order INT NOT NULL,
+    speaker_name CHAR(200) NOT NULL,
+    title CHAR(200) NULL,
+    expected_duration INT NULL,
+    deleted_dt DATETIME NULL,
+    inserted_dt DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_dt DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    PRIMARY KEY (id)
+);
+
+CREATE TABLE TimerItems (
+    id CHAR(36) NOT NULL,
+    meeting_id CHAR(36) NOT NULL,
+    meeting_role VARCHAR(50) NOT NULL,
+    item_type VARCHAR(20) NOT NULL DEFAULT 'blank',
+    item_order INT NOT NULL,
+    show_meeting_details TINYINT(1) NOT NULL DEFAULT 0,
+    deleted_dt DATETIME NULL,
+    inserted_dt DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_dt DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    PRIMARY KEY (id)
+);
+
+INSERT INTO BlankItems (id, meeting_id, meeting_role, item_type, item_order, deleted_dt, inserted_dt, updated_dt)
+SELECT id, meeting_id, meeting_role, item_type, item_order, deleted_dt, inserted_dt, updated_dt
+FROM AgendaItems WHERE item_type = 'blank';
+
+INSERT INTO LyricsItems (id, meeting_id, meeting_role, item_type, item_order, hymn_id, show_translation, deleted_dt, inserted_dt, updated_dt)
+SELECT id, meeting_id, meeting_role, item_type, item_order,
+    NULLIF(JSON_UNQUOTE(JSON_EXTRACT(details, '$.hymn_id')), ''),
+    COALESCE(JSON_EXTRACT(details, '$.show_translation') = CAST('true' AS JSON), 0),
+    deleted_dt, inserted_dt, updated_dt
+FROM AgendaItems WHERE item_type = 'lyrics';
+
+INSERT INTO MessageItems (id, meeting_id, meeting_role, item_type, item_order, primary_text, secondary_text, deleted_dt, inserted_dt, updated_dt)
+SELECT id, meeting_id, meeting_role, item_type, item_order,
+    COALESCE(JSON_UNQUOTE(JSON_EXTRACT(details, '$.primary_text')), ''),
+    CASE WHEN JSON_EXTRACT(details, '$.secondary_text') = CAST('null' AS JSON) THEN NULL ELSE JSON_UNQUOTE(JSON_EXTRACT(details, '$.secondary_text')) END,
+    deleted_dt, inserted_dt, updated_dt
+FROM AgendaItems WHERE item_type = 'message';
+
+INSERT INTO SpeakerItems (id, meeting_id, meeting_role, item_type, item_order, speaker_name, title, expected_duration, deleted_dt, inserted_dt, updated_dt)
+SELECT id, meeting_id, meeting_role, item_type, item_order,
+    COALESCE(JSON_UNQUOTE(JSON_EXTRACT(details, '$.name')), ''),
+    CASE WHEN JSON_EXTRACT(details, '$.title') = CAST('null' AS JSON) THEN NULL ELSE JSON_UNQUOTE(JSON_EXTRACT(details, '$.title')) END,
+    CASE WHEN JSON_EXTRACT(details, '$.expected_duration') = CAST('null' AS JSON) THEN NULL ELSE CAST(JSON_EXTRACT(details, '$.expected_duration') AS SIGNED) END,
+    deleted_dt, inserted_dt, updated_dt
+FROM AgendaItems WHERE item_type = 'speaker';
+
+INSERT INTO TimerItems (id, meeting_id, meeting_role, item_type, item_order, show_meeting_details, deleted_dt, inserted_dt, updated_dt)
+SELECT id, meeting_id, meeting_role, item_type, item_order,
+    COALESCE(JSON_EXTRACT(details, '$.show_meeting_details') = CAST('true' AS JSON), 0),
+    deleted_dt, inserted_dt, updated_dt
+FROM AgendaItems WHERE item_type = 'timer';
+
+DROP TABLE AgendaItems;
@@ -0,0 +1,52 @@
+-- Moves agenda items from the per-type item tables into AgendaItems. Each row keeps its ID, meeting, order, role and
+-- timestamps; the columns of its type become the details payload. The type comes from the table, since the old
+-- item_type column defaulted to 'blank' whatever the table. IDs have to be unique across the old tables, or this
+-- fails on the first one that isn't.
+
+CREATE TABLE AgendaItems (
+    id CHAR(36) NOT NULL,
+    meeting_id CHAR(36) NOT NULL,
+    item_type VARCHAR(20) NOT NULL,
+    item_order INT NOT NULL,
+    meeting_role VARCHAR(50) NOT NULL,
+    details JSON NOT NULL,
+    deleted_dt DATETIME NULL,
+    inserted_dt DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_dt DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    PRIMARY KEY (id),
+    INDEX idx_agenda_items_meeting (meeting_id)
+);
+
+INSERT INTO AgendaItems (id, meeting_id, item_type, item_order, meeting_role, details, deleted_dt, inserted_dt, updated_dt)
+SELECT id, meeting_id, 'blank', item_order, meeting_role, JSON_OBJECT(), deleted_dt, inserted_dt, updated_dt
+FROM BlankItems;
+
+INSERT INTO AgendaItems (id, meeting_id, item_type, item_order, meeting_role, details, deleted_dt, inserted_dt, updated_dt)
+SELECT id, meeting_id, 'lyrics', item_order, meeting_role,
+    JSON_OBJECT('hymn_id', COALESCE(hymn_id, ''), 'show_translation', IF(show_translation, CAST('true' AS JSON), CAST('false' AS JSON))),
+    deleted_dt, inserted_dt, updated_dt
+FROM LyricsItems;
+
+INSERT INTO AgendaItems (id, meeting_id, item_type, item_order, meeting_role, details, deleted_dt, inserted_dt, updated_dt)
+SELECT id, meeting_id, 'message', item_order, meeting_role,
+    JSON_OBJECT('primary_text', primary_text, 'secondary_text', secondary_text),
+    deleted_dt, inserted_dt, updated_dt
+FROM MessageItems;
+
+INSERT INTO AgendaItems (id, meeting_id, item_type, item_order, meeting_role, details, deleted_dt, inserted_dt, updated_dt)
+SELECT id, meeting_id, 'speaker', item_order, meeting_role,
+    JSON_OBJECT('name', speaker_name, 'title', title, 'expected_duration', expected_duration),
+    deleted_dt, inserted_dt, updated_dt
+FROM SpeakerItems;
+
+INSERT INTO AgendaItems (id, meeting_id, item_type, item_order, meeting_role, details, deleted_dt, inserted_dt, updated_dt)
+SELECT id, meeting_id, 'timer', item_order, meeting_role,
+    JSON_OBJECT('show_meeting_details', IF(show_meeting_details, CAST('true' AS JSON), CAST('false' AS JSON))),
+    deleted_dt, inserted_dt, updated_dt
+FROM TimerItems;
+
+DROP TABLE BlankItems;
+DROP TABLE LyricsItems;
+DROP TABLE MessageItems;
+DROP TABLE SpeakerItems;
+DROP TABLE TimerItems;
@@ -0,0 +1,17 @@
+-- Invitations to join an organization, sent by email and accepted with a signed link
+
+CREATE TABLE Invitations (
+    id CHAR(36) NOT NULL,
+    org_id CHAR(36) NOT NULL,
+    email VARCHAR(60) NOT NULL,
+    role VARCHAR(20) NOT NULL DEFAULT 'viewer',
+    invited_by CHAR(36) NOT NULL,
+    expires_dt DATETIME NOT NULL,
+    accepted_by CHAR(36) NULL,
+    accepted_dt DATETIME NULL,
+    deleted_dt DATETIME NULL,
+    inserted_dt DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_dt DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    PRIMARY KEY (id),
+    INDEX idx_invitations_org (org_id)
+);
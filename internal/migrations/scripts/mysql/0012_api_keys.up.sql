@@ -0,0 +1,20 @@
+-- API keys for devices that can't sign in, like overlay machines and control surfaces. Each key acts as its own
+-- member of one org, and only its hash is stored.
+
+CREATE TABLE ApiKeys (
+    id CHAR(36) NOT NULL,
+    org_id CHAR(36) NOT NULL,
+    user_id CHAR(36) NOT NULL,
+    name VARCHAR(50) NOT NULL,
+    scope VARCHAR(20) NOT NULL,
+    prefix VARCHAR(12) NOT NULL,
+    key_hash CHAR(64) NOT NULL,
+    created_by CHAR(36) NOT NULL,
+    last_used_dt DATETIME NULL,
+    deleted_dt DATETIME NULL,
+    inserted_dt DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_dt DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    PRIMARY KEY (id),
+    UNIQUE KEY unique_key_hash (key_hash),
+    INDEX idx_api_keys_org (org_id)
+);
@@ -0,0 +1,17 @@
+-- Audit log of every change made through the service: who made it, in which org and request, and what it changed.
+-- Records are only ever added; id keeps them in the order they were made.
+
+CREATE TABLE AuditLog (
+    id BIGINT NOT NULL AUTO_INCREMENT,
+    org_id CHAR(36) NULL,
+    actor_id CHAR(36) NULL,
+    entity_type VARCHAR(30) NOT NULL,
+    entity_id VARCHAR(64) NOT NULL,
+    action VARCHAR(10) NOT NULL,
+    diff JSON NOT NULL,
+    request_id VARCHAR(64) NULL,
+    inserted_dt DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (id),
+    INDEX idx_audit_log_org (org_id, id),
+    INDEX idx_audit_log_entity (entity_type, entity_id)
+);
@@ -0,0 +1,13 @@
+-- Drops everything the baseline made
+
+DROP TABLE HymnVerses;
+DROP TABLE Hymns;
+DROP TABLE OrgUsers;
+DROP TABLE Organization;
+DROP TABLE Meetings;
+DROP TABLE Users;
+DROP TABLE TimerItems;
+DROP TABLE SpeakerItems;
+DROP TABLE MessageItems;
+DROP TABLE LyricsItems;
+DROP TABLE BlankItems;
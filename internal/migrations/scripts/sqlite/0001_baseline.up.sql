@@ -0,0 +1,295 @@
+-- The schema data/setup.sql and data/hymns.sql made before migrations existed, for SQLite. SQLite has no ON UPDATE,
+-- so triggers keep updated_dt current, and indexes are created separately. SQLite doesn't limit VARCHAR lengths, so
+-- checks reject the values MySQL would.
+
+CREATE TABLE BlankItems (
+    id CHAR(36) NOT NULL,
+    meeting_id CHAR(36) NOT NULL,
+    meeting_role VARCHAR(50) NOT NULL CHECK (length(meeting_role) <= 50),
+    item_type VARCHAR(20) NOT NULL DEFAULT 'blank' CHECK (length(item_type) <= 20),
+    item_order INT NOT NULL,
+    deleted_dt DATETIME NULL,
+    inserted_dt DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_dt DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (id)
+);
+CREATE TRIGGER BlankItems_updated_dt AFTER UPDATE ON BlankItems FOR EACH ROW WHEN NEW.updated_dt = OLD.updated_dt
+BEGIN
+    UPDATE BlankItems SET updated_dt = CURRENT_TIMESTAMP WHERE rowid = NEW.rowid;
+END;
+
+CREATE TABLE LyricsItems (
+    id CHAR(36) NOT NULL,
+    meeting_id CHAR(36) NOT NULL,
+    meeting_role VARCHAR(50) NOT NULL CHECK (length(meeting_role) <= 50),
+    item_type VARCHAR(20) NOT NULL DEFAULT 'blank' CHECK (length(item_type) <= 20),
+    item_order INT NOT NULL,
+    hymn_id CHAR(36) NULL,
+    show_translation TINYINT(1) NOT NULL DEFAULT 0,
+    deleted_dt DATETIME NULL,
+    inserted_dt DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_dt DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (id)
+);
+CREATE TRIGGER LyricsItems_updated_dt AFTER UPDATE ON LyricsItems FOR EACH ROW WHEN NEW.updated_dt = OLD.updated_dt
+BEGIN
+    UPDATE LyricsItems SET updated_dt = CURRENT_TIMESTAMP WHERE rowid = NEW.rowid;
+END;
+
+CREATE TABLE MessageItems (
+    id CHAR(36) NOT NULL,
+    meeting_id CHAR(36) NOT NULL,
+    meeting_role VARCHAR(50) NOT NULL CHECK (length(meeting_role) <= 50),
+    item_type VARCHAR(20) NOT NULL DEFAULT 'blank' CHECK (length(item_type) <= 20),
+    item_order INT NOT NULL,
+    primary_text CHAR(200) NOT NULL,
+    secondary_text CHAR(200) NULL,
+    deleted_dt DATETIME NULL,
+    inserted_dt DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_dt DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (id)
+);
+CREATE TRIGGER MessageItems_updated_dt AFTER UPDATE ON MessageItems FOR EACH ROW WHEN NEW.updated_dt = OLD.updated_dt
+BEGIN
+    UPDATE MessageItems SET updated_dt = CURRENT_TIMESTAMP WHERE rowid = NEW.rowid;
+END;
+
+CREATE TABLE SpeakerItems (
+    id CHAR(36) NOT NULL,
+    meeting_id CHAR(36) NOT NULL,
+    meeting_role VARCHAR(50) NOT NULL CHECK (length(meeting_role) <= 50),
+    item_type VARCHAR(20) NOT NULL DEFAULT 'blank' CHECK (length(item_type) <= 20),
+    item_order INT NOT NULL,
+    speaker_name CHAR(200) NOT NULL,
+    title CHAR(200) NULL,
+    expected_duration INT NULL,
+    deleted_dt DATETIME NULL,
+    inserted_dt DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_dt DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (id)
+);
+CREATE TRIGGER SpeakerItems_updated_dt AFTER UPDATE ON SpeakerItems FOR EACH ROW WHEN NEW.updated_dt = OLD.updated_dt
+BEGIN
+    UPDATE SpeakerItems SET updated_dt = CURRENT_TIMESTAMP WHERE rowid = NEW.rowid;
+END;
+
+CREATE TABLE TimerItems (
+    id CHAR(36) NOT NULL,
+    meeting_id CHAR(36) NOT NULL,
+    meeting_role VARCHAR(50) NOT NULL CHECK (length(meeting_role) <= 50),
+    item_type VARCHAR(20) NOT NULL DEFAULT 'blank' CHECK (length(item_type) <= 20),
+    item_order INT NOT NULL,
+    show_meeting_details TINYINT(1) NOT NULL DEFAULT 0,
+    deleted_dt DATETIME NULL,
+    inserted_dt DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_dt DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (id)
+);
+CREATE TRIGGER TimerItems_updated_dt AFTER UPDATE ON TimerItems FOR EACH ROW WHEN NEW.updated_dt = OLD.updated_dt
+BEGIN
+    UPDATE TimerItems SET updated_dt = CURRENT_TIMESTAMP WHERE rowid = NEW.rowid;
+END;
+
+CREATE TABLE Users (
+    id CHAR(36) NOT NULL,
+    social_id VARCHAR(60) NULL CHECK (length(social_id) <= 60),
+    email VARCHAR(60) NOT NULL CHECK (length(email) <= 60),
+    first_name VARCHAR(50) NULL CHECK (length(first_name) <= 50),
+    full_name VARCHAR(50) NULL CHECK (length(full_name) <= 50),
+    last_name VARCHAR(50) NULL CHECK (length(last_name) <= 50),
+    photo_url VARCHAR(2000) NULL CHECK (length(photo_url) <= 2000),
+    deleted_dt DATETIME NULL,
+    inserted_dt DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_dt DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (id),
+    UNIQUE (email),
+    UNIQUE (social_id)
+);
+CREATE TRIGGER Users_updated_dt AFTER UPDATE ON Users FOR EACH ROW WHEN NEW.updated_dt = OLD.updated_dt
+BEGIN
+    UPDATE Users SET updated_dt = CURRENT_TIMESTAMP WHERE rowid = NEW.rowid;
+END;
+
+CREATE TABLE Meetings (
+    id CHAR(36) NOT NULL,
+    org_id CHAR(36) NOT NULL,
+    conference VARCHAR(200) NULL CHECK (length(conference) <= 200),
+    meeting VARCHAR(200) NOT NULL CHECK (length(meeting) <= 200),
+    meeting_date DATETIME NOT NULL,
+    duration INT,
+    deleted_dt DATETIME NULL,
+    inserted_dt DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_dt DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (id)
+);
+CREATE TRIGGER Meetings_updated_dt AFTER UPDATE ON Meetings FOR EACH ROW WHEN NEW.updated_dt = OLD.updated_dt
+BEGIN
+    UPDATE Meetings SET updated_dt = CURRENT_TIMESTAMP WHERE rowid = NEW.rowid;
+END;
+
+CREATE TABLE Organization (
+    id CHAR(36) NOT NULL,
+    name CHAR(200) NOT NULL,
+    deleted_dt DATETIME NULL,
+    inserted_dt DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_dt DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (id),
+    UNIQUE (name)
+);
+CREATE TRIGGER Organization_updated_dt AFTER UPDATE ON Organization FOR EACH ROW WHEN NEW.updated_dt = OLD.updated_dt
+BEGIN
+    UPDATE Organization SET updated_dt = CURRENT_TIMESTAMP WHERE rowid = NEW.rowid;
+END;
+
+CREATE TABLE OrgUsers (
+    org_id CHAR(36) NOT NULL,
+    user_id CHAR(36) NOT NULL,
+    deleted_dt DATETIME NULL,
+    inserted_dt DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (org_id, user_id, deleted_dt)
+);
+
+CREATE TABLE Hymns (
+   id CHAR(36) NOT NULL,
+   page INT NOT NULL,
+   language CHAR(3) NOT NULL,
+   name CHAR(100) NOT NULL,
+   translation_id CHAR(36) NULL,
+   deleted_dt DATETIME NULL,
+   inserted_dt DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+   updated_dt DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+   PRIMARY KEY (id)
+);
+CREATE TRIGGER Hymns_updated_dt AFTER UPDATE ON Hymns FOR EACH ROW WHEN NEW.updated_dt = OLD.updated_dt
+BEGIN
+    UPDATE Hymns SET updated_dt = CURRENT_TIMESTAMP WHERE rowid = NEW.rowid;
+END;
+
+CREATE TABLE HymnVerses (
+    hymn_id CHAR(36) NOT NULL,
+    verse_number INT NOT NULL,
+    verse_lines TEXT,
+    optional TINYINT(1) NOT NULL DEFAULT 0,
+    deleted_dt DATETIME NULL,
+    inserted_dt DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_dt DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (hymn_id, verse_number, deleted_dt)
+);
+CREATE TRIGGER HymnVerses_updated_dt AFTER UPDATE ON HymnVerses FOR EACH ROW WHEN NEW.updated_dt = OLD.updated_dt
+BEGIN
+    UPDATE HymnVerses SET updated_dt = CURRENT_TIMESTAMP WHERE rowid = NEW.rowid;
+END;
+
+-- The hymn catalog from data/hymns.sql
+INSERT INTO Hymns (id, language, page, name) VALUES ('fd5905bb-35a4-4a2f-9e29-041f58f3d1a9', 'eng', 243, 'Let Us All Press On');
+INSERT INTO Hymns (id, language, page, name) VALUES ('3549ebe2-b6cc-4433-a0dd-365ec4113d38', 'spa', 158, 'Trabajemos hoy en la obra');
+INSERT INTO Hymns (id, language, page, name) VALUES ('bb125745-55eb-448c-b255-dac7ef6444cc', 'eng', 66, 'Rejoice, the Lord is King!');
+INSERT INTO Hymns (id, language, page, name) VALUES ('339dee9c-e944-4eb1-bdb8-bf7e1b9c411f', 'eng', 3, 'Now Let Us Rejoice');
+INSERT INTO Hymns (id, language, page, name) VALUES ('dbb6cabf-9466-46f2-9cfd-f0e06aa62869', 'eng', 6, 'Redeemer of Israel');
+INSERT INTO Hymns (id, language, page, name) VALUES ('a4d02b9f-bef8-47db-8765-4e8cee76bb64', 'spa', 5, 'Redentor de Israel');
+UPDATE Hymns SET translation_id = 'a4d02b9f-bef8-47db-8765-4e8cee76bb64' WHERE id = 'dbb6cabf-9466-46f2-9cfd-f0e06aa62869';
+UPDATE Hymns SET translation_id = '3549ebe2-b6cc-4433-a0dd-365ec4113d38' WHERE id = 'fd5905bb-35a4-4a2f-9e29-041f58f3d1a9';
+INSERT INTO HymnVerses (hymn_id, verse_number, verse_lines)
+VALUES ('dbb6cabf-9466-46f2-9cfd-f0e06aa62869', 1, 'Redeemer of Israel, our only delight,
+On whom for a blessing we call.
+Our Shadow by day and our pillar by night,
+Our King, our Deliv’rer, our all');
+INSERT INTO HymnVerses (hymn_id, verse_number, verse_lines)
+VALUES ('dbb6cabf-9466-46f2-9cfd-f0e06aa62869', 2, 'We know he is coming to gather his sheep
+And lead them to Zion in love,
+For why in the valley Of death should they weep
+Or in the lone wilderness rove?');
+INSERT INTO HymnVerses (hymn_id, verse_number, verse_lines)
+VALUES ('dbb6cabf-9466-46f2-9cfd-f0e06aa62869', 3, 'How long we have wandered as strangers in sin
+And cried in the desert for thee!
+Our foes have rejoiced When our sorrows they’ve seen,
+But Israel will shortly be free.');
+INSERT INTO HymnVerses (hymn_id, verse_number, verse_lines)
+VALUES ('dbb6cabf-9466-46f2-9cfd-f0e06aa62869', 4, 'As children of Zion, good tidings for us.
+The tokens already appear.
+Fear not, and be just, For the kingdom is ours.
+The hour of redemption is near.');
+INSERT INTO HymnVerses (hymn_id, verse_number, verse_lines, optional)
+VALUES ('dbb6cabf-9466-46f2-9cfd-f0e06aa62869', 5, 'Restore, my dear Savior, the light of thy face;
+Thy soul-cheering comfort impart;
+And let the sweet longing For thy holy place
+Bring hope to my desolate heart.', 1);
+INSERT INTO HymnVerses (hymn_id, verse_number, verse_lines, optional)
+VALUES ('dbb6cabf-9466-46f2-9cfd-f0e06aa62869', 6, 'He looks! and ten thousands Of angels rejoice,
+And myriads wait for his word;
+He speaks! and eternity, Filled with his voice,
+Re-echoes the praise of the Lord', 1);
+INSERT INTO HymnVerses (hymn_id, verse_number, verse_lines)
+VALUES ('a4d02b9f-bef8-47db-8765-4e8cee76bb64', 1, 'Oh Dios de Israel, te rendimos loor
+a ti, nuestro gran Redentor,
+de día la sombra, de noche la luz,
+del mundo eres Rey y Señor.');
+INSERT INTO HymnVerses (hymn_id, verse_number, verse_lines)
+VALUES ('a4d02b9f-bef8-47db-8765-4e8cee76bb64', 2, 'Sabemos que vienes tu grey a juntar,
+la cual has de guiar a Sión.
+En valle de muerte no nos dejarás,
+ni en la vasta desolación.');
+INSERT INTO HymnVerses (hymn_id, verse_number, verse_lines)
+VALUES ('a4d02b9f-bef8-47db-8765-4e8cee76bb64', 3, 'Hemos errado mucho, clamando a ti,
+extraños, en yermos del mal.
+Los malos se gozan de nuestro pesar,
+mas libre Israel quedará.');
+INSERT INTO HymnVerses (hymn_id, verse_number, verse_lines)
+VALUES ('a4d02b9f-bef8-47db-8765-4e8cee76bb64', 4, 'Nos regocijamos, oh hijos de Dios;
+las señas presentes están.
+Seamos valientes y fieles al Rey;
+se vislumbra la gran redención.');
+INSERT INTO HymnVerses (hymn_id, verse_number, verse_lines)
+VALUES ('fd5905bb-35a4-4a2f-9e29-041f58f3d1a9', 1, 'Let us all press on in the work of the Lord,
+That when life is o’er we may gain a reward;
+In the fight for right let us wield a sword,
+The mighty sword of truth.
+Fear not, though the enemy deride;
+Courage, for the Lord is on our side.
+We will heed not what the wicked may say,
+But the Lord alone we will obey.');
+INSERT INTO HymnVerses (hymn_id, verse_number, verse_lines)
+VALUES ('fd5905bb-35a4-4a2f-9e29-041f58f3d1a9', 2, 'We will not retreat, though our numbers may be few
+When compared with the opposite host in view;
+But an unseen pow’r will aid me and you
+In the glorious cause of truth.
+Fear not, though the enemy deride;
+Courage, for the Lord is on our side.
+We will heed not what the wicked may say,
+But the Lord alone we will obey.');
+INSERT INTO HymnVerses (hymn_id, verse_number, verse_lines)
+VALUES ('fd5905bb-35a4-4a2f-9e29-041f58f3d1a9', 3, 'If we do what’s right we have no need to fear,
+For the Lord, our helper, will ever be near;
+In the days of trial his Saints he will cheer,
+And prosper the cause of truth.
+Fear not, though the enemy deride;
+Courage, for the Lord is on our side.
+We will heed not what the wicked may say,
+But the Lord alone we will obey.');
+INSERT INTO HymnVerses (hymn_id, verse_number, verse_lines)
+VALUES ('3549ebe2-b6cc-4433-a0dd-365ec4113d38', 1, 'Trabajemos hoy en la obra del Señor,
+y ganemos así un hogar celestial.
+En la lucha cruel empuñemos, sin temor,
+la espada de la verdad.
+Firmes y valientes en la lid,
+todo enemigo confundid.
+Lucharemos a vencer el error;
+seguiremos sólo al Señor.');
+INSERT INTO HymnVerses (hymn_id, verse_number, verse_lines)
+VALUES ('3549ebe2-b6cc-4433-a0dd-365ec4113d38', 2, 'Nuestras filas chicas jamás desmayarán,
+a pesar de las huestes que contenderán,
+y del cielo, Cristo poder nos dará
+en defensa de la verdad.
+Firmes y valientes en la lid,
+todo enemigo confundid.
+Lucharemos a vencer el error;
+seguiremos sólo al Señor.');
+INSERT INTO HymnVerses (hymn_id, verse_number, verse_lines)
+VALUES ('3549ebe2-b6cc-4433-a0dd-365ec4113d38', 3, 'Toda obra buena aleja el temor,
+pues tenemos en Cristo un gran Defensor.
+En las duras pruebas nos da el valor
+de luchar por la verdad.
+Firmes y valientes en la lid,
+todo enemigo confundid.
+Lucharemos a vencer el error;
+seguiremos sólo al Señor.');
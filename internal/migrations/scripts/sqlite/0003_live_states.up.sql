@@ -0,0 +1,18 @@
+-- The program and preview state of each meeting being run live
+
+CREATE TABLE LiveStates (
+    meeting_id CHAR(36) NOT NULL,
+    program_item_id CHAR(36) NULL,
+    preview_item_id CHAR(36) NULL,
+    visible TINYINT(1) NOT NULL DEFAULT 0,
+    program_changed_dt DATETIME NULL,
+    preview_changed_dt DATETIME NULL,
+    visible_changed_dt DATETIME NULL,
+    inserted_dt DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_dt DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (meeting_id)
+);
+CREATE TRIGGER LiveStates_updated_dt AFTER UPDATE ON LiveStates FOR EACH ROW WHEN NEW.updated_dt = OLD.updated_dt
+BEGIN
+    UPDATE LiveStates SET updated_dt = CURRENT_TIMESTAMP WHERE rowid = NEW.rowid;
+END;
@@ -0,0 +1,5 @@
+ALTER TABLE LiveStates DROP COLUMN timer_elapsed_ms;
+ALTER TABLE LiveStates DROP COLUMN timer_started_dt;
+ALTER TABLE LiveStates DROP COLUMN verse_count;
+ALTER TABLE LiveStates DROP COLUMN verse_index;
+ALTER TABLE LiveStates DROP COLUMN version;
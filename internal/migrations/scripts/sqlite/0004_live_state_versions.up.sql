@@ -0,0 +1,7 @@
+-- Versions live state for the control channel, and adds the lyrics verse and a timer to it
+
+ALTER TABLE LiveStates ADD COLUMN version BIGINT NOT NULL DEFAULT 0;
+ALTER TABLE LiveStates ADD COLUMN verse_index INT NOT NULL DEFAULT 0;
+ALTER TABLE LiveStates ADD COLUMN verse_count INT NOT NULL DEFAULT 0;
+ALTER TABLE LiveStates ADD COLUMN timer_started_dt DATETIME NULL;
+ALTER TABLE LiveStates ADD COLUMN timer_elapsed_ms BIGINT NOT NULL DEFAULT 0;
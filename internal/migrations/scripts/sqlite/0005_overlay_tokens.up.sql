@@ -0,0 +1,15 @@
+-- Tokens that let a browser source show a meeting's overlay without signing in
+
+CREATE TABLE OverlayTokens (
+    token VARCHAR(64) NOT NULL CHECK (length(token) <= 64),
+    meeting_id CHAR(36) NOT NULL,
+    deleted_dt DATETIME NULL,
+    inserted_dt DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_dt DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (token)
+);
+CREATE INDEX idx_overlay_tokens_meeting ON OverlayTokens (meeting_id);
+CREATE TRIGGER OverlayTokens_updated_dt AFTER UPDATE ON OverlayTokens FOR EACH ROW WHEN NEW.updated_dt = OLD.updated_dt
+BEGIN
+    UPDATE OverlayTokens SET updated_dt = CURRENT_TIMESTAMP WHERE rowid = NEW.rowid;
+END;
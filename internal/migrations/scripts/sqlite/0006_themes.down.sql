@@ -0,0 +1,2 @@
+DROP TABLE MeetingThemes;
+DROP TABLE OrgThemes;
@@ -0,0 +1,25 @@
+-- Org themes, and the overrides of a meeting's theme
+
+CREATE TABLE OrgThemes (
+    org_id CHAR(36) NOT NULL,
+    theme JSON NOT NULL,
+    inserted_dt DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_dt DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (org_id)
+);
+CREATE TRIGGER OrgThemes_updated_dt AFTER UPDATE ON OrgThemes FOR EACH ROW WHEN NEW.updated_dt = OLD.updated_dt
+BEGIN
+    UPDATE OrgThemes SET updated_dt = CURRENT_TIMESTAMP WHERE rowid = NEW.rowid;
+END;
+
+CREATE TABLE MeetingThemes (
+    meeting_id CHAR(36) NOT NULL,
+    overrides JSON NOT NULL,
+    inserted_dt DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_dt DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (meeting_id)
+);
+CREATE TRIGGER MeetingThemes_updated_dt AFTER UPDATE ON MeetingThemes FOR EACH ROW WHEN NEW.updated_dt = OLD.updated_dt
+BEGIN
+    UPDATE MeetingThemes SET updated_dt = CURRENT_TIMESTAMP WHERE rowid = NEW.rowid;
+END;
@@ -0,0 +1,4 @@
+DROP TABLE Timers;
+
+ALTER TABLE LiveStates ADD COLUMN timer_started_dt DATETIME NULL;
+ALTER TABLE LiveStates ADD COLUMN timer_elapsed_ms BIGINT NOT NULL DEFAULT 0;
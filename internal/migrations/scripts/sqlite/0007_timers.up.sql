@@ -0,0 +1,24 @@
+-- Moves timers out of live state into a row per timer item. Running live state timers aren't carried over.
+
+ALTER TABLE LiveStates DROP COLUMN timer_started_dt;
+ALTER TABLE LiveStates DROP COLUMN timer_elapsed_ms;
+
+CREATE TABLE Timers (
+    item_id CHAR(36) NOT NULL,
+    meeting_id CHAR(36) NOT NULL,
+    version BIGINT NOT NULL DEFAULT 0,
+    kind VARCHAR(16) NOT NULL CHECK (length(kind) <= 16),
+    duration_ms BIGINT NOT NULL DEFAULT 0,
+    target_dt DATETIME NULL,
+    added_ms BIGINT NOT NULL DEFAULT 0,
+    elapsed_ms BIGINT NOT NULL DEFAULT 0,
+    started_dt DATETIME NULL,
+    inserted_dt DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_dt DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (item_id)
+);
+CREATE INDEX idx_timers_meeting ON Timers (meeting_id);
+CREATE TRIGGER Timers_updated_dt AFTER UPDATE ON Timers FOR EACH ROW WHEN NEW.updated_dt = OLD.updated_dt
+BEGIN
+    UPDATE Timers SET updated_dt = CURRENT_TIMESTAMP WHERE rowid = NEW.rowid;
+END;
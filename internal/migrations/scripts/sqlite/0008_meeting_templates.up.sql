@@ -0,0 +1,20 @@
+-- Templates new meetings are made from
+
+CREATE TABLE MeetingTemplates (
+    id CHAR(36) NOT NULL,
+    org_id CHAR(36) NOT NULL,
+    name VARCHAR(200) NOT NULL CHECK (length(name) <= 200),
+    conference VARCHAR(200) NULL CHECK (length(conference) <= 200),
+    meeting VARCHAR(200) NOT NULL CHECK (length(meeting) <= 200),
+    duration INT,
+    items JSON NOT NULL,
+    deleted_dt DATETIME NULL,
+    inserted_dt DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_dt DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (id)
+);
+CREATE INDEX idx_meeting_templates_org ON MeetingTemplates (org_id);
+CREATE TRIGGER MeetingTemplates_updated_dt AFTER UPDATE ON MeetingTemplates FOR EACH ROW WHEN NEW.updated_dt = OLD.updated_dt
+BEGIN
+    UPDATE MeetingTemplates SET updated_dt = CURRENT_TIMESTAMP WHERE rowid = NEW.rowid;
+END;
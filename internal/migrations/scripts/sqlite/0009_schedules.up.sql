@@ -0,0 +1,30 @@
+-- Recurring schedules of meetings made from a template, and the meetings made for each occurrence
+
+CREATE TABLE Schedules (
+    id CHAR(36) NOT NULL,
+    org_id CHAR(36) NOT NULL,
+    template_id CHAR(36) NOT NULL,
+    name VARCHAR(200) NOT NULL CHECK (length(name) <= 200),
+    rrule VARCHAR(500) NOT NULL CHECK (length(rrule) <= 500),
+    time_zone VARCHAR(64) NOT NULL CHECK (length(time_zone) <= 64),
+    start_dt DATETIME NOT NULL,
+    exceptions JSON NOT NULL,
+    lead_days INT NOT NULL DEFAULT 14,
+    deleted_dt DATETIME NULL,
+    inserted_dt DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_dt DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (id)
+);
+CREATE INDEX idx_schedules_org ON Schedules (org_id);
+CREATE TRIGGER Schedules_updated_dt AFTER UPDATE ON Schedules FOR EACH ROW WHEN NEW.updated_dt = OLD.updated_dt
+BEGIN
+    UPDATE Schedules SET updated_dt = CURRENT_TIMESTAMP WHERE rowid = NEW.rowid;
+END;
+
+CREATE TABLE ScheduledMeetings (
+    schedule_id CHAR(36) NOT NULL,
+    occurrence_dt DATETIME NOT NULL,
+    meeting_id CHAR(36) NOT NULL,
+    inserted_dt DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (schedule_id, occurrence_dt)
+);
@@ -0,0 +1,123 @@
+-- Moves agenda items back into the per-type item tables. Each type's details become the columns of its table.
+
+CREATE TABLE BlankItems (
+    id CHAR(36) NOT NULL,
+    meeting_id CHAR(36) NOT NULL,
+    meeting_role VARCHAR(50) NOT NULL CHECK (length(meeting_role) <= 50),
+    item_type VARCHAR(20) NOT NULL DEFAULT 'blank' CHECK (length(item_type) <= 20),
+    item_order INT NOT NULL,
+    deleted_dt DATETIME NULL,
+    inserted_dt DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_dt DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (id)
+);
+CREATE TRIGGER BlankItems_updated_dt AFTER UPDATE ON BlankItems FOR EACH ROW WHEN NEW.updated_dt = OLD.updated_dt
+BEGIN
+    UPDATE BlankItems SET updated_dt = CURRENT_TIMESTAMP WHERE rowid = NEW.rowid;
+END;
+
+CREATE TABLE LyricsItems (
+    id CHAR(36) NOT NULL,
+    meeting_id CHAR(36) NOT NULL,
+    meeting_role VARCHAR(50) NOT NULL CHECK (length(meeting_role) <= 50),
+    item_type VARCHAR(20) NOT NULL DEFAULT 'blank' CHECK (length(item_type) <= 20),
+    item_order INT NOT NULL,
+    hymn_id CHAR(36) NULL,
+    show_translation TINYINT(1) NOT NULL DEFAULT 0,
+    deleted_dt DATETIME NULL,
+    inserted_dt DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_dt DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (id)
+);
+CREATE TRIGGER LyricsItems_updated_dt AFTER UPDATE ON LyricsItems FOR EACH ROW WHEN NEW.updated_dt = OLD.updated_dt
+BEGIN
+    UPDATE LyricsItems SET updated_dt = CURRENT_TIMESTAMP WHERE rowid = NEW.rowid;
+END;
+
+CREATE TABLE MessageItems (
+    id CHAR(36) NOT NULL,
+    meeting_id CHAR(36) NOT NULL,
+    meeting_role VARCHAR(50) NOT NULL CHECK (length(meeting_role) <= 50),
+    item_type VARCHAR(20) NOT NULL DEFAULT 'blank' CHECK (length(item_type) <= 20),
+    item_order INT NOT NULL,
+    primary_text CHAR(200) NOT NULL,
+    secondary_text CHAR(200) NULL,
+    deleted_dt DATETIME NULL,
+    inserted_dt DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_dt DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (id)
+);
+CREATE TRIGGER MessageItems_updated_dt AFTER UPDATE ON MessageItems FOR EACH ROW WHEN NEW.updated_dt = OLD.updated_dt
+BEGIN
+    UPDATE MessageItems SET updated_dt = CURRENT_TIMESTAMP WHERE rowid = NEW.rowid;
+END;
+
+CREATE TABLE SpeakerItems (
+    id CHAR(36) NOT NULL,
+    meeting_id CHAR(36) NOT NULL,
+    meeting_role VARCHAR(50) NOT NULL CHECK (length(meeting_role) <= 50),
+    item_type VARCHAR(20) NOT NULL DEFAULT 'blank' CHECK (length(item_type) <= 20),
+    item_order INT NOT NULL,
+    speaker_name CHAR(200) NOT NULL,
+    title CHAR(200) NULL,
+    expected_duration INT NULL,
+    deleted_dt DATETIME NULL,
+    inserted_dt DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_dt DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (id)
+);
+CREATE TRIGGER SpeakerItems_updated_dt AFTER UPDATE ON SpeakerItems FOR EACH ROW WHEN NEW.updated_dt = OLD.updated_dt
+BEGIN
+    UPDATE SpeakerItems SET updated_dt = CURRENT_TIMESTAMP WHERE rowid = NEW.rowid;
+END;
+
+CREATE TABLE TimerItems (
+    id CHAR(36) NOT NULL,
+    meeting_id CHAR(36) NOT NULL,
+    meeting_role VARCHAR(50) NOT NULL CHECK (length(meeting_role) <= 50),
+    item_type VARCHAR(20) NOT NULL DEFAULT 'blank' CHECK (length(item_type) <= 20),
+    item_order INT NOT NULL,
+    show_meeting_details TINYINT(1) NOT NULL DEFAULT 0,
+    deleted_dt DATETIME NULL,
+    inserted_dt DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_dt DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (id)
+);
+CREATE TRIGGER TimerItems_updated_dt AFTER UPDATE ON TimerItems FOR EACH ROW WHEN NEW.updated_dt = OLD.updated_dt
+BEGIN
+    UPDATE TimerItems SET updated_dt = CURRENT_TIMESTAMP WHERE rowid = NEW.rowid;
+END;
+
+INSERT INTO BlankItems (id, meeting_id, meeting_role, item_type, item_order, deleted_dt, inserted_dt, updated_dt)
+SELECT id, meeting_id, meeting_role, item_type, item_order, deleted_dt, inserted_dt, updated_dt
+FROM AgendaItems WHERE item_type = 'blank';
+
+INSERT INTO LyricsItems (id, meeting_id, meeting_role, item_type, item_order, hymn_id, show_translation, deleted_dt, inserted_dt, updated_dt)
+SELECT id, meeting_id, meeting_role, item_type, item_order,
+    NULLIF(json_extract(details, '$.hymn_id'), ''),
+    COALESCE(json_extract(details, '$.show_translation'), 0),
+    deleted_dt, inserted_dt, updated_dt
+FROM AgendaItems WHERE item_type = 'lyrics';
+
+INSERT INTO MessageItems (id, meeting_id, meeting_role, item_type, item_order, primary_text, secondary_text, deleted_dt, inserted_dt, updated_dt)
+SELECT id, meeting_id, meeting_role, item_type, item_order,
+    COALESCE(json_extract(details, '$.primary_text'), ''),
+    json_extract(details, '$.secondary_text'),
+    deleted_dt, inserted_dt, updated_dt
+FROM AgendaItems WHERE item_type = 'message';
+
+INSERT INTO SpeakerItems (id, meeting_id, meeting_role, item_type, item_order, speaker_name, title, expected_duration, deleted_dt, inserted_dt, updated_dt)
+SELECT id, meeting_id, meeting_role, item_type, item_order,
+    COALESCE(json_extract(details, '$.name'), ''),
+    json_extract(details, '$.title'),
+    json_extract(details, '$.expected_duration'),
+    deleted_dt, inserted_dt, updated_dt
+FROM AgendaItems WHERE item_type = 'speaker';
+
+INSERT INTO TimerItems (id, meeting_id, meeting_role, item_type, item_order, show_meeting_details, deleted_dt, inserted_dt, updated_dt)
+SELECT id, meeting_id, meeting_role, item_type, item_order,
+    COALESCE(json_extract(details, '$.show_meeting_details'), 0),
+    deleted_dt, inserted_dt, updated_dt
+FROM AgendaItems WHERE item_type = 'timer';
+
+DROP TABLE AgendaItems;
@@ -0,0 +1,56 @@
+-- Moves agenda items from the per-type item tables into AgendaItems. Each row keeps its ID, meeting, order, role and
+-- timestamps; the columns of its type become the details payload. The type comes from the table, since the old
+-- item_type column defaulted to 'blank' whatever the table. IDs have to be unique across the old tables, or this
+-- fails on the first one that isn't.
+
+CREATE TABLE AgendaItems (
+    id CHAR(36) NOT NULL,
+    meeting_id CHAR(36) NOT NULL,
+    item_type VARCHAR(20) NOT NULL CHECK (length(item_type) <= 20),
+    item_order INT NOT NULL,
+    meeting_role VARCHAR(50) NOT NULL CHECK (length(meeting_role) <= 50),
+    details JSON NOT NULL,
+    deleted_dt DATETIME NULL,
+    inserted_dt DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_dt DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (id)
+);
+CREATE INDEX idx_agenda_items_meeting ON AgendaItems (meeting_id);
+CREATE TRIGGER AgendaItems_updated_dt AFTER UPDATE ON AgendaItems FOR EACH ROW WHEN NEW.updated_dt = OLD.updated_dt
+BEGIN
+    UPDATE AgendaItems SET updated_dt = CURRENT_TIMESTAMP WHERE rowid = NEW.rowid;
+END;
+
+INSERT INTO AgendaItems (id, meeting_id, item_type, item_order, meeting_role, details, deleted_dt, inserted_dt, updated_dt)
+SELECT id, meeting_id, 'blank', item_order, meeting_role, json_object(), deleted_dt, inserted_dt, updated_dt
+FROM BlankItems;
+
+INSERT INTO AgendaItems (id, meeting_id, item_type, item_order, meeting_role, details, deleted_dt, inserted_dt, updated_dt)
+SELECT id, meeting_id, 'lyrics', item_order, meeting_role,
+    json_object('hymn_id', COALESCE(hymn_id, ''), 'show_translation', iif(show_translation, json('true'), json('false'))),
+    deleted_dt, inserted_dt, updated_dt
+FROM LyricsItems;
+
+INSERT INTO AgendaItems (id, meeting_id, item_type, item_order, meeting_role, details, deleted_dt, inserted_dt, updated_dt)
+SELECT id, meeting_id, 'message', item_order, meeting_role,
+    json_object('primary_text', primary_text, 'secondary_text', secondary_text),
+    deleted_dt, inserted_dt, updated_dt
+FROM MessageItems;
+
+INSERT INTO AgendaItems (id, meeting_id, item_type, item_order, meeting_role, details, deleted_dt, inserted_dt, updated_dt)
+SELECT id, meeting_id, 'speaker', item_order, meeting_role,
+    json_object('name', speaker_name, 'title', title, 'expected_duration', expected_duration),
+    deleted_dt, inserted_dt, updated_dt
+FROM SpeakerItems;
+
+INSERT INTO AgendaItems (id, meeting_id, item_type, item_order, meeting_role, details, deleted_dt, inserted_dt, updated_dt)
+SELECT id, meeting_id, 'timer', item_order, meeting_role,
+    json_object('show_meeting_details', iif(show_meeting_details, json('true'), json('false'))),
+    deleted_dt, inserted_dt, updated_dt
+FROM TimerItems;
+
+DROP TABLE BlankItems;
+DROP TABLE LyricsItems;
+DROP TABLE MessageItems;
+DROP TABLE SpeakerItems;
+DROP TABLE TimerItems;
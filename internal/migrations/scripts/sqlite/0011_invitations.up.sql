@@ -0,0 +1,21 @@
+-- Invitations to join an organization, sent by email and accepted with a signed link
+
+CREATE TABLE Invitations (
+    id CHAR(36) NOT NULL,
+    org_id CHAR(36) NOT NULL,
+    email VARCHAR(60) NOT NULL CHECK (length(email) <= 60),
+    role VARCHAR(20) NOT NULL DEFAULT 'viewer' CHECK (length(role) <= 20),
+    invited_by CHAR(36) NOT NULL,
+    expires_dt DATETIME NOT NULL,
+    accepted_by CHAR(36) NULL,
+    accepted_dt DATETIME NULL,
+    deleted_dt DATETIME NULL,
+    inserted_dt DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_dt DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (id)
+);
+CREATE INDEX idx_invitations_org ON Invitations (org_id);
+CREATE TRIGGER Invitations_updated_dt AFTER UPDATE ON Invitations FOR EACH ROW WHEN NEW.updated_dt = OLD.updated_dt
+BEGIN
+    UPDATE Invitations SET updated_dt = CURRENT_TIMESTAMP WHERE rowid = NEW.rowid;
+END;
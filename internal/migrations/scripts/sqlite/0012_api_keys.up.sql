@@ -0,0 +1,24 @@
+-- API keys for devices that can't sign in, like overlay machines and control surfaces. Each key acts as its own
+-- member of one org, and only its hash is stored.
+
+CREATE TABLE ApiKeys (
+    id CHAR(36) NOT NULL,
+    org_id CHAR(36) NOT NULL,
+    user_id CHAR(36) NOT NULL,
+    name VARCHAR(50) NOT NULL CHECK (length(name) <= 50),
+    scope VARCHAR(20) NOT NULL CHECK (length(scope) <= 20),
+    prefix VARCHAR(12) NOT NULL CHECK (length(prefix) <= 12),
+    key_hash CHAR(64) NOT NULL,
+    created_by CHAR(36) NOT NULL,
+    last_used_dt DATETIME NULL,
+    deleted_dt DATETIME NULL,
+    inserted_dt DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_dt DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (id),
+    UNIQUE (key_hash)
+);
+CREATE INDEX idx_api_keys_org ON ApiKeys (org_id);
+CREATE TRIGGER ApiKeys_updated_dt AFTER UPDATE ON ApiKeys FOR EACH ROW WHEN NEW.updated_dt = OLD.updated_dt
+BEGIN
+    UPDATE ApiKeys SET updated_dt = CURRENT_TIMESTAMP WHERE rowid = NEW.rowid;
+END;
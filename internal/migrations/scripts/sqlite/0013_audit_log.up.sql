@@ -0,0 +1,16 @@
+-- Audit log of every change made through the service: who made it, in which org and request, and what it changed.
+-- Records are only ever added; id keeps them in the order they were made.
+
+CREATE TABLE AuditLog (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    org_id CHAR(36) NULL,
+    actor_id CHAR(36) NULL,
+    entity_type VARCHAR(30) NOT NULL CHECK (length(entity_type) <= 30),
+    entity_id VARCHAR(64) NOT NULL CHECK (length(entity_id) <= 64),
+    action VARCHAR(10) NOT NULL CHECK (length(action) <= 10),
+    diff JSON NOT NULL,
+    request_id VARCHAR(64) NULL CHECK (length(request_id) <= 64),
+    inserted_dt DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+CREATE INDEX idx_audit_log_org ON AuditLog (org_id, id);
+CREATE INDEX idx_audit_log_entity ON AuditLog (entity_type, entity_id);
@@ -0,0 +1,378 @@
+// Package recurrence expands RRULE-style recurrence rules (RFC 5545) into meeting times. It supports the parts of
+// the grammar that meeting schedules need: FREQ (DAILY, WEEKLY, MONTHLY or YEARLY), INTERVAL, COUNT, UNTIL, BYMONTH,
+// BYMONTHDAY, BYDAY (with ordinals like 1SU or -1SU in monthly and yearly rules), BYHOUR, BYMINUTE and WKST.
+// Ordinal BYDAY values in a yearly rule count within each month, so rules like "the first Sunday of April and
+// October" are written FREQ=YEARLY;BYMONTH=4,10;BYDAY=1SU.
+package recurrence
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	// Schedules name IANA time zones, which have to load on hosts without a zoneinfo database
+	_ "time/tzdata"
+)
+
+// maxPeriods bounds how far a rule is expanded, well past any schedule's lifetime
+const maxPeriods = 100000
+
+var ErrInvalidRule = errors.New("invalid recurrence rule")
+
+// Frequency is how often a rule repeats
+type Frequency string
+
+const (
+	Daily   Frequency = "DAILY"
+	Weekly  Frequency = "WEEKLY"
+	Monthly Frequency = "MONTHLY"
+	Yearly  Frequency = "YEARLY"
+)
+
+// WeekdayNum is a BYDAY value: a weekday, and for monthly and yearly rules which one in the month (1 is the first,
+// -1 the last, 0 every one)
+type WeekdayNum struct {
+	Weekday time.Weekday
+	N       int
+}
+
+// Rule is a parsed recurrence rule
+type Rule struct {
+	Freq       Frequency
+	Interval   int
+	Count      int
+	Until      time.Time
+	ByMonth    []time.Month
+	ByMonthDay []int
+	ByDay      []WeekdayNum
+	ByHour     []int
+	ByMinute   []int
+	WeekStart  time.Weekday
+}
+
+var weekdays = map[string]time.Weekday{
+	"SU": time.Sunday, "MO": time.Monday, "TU": time.Tuesday, "WE": time.Wednesday,
+	"TH": time.Thursday, "FR": time.Friday, "SA": time.Saturday,
+}
+
+// Parse reads a rule such as "FREQ=WEEKLY;BYDAY=SU;BYHOUR=9". An "RRULE:" prefix is allowed.
+func Parse(s string) (Rule, error) {
+	rule := Rule{Interval: 1, WeekStart: time.Monday}
+	s = strings.TrimPrefix(strings.TrimSpace(s), "RRULE:")
+	if s == "" {
+		return Rule{}, fmt.Errorf("%w: FREQ is required", ErrInvalidRule)
+	}
+
+	for _, part := range strings.Split(s, ";") {
+		name, value, ok := strings.Cut(part, "=")
+		if !ok || value == "" {
+			return Rule{}, fmt.Errorf("%w: %q isn't NAME=VALUE", ErrInvalidRule, part)
+		}
+		var err error
+		switch strings.ToUpper(name) {
+		case "FREQ":
+			rule.Freq = Frequency(strings.ToUpper(value))
+			switch rule.Freq {
+			case Daily, Weekly, Monthly, Yearly:
+			default:
+				err = fmt.Errorf("FREQ %s isn't supported", value)
+			}
+		case "INTERVAL":
+			rule.Interval, err = parseInt(value, 1, 1000)
+		case "COUNT":
+			rule.Count, err = parseInt(value, 1, 10000)
+		case "UNTIL":
+			rule.Until, err = parseUntil(value)
+		case "BYMONTH":
+			err = parseList(value, func(v string) error {
+				month, err := parseInt(v, 1, 12)
+				rule.ByMonth = append(rule.ByMonth, time.Month(month))
+				return err
+			})
+		case "BYMONTHDAY":
+			err = parseList(value, func(v string) error {
+				day, err := parseInt(v, -31, 31)
+				if day == 0 {
+					err = errors.New("BYMONTHDAY can't be 0")
+				}
+				rule.ByMonthDay = append(rule.ByMonthDay, day)
+				return err
+			})
+		case "BYDAY":
+			err = parseList(value, func(v string) error {
+				day, err := parseWeekdayNum(v)
+				rule.ByDay = append(rule.ByDay, day)
+				return err
+			})
+		case "BYHOUR":
+			err = parseList(value, func(v string) error {
+				hour, err := parseInt(v, 0, 23)
+				rule.ByHour = append(rule.ByHour, hour)
+				return err
+			})
+		case "BYMINUTE":
+			err = parseList(value, func(v string) error {
+				minute, err := parseInt(v, 0, 59)
+				rule.ByMinute = append(rule.ByMinute, minute)
+				return err
+			})
+		case "WKST":
+			day, ok := weekdays[strings.ToUpper(value)]
+			if !ok {
+				err = fmt.Errorf("WKST %s isn't a weekday", value)
+			}
+			rule.WeekStart = day
+		default:
+			err = fmt.Errorf("%s isn't supported", name)
+		}
+		if err != nil {
+			return Rule{}, fmt.Errorf("%w: %v", ErrInvalidRule, err)
+		}
+	}
+
+	if rule.Freq == "" {
+		return Rule{}, fmt.Errorf("%w: FREQ is required", ErrInvalidRule)
+	}
+	if rule.Count > 0 && !rule.Until.IsZero() {
+		return Rule{}, fmt.Errorf("%w: COUNT and UNTIL can't both be set", ErrInvalidRule)
+	}
+	for _, day := range rule.ByDay {
+		if day.N != 0 && rule.Freq != Monthly && rule.Freq != Yearly {
+			return Rule{}, fmt.Errorf("%w: BYDAY ordinals need a MONTHLY or YEARLY rule", ErrInvalidRule)
+		}
+	}
+	return rule, nil
+}
+
+// Between returns the times in [after, before) that the rule produces when it starts at start. The rule repeats on
+// start's wall clock in start's location, so a 9:00 meeting stays at 9:00 across daylight saving changes, and
+// anything the rule doesn't set (the weekday, day of the month, month or time of day) comes from start.
+func (r Rule) Between(start, after, before time.Time) []time.Time {
+	var times []time.Time
+	count := 0
+	for period := 0; period < maxPeriods; period++ {
+		// Stopping at the first period past before also ends rules that never match, like the 31st of February
+		if !r.periodStart(start, period*r.Interval).Before(before) {
+			break
+		}
+		for _, t := range r.period(start, period*r.Interval) {
+			if t.Before(start) {
+				continue
+			}
+			if !r.Until.IsZero() && t.After(r.Until) {
+				return times
+			}
+			count++
+			if r.Count > 0 && count > r.Count {
+				return times
+			}
+			if !t.Before(before) {
+				return times
+			}
+			if !t.Before(after) {
+				times = append(times, t)
+			}
+		}
+	}
+	return times
+}
+
+// periodStart is the first day of the period offset periods after start's
+func (r Rule) periodStart(start time.Time, offset int) time.Time {
+	y, m, d := start.Date()
+	loc := start.Location()
+	switch r.Freq {
+	case Daily:
+		return time.Date(y, m, d+offset, 0, 0, 0, 0, loc)
+	case Weekly:
+		back := (int(start.Weekday()) - int(r.WeekStart) + 7) % 7
+		return time.Date(y, m, d-back+7*offset, 0, 0, 0, 0, loc)
+	case Monthly:
+		return time.Date(y, m+time.Month(offset), 1, 0, 0, 0, 0, loc)
+	default:
+		return time.Date(y+offset, 1, 1, 0, 0, 0, 0, loc)
+	}
+}
+
+// period returns the times the rule produces in one period, in order
+func (r Rule) period(start time.Time, offset int) []time.Time {
+	first := r.periodStart(start, offset)
+	var days []time.Time
+	switch r.Freq {
+	case Daily:
+		if r.matchesDay(first) {
+			days = append(days, first)
+		}
+	case Weekly:
+		for i := 0; i < 7; i++ {
+			day := first.AddDate(0, 0, i)
+			if r.inMonths(day.Month()) && r.onWeekday(day, start) {
+				days = append(days, day)
+			}
+		}
+	case Monthly:
+		if r.inMonths(first.Month()) {
+			days = r.monthDays(first, start)
+		}
+	case Yearly:
+		months := r.ByMonth
+		if len(months) == 0 {
+			months = []time.Month{start.Month()}
+		}
+		for _, month := range months {
+			days = append(days, r.monthDays(time.Date(first.Year(), month, 1, 0, 0, 0, 0, first.Location()), start)...)
+		}
+	}
+
+	var times []time.Time
+	for _, day := range days {
+		for _, hour := range orDefault(r.ByHour, start.Hour()) {
+			for _, minute := range orDefault(r.ByMinute, start.Minute()) {
+				times = append(times, time.Date(day.Year(), day.Month(), day.Day(), hour, minute, 0, 0, day.Location()))
+			}
+		}
+	}
+	sort.Slice(times, func(i, j int) bool { return times[i].Before(times[j]) })
+	return times
+}
+
+// monthDays returns the days of the month starting at first that the rule picks
+func (r Rule) monthDays(first, start time.Time) []time.Time {
+	last := first.AddDate(0, 1, -1).Day()
+	var days []time.Time
+	for d := 1; d <= last; d++ {
+		day := time.Date(first.Year(), first.Month(), d, 0, 0, 0, 0, first.Location())
+		switch {
+		case len(r.ByMonthDay) == 0 && len(r.ByDay) == 0:
+			if d == start.Day() {
+				days = append(days, day)
+			}
+		case len(r.ByMonthDay) > 0 && !r.onMonthDay(d, last):
+		case len(r.ByDay) > 0 && !r.onNthWeekday(day, last):
+		default:
+			days = append(days, day)
+		}
+	}
+	return days
+}
+
+// matchesDay applies a daily rule's filters
+func (r Rule) matchesDay(day time.Time) bool {
+	if !r.inMonths(day.Month()) {
+		return false
+	}
+	last := time.Date(day.Year(), day.Month()+1, 0, 0, 0, 0, 0, day.Location()).Day()
+	if len(r.ByMonthDay) > 0 && !r.onMonthDay(day.Day(), last) {
+		return false
+	}
+	return len(r.ByDay) == 0 || r.onNthWeekday(day, last)
+}
+
+func (r Rule) inMonths(month time.Month) bool {
+	if len(r.ByMonth) == 0 {
+		return true
+	}
+	for _, m := range r.ByMonth {
+		if m == month {
+			return true
+		}
+	}
+	return false
+}
+
+func (r Rule) onMonthDay(d, last int) bool {
+	for _, md := range r.ByMonthDay {
+		if md == d || last+md+1 == d {
+			return true
+		}
+	}
+	return false
+}
+
+// onWeekday checks a weekly rule's days, which default to start's weekday
+func (r Rule) onWeekday(day, start time.Time) bool {
+	if len(r.ByDay) == 0 {
+		return day.Weekday() == start.Weekday()
+	}
+	for _, wd := range r.ByDay {
+		if wd.Weekday == day.Weekday() {
+			return true
+		}
+	}
+	return false
+}
+
+// onNthWeekday checks BYDAY against a day of a month with last days, counting ordinals within the month
+func (r Rule) onNthWeekday(day time.Time, last int) bool {
+	nth := (day.Day()-1)/7 + 1
+	nthFromEnd := -((last-day.Day())/7 + 1)
+	for _, wd := range r.ByDay {
+		if wd.Weekday == day.Weekday() && (wd.N == 0 || wd.N == nth || wd.N == nthFromEnd) {
+			return true
+		}
+	}
+	return false
+}
+
+func orDefault(values []int, fallback int) []int {
+	if len(values) == 0 {
+		return []int{fallback}
+	}
+	sorted := append([]int(nil), values...)
+	sort.Ints(sorted)
+	return sorted
+}
+
+func parseList(value string, parse func(string) error) error {
+	for _, v := range strings.Split(value, ",") {
+		if err := parse(strings.TrimSpace(v)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func parseInt(value string, min, max int) (int, error) {
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, fmt.Errorf("%q isn't a number", value)
+	}
+	if n < min || n > max {
+		return 0, fmt.Errorf("%d isn't between %d and %d", n, min, max)
+	}
+	return n, nil
+}
+
+func parseWeekdayNum(value string) (WeekdayNum, error) {
+	value = strings.ToUpper(value)
+	if len(value) < 2 {
+		return WeekdayNum{}, fmt.Errorf("%q isn't a weekday", value)
+	}
+	day, ok := weekdays[value[len(value)-2:]]
+	if !ok {
+		return WeekdayNum{}, fmt.Errorf("%q isn't a weekday", value)
+	}
+	wd := WeekdayNum{Weekday: day}
+	if prefix := value[:len(value)-2]; prefix != "" {
+		n, err := parseInt(strings.TrimPrefix(prefix, "+"), -5, 5)
+		if err != nil || n == 0 {
+			return WeekdayNum{}, fmt.Errorf("%q has a bad ordinal", value)
+		}
+		wd.N = n
+	}
+	return wd, nil
+}
+
+// parseUntil reads UNTIL as a UTC date-time (20251231T235959Z) or a date, which includes the whole day
+func parseUntil(value string) (time.Time, error) {
+	if t, err := time.Parse("20060102T150405Z", value); err == nil {
+		return t, nil
+	}
+	if t, err := time.Parse("20060102", value); err == nil {
+		return t.Add(24*time.Hour - time.Second), nil
+	}
+	return time.Time{}, fmt.Errorf("UNTIL %q isn't a date", value)
+}
@@ -0,0 +1,120 @@
+package recurrence
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func mustParse(t *testing.T, s string) Rule {
+	t.Helper()
+	rule, err := Parse(s)
+	if err != nil {
+		t.Fatalf("Parse(%q) failed: %v", s, err)
+	}
+	return rule
+}
+
+func TestWeekly(t *testing.T) {
+	phoenix, err := time.LoadLocation("America/Phoenix")
+	if err != nil {
+		t.Fatalf("LoadLocation failed: %v", err)
+	}
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, phoenix) // a Wednesday
+	rule := mustParse(t, "RRULE:FREQ=WEEKLY;BYDAY=SU;BYHOUR=9;BYMINUTE=0")
+
+	got := rule.Between(start, time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC), time.Date(2025, 1, 20, 0, 0, 0, 0, time.UTC))
+	want := []time.Time{
+		time.Date(2025, 1, 5, 9, 0, 0, 0, phoenix),
+		time.Date(2025, 1, 12, 9, 0, 0, 0, phoenix),
+		time.Date(2025, 1, 19, 9, 0, 0, 0, phoenix),
+	}
+	if len(got) != len(want) {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+	for i := range want {
+		if !got[i].Equal(want[i]) {
+			t.Errorf("occurrence %d: expected %v, got %v", i, want[i], got[i])
+		}
+	}
+}
+
+func TestDaylightSaving(t *testing.T) {
+	denver, _ := time.LoadLocation("America/Denver")
+	start := time.Date(2025, 3, 2, 9, 0, 0, 0, denver)
+	rule := mustParse(t, "FREQ=WEEKLY")
+
+	// Clocks change on March 9th; the meeting stays at 9:00 local time
+	got := rule.Between(start, start, start.AddDate(0, 0, 14))
+	if len(got) != 2 || got[1].Hour() != 9 || got[1].Sub(got[0]) != 7*24*time.Hour-time.Hour {
+		t.Errorf("expected 9:00 on both Sundays, got %v", got)
+	}
+}
+
+func TestMonthlyAndYearly(t *testing.T) {
+	start := time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC)
+
+	// Stake conference on the first Sunday of April and October
+	rule := mustParse(t, "FREQ=YEARLY;BYMONTH=4,10;BYDAY=1SU")
+	got := rule.Between(start, start, time.Date(2027, 1, 1, 0, 0, 0, 0, time.UTC))
+	want := []string{"2025-04-06", "2025-10-05", "2026-04-05", "2026-10-04"}
+	if len(got) != len(want) {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+	for i := range want {
+		if got[i].Format("2006-01-02") != want[i] || got[i].Hour() != 10 {
+			t.Errorf("occurrence %d: expected %s at 10:00, got %v", i, want[i], got[i])
+		}
+	}
+
+	// Last Sunday of every other month, three times
+	rule = mustParse(t, "FREQ=MONTHLY;INTERVAL=2;BYDAY=-1SU;COUNT=3")
+	got = rule.Between(start, start, time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC))
+	want = []string{"2025-01-26", "2025-03-30", "2025-05-25"}
+	if len(got) != len(want) {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+	for i := range want {
+		if got[i].Format("2006-01-02") != want[i] {
+			t.Errorf("occurrence %d: expected %s, got %v", i, want[i], got[i])
+		}
+	}
+
+	// COUNT counts from the start, not from after
+	got = rule.Between(start, time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC), time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC))
+	if len(got) != 2 {
+		t.Errorf("expected the last 2 of 3 occurrences, got %v", got)
+	}
+
+	// Rules that never match end at before
+	rule = mustParse(t, "FREQ=YEARLY;BYMONTH=2;BYMONTHDAY=31")
+	if got := rule.Between(start, start, start.AddDate(5, 0, 0)); len(got) != 0 {
+		t.Errorf("expected no occurrences, got %v", got)
+	}
+}
+
+func TestUntil(t *testing.T) {
+	start := time.Date(2025, 1, 5, 9, 0, 0, 0, time.UTC)
+	rule := mustParse(t, "FREQ=DAILY;UNTIL=20250107")
+	if got := rule.Between(start, start, start.AddDate(0, 1, 0)); len(got) != 3 {
+		t.Errorf("expected 3 days, got %v", got)
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	for _, s := range []string{
+		"",
+		"BYDAY=SU",
+		"FREQ=HOURLY",
+		"FREQ=WEEKLY;BYDAY=XX",
+		"FREQ=WEEKLY;BYDAY=1SU",
+		"FREQ=MONTHLY;BYMONTHDAY=0",
+		"FREQ=DAILY;COUNT=2;UNTIL=20250101",
+		"FREQ=DAILY;BYSETPOS=1",
+		"FREQ=DAILY;INTERVAL",
+	} {
+		if _, err := Parse(s); !errors.Is(err, ErrInvalidRule) {
+			t.Errorf("expected ErrInvalidRule for %q, got %v", s, err)
+		}
+	}
+}
@@ -0,0 +1,109 @@
+package render
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"image"
+	"image/draw"
+	"image/png"
+	"io"
+	"lowerthirdsapi/internal/entities"
+	"strings"
+
+	xdraw "golang.org/x/image/draw"
+	"golang.org/x/image/font"
+	"golang.org/x/image/math/fixed"
+)
+
+// PNG writes the content as a 1920x1080 PNG with an alpha channel
+func PNG(w io.Writer, theme entities.Theme, content Content) error {
+	img, err := Image(theme, content)
+	if err != nil {
+		return err
+	}
+	return png.Encode(w, img)
+}
+
+// Image draws the content on a transparent 1920x1080 frame. Logos are only drawn from PNG data URLs, since the
+// renderer doesn't fetch anything over the network.
+func Image(theme entities.Theme, content Content) (*image.NRGBA, error) {
+	fs := faces{}
+	defer fs.Close()
+	l, err := layoutContent(theme, content, fs)
+	if err != nil {
+		return nil, err
+	}
+
+	img := image.NewNRGBA(image.Rect(0, 0, Width, Height))
+	if content.Empty() {
+		return img, nil
+	}
+	// Src keeps the background's alpha instead of blending it with the transparent frame
+	draw.Draw(img, rect(l.Background), image.NewUniform(l.BackgroundColor), image.Point{}, draw.Src)
+	draw.Draw(img, rect(l.Accent), image.NewUniform(l.AccentColor), image.Point{}, draw.Over)
+
+	for _, line := range l.Lines {
+		f, err := fs.get(l.Font, line.Bold, line.Size)
+		if err != nil {
+			return nil, err
+		}
+		d := font.Drawer{
+			Dst:  img,
+			Src:  image.NewUniform(line.Color),
+			Face: f,
+			Dot:  fixed.P(line.X, line.Y),
+		}
+		d.DrawString(line.Text)
+	}
+
+	if l.Logo.W > 0 {
+		logo, err := decodeLogo(theme.LogoURL)
+		if err != nil {
+			return nil, err
+		}
+		if logo != nil {
+			drawLogo(img, logo, l.Logo, l.LogoAlign)
+		}
+	}
+	return img, nil
+}
+
+// decodeLogo decodes a PNG data URL, or returns nil for a logo that has to be fetched
+func decodeLogo(url string) (image.Image, error) {
+	data, ok := strings.CutPrefix(url, "data:image/png;base64,")
+	if !ok {
+		return nil, nil
+	}
+	raw, err := base64.StdEncoding.DecodeString(data)
+	if err != nil {
+		return nil, fmt.Errorf("logo_url: %w", err)
+	}
+	logo, err := png.Decode(bytes.NewReader(raw))
+	if err != nil {
+		return nil, fmt.Errorf("logo_url: %w", err)
+	}
+	return logo, nil
+}
+
+// drawLogo scales the logo to fit in b, keeping its aspect ratio, and aligns it to the given side
+func drawLogo(img draw.Image, logo image.Image, b box, align string) {
+	size := logo.Bounds().Size()
+	if size.X == 0 || size.Y == 0 {
+		return
+	}
+	w, h := b.W, size.Y*b.W/size.X
+	if h > b.H {
+		w, h = size.X*b.H/size.Y, b.H
+	}
+	x := b.X
+	if align == "right" {
+		x = b.X + b.W - w
+	}
+	dst := image.Rect(x, b.Y+(b.H-h)/2, x+w, b.Y+(b.H-h)/2+h)
+	xdraw.CatmullRom.Scale(img, dst, logo, logo.Bounds(), draw.Over, nil)
+}
+
+func rect(b box) image.Rectangle {
+	return image.Rect(b.X, b.Y, b.X+b.W, b.Y+b.H)
+}
@@ -0,0 +1,311 @@
+package render
+
+import (
+	"fmt"
+	"image/color"
+	"lowerthirdsapi/internal/entities"
+	"strings"
+	"sync"
+
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/gofont/gobold"
+	"golang.org/x/image/font/gofont/gomedium"
+	"golang.org/x/image/font/gofont/gomono"
+	"golang.org/x/image/font/gofont/gomonobold"
+	"golang.org/x/image/font/gofont/goregular"
+	"golang.org/x/image/font/gofont/gosmallcaps"
+	"golang.org/x/image/font/opentype"
+	"golang.org/x/image/math/fixed"
+)
+
+// Frame size of every rendered graphic
+const (
+	Width  = 1920
+	Height = 1080
+)
+
+// lineHeight is the spacing of text lines as a multiple of their size
+const lineHeight = 1.25
+
+// Content is the text of one graphic. Primary lines are drawn large and bold, secondary lines below them.
+type Content struct {
+	Primary   []string
+	Secondary []string
+}
+
+// Empty reports whether there's nothing to draw, so the graphic is fully transparent
+func (c Content) Empty() bool {
+	return len(c.Primary) == 0 && len(c.Secondary) == 0
+}
+
+// ItemContent is the text an item shows on screen. slide is the lyrics slide to show, and meeting is the item's
+// meeting, which timers show the start of. Blank items have no content.
+func ItemContent(item entities.Item, slide *entities.Slide, meeting *entities.Meeting) Content {
+	var content Content
+	switch item := item.(type) {
+	case *entities.SpeakerItem:
+		content.Primary = []string{item.SpeakerName}
+		if item.Title.Valid && item.Title.String != "" {
+			content.Secondary = []string{item.Title.String}
+		}
+	case *entities.MessageItem:
+		content.Primary = []string{item.PrimaryText}
+		if item.SecondaryText.Valid && item.SecondaryText.String != "" {
+			content.Secondary = []string{item.SecondaryText.String}
+		}
+	case *entities.LyricsItem:
+		if slide != nil {
+			content.Primary = slide.Lines
+			content.Secondary = slide.TranslationLines
+		}
+	case *entities.TimerItem:
+		// A still image can't count down, so show when the meeting starts
+		if meeting != nil {
+			content.Primary = []string{"Starting at " + meeting.MeetingDate.Format("3:04 PM")}
+			if item.ShowMeetingDetails {
+				details := meeting.Meeting
+				if meeting.Conference.Valid && meeting.Conference.String != "" {
+					details = meeting.Conference.String + " · " + details
+				}
+				content.Secondary = []string{details}
+			}
+		}
+	}
+	return content
+}
+
+// box is a rectangle in frame pixels
+type box struct {
+	X, Y, W, H int
+}
+
+// textLine is a line of text placed on the frame, with Y at its baseline
+type textLine struct {
+	Text  string
+	X, Y  int
+	Size  int
+	Bold  bool
+	Color color.NRGBA
+}
+
+// layout is where everything in a graphic goes
+type layout struct {
+	Font            string
+	Background      box
+	BackgroundColor color.NRGBA
+	Accent          box
+	AccentColor     color.NRGBA
+	Lines           []textLine
+	// Logo is the box the theme's logo is fit in, aligned to LogoAlign. It's empty when there's no logo.
+	Logo      box
+	LogoAlign string
+}
+
+// colors are a theme's parsed colors
+type colors struct {
+	background, accent, primary, secondary color.NRGBA
+}
+
+func parseColors(theme entities.Theme) (colors, error) {
+	var c colors
+	var err error
+	for _, field := range []struct {
+		name  string
+		value string
+		dest  *color.NRGBA
+	}{
+		{"background_color", theme.BackgroundColor, &c.background},
+		{"accent_color", theme.AccentColor, &c.accent},
+		{"primary_color", theme.PrimaryColor, &c.primary},
+		{"secondary_color", theme.SecondaryColor, &c.secondary},
+	} {
+		*field.dest, err = entities.ParseColor(field.value)
+		if err != nil {
+			return colors{}, fmt.Errorf("%s: %w", field.name, err)
+		}
+	}
+	return c, nil
+}
+
+// layoutContent wraps the content to the theme's width and stacks it in a band anchored to the bottom of the frame
+func layoutContent(theme entities.Theme, content Content, fs faces) (layout, error) {
+	c, err := parseColors(theme)
+	if err != nil {
+		return layout{}, err
+	}
+	l := layout{Font: theme.Font, BackgroundColor: c.background, AccentColor: c.accent}
+	if content.Empty() {
+		return l, nil
+	}
+
+	textX := theme.MarginX + theme.AccentWidth + theme.Padding
+	textWidth := Width - theme.MarginX - theme.Padding - textX
+	// Logos are fit in a box twice as wide as it's tall. One in the band takes its room from the text.
+	logoWidth := 2 * theme.LogoHeight
+	if theme.ShowsLogo() && theme.LogoPosition == "band-right" {
+		textWidth -= logoWidth + theme.Padding
+	}
+
+	type block struct {
+		lines []string
+		size  int
+		bold  bool
+		color color.NRGBA
+	}
+	blocks := []block{
+		{content.Primary, theme.PrimarySize, true, c.primary},
+		{content.Secondary, theme.SecondarySize, false, c.secondary},
+	}
+	for i, b := range blocks {
+		f, err := fs.get(theme.Font, b.bold, b.size)
+		if err != nil {
+			return layout{}, err
+		}
+		blocks[i].lines = wrap(b.lines, f, textWidth)
+	}
+
+	// Measure the band from the bottom up, then place the lines top down
+	height := 2 * theme.Padding
+	for _, b := range blocks {
+		height += len(b.lines) * int(float64(b.size)*lineHeight)
+	}
+	top := Height - theme.MarginBottom - height
+	l.Background = box{X: theme.MarginX, Y: top, W: Width - 2*theme.MarginX, H: height}
+	l.Accent = box{X: theme.MarginX, Y: top, W: theme.AccentWidth, H: height}
+
+	y := top + theme.Padding
+	for _, b := range blocks {
+		step := int(float64(b.size) * lineHeight)
+		for _, text := range b.lines {
+			// Center the glyphs in their line: the baseline sits below the middle by about a third of the size
+			baseline := y + step/2 + b.size*35/100
+			l.Lines = append(l.Lines, textLine{Text: text, X: textX, Y: baseline, Size: b.size, Bold: b.bold, Color: b.color})
+			y += step
+		}
+	}
+
+	if theme.ShowsLogo() {
+		switch theme.LogoPosition {
+		case "top-left":
+			l.Logo = box{X: theme.MarginX, Y: theme.MarginTop, W: logoWidth, H: theme.LogoHeight}
+			l.LogoAlign = "left"
+		case "top-right":
+			l.Logo = box{X: Width - theme.MarginX - logoWidth, Y: theme.MarginTop, W: logoWidth, H: theme.LogoHeight}
+			l.LogoAlign = "right"
+		case "band-right":
+			h := min(theme.LogoHeight, height-2*theme.Padding)
+			l.Logo = box{X: Width - theme.MarginX - theme.Padding - logoWidth, Y: top + (height-h)/2, W: logoWidth, H: h}
+			l.LogoAlign = "right"
+		}
+	}
+	return l, nil
+}
+
+// wrap breaks lines at spaces so each fits in width pixels. A word wider than the width is left on its own line.
+func wrap(lines []string, f font.Face, width int) []string {
+	var wrapped []string
+	limit := fixed.I(width)
+	for _, line := range lines {
+		words := strings.Fields(line)
+		if len(words) == 0 {
+			continue
+		}
+		current := words[0]
+		for _, word := range words[1:] {
+			next := current + " " + word
+			if font.MeasureString(f, next) > limit {
+				wrapped = append(wrapped, current)
+				current = word
+				continue
+			}
+			current = next
+		}
+		wrapped = append(wrapped, current)
+	}
+	return wrapped
+}
+
+// fontFiles are the regular and bold fonts for each of the theme fonts. Go Smallcaps has no bold.
+var fontFiles = map[string][2][]byte{
+	"go":           {goregular.TTF, gobold.TTF},
+	"go-medium":    {gomedium.TTF, gobold.TTF},
+	"go-mono":      {gomono.TTF, gomonobold.TTF},
+	"go-smallcaps": {gosmallcaps.TTF, gosmallcaps.TTF},
+}
+
+// cssFontFamilies name each theme font for browsers, falling back to similar installed fonts
+var cssFontFamilies = map[string]string{
+	"go":           "Go, 'Helvetica Neue', Arial, sans-serif",
+	"go-medium":    "'Go Medium', Go, 'Helvetica Neue', Arial, sans-serif",
+	"go-mono":      "'Go Mono', Menlo, Consolas, monospace",
+	"go-smallcaps": "'Go Smallcaps', Go, 'Helvetica Neue', Arial, sans-serif",
+}
+
+// CSSFontFamily is the CSS font-family for a theme font
+func CSSFontFamily(name string) string {
+	if family, ok := cssFontFamilies[name]; ok {
+		return family
+	}
+	return cssFontFamilies["go"]
+}
+
+var (
+	fontsOnce sync.Once
+	fontsErr  error
+	fonts     map[string][2]*opentype.Font
+)
+
+type faceKey struct {
+	font string
+	bold bool
+	size int
+}
+
+// faces opens the theme fonts at the sizes a graphic needs. Faces aren't safe for concurrent use, so every render
+// opens its own and closes them when it's done.
+type faces map[faceKey]font.Face
+
+func (fs faces) get(name string, isBold bool, size int) (font.Face, error) {
+	fontsOnce.Do(func() {
+		fonts = make(map[string][2]*opentype.Font, len(fontFiles))
+		for fontName, files := range fontFiles {
+			var parsed [2]*opentype.Font
+			for i, ttf := range files {
+				parsed[i], fontsErr = opentype.Parse(ttf)
+				if fontsErr != nil {
+					return
+				}
+			}
+			fonts[fontName] = parsed
+		}
+	})
+	if fontsErr != nil {
+		return nil, fontsErr
+	}
+
+	src, ok := fonts[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown font %q", name)
+	}
+	key := faceKey{font: name, bold: isBold, size: size}
+	if f, ok := fs[key]; ok {
+		return f, nil
+	}
+	ttf := src[0]
+	if isBold {
+		ttf = src[1]
+	}
+	// At 72 DPI a point is a pixel
+	f, err := opentype.NewFace(ttf, &opentype.FaceOptions{Size: float64(size), DPI: 72, Hinting: font.HintingFull})
+	if err != nil {
+		return nil, err
+	}
+	fs[key] = f
+	return f, nil
+}
+
+func (fs faces) Close() {
+	for _, f := range fs {
+		_ = f.Close()
+	}
+}
@@ -0,0 +1,143 @@
+package render
+
+import (
+	"bytes"
+	"encoding/base64"
+	"image"
+	"image/png"
+	"lowerthirdsapi/internal/entities"
+	"strings"
+	"testing"
+
+	"gopkg.in/guregu/null.v4"
+)
+
+func TestItemContent(t *testing.T) {
+	speaker := &entities.SpeakerItem{SpeakerName: "Jane Doe", Title: null.StringFrom("Relief Society President")}
+	content := ItemContent(speaker, nil, nil)
+	if content.Primary[0] != "Jane Doe" || content.Secondary[0] != "Relief Society President" {
+		t.Errorf("unexpected speaker content %+v", content)
+	}
+
+	lyrics := &entities.LyricsItem{}
+	slide := &entities.Slide{Lines: []string{"Line one", "Line two"}, TranslationLines: []string{"Linea uno"}}
+	content = ItemContent(lyrics, slide, nil)
+	if len(content.Primary) != 2 || len(content.Secondary) != 1 {
+		t.Errorf("unexpected lyrics content %+v", content)
+	}
+
+	if !ItemContent(&entities.BlankItem{}, nil, nil).Empty() {
+		t.Error("expected blank items to have no content")
+	}
+}
+
+func TestPNG(t *testing.T) {
+	theme, _ := entities.ThemeByName("")
+	content := Content{Primary: []string{"Jane Doe"}, Secondary: []string{"Relief Society President"}}
+
+	var buf bytes.Buffer
+	if err := PNG(&buf, theme, content); err != nil {
+		t.Fatalf("PNG failed: %v", err)
+	}
+	img, err := png.Decode(&buf)
+	if err != nil {
+		t.Fatalf("decode failed: %v", err)
+	}
+	if b := img.Bounds(); b.Dx() != Width || b.Dy() != Height {
+		t.Fatalf("expected %dx%d, got %v", Width, Height, b)
+	}
+
+	// The top of the frame is transparent and the band near the bottom is translucent
+	if _, _, _, a := img.At(10, 10).RGBA(); a != 0 {
+		t.Errorf("expected a transparent corner, got alpha %d", a)
+	}
+	l, err := layoutContent(theme, content, faces{})
+	if err != nil {
+		t.Fatalf("layout failed: %v", err)
+	}
+	bandX, bandY := l.Background.X+l.Background.W-5, l.Background.Y+5
+	if _, _, _, a := img.At(bandX, bandY).RGBA(); a == 0 || a == 0xFFFF {
+		t.Errorf("expected a translucent band, got alpha %d", a)
+	}
+}
+
+func TestWrapAndSVG(t *testing.T) {
+	theme, _ := entities.ThemeByName("light")
+	long := strings.Repeat("All creatures of our God and King ", 6)
+	content := Content{Primary: []string{long}, Secondary: []string{"Tom & Jerry <3"}}
+
+	l, err := layoutContent(theme, content, faces{})
+	if err != nil {
+		t.Fatalf("layout failed: %v", err)
+	}
+	if len(l.Lines) < 3 {
+		t.Errorf("expected the long line to wrap, got %d lines", len(l.Lines))
+	}
+	if bottom := l.Background.Y + l.Background.H; bottom != Height-theme.MarginBottom {
+		t.Errorf("expected the band to end %dpx above the bottom, ends at %d", theme.MarginBottom, bottom)
+	}
+
+	var buf bytes.Buffer
+	if err := SVG(&buf, theme, content); err != nil {
+		t.Fatalf("SVG failed: %v", err)
+	}
+	svg := buf.String()
+	if !strings.Contains(svg, `width="1920" height="1080"`) || !strings.Contains(svg, "Tom &amp; Jerry &lt;3") {
+		t.Errorf("unexpected SVG:\n%s", svg)
+	}
+
+	theme.PrimaryColor = "white"
+	if err := SVG(&buf, theme, content); err == nil {
+		t.Error("expected an error for an invalid theme color")
+	}
+}
+
+func TestLogoAndFont(t *testing.T) {
+	// A solid red logo twice as wide as it's tall
+	red := image.NewNRGBA(image.Rect(0, 0, 20, 10))
+	for i := 0; i < len(red.Pix); i += 4 {
+		copy(red.Pix[i:], []byte{0xFF, 0, 0, 0xFF})
+	}
+	var logo bytes.Buffer
+	_ = png.Encode(&logo, red)
+
+	theme, _ := entities.ThemeByName("")
+	theme.Font = "go-mono"
+	theme.LogoPosition = "top-right"
+	theme.LogoHeight = 50
+	theme.LogoURL = "data:image/png;base64," + base64.StdEncoding.EncodeToString(logo.Bytes())
+	content := Content{Primary: []string{"Jane Doe"}}
+
+	img, err := Image(theme, content)
+	if err != nil {
+		t.Fatalf("Image failed: %v", err)
+	}
+	// The logo fills its box in the top right corner of the safe area
+	x, y := Width-theme.MarginX-10, theme.MarginTop+25
+	if c := img.NRGBAAt(x, y); c.R < 0xF0 || c.A != 0xFF {
+		t.Errorf("expected the logo at %d,%d, got %v", x, y, c)
+	}
+	if c := img.NRGBAAt(theme.MarginX+10, y); c.A != 0 {
+		t.Errorf("expected nothing in the top left, got %v", c)
+	}
+
+	var buf bytes.Buffer
+	if err := SVG(&buf, theme, content); err != nil {
+		t.Fatalf("SVG failed: %v", err)
+	}
+	svg := buf.String()
+	if !strings.Contains(svg, `preserveAspectRatio="xMaxYMid meet" href="data:image/png;base64,`) ||
+		!strings.Contains(svg, "Go Mono") {
+		t.Errorf("unexpected SVG:\n%s", svg)
+	}
+
+	// Logos in the band narrow the text
+	theme.LogoPosition = "band-right"
+	l, err := layoutContent(theme, content, faces{})
+	if err != nil {
+		t.Fatalf("layout failed: %v", err)
+	}
+	if l.Logo.Y < l.Background.Y || l.Logo.Y+l.Logo.H > l.Background.Y+l.Background.H {
+		t.Errorf("expected the logo %+v inside the band %+v", l.Logo, l.Background)
+	}
+}
@@ -0,0 +1,65 @@
+package render
+
+import (
+	"bufio"
+	"encoding/xml"
+	"fmt"
+	"image/color"
+	"io"
+	"lowerthirdsapi/internal/entities"
+)
+
+// SVG writes the content as a 1920x1080 SVG with a transparent background
+func SVG(w io.Writer, theme entities.Theme, content Content) error {
+	fs := faces{}
+	defer fs.Close()
+	l, err := layoutContent(theme, content, fs)
+	if err != nil {
+		return err
+	}
+
+	bw := bufio.NewWriter(w)
+	fmt.Fprintf(bw, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d">`,
+		Width, Height, Width, Height)
+	bw.WriteString("\n")
+	if !content.Empty() {
+		writeRect(bw, l.Background, l.BackgroundColor)
+		writeRect(bw, l.Accent, l.AccentColor)
+	}
+	if l.Logo.W > 0 {
+		// Align the logo to the outer edge of its box, keeping its aspect ratio
+		align := "xMinYMid"
+		if l.LogoAlign == "right" {
+			align = "xMaxYMid"
+		}
+		fmt.Fprintf(bw, `<image x="%d" y="%d" width="%d" height="%d" preserveAspectRatio="%s meet" href="`,
+			l.Logo.X, l.Logo.Y, l.Logo.W, l.Logo.H, align)
+		_ = xml.EscapeText(bw, []byte(theme.LogoURL))
+		bw.WriteString(`"/>` + "\n")
+	}
+	fontFamily := CSSFontFamily(l.Font)
+	for _, line := range l.Lines {
+		weight := "normal"
+		if line.Bold {
+			weight = "bold"
+		}
+		fmt.Fprintf(bw, `<text x="%d" y="%d" font-family="%s" font-size="%d" font-weight="%s" %s>`,
+			line.X, line.Y, fontFamily, line.Size, weight, fill(line.Color))
+		_ = xml.EscapeText(bw, []byte(line.Text))
+		bw.WriteString("</text>\n")
+	}
+	bw.WriteString("</svg>\n")
+	return bw.Flush()
+}
+
+func writeRect(w io.Writer, b box, c color.NRGBA) {
+	if b.W <= 0 || b.H <= 0 || c.A == 0 {
+		return
+	}
+	fmt.Fprintf(w, `<rect x="%d" y="%d" width="%d" height="%d" %s/>`+"\n", b.X, b.Y, b.W, b.H, fill(c))
+}
+
+// fill is the SVG fill attributes for a color
+func fill(c color.NRGBA) string {
+	return fmt.Sprintf(`fill="#%02X%02X%02X" fill-opacity="%.3g"`, c.R, c.G, c.B, float64(c.A)/255)
+}
@@ -0,0 +1,48 @@
+// Package scheduler runs the background job that makes meetings from recurring schedules ahead of time
+package scheduler
+
+import (
+	"context"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+type Config struct {
+	Enabled  bool          `envconfig:"SCHEDULER_ENABLED" default:"true"`
+	Interval time.Duration `envconfig:"SCHEDULER_INTERVAL" default:"15m"`
+}
+
+// Generator makes the meetings coming up after now and returns how many it made
+type Generator interface {
+	GenerateScheduledMeetings(ctx context.Context, now time.Time) (int, error)
+}
+
+// Run generates meetings straight away and then every interval until ctx is done. Errors are logged and retried
+// on the next tick, since generation is safe to repeat.
+func Run(ctx context.Context, cfg Config, g Generator, log *logrus.Entry) {
+	if !cfg.Enabled {
+		log.Info("scheduler disabled")
+		return
+	}
+	if cfg.Interval <= 0 {
+		cfg.Interval = 15 * time.Minute
+	}
+
+	ticker := time.NewTicker(cfg.Interval)
+	defer ticker.Stop()
+	for {
+		created, err := g.GenerateScheduledMeetings(ctx, time.Now().UTC())
+		if err != nil {
+			log.Error("scheduler error ", err)
+		} else if created > 0 {
+			log.Info("scheduler created meetings: ", created)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
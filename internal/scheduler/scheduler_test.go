@@ -0,0 +1,51 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+type fakeGenerator struct {
+	runs atomic.Int32
+}
+
+func (g *fakeGenerator) GenerateScheduledMeetings(ctx context.Context, now time.Time) (int, error) {
+	if g.runs.Add(1) == 1 {
+		return 0, errors.New("database unavailable")
+	}
+	return 1, nil
+}
+
+func TestRun(t *testing.T) {
+	log := logrus.NewEntry(logrus.New())
+	ctx, cancel := context.WithCancel(context.Background())
+	g := &fakeGenerator{}
+
+	done := make(chan struct{})
+	go func() {
+		Run(ctx, Config{Enabled: true, Interval: time.Millisecond}, g, log)
+		close(done)
+	}()
+
+	// An error doesn't stop later runs
+	deadline := time.Now().Add(time.Second)
+	for g.runs.Load() < 3 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	cancel()
+	<-done
+	if g.runs.Load() < 3 {
+		t.Errorf("expected repeated runs, got %d", g.runs.Load())
+	}
+
+	g = &fakeGenerator{}
+	Run(context.Background(), Config{Enabled: false}, g, log)
+	if g.runs.Load() != 0 {
+		t.Errorf("expected a disabled scheduler not to run, got %d", g.runs.Load())
+	}
+}
@@ -0,0 +1,98 @@
+package server
+
+import (
+	"encoding/json"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"lowerthirdsapi/internal/entities"
+	"lowerthirdsapi/internal/helpers"
+	"net/http"
+)
+
+// APIKeyResponse is a new API key with the key itself, which is only ever shown here
+type APIKeyResponse struct {
+	entities.APIKey
+	Key string `json:"key"`
+}
+
+func (s *Server) getOrgKeys() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		ctx := req.Context()
+
+		orgID, err := uuid.Parse(mux.Vars(req)["OrgID"])
+		if err != nil {
+			s.Logger.Error("[getOrgKeys] error ", err)
+			helpers.WriteError(ctx, err, w)
+			return
+		}
+
+		keys, err := s.lowerThirdsService.GetAPIKeysByOrg(ctx, orgID)
+		if err != nil {
+			s.Logger.Error("[getOrgKeys] error ", err)
+			helpers.WriteError(ctx, err, w)
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+		_ = json.NewEncoder(w).Encode(keys)
+	})
+}
+
+func (s *Server) postOrgKey() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		ctx := req.Context()
+
+		orgID, err := uuid.Parse(mux.Vars(req)["OrgID"])
+		if err != nil {
+			s.Logger.Error("[postOrgKey] error ", err)
+			helpers.WriteError(ctx, err, w)
+			return
+		}
+
+		var key entities.APIKey
+		if err := json.NewDecoder(req.Body).Decode(&key); err != nil {
+			s.Logger.Error("[postOrgKey] ", err)
+			helpers.WriteError(ctx, err, w)
+			return
+		}
+
+		secret, err := s.lowerThirdsService.CreateAPIKey(ctx, orgID, &key)
+		if err != nil {
+			s.Logger.Error("[postOrgKey] CreateAPIKey error ", err)
+			helpers.WriteError(ctx, err, w)
+			return
+		}
+
+		w.WriteHeader(http.StatusCreated)
+		_ = json.NewEncoder(w).Encode(APIKeyResponse{APIKey: key, Key: secret})
+	})
+}
+
+func (s *Server) deleteOrgKey() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		ctx := req.Context()
+
+		vars := mux.Vars(req)
+		orgID, err := uuid.Parse(vars["OrgID"])
+		if err != nil {
+			s.Logger.Error("[deleteOrgKey] error ", err)
+			helpers.WriteError(ctx, err, w)
+			return
+		}
+		keyID, err := uuid.Parse(vars["KeyID"])
+		if err != nil {
+			s.Logger.Error("[deleteOrgKey] error ", err)
+			helpers.WriteError(ctx, err, w)
+			return
+		}
+
+		err = s.lowerThirdsService.DeleteAPIKey(ctx, orgID, keyID)
+		if err != nil {
+			s.Logger.Error("[deleteOrgKey] DeleteAPIKey error ", err)
+			helpers.WriteError(ctx, err, w)
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	})
+}
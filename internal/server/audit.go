@@ -0,0 +1,40 @@
+package server
+
+import (
+	"context"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"lowerthirdsapi/internal/helpers"
+	"net/http"
+)
+
+func (s *Server) getOrgAudit() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		ctx := req.Context()
+
+		orgID, err := uuid.Parse(mux.Vars(req)["OrgID"])
+		if err != nil {
+			s.Logger.Error("[getOrgAudit] error ", err)
+			helpers.WriteError(ctx, err, w)
+			return
+		}
+
+		qp := helpers.GetQueryParams(ctx)
+		err = auditQueryParams(req.URL.Query(), &qp)
+		if err != nil {
+			s.Logger.Error("[getOrgAudit] ", err)
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		ctx = context.WithValue(ctx, helpers.QueryParametersKey, qp)
+
+		records, total, err := s.lowerThirdsService.GetAuditLogByOrg(ctx, orgID)
+		if err != nil {
+			s.Logger.Error("[getOrgAudit] error ", err)
+			helpers.WriteError(ctx, err, w)
+			return
+		}
+
+		s.writeList(w, req, records, total)
+	})
+}
@@ -2,42 +2,27 @@ package server
 
 import (
 	"context"
-	"fmt"
+	"database/sql"
+	"errors"
 	"github.com/sirupsen/logrus"
+	"lowerthirdsapi/internal/auth"
+	"lowerthirdsapi/internal/entities"
 	"lowerthirdsapi/internal/helpers"
 	"net/http"
 	"strings"
-	"time"
 
-	"github.com/MicahParks/keyfunc"
-	"github.com/golang-jwt/jwt/v4"
 	"github.com/gorilla/mux"
+	"github.com/gorilla/websocket"
 )
 
-const (
-	FirebaseProjectID = "lower3-d26f2"
-	FirebaseIssuer    = "https://securetoken.google.com/" + FirebaseProjectID
-)
-
-var firebaseJWKS *keyfunc.JWKS
-
-func init() {
-	jwksURL := "https://www.googleapis.com/robot/v1/metadata/x509/securetoken@system.gserviceaccount.com"
-
-	var err error
-	firebaseJWKS, err = keyfunc.Get(jwksURL, keyfunc.Options{
-		RefreshInterval:   time.Hour,
-		RefreshRateLimit:  time.Minute * 5,
-		RefreshTimeout:    10 * time.Second,
-		RefreshUnknownKID: true,
-	})
-	if err != nil {
-		panic(fmt.Sprintf("failed to get JWKS: %v", err))
-	}
+// apiKeyAuthenticator looks up the API keys devices send in place of ID tokens
+type apiKeyAuthenticator interface {
+	AuthenticateAPIKey(ctx context.Context, key string) (*entities.APIKey, error)
 }
 
-// authClaims is a middleware function to check auth headers
-func authClaims(log *logrus.Entry) mux.MiddlewareFunc {
+// authClaims is a middleware function to check auth headers. The bearer token is a Firebase ID token, or an API key
+// whose user stands in for the signed-in account.
+func authClaims(log *logrus.Entry, verifier auth.TokenVerifier, keys apiKeyAuthenticator) mux.MiddlewareFunc {
 	return mux.MiddlewareFunc(func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			log.Debug("authClaims middleware")
@@ -55,45 +40,65 @@ func authClaims(log *logrus.Entry) mux.MiddlewareFunc {
 				return
 			}
 
-			a := r.Header.Get("Authorization")
-			if a == "" || !strings.HasPrefix(a, "Bearer ") {
+			tokenStr, ok := bearerToken(r)
+			if !ok {
 				http.Error(w, "missing or invalid token", http.StatusUnauthorized)
 				return
 			}
 
-			tokenStr := strings.TrimPrefix(a, "Bearer ")
-
-			token, _ := jwt.Parse(tokenStr, firebaseJWKS.Keyfunc)
-			// TODO: come back to this. It's not validating the signature
-			// if err != nil || !token.Valid {
-			// 	log.Debugf("token: %+v", token)
-			// 	http.Error(w, "invalid token: "+err.Error(), http.StatusUnauthorized)
-			// 	return
-			// }
+			if strings.HasPrefix(tokenStr, entities.APIKeyPrefix) {
+				key, err := keys.AuthenticateAPIKey(r.Context(), tokenStr)
+				if errors.Is(err, sql.ErrNoRows) {
+					http.Error(w, "invalid API key", http.StatusUnauthorized)
+					return
+				}
+				if err != nil {
+					log.Error(err)
+					helpers.WriteError(r.Context(), err, w)
+					return
+				}
 
-			claims, ok := token.Claims.(jwt.MapClaims)
-			if !ok {
-				http.Error(w, "invalid claims", http.StatusUnauthorized)
+				ctx := context.WithValue(r.Context(), helpers.SocialIDKey, key.SocialID())
+				ctx = context.WithValue(ctx, helpers.APIKeyKey, key)
+				next.ServeHTTP(w, r.WithContext(ctx))
 				return
 			}
 
-			// Verify standard Firebase claims
-			if claims["aud"] != FirebaseProjectID {
-				http.Error(w, "invalid audience", http.StatusUnauthorized)
-				return
-			}
-			if claims["iss"] != FirebaseIssuer {
-				http.Error(w, "invalid issuer", http.StatusUnauthorized)
-				return
-			}
-			if _, ok := claims["user_id"].(string); !ok {
-				http.Error(w, "user_id claim missing or invalid", http.StatusUnauthorized)
+			claims, err := verifier.Verify(r.Context(), tokenStr)
+			if err != nil {
+				log.Debug("token rejected: ", err)
+				http.Error(w, err.Error(), http.StatusUnauthorized)
 				return
 			}
 
-			socialID := claims["user_id"].(string)
-			ctx := context.WithValue(r.Context(), helpers.SocialIDKey, socialID)
+			ctx := context.WithValue(r.Context(), helpers.SocialIDKey, claims.UserID)
+			ctx = context.WithValue(ctx, helpers.AuthKey, claims)
 			next.ServeHTTP(w, r.WithContext(ctx))
 		})
 	})
 }
+
+// queryTokenRoutes are the routes browsers open with EventSource, which can't set headers either
+var queryTokenRoutes = map[string]bool{
+	"getMeetingEvents": true,
+}
+
+// bearerToken reads the token from the Authorization header. Browsers can't set headers on a WebSocket handshake or
+// an EventSource, so upgrade requests and event streams may pass it in the access_token query parameter instead.
+func bearerToken(r *http.Request) (string, bool) {
+	a := r.Header.Get("Authorization")
+	if a == "" && (websocket.IsWebSocketUpgrade(r) || queryTokenRoute(r)) {
+		token := r.URL.Query().Get("access_token")
+		return token, token != ""
+	}
+	if !strings.HasPrefix(a, "Bearer ") {
+		return "", false
+	}
+	return strings.TrimPrefix(a, "Bearer "), true
+}
+
+// queryTokenRoute reports whether the request matched one of queryTokenRoutes
+func queryTokenRoute(r *http.Request) bool {
+	route := mux.CurrentRoute(r)
+	return route != nil && queryTokenRoutes[route.GetName()]
+}
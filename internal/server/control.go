@@ -0,0 +1,288 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"github.com/gorilla/websocket"
+	"lowerthirdsapi/internal/apierrors"
+	"lowerthirdsapi/internal/entities"
+	"lowerthirdsapi/internal/events"
+	"lowerthirdsapi/internal/helpers"
+	"net/http"
+	"sync"
+	"time"
+)
+
+const (
+	controlWriteWait  = 10 * time.Second
+	controlPongWait   = 60 * time.Second
+	controlPingPeriod = controlPongWait * 9 / 10
+	controlMaxMessage = 4096
+)
+
+// Control message types sent to operator consoles
+const (
+	ControlMessageState = "state"
+	ControlMessageTimer = "timer"
+	ControlMessageAck   = "ack"
+	ControlMessageError = "error"
+)
+
+var controlUpgrader = websocket.Upgrader{
+	ReadBufferSize:  1024,
+	WriteBufferSize: 1024,
+	// Consoles authenticate with a token rather than cookies, so any origin may connect
+	CheckOrigin: func(r *http.Request) bool { return true },
+}
+
+// ControlCommand is a live action sent by an operator console, or a timer action when Timer is set. Timer actions
+// apply to ItemID's timer, or the program item's when ItemID is empty, and add_time adds Seconds. ID is echoed back
+// in the reply, and Version is the state or timer version the console last saw.
+type ControlCommand struct {
+	ID      string               `json:"id"`
+	Type    entities.LiveAction  `json:"type"`
+	Timer   entities.TimerAction `json:"timer"`
+	ItemID  uuid.UUID            `json:"item_id"`
+	Seconds int                  `json:"seconds"`
+	Version int64                `json:"version"`
+}
+
+// ControlMessage is sent to operator consoles: a state or timer snapshot, or the ack or error for a command.
+// Commands are applied last-writer-wins; Conflict is set on an ack when another operator changed the state or timer
+// after the version the command was based on.
+type ControlMessage struct {
+	Type     string              `json:"type"`
+	ID       string              `json:"id,omitempty"`
+	Version  int64               `json:"version"`
+	Conflict bool                `json:"conflict,omitempty"`
+	State    *entities.LiveState `json:"state,omitempty"`
+	Timer    *entities.Timer     `json:"timer,omitempty"`
+	Error    *apierrors.Error    `json:"error,omitempty"`
+}
+
+// controlConn serializes writes to a console's connection
+type controlConn struct {
+	mu   sync.Mutex
+	conn *websocket.Conn
+}
+
+func (c *controlConn) write(msg ControlMessage) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	_ = c.conn.SetWriteDeadline(time.Now().Add(controlWriteWait))
+	return c.conn.WriteJSON(msg)
+}
+
+func (c *controlConn) ping() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(controlWriteWait))
+}
+
+func (s *Server) meetingControl() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		ctx := req.Context()
+
+		meetingID, err := uuid.Parse(mux.Vars(req)["MeetingID"])
+		if err != nil {
+			s.Logger.Error("[meetingControl] error ", err)
+			helpers.WriteError(ctx, err, w)
+			return
+		}
+
+		// Subscribe before reading the state so no change falls between the two
+		sub, err := s.lowerThirdsService.SubscribeMeetingEvents(ctx, meetingID, 0)
+		if err != nil {
+			s.Logger.Error("[meetingControl] error ", err)
+			helpers.WriteError(ctx, err, w)
+			return
+		}
+		defer sub.Close()
+
+		liveState, err := s.lowerThirdsService.GetLiveState(ctx, meetingID)
+		if err != nil {
+			s.Logger.Error("[meetingControl] error ", err)
+			helpers.WriteError(ctx, err, w)
+			return
+		}
+		timers, err := s.lowerThirdsService.GetMeetingTimers(ctx, meetingID)
+		if err != nil {
+			s.Logger.Error("[meetingControl] error ", err)
+			helpers.WriteError(ctx, err, w)
+			return
+		}
+
+		ws, err := controlUpgrader.Upgrade(w, req, nil)
+		if err != nil {
+			// The upgrader has already written the error response
+			s.Logger.Error("[meetingControl] upgrade error ", err)
+			return
+		}
+		defer ws.Close()
+		conn := &controlConn{conn: ws}
+
+		// The connection outlives the request, so stop when the console goes away
+		ctx, cancel := context.WithCancel(ctx)
+		defer cancel()
+		go s.readControlCommands(ctx, cancel, conn, meetingID)
+
+		err = conn.write(ControlMessage{Type: ControlMessageState, Version: liveState.Version, State: liveState})
+		if err != nil {
+			return
+		}
+		for _, timer := range *timers {
+			err = conn.write(ControlMessage{Type: ControlMessageTimer, Version: timer.Version, Timer: &timer})
+			if err != nil {
+				return
+			}
+		}
+
+		ping := time.NewTicker(controlPingPeriod)
+		defer ping.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case event, ok := <-sub.C:
+				if !ok {
+					// Dropped for falling behind; the console reconnects for a fresh snapshot
+					_ = ws.WriteControl(websocket.CloseMessage,
+						websocket.FormatCloseMessage(websocket.CloseTryAgainLater, "fell behind"),
+						time.Now().Add(controlWriteWait))
+					return
+				}
+				msg, ok, err := controlEvent(event)
+				if err != nil {
+					s.Logger.Error("[meetingControl] event error ", err)
+					continue
+				}
+				if !ok {
+					continue
+				}
+				if err := conn.write(msg); err != nil {
+					return
+				}
+			case <-ping.C:
+				if err := conn.ping(); err != nil {
+					return
+				}
+			}
+		}
+	})
+}
+
+// readControlCommands applies commands from a console until the connection closes, then cancels the connection
+func (s *Server) readControlCommands(ctx context.Context, cancel context.CancelFunc, conn *controlConn, meetingID uuid.UUID) {
+	defer cancel()
+
+	ws := conn.conn
+	ws.SetReadLimit(controlMaxMessage)
+	_ = ws.SetReadDeadline(time.Now().Add(controlPongWait))
+	ws.SetPongHandler(func(string) error {
+		return ws.SetReadDeadline(time.Now().Add(controlPongWait))
+	})
+
+	for {
+		var cmd ControlCommand
+		err := ws.ReadJSON(&cmd)
+		var syntaxErr *json.SyntaxError
+		var typeErr *json.UnmarshalTypeError
+		if errors.As(err, &syntaxErr) || errors.As(err, &typeErr) {
+			reply := controlError("", apierrors.New(http.StatusBadRequest, "INVALID_COMMAND", "Invalid command", err.Error()))
+			if conn.write(reply) != nil {
+				return
+			}
+			continue
+		}
+		if err != nil {
+			if websocket.IsUnexpectedCloseError(err, websocket.CloseGoingAway, websocket.CloseNormalClosure) {
+				s.Logger.Debug("[meetingControl] read error ", err)
+			}
+			return
+		}
+
+		if cmd.Timer != "" {
+			if s.applyTimerCommand(ctx, conn, meetingID, cmd) != nil {
+				return
+			}
+			continue
+		}
+
+		liveState, err := s.lowerThirdsService.UpdateLiveState(ctx, meetingID, cmd.Type, cmd.ItemID)
+		if err != nil {
+			s.Logger.Debug("[meetingControl] UpdateLiveState error ", err)
+			if conn.write(controlError(cmd.ID, err)) != nil {
+				return
+			}
+			continue
+		}
+
+		err = conn.write(ControlMessage{
+			Type:     ControlMessageAck,
+			ID:       cmd.ID,
+			Version:  liveState.Version,
+			Conflict: cmd.Version < liveState.Version-1,
+			State:    liveState,
+		})
+		if err != nil {
+			return
+		}
+	}
+}
+
+// applyTimerCommand applies a timer command and replies to it, returning an error only when the reply can't be sent
+func (s *Server) applyTimerCommand(ctx context.Context, conn *controlConn, meetingID uuid.UUID, cmd ControlCommand) error {
+	itemID := cmd.ItemID
+	if itemID == uuid.Nil {
+		liveState, err := s.lowerThirdsService.GetLiveState(ctx, meetingID)
+		if err != nil {
+			return conn.write(controlError(cmd.ID, err))
+		}
+		if !liveState.ProgramItemID.Valid {
+			return conn.write(controlError(cmd.ID, apierrors.New(http.StatusConflict, "TIMER_CONFLICT", "Timer conflict",
+				entities.ErrNoProgram.Error())))
+		}
+		itemID = liveState.ProgramItemID.UUID
+	}
+
+	timer, err := s.lowerThirdsService.UpdateItemTimer(ctx, itemID, cmd.Timer, time.Duration(cmd.Seconds)*time.Second)
+	if err != nil {
+		s.Logger.Debug("[meetingControl] UpdateItemTimer error ", err)
+		return conn.write(controlError(cmd.ID, err))
+	}
+	return conn.write(ControlMessage{
+		Type:     ControlMessageAck,
+		ID:       cmd.ID,
+		Version:  timer.Version,
+		Conflict: cmd.Version < timer.Version-1,
+		Timer:    timer,
+	})
+}
+
+// controlEvent turns a meeting event into the message consoles get for it. Only live and timer changes are sent.
+func controlEvent(event events.Event) (ControlMessage, bool, error) {
+	switch event.Type {
+	case events.LiveChanged:
+		var state entities.LiveState
+		if err := json.Unmarshal(event.Data, &state); err != nil {
+			return ControlMessage{}, false, err
+		}
+		return ControlMessage{Type: ControlMessageState, Version: state.Version, State: &state}, true, nil
+	case events.TimerChanged:
+		var timer entities.Timer
+		if err := json.Unmarshal(event.Data, &timer); err != nil {
+			return ControlMessage{}, false, err
+		}
+		return ControlMessage{Type: ControlMessageTimer, Version: timer.Version, Timer: &timer}, true, nil
+	default:
+		return ControlMessage{}, false, nil
+	}
+}
+
+// controlError builds the error reply for a command
+func controlError(id string, err error) ControlMessage {
+	return ControlMessage{Type: ControlMessageError, ID: id, Error: apierrors.FromError(err)}
+}
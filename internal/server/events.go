@@ -0,0 +1,115 @@
+package server
+
+import (
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"lowerthirdsapi/internal/events"
+	"lowerthirdsapi/internal/helpers"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// heartbeatInterval keeps idle event streams open through proxies
+const heartbeatInterval = 15 * time.Second
+
+func (s *Server) getMeetingEvents() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		ctx := req.Context()
+
+		meetingID, err := uuid.Parse(mux.Vars(req)["MeetingID"])
+		if err != nil {
+			s.Logger.Error("[getMeetingEvents] error ", err)
+			helpers.WriteError(ctx, err, w)
+			return
+		}
+
+		lastEventID, err := parseLastEventID(req)
+		if err != nil {
+			http.Error(w, "Invalid Last-Event-ID", http.StatusBadRequest)
+			return
+		}
+
+		sub, err := s.lowerThirdsService.SubscribeMeetingEvents(ctx, meetingID, lastEventID)
+		if err != nil {
+			s.Logger.Error("[getMeetingEvents] error ", err)
+			helpers.WriteError(ctx, err, w)
+			return
+		}
+		defer sub.Close()
+
+		s.streamEvents(w, req, sub)
+	})
+}
+
+// streamEvents writes a subscription's replay and then its live events as a text/event-stream until the client
+// goes away
+func (s *Server) streamEvents(w http.ResponseWriter, req *http.Request, sub *events.Subscription) {
+	ctx := req.Context()
+
+	// The stream outlives the server's write timeout
+	rc := http.NewResponseController(w)
+	err := rc.SetWriteDeadline(time.Time{})
+	if err != nil {
+		s.Logger.Error("[streamEvents] error clearing write deadline ", err)
+		helpers.WriteError(ctx, err, w)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+
+	for _, event := range sub.Replay {
+		if err := writeEvent(w, event); err != nil {
+			return
+		}
+	}
+	if err := rc.Flush(); err != nil {
+		s.Logger.Error("[streamEvents] flush error ", err)
+		return
+	}
+
+	heartbeat := time.NewTicker(heartbeatInterval)
+	defer heartbeat.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case event, ok := <-sub.C:
+			if !ok {
+				// Dropped for falling behind; the client resumes from its last event
+				return
+			}
+			if err := writeEvent(w, event); err != nil {
+				return
+			}
+		case <-heartbeat.C:
+			if _, err := fmt.Fprint(w, ": heartbeat\n\n"); err != nil {
+				return
+			}
+		}
+		if err := rc.Flush(); err != nil {
+			return
+		}
+	}
+}
+
+// parseLastEventID reads the ID of the last event a reconnecting client received, which browsers resend when
+// they reconnect
+func parseLastEventID(req *http.Request) (uint64, error) {
+	lastEventIDStr := req.Header.Get("Last-Event-ID")
+	if lastEventIDStr == "" {
+		return 0, nil
+	}
+	return strconv.ParseUint(lastEventIDStr, 10, 64)
+}
+
+// writeEvent writes an event in the text/event-stream format
+func writeEvent(w http.ResponseWriter, event events.Event) error {
+	_, err := fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", event.ID, event.Type, event.Data)
+	return err
+}
@@ -0,0 +1,50 @@
+package server
+
+import (
+	"encoding/json"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"lowerthirdsapi/internal/helpers"
+	"net/http"
+)
+
+func (s *Server) getHymns() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		ctx := req.Context()
+		hymns, total, err := s.lowerThirdsService.GetHymns(ctx)
+		if err != nil {
+			s.Logger.Error(err)
+			helpers.WriteError(ctx, err, w)
+			return
+		}
+
+		s.writeList(w, req, hymns, total)
+	})
+}
+
+func (s *Server) getHymn() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		ctx := req.Context()
+
+		hymnID, err := uuid.Parse(mux.Vars(req)["HymnID"])
+		if err != nil {
+			s.Logger.Error("[getHymn] error ", err)
+			helpers.WriteError(ctx, err, w)
+			return
+		}
+
+		hymn, err := s.lowerThirdsService.GetHymn(ctx, hymnID)
+		if err != nil {
+			s.Logger.Error(err)
+			helpers.WriteError(ctx, err, w)
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+		err = json.NewEncoder(w).Encode(hymn)
+		if err != nil {
+			s.Logger.Error(err)
+			helpers.WriteError(ctx, err, w)
+		}
+	})
+}
@@ -0,0 +1,173 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"lowerthirdsapi/internal/apierrors"
+	"lowerthirdsapi/internal/entities"
+	"lowerthirdsapi/internal/helpers"
+	"lowerthirdsapi/internal/invitations"
+	"lowerthirdsapi/internal/mail"
+	"net/http"
+	"time"
+)
+
+// InvitationResponse is a new invitation with the link emailed to the invitee
+type InvitationResponse struct {
+	entities.Invitation
+	URL string `json:"url"`
+}
+
+// postOrgInvitation invites an email address to the org and emails them a signed link to accept it. If the email
+// can't be sent the invitation is revoked again.
+func (s *Server) postOrgInvitation() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		ctx := req.Context()
+
+		orgID, err := uuid.Parse(mux.Vars(req)["OrgID"])
+		if err != nil {
+			s.Logger.Error("[postOrgInvitation] error ", err)
+			helpers.WriteError(ctx, err, w)
+			return
+		}
+
+		var invitation entities.Invitation
+		if err := json.NewDecoder(req.Body).Decode(&invitation); err != nil {
+			s.Logger.Error("[postOrgInvitation] ", err)
+			helpers.WriteError(ctx, err, w)
+			return
+		}
+		invitation.InvitationID = uuid.New()
+		invitation.ExpiresDT = time.Now().Add(s.invitations.TTL).UTC().Truncate(time.Second)
+
+		token, err := s.signer.Sign(invitation.InvitationID, invitation.ExpiresDT)
+		if err != nil {
+			s.Logger.Error("[postOrgInvitation] Sign error ", err)
+			helpers.WriteError(ctx, err, w)
+			return
+		}
+
+		err = s.lowerThirdsService.CreateInvitation(ctx, orgID, &invitation)
+		if err != nil {
+			s.Logger.Error("[postOrgInvitation] CreateInvitation error ", err)
+			helpers.WriteError(ctx, err, w)
+			return
+		}
+
+		url := s.invitations.URL + token
+		err = s.sendInvitation(ctx, invitation, url)
+		if err != nil {
+			s.Logger.Error("[postOrgInvitation] sendInvitation error ", err)
+			if revokeErr := s.lowerThirdsService.DeleteInvitation(ctx, orgID, invitation.InvitationID); revokeErr != nil {
+				s.Logger.Error("[postOrgInvitation] DeleteInvitation error ", revokeErr)
+			}
+			helpers.WriteError(ctx, err, w)
+			return
+		}
+
+		w.WriteHeader(http.StatusCreated)
+		_ = json.NewEncoder(w).Encode(InvitationResponse{Invitation: invitation, URL: url})
+	})
+}
+
+// sendInvitation emails the invitee the link to accept an invitation
+func (s *Server) sendInvitation(ctx context.Context, invitation entities.Invitation, url string) error {
+	org, err := s.lowerThirdsService.GetOrg(ctx, invitation.OrgID)
+	if err != nil {
+		return err
+	}
+	return s.mailer.Send(ctx, mail.Message{
+		To:      invitation.Email,
+		Subject: fmt.Sprintf("You're invited to %s", org.Name),
+		Body: fmt.Sprintf("You've been invited to join %s as %s.\n\nAccept the invitation here:\n%s\n\n"+
+			"The link expires %s.\n", org.Name, invitation.Role, url, invitation.ExpiresDT.Format(time.RFC1123)),
+	})
+}
+
+func (s *Server) getOrgInvitations() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		ctx := req.Context()
+
+		orgID, err := uuid.Parse(mux.Vars(req)["OrgID"])
+		if err != nil {
+			s.Logger.Error("[getOrgInvitations] error ", err)
+			helpers.WriteError(ctx, err, w)
+			return
+		}
+
+		invitations, err := s.lowerThirdsService.GetInvitationsByOrg(ctx, orgID)
+		if err != nil {
+			s.Logger.Error("[getOrgInvitations] error ", err)
+			helpers.WriteError(ctx, err, w)
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+		_ = json.NewEncoder(w).Encode(invitations)
+	})
+}
+
+func (s *Server) deleteOrgInvitation() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		ctx := req.Context()
+
+		vars := mux.Vars(req)
+		orgID, err := uuid.Parse(vars["OrgID"])
+		if err != nil {
+			s.Logger.Error("[deleteOrgInvitation] error ", err)
+			helpers.WriteError(ctx, err, w)
+			return
+		}
+		invitationID, err := uuid.Parse(vars["InvitationID"])
+		if err != nil {
+			s.Logger.Error("[deleteOrgInvitation] error ", err)
+			helpers.WriteError(ctx, err, w)
+			return
+		}
+
+		err = s.lowerThirdsService.DeleteInvitation(ctx, orgID, invitationID)
+		if err != nil {
+			s.Logger.Error("[deleteOrgInvitation] DeleteInvitation error ", err)
+			helpers.WriteError(ctx, err, w)
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	})
+}
+
+// acceptInvitation adds the caller to the org of the invitation the token in the link is for
+func (s *Server) acceptInvitation() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		ctx := req.Context()
+
+		invitationID, err := s.signer.Verify(mux.Vars(req)["Token"], time.Now())
+		switch {
+		case errors.Is(err, invitations.ErrInvalidToken):
+			err = apierrors.New(http.StatusNotFound, "INVITATION_NOT_FOUND", "Invitation not found",
+				"The invitation link isn't valid.")
+		case errors.Is(err, invitations.ErrExpiredToken):
+			err = apierrors.New(http.StatusGone, "INVITATION_CLOSED", "Invitation closed",
+				"The invitation link has expired.")
+		}
+		if err != nil {
+			s.Logger.Error("[acceptInvitation] error ", err)
+			helpers.WriteError(ctx, err, w)
+			return
+		}
+
+		invitation, err := s.lowerThirdsService.AcceptInvitation(ctx, invitationID)
+		if err != nil {
+			s.Logger.Error("[acceptInvitation] AcceptInvitation error ", err)
+			helpers.WriteError(ctx, err, w)
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+		_ = json.NewEncoder(w).Encode(invitation)
+	})
+}
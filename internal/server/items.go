@@ -1,13 +1,14 @@
 package server
 
 import (
+    "context"
     "encoding/json"
     "io"
     "lowerthirdsapi/internal/entities"
     "lowerthirdsapi/internal/helpers"
+    "lowerthirdsapi/internal/storage"
     "net/http"
 
-    "github.com/go-sql-driver/mysql"
     "github.com/google/uuid"
     "github.com/gorilla/mux"
 )
@@ -37,15 +38,45 @@ func (s *Server) deleteItem() http.Handler {
 func (s *Server) getItems() http.Handler {
     return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
         ctx := req.Context()
-        items, err := s.lowerThirdsService.GetItems(ctx)
+        items, total, err := s.lowerThirdsService.GetItems(ctx)
         if err != nil {
             s.Logger.Error("[getItems] error ", err)
             helpers.WriteError(ctx, err, w)
             return
         }
+        s.writeList(w, req, items, total)
+    })
+}
+
+func (s *Server) getItemSlides() http.Handler {
+    return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+        ctx := req.Context()
+
+        itemID, err := uuid.Parse(mux.Vars(req)["ItemID"])
+        if err != nil {
+            s.Logger.Error("[getItemSlides] error ", err)
+            helpers.WriteError(ctx, err, w)
+            return
+        }
+
+        qp := helpers.GetQueryParams(ctx)
+        err = slideQueryParams(req.URL.Query(), &qp)
+        if err != nil {
+            s.Logger.Error("[getItemSlides] ", err)
+            http.Error(w, err.Error(), http.StatusBadRequest)
+            return
+        }
+        ctx = context.WithValue(ctx, helpers.QueryParametersKey, qp)
+
+        slides, err := s.lowerThirdsService.GetItemSlides(ctx, itemID)
+        if err != nil {
+            s.Logger.Error("[getItemSlides] error ", err)
+            helpers.WriteError(ctx, err, w)
+            return
+        }
         w.Header().Set("Content-Type", "application/json")
         w.WriteHeader(http.StatusOK)
-        _ = json.NewEncoder(w).Encode(items)
+        _ = json.NewEncoder(w).Encode(slides)
     })
 }
 
@@ -73,7 +104,7 @@ func (s *Server) postItem() http.Handler {
         err = s.lowerThirdsService.CreateItem(ctx, item)
         if err != nil {
             // Check for MySQL duplicate entry error
-            if mysqlErr, ok := err.(*mysql.MySQLError); ok && mysqlErr.Number == 1062 {
+            if storage.IsDuplicate(err) {
                 http.Error(w, "[postItem] already exists", http.StatusConflict)
                 return
             }
@@ -119,7 +150,7 @@ func (s *Server) updateItem() http.Handler {
         err = s.lowerThirdsService.UpdateItem(ctx, itemID, item)
         if err != nil {
             // Check for MySQL duplicate entry error
-            if mysqlErr, ok := err.(*mysql.MySQLError); ok && mysqlErr.Number == 1062 {
+            if storage.IsDuplicate(err) {
                 http.Error(w, "[updateItem] already exists", http.StatusConflict)
                 return
             }
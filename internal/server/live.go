@@ -0,0 +1,76 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"io"
+	"lowerthirdsapi/internal/entities"
+	"lowerthirdsapi/internal/helpers"
+	"net/http"
+)
+
+// LiveActionRequest is the optional body of a live action, naming the item to take or cue
+type LiveActionRequest struct {
+	ItemID uuid.UUID `json:"item_id"`
+}
+
+func (s *Server) getLiveState() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		ctx := req.Context()
+
+		meetingID, err := uuid.Parse(mux.Vars(req)["MeetingID"])
+		if err != nil {
+			s.Logger.Error("[getLiveState] error ", err)
+			helpers.WriteError(ctx, err, w)
+			return
+		}
+
+		liveState, err := s.lowerThirdsService.GetLiveState(ctx, meetingID)
+		if err != nil {
+			s.Logger.Error("[getLiveState] error ", err)
+			helpers.WriteError(ctx, err, w)
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+		err = json.NewEncoder(w).Encode(liveState)
+		if err != nil {
+			s.Logger.Error(err)
+			helpers.WriteError(ctx, err, w)
+		}
+	})
+}
+
+func (s *Server) updateLiveState() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		ctx := req.Context()
+
+		meetingID, err := uuid.Parse(mux.Vars(req)["MeetingID"])
+		if err != nil {
+			s.Logger.Error("[updateLiveState] error ", err)
+			helpers.WriteError(ctx, err, w)
+			return
+		}
+		action := entities.LiveAction(mux.Vars(req)["Action"])
+
+		// Only take and cue need a body
+		var body LiveActionRequest
+		if err := json.NewDecoder(req.Body).Decode(&body); err != nil && !errors.Is(err, io.EOF) {
+			s.Logger.Error("[updateLiveState] ", err)
+			helpers.WriteError(ctx, err, w)
+			return
+		}
+
+		liveState, err := s.lowerThirdsService.UpdateLiveState(ctx, meetingID, action, body.ItemID)
+		if err != nil {
+			s.Logger.Error("[updateLiveState] UpdateLiveState error ", err)
+			helpers.WriteError(ctx, err, w)
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+		_ = json.NewEncoder(w).Encode(liveState)
+	})
+}
@@ -0,0 +1,72 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"gopkg.in/guregu/null.v4"
+	"lowerthirdsapi/internal/apierrors"
+	"lowerthirdsapi/internal/auth"
+	"lowerthirdsapi/internal/entities"
+	"lowerthirdsapi/internal/helpers"
+	"net/http"
+)
+
+// MeResponse is the signed-in user with the orgs they belong to and their role in each
+type MeResponse struct {
+	entities.User
+	Orgs []entities.Membership `json:"orgs"`
+}
+
+// getMe returns the signed-in user, making one on their first login
+func (s *Server) getMe() http.Handler {
+	return s.me(false)
+}
+
+// putMe returns the signed-in user like getMe, after updating their email, name and photo from the token
+func (s *Server) putMe() http.Handler {
+	return s.me(true)
+}
+
+// me provisions the user for the token's claims. These routes run without resolveUser, since the user may not exist
+// yet.
+func (s *Server) me(refresh bool) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		ctx := req.Context()
+
+		claims, ok := ctx.Value(helpers.AuthKey).(*auth.Claims)
+		if !ok {
+			err := apierrors.New(http.StatusForbidden, "FORBIDDEN", "Forbidden", "only signed-in accounts have a user to provision")
+			s.Logger.Error("[me] error ", err)
+			helpers.WriteError(ctx, err, w)
+			return
+		}
+
+		profile := &entities.User{
+			SocialID: null.StringFrom(claims.UserID),
+			Email:    claims.Email,
+			FullName: null.NewString(claims.Name, claims.Name != ""),
+			PhotoURL: null.NewString(claims.Picture, claims.Picture != ""),
+		}
+		user, err := s.lowerThirdsService.ProvisionUser(ctx, profile, claims.EmailVerified, refresh)
+		if err != nil {
+			s.Logger.Error("[me] error ", err)
+			helpers.WriteError(ctx, err, w)
+			return
+		}
+
+		ctx = context.WithValue(ctx, helpers.UserIDKey, user)
+		orgs, err := s.lowerThirdsService.GetMembershipsByUser(ctx, user.UserID)
+		if err != nil {
+			s.Logger.Error("[me] error ", err)
+			helpers.WriteError(ctx, err, w)
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+		err = json.NewEncoder(w).Encode(MeResponse{User: *user, Orgs: *orgs})
+		if err != nil {
+			s.Logger.Error(err)
+			helpers.WriteError(ctx, err, w)
+		}
+	})
+}
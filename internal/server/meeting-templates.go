@@ -0,0 +1,195 @@
+package server
+
+import (
+	"encoding/json"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"lowerthirdsapi/internal/apierrors"
+	"lowerthirdsapi/internal/entities"
+	"lowerthirdsapi/internal/helpers"
+	"net/http"
+	"time"
+)
+
+// InstantiateTemplateRequest is the body of a template instantiation: the date of the new meeting
+type InstantiateTemplateRequest struct {
+	Date time.Time `json:"date"`
+}
+
+func (s *Server) getOrgTemplates() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		ctx := req.Context()
+
+		orgID, err := uuid.Parse(mux.Vars(req)["OrgID"])
+		if err != nil {
+			s.Logger.Error("[getOrgTemplates] error ", err)
+			helpers.WriteError(ctx, err, w)
+			return
+		}
+
+		templates, err := s.lowerThirdsService.GetTemplatesByOrg(ctx, orgID)
+		if err != nil {
+			s.Logger.Error("[getOrgTemplates] GetTemplatesByOrg error ", err)
+			helpers.WriteError(ctx, err, w)
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+		_ = json.NewEncoder(w).Encode(templates)
+	})
+}
+
+func (s *Server) getOrgTemplate() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		ctx := req.Context()
+
+		orgID, templateID, err := templateVars(req)
+		if err != nil {
+			s.Logger.Error("[getOrgTemplate] error ", err)
+			helpers.WriteError(ctx, err, w)
+			return
+		}
+
+		template, err := s.lowerThirdsService.GetTemplate(ctx, orgID, templateID)
+		if err != nil {
+			s.Logger.Error("[getOrgTemplate] GetTemplate error ", err)
+			helpers.WriteError(ctx, err, w)
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+		_ = json.NewEncoder(w).Encode(template)
+	})
+}
+
+func (s *Server) postOrgTemplate() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		ctx := req.Context()
+
+		orgID, err := uuid.Parse(mux.Vars(req)["OrgID"])
+		if err != nil {
+			s.Logger.Error("[postOrgTemplate] error ", err)
+			helpers.WriteError(ctx, err, w)
+			return
+		}
+
+		var template entities.MeetingTemplate
+		if err := json.NewDecoder(req.Body).Decode(&template); err != nil {
+			s.Logger.Error("[postOrgTemplate] ", err)
+			helpers.WriteError(ctx, err, w)
+			return
+		}
+
+		err = s.lowerThirdsService.CreateTemplate(ctx, orgID, &template)
+		if err != nil {
+			s.Logger.Error("[postOrgTemplate] CreateTemplate error ", err)
+			helpers.WriteError(ctx, err, w)
+			return
+		}
+
+		w.WriteHeader(http.StatusCreated)
+		_ = json.NewEncoder(w).Encode(template)
+	})
+}
+
+func (s *Server) updateOrgTemplate() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		ctx := req.Context()
+
+		orgID, templateID, err := templateVars(req)
+		if err != nil {
+			s.Logger.Error("[updateOrgTemplate] error ", err)
+			helpers.WriteError(ctx, err, w)
+			return
+		}
+
+		var template entities.MeetingTemplate
+		if err := json.NewDecoder(req.Body).Decode(&template); err != nil {
+			s.Logger.Error("[updateOrgTemplate] ", err)
+			helpers.WriteError(ctx, err, w)
+			return
+		}
+
+		err = s.lowerThirdsService.UpdateTemplate(ctx, orgID, templateID, &template)
+		if err != nil {
+			s.Logger.Error("[updateOrgTemplate] UpdateTemplate error ", err)
+			helpers.WriteError(ctx, err, w)
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+		_ = json.NewEncoder(w).Encode(template)
+	})
+}
+
+func (s *Server) deleteOrgTemplate() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		ctx := req.Context()
+
+		orgID, templateID, err := templateVars(req)
+		if err != nil {
+			s.Logger.Error("[deleteOrgTemplate] error ", err)
+			helpers.WriteError(ctx, err, w)
+			return
+		}
+
+		err = s.lowerThirdsService.DeleteTemplate(ctx, orgID, templateID)
+		if err != nil {
+			s.Logger.Error("[deleteOrgTemplate] DeleteTemplate error ", err)
+			helpers.WriteError(ctx, err, w)
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	})
+}
+
+func (s *Server) instantiateOrgTemplate() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		ctx := req.Context()
+
+		orgID, templateID, err := templateVars(req)
+		if err != nil {
+			s.Logger.Error("[instantiateOrgTemplate] error ", err)
+			helpers.WriteError(ctx, err, w)
+			return
+		}
+
+		var body InstantiateTemplateRequest
+		if err := json.NewDecoder(req.Body).Decode(&body); err != nil {
+			s.Logger.Error("[instantiateOrgTemplate] ", err)
+			helpers.WriteError(ctx, err, w)
+			return
+		}
+		if body.Date.IsZero() {
+			s.Logger.Error("[instantiateOrgTemplate] Missing or invalid date")
+			helpers.WriteError(ctx, apierrors.New(http.StatusBadRequest, "INVALID_DATE", "Invalid date",
+				"date is required in RFC3339 format"), w)
+			return
+		}
+
+		meeting, err := s.lowerThirdsService.InstantiateTemplate(ctx, orgID, templateID, body.Date)
+		if err != nil {
+			s.Logger.Error("[instantiateOrgTemplate] InstantiateTemplate error ", err)
+			helpers.WriteError(ctx, err, w)
+			return
+		}
+
+		w.WriteHeader(http.StatusCreated)
+		_ = json.NewEncoder(w).Encode(meeting)
+	})
+}
+
+// templateVars parses the org and template IDs from a template route
+func templateVars(req *http.Request) (uuid.UUID, uuid.UUID, error) {
+	vars := mux.Vars(req)
+	orgID, err := uuid.Parse(vars["OrgID"])
+	if err != nil {
+		return uuid.Nil, uuid.Nil, err
+	}
+	templateID, err := uuid.Parse(vars["TemplateID"])
+	if err != nil {
+		return uuid.Nil, uuid.Nil, err
+	}
+	return orgID, templateID, nil
+}
@@ -1,16 +1,62 @@
 package server
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
-	"github.com/go-sql-driver/mysql"
 	"github.com/google/uuid"
 	"github.com/gorilla/mux"
+	"lowerthirdsapi/internal/apierrors"
 	"lowerthirdsapi/internal/entities"
 	"lowerthirdsapi/internal/helpers"
+	"lowerthirdsapi/internal/storage"
 	"net/http"
+	"time"
 )
 
+// CloneMeetingRequest is the body of a meeting clone: the date of the copy, and the org to put it in if it isn't
+// the meeting's own
+type CloneMeetingRequest struct {
+	Date  time.Time `json:"date"`
+	OrgID uuid.UUID `json:"org_id"`
+}
+
+func (s *Server) cloneMeeting() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		ctx := req.Context()
+
+		meetingID, err := uuid.Parse(mux.Vars(req)["MeetingID"])
+		if err != nil {
+			s.Logger.Error("[cloneMeeting] error ", err)
+			helpers.WriteError(ctx, err, w)
+			return
+		}
+
+		var body CloneMeetingRequest
+		if err := json.NewDecoder(req.Body).Decode(&body); err != nil {
+			s.Logger.Error("[cloneMeeting] ", err)
+			helpers.WriteError(ctx, err, w)
+			return
+		}
+		if body.Date.IsZero() {
+			s.Logger.Error("[cloneMeeting] Missing or invalid date")
+			helpers.WriteError(ctx, apierrors.New(http.StatusBadRequest, "INVALID_DATE", "Invalid date",
+				"date is required in RFC3339 format"), w)
+			return
+		}
+
+		meeting, err := s.lowerThirdsService.CloneMeeting(ctx, meetingID, body.Date, body.OrgID)
+		if err != nil {
+			s.Logger.Error("[cloneMeeting] CloneMeeting error ", err)
+			helpers.WriteError(ctx, err, w)
+			return
+		}
+
+		w.WriteHeader(http.StatusCreated)
+		_ = json.NewEncoder(w).Encode(meeting)
+	})
+}
+
 func (s *Server) deleteMeeting() http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
 		ctx := req.Context()
@@ -36,37 +82,47 @@ func (s *Server) deleteMeeting() http.Handler {
 func (s *Server) getMeetings() http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
 		ctx := req.Context()
-		meetings, err := s.lowerThirdsService.GetMeetings(ctx)
+		meetings, total, err := s.lowerThirdsService.GetMeetings(ctx)
 		if err != nil {
 			s.Logger.Error(err)
 			helpers.WriteError(ctx, err, w)
 			return
 		}
 
-		// Get agenda items for every meeting
 		// TODO: this method should be deprecated. The app should query for items by meeting
-		var meetingsWithItems []entities.Meeting
-		for _, meeting := range *meetings {
-			agendaItems, err := s.lowerThirdsService.GetItemsByMeeting(ctx, meeting.MeetingID)
-			if err != nil {
-				s.Logger.Error(err)
-				helpers.WriteError(ctx, err, w)
-				return
-			}
-
-			meeting.AgendaItems = *agendaItems
-			meetingsWithItems = append(meetingsWithItems, meeting)
-		}
-
-		w.WriteHeader(http.StatusOK)
-		err = json.NewEncoder(w).Encode(meetingsWithItems)
+		meetingsWithItems, err := s.withAgendaItems(ctx, *meetings)
 		if err != nil {
 			s.Logger.Error(err)
 			helpers.WriteError(ctx, err, w)
+			return
 		}
+
+		s.writeList(w, req, meetingsWithItems, total)
 	})
 }
 
+// withAgendaItems fills in the agenda items of meetings, loading them all at once
+func (s *Server) withAgendaItems(ctx context.Context, meetings []entities.Meeting) ([]entities.Meeting, error) {
+	meetingIDs := make([]uuid.UUID, 0, len(meetings))
+	for _, meeting := range meetings {
+		meetingIDs = append(meetingIDs, meeting.MeetingID)
+	}
+	items, err := s.lowerThirdsService.GetItemsByMeetings(ctx, meetingIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	meetingsWithItems := make([]entities.Meeting, 0, len(meetings))
+	for _, meeting := range meetings {
+		meeting.AgendaItems = items[meeting.MeetingID]
+		if meeting.AgendaItems == nil {
+			meeting.AgendaItems = []entities.Item{}
+		}
+		meetingsWithItems = append(meetingsWithItems, meeting)
+	}
+	return meetingsWithItems, nil
+}
+
 func (s *Server) getMeeting() http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
 		ctx := req.Context()
@@ -156,7 +212,7 @@ func (s *Server) postMeeting() http.Handler {
 		err := s.lowerThirdsService.CreateMeeting(ctx, &meeting)
 		if err != nil {
 			// Check for MySQL duplicate entry error
-			if mysqlErr, ok := err.(*mysql.MySQLError); ok && mysqlErr.Number == 1062 {
+			if storage.IsDuplicate(err) {
 				http.Error(w, "[postMeeting] already exists", http.StatusConflict)
 				return
 			}
@@ -200,7 +256,7 @@ func (s *Server) updateMeeting() http.Handler {
 		err = s.lowerThirdsService.UpdateMeeting(ctx, meetingID, &meeting)
 		if err != nil {
 			// Check for MySQL duplicate entry error
-			if mysqlErr, ok := err.(*mysql.MySQLError); ok && mysqlErr.Number == 1062 {
+			if storage.IsDuplicate(err) {
 				http.Error(w, "[updateMeeting] already exists", http.StatusConflict)
 				return
 			}
@@ -213,3 +269,33 @@ func (s *Server) updateMeeting() http.Handler {
 		_ = json.NewEncoder(w).Encode(meeting)
 	})
 }
+
+func (s *Server) updateMeetingOrder() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		ctx := req.Context()
+
+		meetingID, err := uuid.Parse(mux.Vars(req)["MeetingID"])
+		if err != nil {
+			s.Logger.Error("[updateMeetingOrder] error ", err)
+			helpers.WriteError(ctx, err, w)
+			return
+		}
+
+		var itemIDs []uuid.UUID
+		if err := json.NewDecoder(req.Body).Decode(&itemIDs); err != nil {
+			s.Logger.Error("[updateMeetingOrder] ", err)
+			helpers.WriteError(ctx, err, w)
+			return
+		}
+
+		items, err := s.lowerThirdsService.ReorderItems(ctx, meetingID, itemIDs)
+		if err != nil {
+			s.Logger.Error("[updateMeetingOrder] ReorderItems error ", err)
+			helpers.WriteError(ctx, err, w)
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+		_ = json.NewEncoder(w).Encode(items)
+	})
+}
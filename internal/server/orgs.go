@@ -2,11 +2,11 @@ package server
 
 import (
 	"encoding/json"
-	"github.com/go-sql-driver/mysql"
 	"github.com/google/uuid"
 	"github.com/gorilla/mux"
 	"lowerthirdsapi/internal/entities"
 	"lowerthirdsapi/internal/helpers"
+	"lowerthirdsapi/internal/storage"
 	"net/http"
 )
 
@@ -40,19 +40,14 @@ func (s *Server) deleteOrg() http.Handler {
 func (s *Server) getOrgs() http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
 		ctx := req.Context()
-		orgs, err := s.lowerThirdsService.GetOrgs(ctx)
+		orgs, total, err := s.lowerThirdsService.GetOrgs(ctx)
 		if err != nil {
 			s.Logger.Error(err)
 			helpers.WriteError(ctx, err, w)
 			return
 		}
 
-		w.WriteHeader(http.StatusOK)
-		err = json.NewEncoder(w).Encode(orgs)
-		if err != nil {
-			s.Logger.Error(err)
-			helpers.WriteError(ctx, err, w)
-		}
+		s.writeList(w, req, orgs, total)
 	})
 }
 
@@ -94,35 +89,21 @@ func (s *Server) getOrgMeetings() http.Handler {
 			return
 		}
 
-		meetings, err := s.lowerThirdsService.GetMeetingsByOrg(ctx, orgID)
+		meetings, total, err := s.lowerThirdsService.GetMeetingsByOrg(ctx, orgID)
 		if err != nil {
 			s.Logger.Error(err)
 			helpers.WriteError(ctx, err, w)
 			return
 		}
 
-		// Get agenda items for each meeting
-		// TODO: this is an inefficient way to get agenda items for each meeting
-		// consider using a JOIN in the SQL query to get all items in one go
-		var meetingsWithItems []entities.Meeting
-		for _, meeting := range *meetings {
-			agendaItems, err := s.lowerThirdsService.GetItemsByMeeting(ctx, meeting.MeetingID)
-			if err != nil {
-				s.Logger.Error(err)
-				helpers.WriteError(ctx, err, w)
-				return
-			}
-
-			meeting.AgendaItems = *agendaItems
-			meetingsWithItems = append(meetingsWithItems, meeting)
-		}
-
-		w.WriteHeader(http.StatusOK)
-		err = json.NewEncoder(w).Encode(meetingsWithItems)
+		meetingsWithItems, err := s.withAgendaItems(ctx, *meetings)
 		if err != nil {
 			s.Logger.Error(err)
 			helpers.WriteError(ctx, err, w)
+			return
 		}
+
+		s.writeList(w, req, meetingsWithItems, total)
 	})
 }
 
@@ -137,19 +118,14 @@ func (s *Server) getUsersByOrg() http.Handler {
 			return
 		}
 
-		meetings, err := s.lowerThirdsService.GetUsersByOrg(ctx, orgID)
+		users, total, err := s.lowerThirdsService.GetUsersByOrg(ctx, orgID)
 		if err != nil {
 			s.Logger.Error("[getUsersByOrg] error ", err)
 			helpers.WriteError(ctx, err, w)
 			return
 		}
 
-		w.WriteHeader(http.StatusOK)
-		err = json.NewEncoder(w).Encode(meetings)
-		if err != nil {
-			s.Logger.Error(err)
-			helpers.WriteError(ctx, err, w)
-		}
+		s.writeList(w, req, users, total)
 	})
 }
 
@@ -173,7 +149,7 @@ func (s *Server) postOrg() http.Handler {
 		err := s.lowerThirdsService.CreateOrg(ctx, &org)
 		if err != nil {
 			// Check for MySQL duplicate entry error
-			if mysqlErr, ok := err.(*mysql.MySQLError); ok && mysqlErr.Number == 1062 {
+			if storage.IsDuplicate(err) {
 				http.Error(w, "[postOrg] already exists", http.StatusConflict)
 				return
 			}
@@ -205,13 +181,15 @@ func (s *Server) updateOrg() http.Handler {
 			return
 		}
 
-		// Ensure the org ID from the path matches the payload and allow for exclusion in the payload
-		org.OrgID = orgID
+		// Allow for exclusion in the payload; a different org ID is rejected by the service
+		if org.OrgID == uuid.Nil {
+			org.OrgID = orgID
+		}
 
 		err = s.lowerThirdsService.UpdateOrg(ctx, orgID, &org)
 		if err != nil {
 			// Check for MySQL duplicate entry error
-			if mysqlErr, ok := err.(*mysql.MySQLError); ok && mysqlErr.Number == 1062 {
+			if storage.IsDuplicate(err) {
 				http.Error(w, "[updateOrg] already exists", http.StatusConflict)
 				return
 			}
@@ -224,3 +202,71 @@ func (s *Server) updateOrg() http.Handler {
 		_ = json.NewEncoder(w).Encode(org)
 	})
 }
+
+func (s *Server) getOrgMembers() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		ctx := req.Context()
+
+		orgID, err := uuid.Parse(mux.Vars(req)["OrgID"])
+		if err != nil {
+			s.Logger.Error("[getOrgMembers] error ", err)
+			helpers.WriteError(ctx, err, w)
+			return
+		}
+
+		members, err := s.lowerThirdsService.GetOrgUsers(ctx, orgID)
+		if err != nil {
+			s.Logger.Error("[getOrgMembers] error ", err)
+			helpers.WriteError(ctx, err, w)
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+		err = json.NewEncoder(w).Encode(members)
+		if err != nil {
+			s.Logger.Error(err)
+			helpers.WriteError(ctx, err, w)
+		}
+	})
+}
+
+func (s *Server) setOrgMemberRole() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		ctx := req.Context()
+
+		orgID, err := uuid.Parse(mux.Vars(req)["OrgID"])
+		if err != nil {
+			s.Logger.Error("[setOrgMemberRole] error ", err)
+			helpers.WriteError(ctx, err, w)
+			return
+		}
+
+		userID, err := uuid.Parse(mux.Vars(req)["UserID"])
+		if err != nil {
+			s.Logger.Error("[setOrgMemberRole] error ", err)
+			helpers.WriteError(ctx, err, w)
+			return
+		}
+
+		var member entities.OrgUser
+		if err := json.NewDecoder(req.Body).Decode(&member); err != nil {
+			s.Logger.Error("[setOrgMemberRole] ", err)
+			helpers.WriteError(ctx, err, w)
+			return
+		}
+
+		// Ensure the IDs from the path match the payload and allow for exclusion in the payload
+		member.OrgID = orgID
+		member.UserID = userID
+
+		err = s.lowerThirdsService.SetOrgUserRole(ctx, orgID, userID, member.Role)
+		if err != nil {
+			s.Logger.Error("[setOrgMemberRole] SetOrgUserRole error ", err)
+			helpers.WriteError(ctx, err, w)
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+		_ = json.NewEncoder(w).Encode(member)
+	})
+}
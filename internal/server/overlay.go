@@ -0,0 +1,244 @@
+package server
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"html/template"
+	"lowerthirdsapi/internal/entities"
+	"lowerthirdsapi/internal/helpers"
+	"lowerthirdsapi/internal/render"
+	"net/http"
+	"strings"
+	"time"
+)
+
+//go:embed templates/overlay.html
+var overlayFS embed.FS
+
+var overlayTemplates = template.Must(template.ParseFS(overlayFS, "templates/overlay.html"))
+
+// overlayView is the data for the overlay templates. At most one of the item layouts is set, and OnAir reports
+// whether one is.
+type overlayView struct {
+	Overlay *entities.Overlay
+	Speaker *entities.SpeakerItem
+	Message *entities.MessageItem
+	Slide   *entities.Slide
+	Timer   *overlayTimer
+	OnAir   bool
+	Style   template.CSS
+	Logo    template.URL
+}
+
+// overlayTimer counts down to Target while the timer runs, and shows Remaining while it's paused
+type overlayTimer struct {
+	Running            bool
+	Target             time.Time
+	Remaining          string
+	ShowMeetingDetails bool
+	Meeting            entities.Meeting
+}
+
+func newOverlayView(overlay *entities.Overlay) overlayView {
+	view := overlayView{Overlay: overlay, Slide: overlay.Slide, Style: overlayStyle(overlay.Theme)}
+	if overlay.Theme.ShowsLogo() {
+		// Validate only lets https and PNG data URLs into a theme
+		view.Logo = template.URL(overlay.Theme.LogoURL)
+	}
+	switch item := overlay.Item.(type) {
+	case *entities.SpeakerItem:
+		view.Speaker = item
+	case *entities.MessageItem:
+		view.Message = item
+	case *entities.TimerItem:
+		view.Timer = &overlayTimer{
+			Running:            true,
+			Target:             overlay.Meeting.MeetingDate,
+			ShowMeetingDetails: item.ShowMeetingDetails,
+			Meeting:            overlay.Meeting,
+		}
+		if timer := overlay.Timer; timer != nil && timer.Reading != nil {
+			view.Timer.Running = timer.Reading.EndsDT.Valid
+			view.Timer.Target = timer.Reading.EndsDT.Time
+			view.Timer.Remaining = formatCountdown(time.Duration(timer.Reading.RemainingMS) * time.Millisecond)
+		}
+	}
+	view.OnAir = view.Speaker != nil || view.Message != nil || view.Slide != nil || view.Timer != nil
+	return view
+}
+
+// formatCountdown shows time left the way the overlay page's script does
+func formatCountdown(d time.Duration) string {
+	left := int((d + time.Second - 1) / time.Second)
+	if left < 0 {
+		left = 0
+	}
+	h, m, sec := left/3600, left%3600/60, left%60
+	if h > 0 {
+		return fmt.Sprintf("%d:%02d:%02d", h, m, sec)
+	}
+	return fmt.Sprintf("%d:%02d", m, sec)
+}
+
+// overlayStyle is the CSS for a theme. Theme sizes are pixels on a 1920x1080 frame, so they're scaled to the
+// viewport to look the same at any browser source size.
+func overlayStyle(theme entities.Theme) template.CSS {
+	vh := func(px int) string { return fmt.Sprintf("%.3fvh", float64(px)*100/1080) }
+	vw := func(px int) string { return fmt.Sprintf("%.3fvw", float64(px)*100/1920) }
+
+	var b strings.Builder
+	fmt.Fprintf(&b, ".lower-third { position: absolute; left: %s; right: %s; bottom: %s; padding: %s %s; "+
+		"background: %s; border-left: %s solid %s; font-family: %s; line-height: 1.25; }\n",
+		vw(theme.MarginX), vw(theme.MarginX), vh(theme.MarginBottom), vh(theme.Padding), vw(theme.Padding),
+		theme.BackgroundColor, vw(theme.AccentWidth), theme.AccentColor, render.CSSFontFamily(theme.Font))
+	if theme.Font == "go-smallcaps" {
+		b.WriteString(".lower-third { font-variant: small-caps; }\n")
+	}
+	fmt.Fprintf(&b, ".primary, .lyrics .line { font-size: %s; font-weight: bold; color: %s; }\n",
+		vh(theme.PrimarySize), theme.PrimaryColor)
+	fmt.Fprintf(&b, ".secondary, .lyrics .translation { font-size: %s; font-weight: normal; color: %s; }\n",
+		vh(theme.SecondarySize), theme.SecondaryColor)
+	fmt.Fprintf(&b, ".timer .countdown { font-size: %s; font-weight: bold; color: %s; }\n",
+		vh(theme.PrimarySize*7/5), theme.PrimaryColor)
+	fmt.Fprintf(&b, ".logo { position: absolute; height: %s; max-width: %s; object-fit: contain; }\n",
+		vh(theme.LogoHeight), vh(2*theme.LogoHeight))
+	switch theme.LogoPosition {
+	case "top-left":
+		fmt.Fprintf(&b, ".logo { top: %s; left: %s; object-position: left; }\n", vh(theme.MarginTop), vw(theme.MarginX))
+	case "top-right":
+		fmt.Fprintf(&b, ".logo { top: %s; right: %s; object-position: right; }\n", vh(theme.MarginTop), vw(theme.MarginX))
+	case "band-right":
+		// Keep the text clear of the logo, and fit the logo inside the band's padding
+		fmt.Fprintf(&b, ".lower-third { padding-right: %s; }\n", vh(2*theme.LogoHeight+2*theme.Padding))
+		fmt.Fprintf(&b, ".logo { top: 50%%; right: %s; transform: translateY(-50%%); max-height: calc(100%% - %s); "+
+			"object-position: right; }\n", vw(theme.Padding), vh(2*theme.Padding))
+	}
+	return template.CSS(b.String())
+}
+
+func (s *Server) getOverlayPage() http.Handler {
+	return s.renderOverlay("page")
+}
+
+func (s *Server) getOverlayContent() http.Handler {
+	return s.renderOverlay("content")
+}
+
+// renderOverlay renders the overlay template with the item on air for the token
+func (s *Server) renderOverlay(name string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		ctx := req.Context()
+
+		overlay, err := s.lowerThirdsService.GetOverlay(ctx, mux.Vars(req)["Token"])
+		if err != nil {
+			s.Logger.Error("[renderOverlay] GetOverlay error ", err)
+			helpers.WriteError(ctx, err, w)
+			return
+		}
+
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.Header().Set("Cache-Control", "no-store")
+		w.WriteHeader(http.StatusOK)
+		err = overlayTemplates.ExecuteTemplate(w, name, newOverlayView(overlay))
+		if err != nil {
+			s.Logger.Error("[renderOverlay] template error ", err)
+		}
+	})
+}
+
+func (s *Server) getOverlayEvents() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		ctx := req.Context()
+
+		lastEventID, err := parseLastEventID(req)
+		if err != nil {
+			http.Error(w, "Invalid Last-Event-ID", http.StatusBadRequest)
+			return
+		}
+
+		sub, err := s.lowerThirdsService.SubscribeOverlayEvents(ctx, mux.Vars(req)["Token"], lastEventID)
+		if err != nil {
+			s.Logger.Error("[getOverlayEvents] error ", err)
+			helpers.WriteError(ctx, err, w)
+			return
+		}
+		defer sub.Close()
+
+		s.streamEvents(w, req, sub)
+	})
+}
+
+func (s *Server) getOverlayTokens() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		ctx := req.Context()
+
+		meetingID, err := uuid.Parse(mux.Vars(req)["MeetingID"])
+		if err != nil {
+			s.Logger.Error("[getOverlayTokens] error ", err)
+			helpers.WriteError(ctx, err, w)
+			return
+		}
+
+		overlayTokens, err := s.lowerThirdsService.GetOverlayTokens(ctx, meetingID)
+		if err != nil {
+			s.Logger.Error("[getOverlayTokens] error ", err)
+			helpers.WriteError(ctx, err, w)
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+		err = json.NewEncoder(w).Encode(overlayTokens)
+		if err != nil {
+			s.Logger.Error(err)
+			helpers.WriteError(ctx, err, w)
+		}
+	})
+}
+
+func (s *Server) postOverlayToken() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		ctx := req.Context()
+
+		meetingID, err := uuid.Parse(mux.Vars(req)["MeetingID"])
+		if err != nil {
+			s.Logger.Error("[postOverlayToken] error ", err)
+			helpers.WriteError(ctx, err, w)
+			return
+		}
+
+		overlayToken, err := s.lowerThirdsService.CreateOverlayToken(ctx, meetingID)
+		if err != nil {
+			s.Logger.Error("[postOverlayToken] CreateOverlayToken error ", err)
+			helpers.WriteError(ctx, err, w)
+			return
+		}
+
+		w.WriteHeader(http.StatusCreated)
+		_ = json.NewEncoder(w).Encode(overlayToken)
+	})
+}
+
+func (s *Server) deleteOverlayToken() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		ctx := req.Context()
+
+		meetingID, err := uuid.Parse(mux.Vars(req)["MeetingID"])
+		if err != nil {
+			s.Logger.Error("[deleteOverlayToken] error ", err)
+			helpers.WriteError(ctx, err, w)
+			return
+		}
+
+		err = s.lowerThirdsService.DeleteOverlayToken(ctx, meetingID, mux.Vars(req)["Token"])
+		if err != nil {
+			s.Logger.Error("[deleteOverlayToken] DeleteOverlayToken error ", err)
+			helpers.WriteError(ctx, err, w)
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	})
+}
@@ -0,0 +1,66 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"lowerthirdsapi/internal/helpers"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// ListResponse is the body of every list endpoint: one page of the list, and where it sits in the whole list.
+// Pages are numbered from 0, and NextPage is null on the last page.
+type ListResponse struct {
+	Data     interface{} `json:"data"`
+	Page     int         `json:"page"`
+	PageSize int         `json:"page_size"`
+	Total    int         `json:"total"`
+	NextPage *int        `json:"next_page"`
+}
+
+// writeList writes one page of a list in a ListResponse, with a Link header pointing to the first, previous, next
+// and last pages of the same request
+func (s *Server) writeList(w http.ResponseWriter, req *http.Request, data interface{}, total int) {
+	ctx := req.Context()
+	qp := helpers.GetQueryParams(ctx)
+
+	resp := ListResponse{
+		Data:     data,
+		Page:     qp.Page,
+		PageSize: qp.PageSize,
+		Total:    total,
+	}
+	lastPage := 0
+	if total > 0 {
+		lastPage = (total - 1) / qp.PageSize
+	}
+
+	links := []string{pageLink(req, 0, "first")}
+	if qp.Page > 0 {
+		links = append(links, pageLink(req, min(qp.Page-1, lastPage), "prev"))
+	}
+	if qp.Page < lastPage {
+		next := qp.Page + 1
+		resp.NextPage = &next
+		links = append(links, pageLink(req, next, "next"))
+	}
+	links = append(links, pageLink(req, lastPage, "last"))
+
+	w.Header().Set("Link", strings.Join(links, ", "))
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	err := json.NewEncoder(w).Encode(resp)
+	if err != nil {
+		s.Logger.Error("[writeList] error ", err)
+	}
+}
+
+// pageLink is a Link header entry for the request's URL at another page
+func pageLink(req *http.Request, page int, rel string) string {
+	u := *req.URL
+	query := u.Query()
+	query.Set("Page", strconv.Itoa(page))
+	u.RawQuery = query.Encode()
+	return fmt.Sprintf(`<%s>; rel="%s"`, u.RequestURI(), rel)
+}
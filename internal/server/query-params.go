@@ -2,16 +2,23 @@ package server
 
 import (
 	"context"
+	"errors"
 	"github.com/google/uuid"
 	"github.com/gorilla/mux"
 	"github.com/sirupsen/logrus"
+	"lowerthirdsapi/internal/entities"
 	"lowerthirdsapi/internal/helpers"
 	"net/http"
+	"net/url"
 	"strconv"
 	"time"
 )
 
-// queryParametersInContext is a middleware function to parse query parameters and put them in the context
+// maxPageSize is the most results a list endpoint returns at once
+const maxPageSize = 500
+
+// queryParametersInContext is a middleware function to parse the query parameters shared by the list endpoints and put
+// them in the context. Parameters only one endpoint takes are parsed by its handler.
 func queryParametersInContext(log *logrus.Entry) mux.MiddlewareFunc {
 	return mux.MiddlewareFunc(func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -28,9 +35,15 @@ func queryParametersInContext(log *logrus.Entry) mux.MiddlewareFunc {
 
 			if pageSizeStr := query.Get("PageSize"); pageSizeStr != "" {
 				if ps, err := strconv.Atoi(pageSizeStr); err == nil && ps > 0 {
-					qp.PageSize = ps
+					qp.PageSize = min(ps, maxPageSize)
 				}
 			}
+			if _, ok := qp.Offset(); !ok {
+				errMsg := "Page is too large"
+				log.Error(errMsg)
+				http.Error(w, errMsg, http.StatusBadRequest)
+				return
+			}
 
 			if dateFromStr := query.Get("DateFrom"); dateFromStr != "" {
 				df, err := time.Parse("2006-01-02", dateFromStr)
@@ -46,7 +59,7 @@ func queryParametersInContext(log *logrus.Entry) mux.MiddlewareFunc {
 			if dateToStr := query.Get("DateTo"); dateToStr != "" {
 				dt, err := time.Parse("2006-01-02", dateToStr)
 				if err != nil {
-					errMsg := "invalid date format (DateFrom)"
+					errMsg := "invalid date format (DateTo)"
 					log.Error(errMsg)
 					http.Error(w, errMsg, http.StatusBadRequest)
 					return
@@ -54,10 +67,21 @@ func queryParametersInContext(log *logrus.Entry) mux.MiddlewareFunc {
 				qp.DateTo = &dt
 			}
 
+			if qp.DateFrom != nil && qp.DateTo != nil && qp.DateTo.Before(*qp.DateFrom) {
+				errMsg := "DateTo is before DateFrom"
+				log.Error(errMsg)
+				http.Error(w, errMsg, http.StatusBadRequest)
+				return
+			}
+
 			if languageStr := query.Get("Language"); languageStr != "" {
 				qp.Language = languageStr
 			}
 
+			if searchStr := query.Get("Search"); searchStr != "" {
+				qp.Search = searchStr
+			}
+
 			if userIDStr := query.Get("UserID"); userIDStr != "" {
 				if userID, err := uuid.Parse(userIDStr); err == nil {
 					qp.UserID = userID
@@ -81,3 +105,66 @@ func queryParametersInContext(log *logrus.Entry) mux.MiddlewareFunc {
 		})
 	})
 }
+
+// slideQueryParams adds the MaxLines and IncludeOptional parameters, which shape a lyrics item's slides, to qp
+func slideQueryParams(query url.Values, qp *helpers.QueryParams) error {
+	if maxLinesStr := query.Get("MaxLines"); maxLinesStr != "" {
+		ml, err := strconv.Atoi(maxLinesStr)
+		if err != nil || ml < 0 {
+			return errors.New("Invalid MaxLines")
+		}
+		qp.MaxLines = ml
+	}
+
+	if includeOptionalStr := query.Get("IncludeOptional"); includeOptionalStr != "" {
+		io, err := strconv.ParseBool(includeOptionalStr)
+		if err != nil {
+			return errors.New("Invalid IncludeOptional")
+		}
+		qp.IncludeOptional = io
+	}
+	return nil
+}
+
+// renderQueryParams adds the Theme, Slide and Format parameters of the render endpoints to qp, along with the slide
+// parameters that split lyrics items into graphics
+func renderQueryParams(query url.Values, qp *helpers.QueryParams) error {
+	if themeStr := query.Get("Theme"); themeStr != "" {
+		qp.Theme = themeStr
+	}
+
+	if slideStr := query.Get("Slide"); slideStr != "" {
+		sl, err := strconv.Atoi(slideStr)
+		if err != nil || sl < 0 {
+			return errors.New("Invalid Slide")
+		}
+		qp.Slide = sl
+	}
+
+	if formatStr := query.Get("Format"); formatStr != "" {
+		if _, ok := renderers[formatStr]; !ok {
+			return errors.New("Invalid Format")
+		}
+		qp.Format = formatStr
+	}
+	return slideQueryParams(query, qp)
+}
+
+// auditQueryParams adds the EntityType, EntityID and Action filters of the audit log to qp
+func auditQueryParams(query url.Values, qp *helpers.QueryParams) error {
+	if entityTypeStr := query.Get("EntityType"); entityTypeStr != "" {
+		qp.EntityType = entityTypeStr
+	}
+
+	if entityIDStr := query.Get("EntityID"); entityIDStr != "" {
+		qp.EntityID = entityIDStr
+	}
+
+	if actionStr := query.Get("Action"); actionStr != "" {
+		if !entities.AuditAction(actionStr).Valid() {
+			return errors.New("Invalid Action")
+		}
+		qp.Action = actionStr
+	}
+	return nil
+}
@@ -0,0 +1,229 @@
+package server
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"io"
+	"lowerthirdsapi/internal/apierrors"
+	"lowerthirdsapi/internal/entities"
+	"lowerthirdsapi/internal/helpers"
+	"lowerthirdsapi/internal/render"
+	"net/http"
+	"regexp"
+	"strings"
+)
+
+// renderers write a graphic in each supported format
+var renderers = map[string]struct {
+	contentType string
+	write       func(w io.Writer, theme entities.Theme, content render.Content) error
+}{
+	"png": {"image/png", render.PNG},
+	"svg": {"image/svg+xml", render.SVG},
+}
+
+func (s *Server) getItemRenderPNG() http.Handler {
+	return s.getItemRender("png")
+}
+
+func (s *Server) getItemRenderSVG() http.Handler {
+	return s.getItemRender("svg")
+}
+
+// getItemRender draws an item as a 1920x1080 still. Lyrics items draw the slide chosen with the Slide parameter.
+func (s *Server) getItemRender(format string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		ctx := req.Context()
+
+		itemID, err := uuid.Parse(mux.Vars(req)["ItemID"])
+		if err != nil {
+			s.Logger.Error("[getItemRender] error ", err)
+			helpers.WriteError(ctx, err, w)
+			return
+		}
+
+		qp := helpers.GetQueryParams(ctx)
+		err = renderQueryParams(req.URL.Query(), &qp)
+		if err != nil {
+			s.Logger.Error("[getItemRender] ", err)
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		ctx = context.WithValue(ctx, helpers.QueryParametersKey, qp)
+
+		item, err := s.lowerThirdsService.GetItem(ctx, itemID)
+		if err != nil {
+			s.Logger.Error("[getItemRender] GetItem error ", err)
+			helpers.WriteError(ctx, err, w)
+			return
+		}
+
+		theme, err := s.renderTheme(ctx, item.GetMeetingID(), qp.Theme)
+		if err != nil {
+			s.Logger.Error("[getItemRender] theme error ", err)
+			helpers.WriteError(ctx, err, w)
+			return
+		}
+
+		contents, err := s.itemContents(ctx, item)
+		if err != nil {
+			s.Logger.Error("[getItemRender] error ", err)
+			helpers.WriteError(ctx, err, w)
+			return
+		}
+		if qp.Slide >= len(contents) {
+			helpers.WriteError(ctx, apierrors.New(http.StatusBadRequest, "INVALID_SLIDE", "Invalid slide",
+				"item %s has %d slides", itemID, len(contents)), w)
+			return
+		}
+
+		var buf bytes.Buffer
+		err = renderers[format].write(&buf, theme, contents[qp.Slide])
+		if err != nil {
+			s.Logger.Error("[getItemRender] render error ", err)
+			helpers.WriteError(ctx, err, w)
+			return
+		}
+
+		w.Header().Set("Content-Type", renderers[format].contentType)
+		w.WriteHeader(http.StatusOK)
+		_, _ = buf.WriteTo(w)
+	})
+}
+
+// getMeetingRender exports every graphic in a meeting's agenda as a zip of stills, in the format chosen with the
+// Format parameter
+func (s *Server) getMeetingRender() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		ctx := req.Context()
+
+		meetingID, err := uuid.Parse(mux.Vars(req)["MeetingID"])
+		if err != nil {
+			s.Logger.Error("[getMeetingRender] error ", err)
+			helpers.WriteError(ctx, err, w)
+			return
+		}
+
+		qp := helpers.GetQueryParams(ctx)
+		err = renderQueryParams(req.URL.Query(), &qp)
+		if err != nil {
+			s.Logger.Error("[getMeetingRender] ", err)
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		ctx = context.WithValue(ctx, helpers.QueryParametersKey, qp)
+
+		theme, err := s.renderTheme(ctx, meetingID, qp.Theme)
+		if err != nil {
+			s.Logger.Error("[getMeetingRender] theme error ", err)
+			helpers.WriteError(ctx, err, w)
+			return
+		}
+
+		meeting, err := s.lowerThirdsService.GetMeeting(ctx, meetingID)
+		if err != nil {
+			s.Logger.Error("[getMeetingRender] GetMeeting error ", err)
+			helpers.WriteError(ctx, err, w)
+			return
+		}
+		items, err := s.lowerThirdsService.GetItemsByMeeting(ctx, meetingID)
+		if err != nil {
+			s.Logger.Error("[getMeetingRender] GetItemsByMeeting error ", err)
+			helpers.WriteError(ctx, err, w)
+			return
+		}
+
+		// Build the whole archive first so a failure can still be reported
+		var buf bytes.Buffer
+		zw := zip.NewWriter(&buf)
+		for i, item := range *items {
+			contents, err := s.itemContents(ctx, item)
+			if err != nil {
+				s.Logger.Error("[getMeetingRender] error ", err)
+				helpers.WriteError(ctx, err, w)
+				return
+			}
+			for j, content := range contents {
+				name := fmt.Sprintf("%02d-%s.%s", i+1, item.GetType(), qp.Format)
+				if len(contents) > 1 {
+					name = fmt.Sprintf("%02d-%s-%02d.%s", i+1, item.GetType(), j+1, qp.Format)
+				}
+				f, err := zw.Create(name)
+				if err == nil {
+					err = renderers[qp.Format].write(f, theme, content)
+				}
+				if err != nil {
+					s.Logger.Error("[getMeetingRender] render error ", err)
+					helpers.WriteError(ctx, err, w)
+					return
+				}
+			}
+		}
+		err = zw.Close()
+		if err != nil {
+			s.Logger.Error("[getMeetingRender] zip error ", err)
+			helpers.WriteError(ctx, err, w)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/zip")
+		w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="%s.zip"`, archiveName(meeting)))
+		w.WriteHeader(http.StatusOK)
+		_, _ = buf.WriteTo(w)
+	})
+}
+
+// itemContents is the content of each graphic an item shows: one per slide for lyrics items, and one otherwise
+func (s *Server) itemContents(ctx context.Context, item entities.Item) ([]render.Content, error) {
+	switch item := item.(type) {
+	case *entities.LyricsItem:
+		if item.HymnID == "" {
+			return []render.Content{{}}, nil
+		}
+		slides, err := s.lowerThirdsService.GetItemSlides(ctx, item.LyricsItemID)
+		if err != nil {
+			return nil, err
+		}
+		contents := make([]render.Content, 0, len(*slides))
+		for _, slide := range *slides {
+			contents = append(contents, render.ItemContent(item, &slide, nil))
+		}
+		return contents, nil
+	case *entities.TimerItem:
+		meeting, err := s.lowerThirdsService.GetMeeting(ctx, item.MeetingID)
+		if err != nil {
+			return nil, err
+		}
+		return []render.Content{render.ItemContent(item, nil, meeting)}, nil
+	default:
+		return []render.Content{render.ItemContent(item, nil, nil)}, nil
+	}
+}
+
+// renderTheme finds the theme chosen with the Theme parameter, or the meeting's own theme when none is chosen
+func (s *Server) renderTheme(ctx context.Context, meetingID uuid.UUID, name string) (entities.Theme, error) {
+	if name == "" {
+		theme, err := s.lowerThirdsService.GetMeetingTheme(ctx, meetingID)
+		if err != nil {
+			return entities.Theme{}, err
+		}
+		return *theme, nil
+	}
+	theme, ok := entities.ThemeByName(name)
+	if !ok {
+		return theme, apierrors.New(http.StatusBadRequest, "UNKNOWN_THEME", "Unknown theme", "there's no theme named %q", name)
+	}
+	return theme, nil
+}
+
+var unsafeFilename = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+
+// archiveName is a safe file name for a meeting's export
+func archiveName(meeting *entities.Meeting) string {
+	name := unsafeFilename.ReplaceAllString(meeting.MeetingDate.Format("2006-01-02")+"-"+meeting.Meeting, "-")
+	return strings.Trim(name, "-")
+}
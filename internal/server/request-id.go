@@ -0,0 +1,36 @@
+package server
+
+import (
+	"context"
+	"github.com/gorilla/mux"
+	"github.com/rs/xid"
+	"github.com/sirupsen/logrus"
+	"lowerthirdsapi/internal/helpers"
+	"net/http"
+	"regexp"
+)
+
+// requestIDHeader carries a request's ID in both directions
+const requestIDHeader = "X-Request-ID"
+
+// validRequestID is what's accepted as a request ID from a client or proxy. Anything else is replaced.
+var validRequestID = regexp.MustCompile(`^[A-Za-z0-9._:-]{1,64}$`)
+
+// requestID is a middleware function that gives every request an ID, keeping one sent in X-Request-ID by a client or
+// proxy. The ID is put in the context under RequestIDKey, where the audit log and error responses pick it up, and
+// sent back in the X-Request-ID header.
+func requestID(log *logrus.Entry) mux.MiddlewareFunc {
+	return mux.MiddlewareFunc(func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			id := r.Header.Get(requestIDHeader)
+			if !validRequestID.MatchString(id) {
+				id = xid.New().String()
+			}
+			log.Debug("requestID middleware ", id)
+
+			w.Header().Set(requestIDHeader, id)
+			ctx := context.WithValue(r.Context(), helpers.RequestIDKey, id)
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	})
+}
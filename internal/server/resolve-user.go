@@ -0,0 +1,46 @@
+package server
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"github.com/gorilla/mux"
+	"github.com/sirupsen/logrus"
+	"lowerthirdsapi/internal/apierrors"
+	"lowerthirdsapi/internal/helpers"
+	"lowerthirdsapi/internal/storage"
+	"net/http"
+)
+
+// resolveUser is a middleware function that looks up the user for the social ID authClaims put in the context, once
+// per request, and puts it in the context under UserIDKey for the storage layer
+func resolveUser(log *logrus.Entry, lowerThirdsService storage.LowerThirdsService) mux.MiddlewareFunc {
+	return mux.MiddlewareFunc(func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			log.Debug("resolveUser middleware")
+			ctx := r.Context()
+
+			socialID, ok := ctx.Value(helpers.SocialIDKey).(string)
+			if !ok {
+				http.Error(w, "missing or invalid token", http.StatusUnauthorized)
+				return
+			}
+
+			user, err := lowerThirdsService.GetUserBySocialID(ctx, socialID)
+			if errors.Is(err, sql.ErrNoRows) {
+				log.Info("no user provisioned for socialID ", socialID)
+				helpers.WriteError(ctx, apierrors.New(http.StatusForbidden, "NOT_PROVISIONED", "Not provisioned",
+					"no user has been provisioned for this account"), w)
+				return
+			}
+			if err != nil {
+				log.Error(err)
+				helpers.WriteError(ctx, err, w)
+				return
+			}
+
+			ctx = context.WithValue(ctx, helpers.UserIDKey, user)
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	})
+}
@@ -15,18 +15,20 @@ type Route struct {
 type Routes []Route
 
 func (s *Server) Route() {
-    s.Router.Methods("OPTIONS").HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+    // add middleware for every API request; overlay pages are public and authorized by their token
+    api := []mux.MiddlewareFunc{requestID(s.Logger), authClaims(s.Logger, s.verifier, s.lowerThirdsService), resolveUser(s.Logger, s.lowerThirdsService), queryParametersInContext(s.Logger)}
+    // the caller's user may not exist yet when provisioning it
+    provisioning := []mux.MiddlewareFunc{requestID(s.Logger), authClaims(s.Logger, s.verifier, s.lowerThirdsService), queryParametersInContext(s.Logger)}
+    public := []mux.MiddlewareFunc{requestID(s.Logger), queryParametersInContext(s.Logger)}
+
+    s.Router.Methods("OPTIONS").Handler(handleWithMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
         s.Logger.Debug("Got a global OPTIONS request")
         w.Header().Set("Access-Control-Allow-Origin", "http://localhost:5173")
         w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
-        w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization")
+        w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization, X-Request-ID")
         w.Header().Set("Access-Control-Max-Age", "86400")
         w.WriteHeader(http.StatusOK)
-    })
-
-    // add middleware for every request
-    s.Router.Use(authClaims(s.Logger))
-    s.Router.Use(queryParametersInContext(s.Logger))
+    }), api...))
 
     var routes = Routes{
         // meetings
@@ -35,7 +37,21 @@ func (s *Server) Route() {
         Route{"getMeeting", "GET", "/v1/meetings/{MeetingID}", s.getMeeting()},
         Route{"updateMeeting", "PUT", "/v1/meetings/{MeetingID}", s.updateMeeting()},
         Route{"deleteMeeting", "DELETE", "/v1/meetings/{MeetingID}", s.deleteMeeting()},
+        Route{"cloneMeeting", "POST", "/v1/meetings/{MeetingID}/clone", s.cloneMeeting()},
         Route{"getMeetingItems", "GET", "/v1/meetings/{MeetingID}/items", s.getMeetingItems()}, // need this? Items are included in meeting
+        Route{"updateMeetingOrder", "PUT", "/v1/meetings/{MeetingID}/order", s.updateMeetingOrder()},
+        Route{"getMeetingEvents", "GET", "/v1/meetings/{MeetingID}/events", s.getMeetingEvents()},
+        Route{"getLiveState", "GET", "/v1/meetings/{MeetingID}/live", s.getLiveState()},
+        Route{"updateLiveState", "POST", "/v1/meetings/{MeetingID}/live/{Action:take|cue|next|previous|clear|show|hide|next_verse|previous_verse}", s.updateLiveState()},
+        Route{"meetingControl", "GET", "/v1/meetings/{MeetingID}/control", s.meetingControl()},
+        Route{"getMeetingTimers", "GET", "/v1/meetings/{MeetingID}/timers", s.getMeetingTimers()},
+        Route{"getMeetingRender", "GET", "/v1/meetings/{MeetingID}/render.zip", s.getMeetingRender()},
+        Route{"getOverlayTokens", "GET", "/v1/meetings/{MeetingID}/overlay-tokens", s.getOverlayTokens()},
+        Route{"postOverlayToken", "POST", "/v1/meetings/{MeetingID}/overlay-tokens", s.postOverlayToken()},
+        Route{"deleteOverlayToken", "DELETE", "/v1/meetings/{MeetingID}/overlay-tokens/{Token}", s.deleteOverlayToken()},
+        Route{"getMeetingTheme", "GET", "/v1/meetings/{MeetingID}/theme", s.getMeetingTheme()},
+        Route{"putMeetingTheme", "PUT", "/v1/meetings/{MeetingID}/theme", s.putMeetingTheme()},
+        Route{"deleteMeetingTheme", "DELETE", "/v1/meetings/{MeetingID}/theme", s.deleteMeetingTheme()},
 
         // orgs
         Route{"getOrgs", "GET", "/v1/orgs", s.getOrgs()},
@@ -45,12 +61,47 @@ func (s *Server) Route() {
         Route{"deleteOrg", "DELETE", "/v1/orgs/{OrgID}", s.deleteOrg()},
         Route{"getOrgMeetings", "GET", "/v1/orgs/{OrgID}/meetings", s.getOrgMeetings()},
         Route{"getOrgUsers", "GET", "/v1/orgs/{OrgID}/users", s.getUsersByOrg()},
+        Route{"getOrgMembers", "GET", "/v1/orgs/{OrgID}/members", s.getOrgMembers()},
+        Route{"setOrgMemberRole", "PUT", "/v1/orgs/{OrgID}/members/{UserID}", s.setOrgMemberRole()},
+        Route{"getOrgTheme", "GET", "/v1/orgs/{OrgID}/theme", s.getOrgTheme()},
+        Route{"putOrgTheme", "PUT", "/v1/orgs/{OrgID}/theme", s.putOrgTheme()},
+        Route{"deleteOrgTheme", "DELETE", "/v1/orgs/{OrgID}/theme", s.deleteOrgTheme()},
+        Route{"getOrgTemplates", "GET", "/v1/orgs/{OrgID}/templates", s.getOrgTemplates()},
+        Route{"postOrgTemplate", "POST", "/v1/orgs/{OrgID}/templates", s.postOrgTemplate()},
+        Route{"getOrgTemplate", "GET", "/v1/orgs/{OrgID}/templates/{TemplateID}", s.getOrgTemplate()},
+        Route{"updateOrgTemplate", "PUT", "/v1/orgs/{OrgID}/templates/{TemplateID}", s.updateOrgTemplate()},
+        Route{"deleteOrgTemplate", "DELETE", "/v1/orgs/{OrgID}/templates/{TemplateID}", s.deleteOrgTemplate()},
+        Route{"instantiateOrgTemplate", "POST", "/v1/orgs/{OrgID}/templates/{TemplateID}/instantiate", s.instantiateOrgTemplate()},
+        Route{"getOrgSchedules", "GET", "/v1/orgs/{OrgID}/schedules", s.getOrgSchedules()},
+        Route{"postOrgSchedule", "POST", "/v1/orgs/{OrgID}/schedules", s.postOrgSchedule()},
+        Route{"getOrgSchedule", "GET", "/v1/orgs/{OrgID}/schedules/{ScheduleID}", s.getOrgSchedule()},
+        Route{"updateOrgSchedule", "PUT", "/v1/orgs/{OrgID}/schedules/{ScheduleID}", s.updateOrgSchedule()},
+        Route{"deleteOrgSchedule", "DELETE", "/v1/orgs/{OrgID}/schedules/{ScheduleID}", s.deleteOrgSchedule()},
+        Route{"getOrgInvitations", "GET", "/v1/orgs/{OrgID}/invitations", s.getOrgInvitations()},
+        Route{"postOrgInvitation", "POST", "/v1/orgs/{OrgID}/invitations", s.postOrgInvitation()},
+        Route{"deleteOrgInvitation", "DELETE", "/v1/orgs/{OrgID}/invitations/{InvitationID}", s.deleteOrgInvitation()},
+        Route{"getOrgKeys", "GET", "/v1/orgs/{OrgID}/keys", s.getOrgKeys()},
+        Route{"postOrgKey", "POST", "/v1/orgs/{OrgID}/keys", s.postOrgKey()},
+        Route{"deleteOrgKey", "DELETE", "/v1/orgs/{OrgID}/keys/{KeyID}", s.deleteOrgKey()},
+        Route{"getOrgAudit", "GET", "/v1/orgs/{OrgID}/audit", s.getOrgAudit()},
+
+        // invitations
+        Route{"acceptInvitation", "POST", "/v1/invitations/{Token}/accept", s.acceptInvitation()},
+
+        // hymns
+        Route{"getHymns", "GET", "/v1/hymns", s.getHymns()},
+        Route{"getHymn", "GET", "/v1/hymns/{HymnID}", s.getHymn()},
 
         // items
         Route{"getItems", "GET", "/v1/items", s.getItems()},
         Route{"postItem", "POST", "/v1/items", s.postItem()},
         Route{"updateItem", "PUT", "/v1/items/{ItemID}", s.updateItem()},
         Route{"deleteItem", "DELETE", "/v1/items/{ItemID}", s.deleteItem()},
+        Route{"getItemSlides", "GET", "/v1/items/{ItemID}/slides", s.getItemSlides()},
+        Route{"getItemRenderPNG", "GET", "/v1/items/{ItemID}/render.png", s.getItemRenderPNG()},
+        Route{"getItemRenderSVG", "GET", "/v1/items/{ItemID}/render.svg", s.getItemRenderSVG()},
+        Route{"getItemTimer", "GET", "/v1/items/{ItemID}/timer", s.getItemTimer()},
+        Route{"updateItemTimer", "POST", "/v1/items/{ItemID}/timer/{Action:start|pause|resume|reset|add_time}", s.updateItemTimer()},
 
         // users
         Route{"getUsers", "GET", "/v1/users", s.getUsers()},
@@ -63,7 +114,26 @@ func (s *Server) Route() {
         Route{"setUserOrgs", "PUT", "/v1/users/{UserID}/orgs", s.setOrgsByUser()},
     }
     for _, r := range routes {
-        s.Router.Handle(r.Pattern, r.Handler).Methods(r.Method).Name(r.Name)
+        s.Router.Handle(r.Pattern, handleWithMiddleware(r.Handler, api...)).Methods(r.Method).Name(r.Name)
+    }
+
+    var provisioningRoutes = Routes{
+        // the signed-in user
+        Route{"getMe", "GET", "/v1/me", s.getMe()},
+        Route{"putMe", "PUT", "/v1/me", s.putMe()},
+    }
+    for _, r := range provisioningRoutes {
+        s.Router.Handle(r.Pattern, handleWithMiddleware(r.Handler, provisioning...)).Methods(r.Method).Name(r.Name)
+    }
+
+    var publicRoutes = Routes{
+        // overlays for browser sources
+        Route{"getOverlayPage", "GET", "/overlay/{Token}", s.getOverlayPage()},
+        Route{"getOverlayContent", "GET", "/overlay/{Token}/content", s.getOverlayContent()},
+        Route{"getOverlayEvents", "GET", "/overlay/{Token}/events", s.getOverlayEvents()},
+    }
+    for _, r := range publicRoutes {
+        s.Router.Handle(r.Pattern, handleWithMiddleware(r.Handler, public...)).Methods(r.Method).Name(r.Name)
     }
 }
 
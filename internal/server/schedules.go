@@ -0,0 +1,152 @@
+package server
+
+import (
+	"encoding/json"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"lowerthirdsapi/internal/entities"
+	"lowerthirdsapi/internal/helpers"
+	"net/http"
+)
+
+func (s *Server) getOrgSchedules() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		ctx := req.Context()
+
+		orgID, err := uuid.Parse(mux.Vars(req)["OrgID"])
+		if err != nil {
+			s.Logger.Error("[getOrgSchedules] error ", err)
+			helpers.WriteError(ctx, err, w)
+			return
+		}
+
+		schedules, err := s.lowerThirdsService.GetSchedulesByOrg(ctx, orgID)
+		if err != nil {
+			s.Logger.Error("[getOrgSchedules] GetSchedulesByOrg error ", err)
+			helpers.WriteError(ctx, err, w)
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+		_ = json.NewEncoder(w).Encode(schedules)
+	})
+}
+
+func (s *Server) getOrgSchedule() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		ctx := req.Context()
+
+		orgID, scheduleID, err := scheduleVars(req)
+		if err != nil {
+			s.Logger.Error("[getOrgSchedule] error ", err)
+			helpers.WriteError(ctx, err, w)
+			return
+		}
+
+		schedule, err := s.lowerThirdsService.GetSchedule(ctx, orgID, scheduleID)
+		if err != nil {
+			s.Logger.Error("[getOrgSchedule] GetSchedule error ", err)
+			helpers.WriteError(ctx, err, w)
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+		_ = json.NewEncoder(w).Encode(schedule)
+	})
+}
+
+func (s *Server) postOrgSchedule() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		ctx := req.Context()
+
+		orgID, err := uuid.Parse(mux.Vars(req)["OrgID"])
+		if err != nil {
+			s.Logger.Error("[postOrgSchedule] error ", err)
+			helpers.WriteError(ctx, err, w)
+			return
+		}
+
+		var schedule entities.Schedule
+		if err := json.NewDecoder(req.Body).Decode(&schedule); err != nil {
+			s.Logger.Error("[postOrgSchedule] ", err)
+			helpers.WriteError(ctx, err, w)
+			return
+		}
+
+		err = s.lowerThirdsService.CreateSchedule(ctx, orgID, &schedule)
+		if err != nil {
+			s.Logger.Error("[postOrgSchedule] CreateSchedule error ", err)
+			helpers.WriteError(ctx, err, w)
+			return
+		}
+
+		w.WriteHeader(http.StatusCreated)
+		_ = json.NewEncoder(w).Encode(schedule)
+	})
+}
+
+func (s *Server) updateOrgSchedule() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		ctx := req.Context()
+
+		orgID, scheduleID, err := scheduleVars(req)
+		if err != nil {
+			s.Logger.Error("[updateOrgSchedule] error ", err)
+			helpers.WriteError(ctx, err, w)
+			return
+		}
+
+		var schedule entities.Schedule
+		if err := json.NewDecoder(req.Body).Decode(&schedule); err != nil {
+			s.Logger.Error("[updateOrgSchedule] ", err)
+			helpers.WriteError(ctx, err, w)
+			return
+		}
+
+		err = s.lowerThirdsService.UpdateSchedule(ctx, orgID, scheduleID, &schedule)
+		if err != nil {
+			s.Logger.Error("[updateOrgSchedule] UpdateSchedule error ", err)
+			helpers.WriteError(ctx, err, w)
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+		_ = json.NewEncoder(w).Encode(schedule)
+	})
+}
+
+func (s *Server) deleteOrgSchedule() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		ctx := req.Context()
+
+		orgID, scheduleID, err := scheduleVars(req)
+		if err != nil {
+			s.Logger.Error("[deleteOrgSchedule] error ", err)
+			helpers.WriteError(ctx, err, w)
+			return
+		}
+
+		err = s.lowerThirdsService.DeleteSchedule(ctx, orgID, scheduleID)
+		if err != nil {
+			s.Logger.Error("[deleteOrgSchedule] DeleteSchedule error ", err)
+			helpers.WriteError(ctx, err, w)
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	})
+}
+
+// scheduleVars parses the org and schedule IDs from a schedule route
+func scheduleVars(req *http.Request) (uuid.UUID, uuid.UUID, error) {
+	vars := mux.Vars(req)
+	orgID, err := uuid.Parse(vars["OrgID"])
+	if err != nil {
+		return uuid.Nil, uuid.Nil, err
+	}
+	scheduleID, err := uuid.Parse(vars["ScheduleID"])
+	if err != nil {
+		return uuid.Nil, uuid.Nil, err
+	}
+	return orgID, scheduleID, nil
+}
@@ -4,7 +4,10 @@ import (
 	"github.com/jmoiron/sqlx"
 	"github.com/sirupsen/logrus"
 	"gopkg.in/DataDog/dd-trace-go.v1/contrib/gorilla/mux"
+	"lowerthirdsapi/internal/auth"
 	"lowerthirdsapi/internal/config"
+	"lowerthirdsapi/internal/invitations"
+	"lowerthirdsapi/internal/mail"
 	"lowerthirdsapi/internal/storage"
 	"net/http"
 	"time"
@@ -15,10 +18,14 @@ type Server struct {
 	DB                 *sqlx.DB
 	Router             *mux.Router
 	lowerThirdsService storage.LowerThirdsService
+	verifier           auth.TokenVerifier
+	invitations        invitations.Config
+	signer             *invitations.Signer
+	mailer             mail.Mailer
 	Logger             *logrus.Entry
 }
 
-func New(cfg *config.Config, db *sqlx.DB, lowerThirdsService storage.LowerThirdsService, log *logrus.Entry) *Server {
+func New(cfg *config.Config, db *sqlx.DB, lowerThirdsService storage.LowerThirdsService, verifier auth.TokenVerifier, mailer mail.Mailer, log *logrus.Entry) *Server {
 	timeout := 20 * time.Second
 	router := mux.NewRouter(mux.WithServiceName("lowerthirds-api"))
 	router.StrictSlash(true)
@@ -33,6 +40,10 @@ func New(cfg *config.Config, db *sqlx.DB, lowerThirdsService storage.LowerThirds
 		},
 		DB:                 db,
 		lowerThirdsService: lowerThirdsService,
+		verifier:           verifier,
+		invitations:        cfg.Invitations,
+		signer:             invitations.NewSigner(cfg.Invitations.Secret),
+		mailer:             mailer,
 		Router:             router,
 		Logger:             log,
 	}
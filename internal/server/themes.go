@@ -0,0 +1,163 @@
+package server
+
+import (
+	"encoding/json"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"io"
+	"lowerthirdsapi/internal/helpers"
+	"net/http"
+)
+
+// maxThemeBody is the largest theme that can be sent, leaving room for a logo data URL
+const maxThemeBody = 1 << 20
+
+func (s *Server) getOrgTheme() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		ctx := req.Context()
+
+		orgID, err := uuid.Parse(mux.Vars(req)["OrgID"])
+		if err != nil {
+			s.Logger.Error("[getOrgTheme] error ", err)
+			helpers.WriteError(ctx, err, w)
+			return
+		}
+
+		theme, err := s.lowerThirdsService.GetOrgTheme(ctx, orgID)
+		if err != nil {
+			s.Logger.Error("[getOrgTheme] GetOrgTheme error ", err)
+			helpers.WriteError(ctx, err, w)
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+		_ = json.NewEncoder(w).Encode(theme)
+	})
+}
+
+func (s *Server) putOrgTheme() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		ctx := req.Context()
+
+		orgID, err := uuid.Parse(mux.Vars(req)["OrgID"])
+		if err != nil {
+			s.Logger.Error("[putOrgTheme] error ", err)
+			helpers.WriteError(ctx, err, w)
+			return
+		}
+
+		body, err := io.ReadAll(http.MaxBytesReader(w, req.Body, maxThemeBody))
+		if err != nil {
+			s.Logger.Error("[putOrgTheme] error ", err)
+			helpers.WriteError(ctx, err, w)
+			return
+		}
+
+		theme, err := s.lowerThirdsService.SetOrgTheme(ctx, orgID, body)
+		if err != nil {
+			s.Logger.Error("[putOrgTheme] SetOrgTheme error ", err)
+			helpers.WriteError(ctx, err, w)
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+		_ = json.NewEncoder(w).Encode(theme)
+	})
+}
+
+func (s *Server) deleteOrgTheme() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		ctx := req.Context()
+
+		orgID, err := uuid.Parse(mux.Vars(req)["OrgID"])
+		if err != nil {
+			s.Logger.Error("[deleteOrgTheme] error ", err)
+			helpers.WriteError(ctx, err, w)
+			return
+		}
+
+		err = s.lowerThirdsService.DeleteOrgTheme(ctx, orgID)
+		if err != nil {
+			s.Logger.Error("[deleteOrgTheme] DeleteOrgTheme error ", err)
+			helpers.WriteError(ctx, err, w)
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	})
+}
+
+func (s *Server) getMeetingTheme() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		ctx := req.Context()
+
+		meetingID, err := uuid.Parse(mux.Vars(req)["MeetingID"])
+		if err != nil {
+			s.Logger.Error("[getMeetingTheme] error ", err)
+			helpers.WriteError(ctx, err, w)
+			return
+		}
+
+		theme, err := s.lowerThirdsService.GetMeetingTheme(ctx, meetingID)
+		if err != nil {
+			s.Logger.Error("[getMeetingTheme] GetMeetingTheme error ", err)
+			helpers.WriteError(ctx, err, w)
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+		_ = json.NewEncoder(w).Encode(theme)
+	})
+}
+
+func (s *Server) putMeetingTheme() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		ctx := req.Context()
+
+		meetingID, err := uuid.Parse(mux.Vars(req)["MeetingID"])
+		if err != nil {
+			s.Logger.Error("[putMeetingTheme] error ", err)
+			helpers.WriteError(ctx, err, w)
+			return
+		}
+
+		body, err := io.ReadAll(http.MaxBytesReader(w, req.Body, maxThemeBody))
+		if err != nil {
+			s.Logger.Error("[putMeetingTheme] error ", err)
+			helpers.WriteError(ctx, err, w)
+			return
+		}
+
+		theme, err := s.lowerThirdsService.SetMeetingTheme(ctx, meetingID, body)
+		if err != nil {
+			s.Logger.Error("[putMeetingTheme] SetMeetingTheme error ", err)
+			helpers.WriteError(ctx, err, w)
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+		_ = json.NewEncoder(w).Encode(theme)
+	})
+}
+
+func (s *Server) deleteMeetingTheme() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		ctx := req.Context()
+
+		meetingID, err := uuid.Parse(mux.Vars(req)["MeetingID"])
+		if err != nil {
+			s.Logger.Error("[deleteMeetingTheme] error ", err)
+			helpers.WriteError(ctx, err, w)
+			return
+		}
+
+		err = s.lowerThirdsService.DeleteMeetingTheme(ctx, meetingID)
+		if err != nil {
+			s.Logger.Error("[deleteMeetingTheme] DeleteMeetingTheme error ", err)
+			helpers.WriteError(ctx, err, w)
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	})
+}
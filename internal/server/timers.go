@@ -0,0 +1,96 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"io"
+	"lowerthirdsapi/internal/entities"
+	"lowerthirdsapi/internal/helpers"
+	"net/http"
+	"time"
+)
+
+// TimerActionRequest is the optional body of a timer action, with the seconds to add for add_time
+type TimerActionRequest struct {
+	Seconds int `json:"seconds"`
+}
+
+func (s *Server) getMeetingTimers() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		ctx := req.Context()
+
+		meetingID, err := uuid.Parse(mux.Vars(req)["MeetingID"])
+		if err != nil {
+			s.Logger.Error("[getMeetingTimers] error ", err)
+			helpers.WriteError(ctx, err, w)
+			return
+		}
+
+		timers, err := s.lowerThirdsService.GetMeetingTimers(ctx, meetingID)
+		if err != nil {
+			s.Logger.Error("[getMeetingTimers] GetMeetingTimers error ", err)
+			helpers.WriteError(ctx, err, w)
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+		_ = json.NewEncoder(w).Encode(timers)
+	})
+}
+
+func (s *Server) getItemTimer() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		ctx := req.Context()
+
+		itemID, err := uuid.Parse(mux.Vars(req)["ItemID"])
+		if err != nil {
+			s.Logger.Error("[getItemTimer] error ", err)
+			helpers.WriteError(ctx, err, w)
+			return
+		}
+
+		timer, err := s.lowerThirdsService.GetItemTimer(ctx, itemID)
+		if err != nil {
+			s.Logger.Error("[getItemTimer] GetItemTimer error ", err)
+			helpers.WriteError(ctx, err, w)
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+		_ = json.NewEncoder(w).Encode(timer)
+	})
+}
+
+func (s *Server) updateItemTimer() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		ctx := req.Context()
+
+		itemID, err := uuid.Parse(mux.Vars(req)["ItemID"])
+		if err != nil {
+			s.Logger.Error("[updateItemTimer] error ", err)
+			helpers.WriteError(ctx, err, w)
+			return
+		}
+		action := entities.TimerAction(mux.Vars(req)["Action"])
+
+		// Only add_time needs a body
+		var body TimerActionRequest
+		if err := json.NewDecoder(req.Body).Decode(&body); err != nil && !errors.Is(err, io.EOF) {
+			s.Logger.Error("[updateItemTimer] ", err)
+			helpers.WriteError(ctx, err, w)
+			return
+		}
+
+		timer, err := s.lowerThirdsService.UpdateItemTimer(ctx, itemID, action, time.Duration(body.Seconds)*time.Second)
+		if err != nil {
+			s.Logger.Error("[updateItemTimer] UpdateItemTimer error ", err)
+			helpers.WriteError(ctx, err, w)
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+		_ = json.NewEncoder(w).Encode(timer)
+	})
+}
@@ -2,11 +2,11 @@ package server
 
 import (
 	"encoding/json"
-	"github.com/go-sql-driver/mysql"
 	"github.com/google/uuid"
 	"github.com/gorilla/mux"
 	"lowerthirdsapi/internal/entities"
 	"lowerthirdsapi/internal/helpers"
+	"lowerthirdsapi/internal/storage"
 	"net/http"
 )
 
@@ -51,38 +51,28 @@ func (s *Server) getOrgsByUser() http.Handler {
 			return
 		}
 
-		meetings, err := s.lowerThirdsService.GetOrgsByUser(ctx, userID)
+		orgs, total, err := s.lowerThirdsService.GetOrgsByUser(ctx, userID)
 		if err != nil {
 			s.Logger.Error("[getOrgsByUser] error ", err)
 			helpers.WriteError(ctx, err, w)
 			return
 		}
 
-		w.WriteHeader(http.StatusOK)
-		err = json.NewEncoder(w).Encode(meetings)
-		if err != nil {
-			s.Logger.Error(err)
-			helpers.WriteError(ctx, err, w)
-		}
+		s.writeList(w, req, orgs, total)
 	})
 }
 
 func (s *Server) getUsers() http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
 		ctx := req.Context()
-		users, err := s.lowerThirdsService.GetUsers(ctx)
+		users, total, err := s.lowerThirdsService.GetUsers(ctx)
 		if err != nil {
 			s.Logger.Error(err)
 			helpers.WriteError(ctx, err, w)
 			return
 		}
 
-		w.WriteHeader(http.StatusOK)
-		err = json.NewEncoder(w).Encode(users)
-		if err != nil {
-			s.Logger.Error(err)
-			helpers.WriteError(ctx, err, w)
-		}
+		s.writeList(w, req, users, total)
 	})
 }
 
@@ -124,19 +114,14 @@ func (s *Server) getUserMeetings() http.Handler {
 			return
 		}
 
-		meetings, err := s.lowerThirdsService.GetMeetingsByUser(ctx, userID)
+		meetings, total, err := s.lowerThirdsService.GetMeetingsByUser(ctx, userID)
 		if err != nil {
 			s.Logger.Error(err)
 			helpers.WriteError(ctx, err, w)
 			return
 		}
 
-		w.WriteHeader(http.StatusOK)
-		err = json.NewEncoder(w).Encode(meetings)
-		if err != nil {
-			s.Logger.Error(err)
-			helpers.WriteError(ctx, err, w)
-		}
+		s.writeList(w, req, meetings, total)
 	})
 }
 
@@ -160,7 +145,7 @@ func (s *Server) postUser() http.Handler {
 		err := s.lowerThirdsService.CreateUser(ctx, &user)
 		if err != nil {
 			// Check for MySQL duplicate entry error
-			if mysqlErr, ok := err.(*mysql.MySQLError); ok && mysqlErr.Number == 1062 {
+			if storage.IsDuplicate(err) {
 				http.Error(w, "[postUser] already exists", http.StatusConflict)
 				return
 			}
@@ -227,7 +212,7 @@ func (s *Server) updateUser() http.Handler {
 		err = s.lowerThirdsService.UpdateUser(ctx, userID, &user)
 		if err != nil {
 			// Check for MySQL duplicate entry error
-			if mysqlErr, ok := err.(*mysql.MySQLError); ok && mysqlErr.Number == 1062 {
+			if storage.IsDuplicate(err) {
 				http.Error(w, "[updateUser] already exists", http.StatusConflict)
 				return
 			}
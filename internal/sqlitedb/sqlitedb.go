@@ -0,0 +1,44 @@
+// Package sqlitedb opens the SQLite databases the storage backends and tests run on. It's kept apart from storage so
+// testutil, which storage's tests import, can open the same databases.
+package sqlitedb
+
+import (
+	"fmt"
+	"net/url"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+
+	_ "modernc.org/sqlite"
+)
+
+// params are the connection settings for both SQLite backends. Writers wait for each other instead of failing,
+// transactions take the write lock up front since SQLite has no SELECT ... FOR UPDATE, and times are written in a
+// format SQLite's date functions and the driver can both read.
+const params = "_pragma=busy_timeout(10000)&_txlock=immediate&_time_format=sqlite"
+
+// Open opens the SQLite database at path, creating the file if it doesn't exist. The schema comes from the
+// migrations, which the caller runs.
+func Open(path string) (*sqlx.DB, error) {
+	dsn := fmt.Sprintf("file:%s?%s&_pragma=journal_mode(WAL)", url.PathEscape(path), params)
+	db, err := sqlx.Connect("sqlite", dsn)
+	if err != nil {
+		return nil, err
+	}
+	return db, nil
+}
+
+// OpenMemory opens a new, empty in-memory database. Its connections share the data, which lasts until the database
+// is closed. The schema comes from the migrations, which the caller runs.
+func OpenMemory() (*sqlx.DB, error) {
+	dsn := fmt.Sprintf("file:/lowerthirds-%s?vfs=memdb&%s", uuid.New(), params)
+	db, err := sqlx.Connect("sqlite", dsn)
+	if err != nil {
+		return nil, err
+	}
+	// Keep a connection open, since the database is dropped when the last one closes
+	db.SetMaxIdleConns(1)
+	db.SetConnMaxLifetime(0)
+	db.SetConnMaxIdleTime(0)
+	return db, nil
+}
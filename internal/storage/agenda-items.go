@@ -0,0 +1,108 @@
+package storage
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"lowerthirdsapi/internal/entities"
+	"time"
+
+	"github.com/google/uuid"
+	"gopkg.in/guregu/null.v4"
+)
+
+// agendaItemColumns are the AgendaItems columns in the order agendaItemRow scans them
+const agendaItemColumns = `i.id, i.meeting_id, i.item_type, i.item_order, i.meeting_role, i.details, i.deleted_dt,
+	i.inserted_dt, i.updated_dt`
+
+// agendaItemBaseKeys are the JSON keys of an item that have their own AgendaItems column. Everything else an item
+// type marshals goes in the details payload.
+var agendaItemBaseKeys = []string{"id", "meeting_id", "type", "order", "meeting_role", "deleted_dt", "inserted_dt",
+	"updated_dt"}
+
+// agendaItemRow is an item of any type as it's stored in AgendaItems
+type agendaItemRow struct {
+	ID          uuid.UUID `db:"id"`
+	MeetingID   uuid.UUID `db:"meeting_id"`
+	ItemType    string    `db:"item_type"`
+	ItemOrder   int       `db:"item_order"`
+	MeetingRole string    `db:"meeting_role"`
+	Details     []byte    `db:"details"`
+	DeletedDT   null.Time `db:"deleted_dt"`
+	InsertedDT  time.Time `db:"inserted_dt"`
+	UpdatedDT   time.Time `db:"updated_dt"`
+}
+
+// newAgendaItemRow splits an item into its base columns and the JSON details of its type. Items that couldn't be
+// read back, like ones of an unknown type, aren't stored.
+func newAgendaItemRow(item entities.Item) (*agendaItemRow, error) {
+	data, err := json.Marshal(item)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := entities.ParseItemJSON(data); err != nil {
+		return nil, err
+	}
+
+	var details map[string]json.RawMessage
+	if err := json.Unmarshal(data, &details); err != nil {
+		return nil, err
+	}
+	for _, key := range agendaItemBaseKeys {
+		delete(details, key)
+	}
+	detailsJSON, err := json.Marshal(details)
+	if err != nil {
+		return nil, err
+	}
+
+	return &agendaItemRow{
+		ID:          item.GetID(),
+		MeetingID:   item.GetMeetingID(),
+		ItemType:    item.GetType(),
+		ItemOrder:   item.GetOrder(),
+		MeetingRole: item.GetMeetingRole(),
+		Details:     detailsJSON,
+	}, nil
+}
+
+// item joins the row's columns and details back into an item of its type
+func (r agendaItemRow) item() (entities.Item, error) {
+	fields := map[string]any{}
+	if len(r.Details) > 0 {
+		if err := json.Unmarshal(r.Details, &fields); err != nil {
+			return nil, fmt.Errorf("item %s has invalid details: %w", r.ID, err)
+		}
+	}
+	fields["id"] = r.ID
+	fields["meeting_id"] = r.MeetingID
+	fields["type"] = r.ItemType
+	fields["order"] = r.ItemOrder
+	fields["meeting_role"] = r.MeetingRole
+	fields["deleted_dt"] = r.DeletedDT
+	fields["inserted_dt"] = r.InsertedDT
+	fields["updated_dt"] = r.UpdatedDT
+
+	data, err := json.Marshal(fields)
+	if err != nil {
+		return nil, err
+	}
+	item, err := entities.ParseItemJSON(data)
+	if errors.Is(err, entities.ErrUnknownItemType) {
+		return nil, fmt.Errorf("invalid item type %q for item %s", r.ItemType, r.ID)
+	}
+	return item, err
+}
+
+// agendaItems converts rows to items, keeping their order
+func agendaItems(rows []agendaItemRow) ([]entities.Item, error) {
+	items := make([]entities.Item, 0, len(rows))
+	for _, row := range rows {
+		item, err := row.item()
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+	return items, nil
+}
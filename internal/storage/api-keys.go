@@ -0,0 +1,285 @@
+package storage
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"gopkg.in/guregu/null.v4"
+	"lowerthirdsapi/internal/apierrors"
+	"lowerthirdsapi/internal/entities"
+	"net/http"
+	"time"
+)
+
+// apiKeyBytes is the amount of randomness in an API key
+const apiKeyBytes = 32
+
+// apiKeyUseInterval is how stale a key's last use may get before it's written again, so busy devices don't write on
+// every request
+const apiKeyUseInterval = time.Minute
+
+// notAPIKeyUser leaves the users API keys act as out of queries on OrgUsers ou. Those users are managed through the
+// org's keys, not as members.
+const notAPIKeyUser = `NOT EXISTS (SELECT 1 FROM ApiKeys k WHERE k.user_id = ou.user_id)`
+
+// hashAPIKey is what's stored for a key. Keys are random, so a plain hash is enough.
+func hashAPIKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+// CreateAPIKey makes a key for a device in the org and returns the key, which isn't stored and can't be shown again.
+// The key gets its own user, a member of the org with the role of the key's scope.
+func (s lowerThirdsService) CreateAPIKey(ctx context.Context, orgID uuid.UUID, k *entities.APIKey) (string, error) {
+	s.logger.Debug("CreateAPIKey for orgID ", orgID)
+
+	if !k.Scope.Valid() {
+		return "", apierrors.New(http.StatusBadRequest, "INVALID_SCOPE", "Invalid scope",
+			"scope %q must be read or operator", k.Scope)
+	}
+	if k.Name == "" {
+		return "", apierrors.New(http.StatusBadRequest, "INVALID_NAME", "Invalid name", "the key needs a name")
+	}
+
+	user, err := s.authorizeOrg(ctx, orgID, entities.RoleOwner)
+	if err != nil {
+		return "", err
+	}
+
+	raw := make([]byte, apiKeyBytes)
+	_, err = rand.Read(raw)
+	if err != nil {
+		s.logger.Error("CreateAPIKey Error", err)
+		return "", err
+	}
+	key := entities.APIKeyPrefix + base64.RawURLEncoding.EncodeToString(raw)
+
+	k.KeyID = uuid.New()
+	k.OrgID = orgID
+	k.UserID = uuid.New()
+	k.Prefix = key[:len(entities.APIKeyPrefix)+8]
+	k.KeyHash = hashAPIKey(key)
+	k.CreatedBy = user.UserID
+
+	tx, err := s.MySqlDB.BeginTxx(ctx, nil)
+	if err != nil {
+		s.logger.Error("CreateAPIKey Begin Error", err)
+		return "", err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.ExecContext(
+		ctx,
+		`INSERT INTO Users (id, email, full_name, social_id) VALUES (?, ?, ?, ?)`,
+		k.UserID,
+		"apikey-"+k.KeyID.String()+"@keys.invalid",
+		k.Name,
+		k.SocialID(),
+	)
+	if err != nil {
+		s.logger.Error("CreateAPIKey User Error", err)
+		return "", err
+	}
+	_, err = tx.ExecContext(
+		ctx,
+		`INSERT INTO OrgUsers (org_id, user_id, role) VALUES (?, ?, ?)`,
+		orgID,
+		k.UserID,
+		k.Scope.Role(),
+	)
+	if err != nil {
+		s.logger.Error("CreateAPIKey OrgUser Error", err)
+		return "", err
+	}
+	_, err = tx.ExecContext(
+		ctx,
+		`INSERT INTO ApiKeys (id, org_id, user_id, name, scope, prefix, key_hash, created_by)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)`,
+		k.KeyID,
+		k.OrgID,
+		k.UserID,
+		k.Name,
+		k.Scope,
+		k.Prefix,
+		k.KeyHash,
+		k.CreatedBy,
+	)
+	if err != nil {
+		s.logger.Error("CreateAPIKey Error", err)
+		return "", err
+	}
+	err = tx.GetContext(ctx, k, `SELECT * FROM ApiKeys WHERE id = ?`, k.KeyID)
+	if err != nil {
+		s.logger.Error("CreateAPIKey Select Error", err)
+		return "", err
+	}
+	err = s.audit(ctx, tx, user, orgID, entities.AuditAPIKey, k.KeyID, nil, k)
+	if err != nil {
+		return "", err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		s.logger.Error("CreateAPIKey Commit Error", err)
+		return "", err
+	}
+	return key, nil
+}
+
+// GetAPIKeysByOrg loads the org's keys that haven't been revoked, oldest first
+func (s lowerThirdsService) GetAPIKeysByOrg(ctx context.Context, orgID uuid.UUID) (*[]entities.APIKey, error) {
+	s.logger.Debug("GetAPIKeysByOrg for orgID ", orgID)
+
+	_, err := s.authorizeOrg(ctx, orgID, entities.RoleOwner)
+	if err != nil {
+		return nil, err
+	}
+
+	keys := []entities.APIKey{}
+	err = s.MySqlDB.SelectContext(
+		ctx,
+		&keys,
+		`SELECT * FROM ApiKeys
+		WHERE org_id = ?
+		  AND deleted_dt IS NULL
+		ORDER BY inserted_dt, id`,
+		orgID,
+	)
+	if err != nil {
+		s.logger.Error("GetAPIKeysByOrg Error", err)
+		return nil, err
+	}
+	return &keys, nil
+}
+
+// DeleteAPIKey revokes one of the org's keys, along with its user and membership
+func (s lowerThirdsService) DeleteAPIKey(ctx context.Context, orgID uuid.UUID, keyID uuid.UUID) error {
+	s.logger.Debug("DeleteAPIKey for orgID ", orgID, " keyID ", keyID)
+
+	user, err := s.authorizeOrg(ctx, orgID, entities.RoleOwner)
+	if err != nil {
+		return err
+	}
+
+	tx, err := s.MySqlDB.BeginTxx(ctx, nil)
+	if err != nil {
+		s.logger.Error("DeleteAPIKey Begin Error", err)
+		return err
+	}
+	defer tx.Rollback()
+
+	var before entities.APIKey
+	err = tx.GetContext(
+		ctx,
+		&before,
+		`SELECT * FROM ApiKeys WHERE id = ? AND org_id = ? AND deleted_dt IS NULL`+s.dialect.forUpdate(),
+		keyID,
+		orgID,
+	)
+	if errors.Is(err, sql.ErrNoRows) {
+		return apierrors.New(http.StatusNotFound, "KEY_NOT_FOUND", "Key not found",
+			"The org has no API key %s.", keyID)
+	}
+	if err != nil {
+		s.logger.Error("DeleteAPIKey Select Error", err)
+		return err
+	}
+
+	revokes := []struct {
+		query string
+		id    uuid.UUID
+	}{
+		{`UPDATE ApiKeys SET deleted_dt = CURRENT_TIMESTAMP WHERE id = ?`, keyID},
+		{`UPDATE OrgUsers SET deleted_dt = CURRENT_TIMESTAMP WHERE user_id = ? AND deleted_dt IS NULL`, before.UserID},
+		{`UPDATE Users SET deleted_dt = CURRENT_TIMESTAMP WHERE id = ?`, before.UserID},
+	}
+	for _, revoke := range revokes {
+		_, err = tx.ExecContext(ctx, revoke.query, revoke.id)
+		if err != nil {
+			s.logger.Error("DeleteAPIKey Error", err)
+			return err
+		}
+	}
+	err = s.audit(ctx, tx, user, orgID, entities.AuditAPIKey, keyID, before, nil)
+	if err != nil {
+		return err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		s.logger.Error("DeleteAPIKey Commit Error", err)
+		return err
+	}
+	return nil
+}
+
+// AuthenticateAPIKey finds the key a device sent, if it hasn't been revoked, and notes that it was used. It needs no
+// user in the context.
+func (s lowerThirdsService) AuthenticateAPIKey(ctx context.Context, key string) (*entities.APIKey, error) {
+	var k entities.APIKey
+	err := s.MySqlDB.GetContext(
+		ctx,
+		&k,
+		`SELECT k.*
+		FROM ApiKeys k
+		INNER JOIN Organization o
+		  ON o.id = k.org_id
+		  AND o.deleted_dt IS NULL
+		WHERE k.key_hash = ?
+		  AND k.deleted_dt IS NULL`,
+		hashAPIKey(key),
+	)
+	if err != nil {
+		if !errors.Is(err, sql.ErrNoRows) {
+			s.logger.Error("AuthenticateAPIKey Error", err)
+		}
+		return nil, err
+	}
+	s.logger.Debug("AuthenticateAPIKey keyID ", k.KeyID)
+
+	now := time.Now().UTC()
+	if !k.LastUsedDT.Valid || now.Sub(k.LastUsedDT.Time) >= apiKeyUseInterval {
+		_, err = s.MySqlDB.ExecContext(
+			ctx,
+			`UPDATE ApiKeys SET last_used_dt = ? WHERE id = ?`,
+			now.Truncate(time.Second),
+			k.KeyID,
+		)
+		if err != nil {
+			s.logger.Error("AuthenticateAPIKey Update Error", err)
+			return nil, err
+		}
+		k.LastUsedDT = null.TimeFrom(now.Truncate(time.Second))
+	}
+	return &k, nil
+}
+
+// rejectAPIKeyUsers fails if any of the users is one an API key acts as, since their memberships only change with the
+// key. It reads through the caller's transaction so the check holds for the changes made in it.
+func (s lowerThirdsService) rejectAPIKeyUsers(ctx context.Context, tx *sqlx.Tx, userIDs []uuid.UUID) error {
+	if len(userIDs) == 0 {
+		return nil
+	}
+
+	query, args, err := sqlx.In(`SELECT user_id FROM ApiKeys WHERE user_id IN (?)`, userIDs)
+	if err != nil {
+		return err
+	}
+	var keyUserIDs []uuid.UUID
+	err = tx.SelectContext(ctx, &keyUserIDs, tx.Rebind(query), args...)
+	if err != nil {
+		s.logger.Error("rejectAPIKeyUsers Error", err)
+		return err
+	}
+	if len(keyUserIDs) > 0 {
+		return apierrors.New(http.StatusConflict, "API_KEY_MEMBER", "API key member",
+			"user %s belongs to an API key; create or revoke the key instead", keyUserIDs[0])
+	}
+	return nil
+}
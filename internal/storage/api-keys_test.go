@@ -0,0 +1,163 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"lowerthirdsapi/internal/apierrors"
+	"lowerthirdsapi/internal/entities"
+	"lowerthirdsapi/internal/helpers"
+	"lowerthirdsapi/internal/testutil"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestAPIKeys(t *testing.T) {
+	testutil.SetupTest(t)
+	defer testutil.TeardownTest()
+
+	service := New(testutil.TestDB, testutil.TestLogger)
+	_, org, meeting := testutil.CreateTestData(t, service)
+
+	// Only real scopes
+	var apiErr *apierrors.Error
+	_, err := service.CreateAPIKey(testutil.TestCtx, org.OrgID, &entities.APIKey{Name: "Stream Deck", Scope: "editor"})
+	if !errors.As(err, &apiErr) || apiErr.Status != http.StatusBadRequest {
+		t.Errorf("Expected bad request for an unknown scope, got %v", err)
+	}
+
+	reader := &entities.APIKey{Name: "OBS PC", Scope: entities.APIKeyScopeRead}
+	readerKey, err := service.CreateAPIKey(testutil.TestCtx, org.OrgID, reader)
+	if err != nil {
+		t.Fatalf("CreateAPIKey failed: %v", err)
+	}
+	if !strings.HasPrefix(readerKey, entities.APIKeyPrefix) || !strings.HasPrefix(readerKey, reader.Prefix) {
+		t.Errorf("Expected a key starting with %q, got %q", reader.Prefix, readerKey)
+	}
+	if reader.KeyHash == "" || strings.Contains(reader.KeyHash, readerKey) {
+		t.Errorf("Expected only a hash of the key stored, got %q", reader.KeyHash)
+	}
+	operator := &entities.APIKey{Name: "Stream Deck", Scope: entities.APIKeyScopeOperator}
+	operatorKey, err := service.CreateAPIKey(testutil.TestCtx, org.OrgID, operator)
+	if err != nil {
+		t.Fatalf("CreateAPIKey failed: %v", err)
+	}
+
+	keys, err := service.GetAPIKeysByOrg(testutil.TestCtx, org.OrgID)
+	if err != nil {
+		t.Fatalf("GetAPIKeysByOrg failed: %v", err)
+	}
+	if len(*keys) != 2 {
+		t.Errorf("Expected 2 keys, got %+v", keys)
+	}
+	body, _ := json.Marshal(keys)
+	if strings.Contains(string(body), reader.KeyHash) {
+		t.Error("Expected key hashes to stay out of JSON")
+	}
+
+	// Keys authenticate as their own user, and their use is noted
+	authenticated, err := service.AuthenticateAPIKey(testutil.TestCtx, readerKey)
+	if err != nil {
+		t.Fatalf("AuthenticateAPIKey failed: %v", err)
+	}
+	if authenticated.KeyID != reader.KeyID || !authenticated.LastUsedDT.Valid {
+		t.Errorf("Expected the reader key used, got %+v", authenticated)
+	}
+	if _, err := service.AuthenticateAPIKey(testutil.TestCtx, readerKey+"x"); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("Expected no rows for a wrong key, got %v", err)
+	}
+	readerCtx := context.WithValue(context.Background(), helpers.SocialIDKey, authenticated.SocialID())
+	readerCtx = context.WithValue(readerCtx, helpers.APIKeyKey, authenticated)
+	operatorCtx := context.WithValue(context.Background(), helpers.SocialIDKey, operator.SocialID())
+	operatorCtx = context.WithValue(operatorCtx, helpers.APIKeyKey, operator)
+
+	// Read keys read, operator keys also run meetings, and neither edits
+	if _, err := service.GetMeeting(readerCtx, meeting.MeetingID); err != nil {
+		t.Errorf("GetMeeting with a read key failed: %v", err)
+	}
+	_, err = service.UpdateLiveState(readerCtx, meeting.MeetingID, entities.LiveActionClear, uuid.Nil)
+	if !errors.As(err, &apiErr) || apiErr.Status != http.StatusForbidden {
+		t.Errorf("Expected forbidden for a read key going live, got %v", err)
+	}
+	if _, err := service.UpdateLiveState(operatorCtx, meeting.MeetingID, entities.LiveActionClear, uuid.Nil); err != nil {
+		t.Errorf("UpdateLiveState with an operator key failed: %v", err)
+	}
+	err = service.CreateMeeting(operatorCtx, &entities.Meeting{MeetingID: uuid.New(), OrgID: org.OrgID, Meeting: "Test Meeting", MeetingDate: time.Now()})
+	if !errors.As(err, &apiErr) || apiErr.Status != http.StatusForbidden {
+		t.Errorf("Expected forbidden for an operator key making a meeting, got %v", err)
+	}
+	err = service.CreateOrg(operatorCtx, &entities.Organization{OrgID: uuid.New(), Name: "Test Org " + uuid.NewString()})
+	if !errors.As(err, &apiErr) || apiErr.Status != http.StatusForbidden {
+		t.Errorf("Expected forbidden for a key making an org, got %v", err)
+	}
+
+	// Key users aren't listed as members, and their memberships only change with the key
+	members, err := service.GetOrgUsers(testutil.TestCtx, org.OrgID)
+	if err != nil {
+		t.Fatalf("GetOrgUsers failed: %v", err)
+	}
+	for _, member := range *members {
+		if member.UserID == reader.UserID || member.UserID == operator.UserID {
+			t.Errorf("Expected key users left out of members, got %+v", members)
+		}
+	}
+	users, total, err := service.GetUsersByOrg(testutil.TestCtx, org.OrgID)
+	if err != nil {
+		t.Fatalf("GetUsersByOrg failed: %v", err)
+	}
+	if total != len(*members) || len(*users) != len(*members) {
+		t.Errorf("Expected %d users like the members, got %d of %d", len(*members), len(*users), total)
+	}
+	got, err := service.GetOrg(testutil.TestCtx, org.OrgID)
+	if err != nil {
+		t.Fatalf("GetOrg failed: %v", err)
+	}
+	if len(got.UserIDs) != len(*members) {
+		t.Errorf("Expected the org's users to leave out keys, got %v", got.UserIDs)
+	}
+	err = service.SetOrgUserRole(testutil.TestCtx, org.OrgID, operator.UserID, entities.RoleOwner)
+	if !errors.As(err, &apiErr) || apiErr.Status != http.StatusConflict {
+		t.Errorf("Expected conflict promoting a key user, got %v", err)
+	}
+	err = service.DeleteOrgUser(testutil.TestCtx, org.OrgID, operator.UserID)
+	if !errors.As(err, &apiErr) || apiErr.Status != http.StatusConflict {
+		t.Errorf("Expected conflict removing a key user, got %v", err)
+	}
+	err = service.SetOrgsByUser(testutil.TestCtx, operator.UserID, nil)
+	if !errors.As(err, &apiErr) || apiErr.Status != http.StatusConflict {
+		t.Errorf("Expected conflict moving a key user, got %v", err)
+	}
+	err = service.UpdateOrg(testutil.TestCtx, org.OrgID, &entities.Organization{OrgID: org.OrgID, Name: org.Name, UserIDs: []uuid.UUID{operator.UserID}})
+	if !errors.As(err, &apiErr) || apiErr.Status != http.StatusConflict {
+		t.Errorf("Expected conflict adding a key user, got %v", err)
+	}
+
+	// Updating the org's members leaves its keys alone
+	err = service.UpdateOrg(testutil.TestCtx, org.OrgID, &entities.Organization{OrgID: org.OrgID, Name: org.Name, UserIDs: got.UserIDs})
+	if err != nil {
+		t.Fatalf("UpdateOrg failed: %v", err)
+	}
+	if _, err := service.GetMeeting(operatorCtx, meeting.MeetingID); err != nil {
+		t.Errorf("Expected the operator key to stay a member, got %v", err)
+	}
+
+	// Revoked keys stop working
+	if err := service.DeleteAPIKey(testutil.TestCtx, org.OrgID, reader.KeyID); err != nil {
+		t.Fatalf("DeleteAPIKey failed: %v", err)
+	}
+	if _, err := service.AuthenticateAPIKey(testutil.TestCtx, readerKey); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("Expected no rows for a revoked key, got %v", err)
+	}
+	err = service.DeleteAPIKey(testutil.TestCtx, org.OrgID, reader.KeyID)
+	if !errors.As(err, &apiErr) || apiErr.Status != http.StatusNotFound {
+		t.Errorf("Expected not found revoking a key twice, got %v", err)
+	}
+	if _, err := service.AuthenticateAPIKey(testutil.TestCtx, operatorKey); err != nil {
+		t.Errorf("Expected the other key to keep working, got %v", err)
+	}
+}
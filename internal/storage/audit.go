@@ -0,0 +1,106 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"gopkg.in/guregu/null.v4"
+	"lowerthirdsapi/internal/entities"
+	"lowerthirdsapi/internal/helpers"
+)
+
+// GetAuditLogByOrg lists a page of the changes made in an org, newest first, with the number on every page. The
+// EntityType, EntityID, Action and UserID query parameters filter on what was changed, how and by whom, and DateFrom
+// and DateTo on when.
+func (s lowerThirdsService) GetAuditLogByOrg(ctx context.Context, orgID uuid.UUID) (*[]entities.AuditRecord, int, error) {
+	s.logger.Debug("GetAuditLogByOrg for orgID ", orgID)
+
+	_, err := s.authorizeOrg(ctx, orgID, entities.RoleOwner)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	qp := helpers.GetQueryParams(ctx)
+	query := `SELECT * FROM AuditLog WHERE org_id = ?`
+	args := []interface{}{orgID}
+	if qp.EntityType != "" {
+		query += ` AND entity_type = ?`
+		args = append(args, qp.EntityType)
+	}
+	if qp.EntityID != "" {
+		query += ` AND entity_id = ?`
+		args = append(args, qp.EntityID)
+	}
+	if qp.Action != "" {
+		query += ` AND action = ?`
+		args = append(args, qp.Action)
+	}
+	if qp.UserID != uuid.Nil {
+		query += ` AND actor_id = ?`
+		args = append(args, qp.UserID)
+	}
+
+	records := []entities.AuditRecord{}
+	total, err := s.selectPage(ctx, &records, query, "inserted_dt", "id DESC", args...)
+	if err != nil {
+		return nil, 0, err
+	}
+	return &records, total, nil
+}
+
+// audit records a change to an entity in the audit log through q, which should be the change's own transaction so
+// the change and its record are kept or lost together. before is nil for a create and after is nil for a delete;
+// updates that change nothing aren't recorded. actor is nil for changes made without a user, and orgID is uuid.Nil
+// for entities outside any org. The request ID comes from the context.
+func (s lowerThirdsService) audit(ctx context.Context, q sqlx.ExecerContext, actor *entities.User, orgID uuid.UUID, entityType entities.AuditEntity, entityID interface{}, before interface{}, after interface{}) error {
+	action := entities.AuditUpdate
+	switch {
+	case before == nil:
+		action = entities.AuditCreate
+	case after == nil:
+		action = entities.AuditDelete
+	}
+	diff, err := entities.NewAuditDiff(before, after)
+	if err != nil {
+		s.logger.Error("audit Diff Error", err)
+		return err
+	}
+	if action == entities.AuditUpdate && len(diff) == 0 {
+		return nil
+	}
+
+	record := entities.AuditRecord{
+		EntityType: entityType,
+		EntityID:   fmt.Sprint(entityID),
+		Action:     action,
+		Diff:       diff,
+	}
+	if orgID != uuid.Nil {
+		record.OrgID = uuid.NullUUID{UUID: orgID, Valid: true}
+	}
+	if actor != nil {
+		record.ActorID = uuid.NullUUID{UUID: actor.UserID, Valid: true}
+	}
+	if requestID, ok := ctx.Value(helpers.RequestIDKey).(string); ok && requestID != "" {
+		record.RequestID = null.StringFrom(requestID)
+	}
+
+	_, err = q.ExecContext(
+		ctx,
+		`INSERT INTO AuditLog (org_id, actor_id, entity_type, entity_id, action, diff, request_id)
+		VALUES (?, ?, ?, ?, ?, ?, ?)`,
+		record.OrgID,
+		record.ActorID,
+		record.EntityType,
+		record.EntityID,
+		record.Action,
+		record.Diff,
+		record.RequestID,
+	)
+	if err != nil {
+		s.logger.Error("audit Error", err)
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,143 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"lowerthirdsapi/internal/apierrors"
+	"lowerthirdsapi/internal/entities"
+	"lowerthirdsapi/internal/helpers"
+	"lowerthirdsapi/internal/testutil"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestAuditLog(t *testing.T) {
+	testutil.SetupTest(t)
+	defer testutil.TeardownTest()
+
+	service := New(testutil.TestDB, testutil.TestLogger)
+	user, org, meeting := testutil.CreateTestData(t, service)
+
+	// Changes are recorded with the request they were made in
+	ctx := context.WithValue(testutil.TestCtx, helpers.RequestIDKey, "test-request-id")
+	updated := *meeting
+	updated.Meeting = "Test Meeting Renamed"
+	err := service.UpdateMeeting(ctx, meeting.MeetingID, &updated)
+	if err != nil {
+		t.Fatalf("UpdateMeeting failed: %v", err)
+	}
+	err = service.DeleteMeeting(ctx, meeting.MeetingID)
+	if err != nil {
+		t.Fatalf("DeleteMeeting failed: %v", err)
+	}
+
+	qp := helpers.DefaultQueryParams()
+	qp.EntityType = string(entities.AuditMeeting)
+	qp.EntityID = meeting.MeetingID.String()
+	records, total, err := service.GetAuditLogByOrg(context.WithValue(testutil.TestCtx, helpers.QueryParametersKey, qp), org.OrgID)
+	if err != nil {
+		t.Fatalf("GetAuditLogByOrg failed: %v", err)
+	}
+	if total != 3 || len(*records) != 3 {
+		t.Fatalf("Expected the meeting's create, update and delete, got %d: %+v", total, records)
+	}
+
+	// Newest first
+	deleted, update, created := (*records)[0], (*records)[1], (*records)[2]
+	if created.Action != entities.AuditCreate || update.Action != entities.AuditUpdate || deleted.Action != entities.AuditDelete {
+		t.Errorf("Unexpected actions %v, %v, %v", created.Action, update.Action, deleted.Action)
+	}
+	for _, record := range *records {
+		if record.ActorID.UUID != user.UserID || record.OrgID.UUID != org.OrgID {
+			t.Errorf("Expected the test user in the test org, got %+v", record)
+		}
+	}
+	if created.RequestID.Valid || update.RequestID.String != "test-request-id" || deleted.RequestID.String != "test-request-id" {
+		t.Errorf("Unexpected request IDs %v, %v, %v", created.RequestID, update.RequestID, deleted.RequestID)
+	}
+	if len(update.Diff) != 1 || string(update.Diff["meeting"].Before) != `"Test Meeting"` || string(update.Diff["meeting"].After) != `"Test Meeting Renamed"` {
+		t.Errorf("Expected only the name changed, got %+v", update.Diff)
+	}
+	if _, ok := deleted.Diff["deleted_dt"]; !ok || deleted.Diff["meeting"].Before == nil {
+		t.Errorf("Expected the deleted meeting recorded, got %+v", deleted.Diff)
+	}
+
+	// Updates that change nothing aren't recorded
+	err = service.UpdateOrg(testutil.TestCtx, org.OrgID, org)
+	if err != nil {
+		t.Fatalf("UpdateOrg failed: %v", err)
+	}
+
+	// Filters
+	qp = helpers.DefaultQueryParams()
+	qp.Action = string(entities.AuditCreate)
+	records, total, err = service.GetAuditLogByOrg(context.WithValue(testutil.TestCtx, helpers.QueryParametersKey, qp), org.OrgID)
+	if err != nil {
+		t.Fatalf("GetAuditLogByOrg failed: %v", err)
+	}
+	if total != 3 {
+		t.Errorf("Expected the org, its owner and the meeting created, got %+v", records)
+	}
+	qp = helpers.DefaultQueryParams()
+	qp.EntityType = string(entities.AuditOrg)
+	records, total, err = service.GetAuditLogByOrg(context.WithValue(testutil.TestCtx, helpers.QueryParametersKey, qp), org.OrgID)
+	if err != nil {
+		t.Fatalf("GetAuditLogByOrg failed: %v", err)
+	}
+	if total != 1 {
+		t.Errorf("Expected only the org's create, got %+v", records)
+	}
+	qp = helpers.DefaultQueryParams()
+	qp.UserID = uuid.New()
+	_, total, err = service.GetAuditLogByOrg(context.WithValue(testutil.TestCtx, helpers.QueryParametersKey, qp), org.OrgID)
+	if err != nil {
+		t.Fatalf("GetAuditLogByOrg failed: %v", err)
+	}
+	if total != 0 {
+		t.Errorf("Expected nothing done by another user, got %d", total)
+	}
+
+	// Only owners read the log
+	viewerID := uuid.New()
+	viewerSocialID := "viewer-social-id-" + viewerID.String()
+	_, err = testutil.TestDB.Exec(`
+		INSERT INTO Users (id, email, social_id)
+		VALUES (?, ?, ?)
+	`, viewerID, "viewer+"+viewerID.String()+"@example.com", viewerSocialID)
+	if err != nil {
+		t.Fatalf("Failed to create viewer: %v", err)
+	}
+	err = service.CreateOrgUser(testutil.TestCtx, org.OrgID, viewerID, entities.RoleEditor)
+	if err != nil {
+		t.Fatalf("CreateOrgUser failed: %v", err)
+	}
+	editorCtx := context.WithValue(context.Background(), helpers.SocialIDKey, viewerSocialID)
+	var apiErr *apierrors.Error
+	_, _, err = service.GetAuditLogByOrg(editorCtx, org.OrgID)
+	if !errors.As(err, &apiErr) || apiErr.Status != http.StatusForbidden {
+		t.Errorf("Expected forbidden for an editor, got %v", err)
+	}
+
+	// A change that fails leaves no record
+	other := &entities.Meeting{MeetingID: uuid.New(), OrgID: org.OrgID, Meeting: "Test Meeting", MeetingDate: time.Now()}
+	err = service.CreateMeeting(editorCtx, other)
+	if err != nil {
+		t.Fatalf("CreateMeeting failed: %v", err)
+	}
+	err = service.CreateMeeting(editorCtx, other)
+	if err == nil {
+		t.Fatal("Expected creating the same meeting twice to fail")
+	}
+	qp = helpers.DefaultQueryParams()
+	qp.EntityID = other.MeetingID.String()
+	_, total, err = service.GetAuditLogByOrg(context.WithValue(testutil.TestCtx, helpers.QueryParametersKey, qp), org.OrgID)
+	if err != nil {
+		t.Fatalf("GetAuditLogByOrg failed: %v", err)
+	}
+	if total != 1 {
+		t.Errorf("Expected only the first create recorded, got %d", total)
+	}
+}
@@ -0,0 +1,175 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"lowerthirdsapi/internal/apierrors"
+	"lowerthirdsapi/internal/auth"
+	"lowerthirdsapi/internal/entities"
+	"lowerthirdsapi/internal/helpers"
+	"net/http"
+
+	"github.com/google/uuid"
+)
+
+// forbidden creates the API error returned when the caller lacks the role required for an operation
+func forbidden(detail string, args ...interface{}) *apierrors.Error {
+	return apierrors.New(http.StatusForbidden, "FORBIDDEN", "Forbidden", detail, args...)
+}
+
+// currentUser returns the user making the request. The API resolves it once per request and puts it in the context
+// under UserIDKey; other callers are looked up from the social ID in the context.
+func (s lowerThirdsService) currentUser(ctx context.Context) (*entities.User, error) {
+	if ctx == nil {
+		return nil, errors.New("context is required")
+	}
+	if user, ok := ctx.Value(helpers.UserIDKey).(*entities.User); ok {
+		return user, nil
+	}
+	socialID, ok := ctx.Value(helpers.SocialIDKey).(string)
+	if !ok {
+		return nil, errors.New("socialID is required in context")
+	}
+	user, err := s.GetUserBySocialID(ctx, socialID)
+	if err != nil {
+		s.logger.Error("User not found by socialID", err)
+		return nil, err
+	}
+	return user, nil
+}
+
+// callerAPIKey returns the API key the request was made with, if it was
+func callerAPIKey(ctx context.Context) (*entities.APIKey, bool) {
+	k, ok := ctx.Value(helpers.APIKeyKey).(*entities.APIKey)
+	return k, ok
+}
+
+// verifiedEmail returns the email the caller's ID token vouches for. API keys and tokens whose email isn't verified
+// have none.
+func verifiedEmail(ctx context.Context) (string, bool) {
+	claims, ok := ctx.Value(helpers.AuthKey).(*auth.Claims)
+	if !ok || !claims.EmailVerified || claims.Email == "" {
+		return "", false
+	}
+	return claims.Email, true
+}
+
+// getOrgRole returns the highest role a user holds in an org, or an empty role if they aren't a member
+func (s lowerThirdsService) getOrgRole(ctx context.Context, orgID uuid.UUID, userID uuid.UUID) (entities.Role, error) {
+	var roles []entities.Role
+	err := s.MySqlDB.SelectContext(
+		ctx,
+		&roles,
+		`SELECT ou.role
+		FROM OrgUsers ou
+		INNER JOIN Users u
+		  ON u.id = ou.user_id
+		  AND u.deleted_dt IS NULL
+		INNER JOIN Organization o
+		  ON o.id = ou.org_id
+		  AND o.deleted_dt IS NULL
+		WHERE ou.org_id = ?
+		  AND ou.user_id = ?
+		  AND ou.deleted_dt IS NULL`,
+		orgID,
+		userID,
+	)
+	if err != nil {
+		s.logger.Error("getOrgRole error ", err)
+		return "", err
+	}
+
+	var highest entities.Role
+	for _, role := range roles {
+		if role.Valid() && !highest.Includes(role) {
+			highest = role
+		}
+	}
+	return highest, nil
+}
+
+// authorizeOrg checks that the calling user holds at least the required role in an org
+func (s lowerThirdsService) authorizeOrg(ctx context.Context, orgID uuid.UUID, required entities.Role) (*entities.User, error) {
+	user, err := s.currentUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// API keys are held to their scope in their own org, whatever their user's membership
+	if k, ok := callerAPIKey(ctx); ok && (k.OrgID != orgID || !k.Scope.Role().Includes(required)) {
+		s.logger.Info("authorizeOrg denied keyID ", k.KeyID, " orgID ", orgID, " scope ", k.Scope, " required ", required)
+		return nil, forbidden("API key %s with the %s scope can't use the %s role in organization %s", k.Prefix, k.Scope, required, orgID)
+	}
+
+	role, err := s.getOrgRole(ctx, orgID, user.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if !role.Includes(required) {
+		s.logger.Info("authorizeOrg denied userID ", user.UserID, " orgID ", orgID, " role ", role, " required ", required)
+		return nil, forbidden("the %s role is required in organization %s", required, orgID)
+	}
+	return user, nil
+}
+
+// authorizeMeeting checks that the calling user holds at least the required role in the meeting's org
+func (s lowerThirdsService) authorizeMeeting(ctx context.Context, meetingID uuid.UUID, required entities.Role) (*entities.User, error) {
+	var orgID uuid.UUID
+	err := s.MySqlDB.GetContext(
+		ctx,
+		&orgID,
+		`SELECT org_id FROM Meetings WHERE id = ? AND deleted_dt IS NULL`,
+		meetingID,
+	)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, forbidden("the %s role is required for meeting %s", required, meetingID)
+	}
+	if err != nil {
+		s.logger.Error("authorizeMeeting error ", err)
+		return nil, err
+	}
+	return s.authorizeOrg(ctx, orgID, required)
+}
+
+// authorizeItem checks that the calling user holds at least the required role in the org of the item's meeting
+func (s lowerThirdsService) authorizeItem(ctx context.Context, itemID uuid.UUID, required entities.Role) (*entities.User, error) {
+	meetingID, err := s.itemMeetingID(ctx, itemID)
+	if err != nil {
+		return nil, err
+	}
+	return s.authorizeMeeting(ctx, meetingID, required)
+}
+
+// itemMeetingID finds the meeting of an item
+func (s lowerThirdsService) itemMeetingID(ctx context.Context, itemID uuid.UUID) (uuid.UUID, error) {
+	var meetingID uuid.UUID
+	err := s.MySqlDB.GetContext(
+		ctx,
+		&meetingID,
+		`SELECT meeting_id FROM AgendaItems WHERE id = ? AND deleted_dt IS NULL`,
+		itemID,
+	)
+	if err != nil {
+		if !errors.Is(err, sql.ErrNoRows) {
+			s.logger.Error("itemMeetingID error ", err)
+		}
+		return uuid.Nil, err
+	}
+	return meetingID, nil
+}
+
+// authorizeUser checks that the calling user is the user being changed
+func (s lowerThirdsService) authorizeUser(ctx context.Context, userID uuid.UUID) (*entities.User, error) {
+	user, err := s.currentUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := callerAPIKey(ctx); ok {
+		return nil, forbidden("API keys can't change accounts")
+	}
+	if user.UserID != userID {
+		return nil, forbidden("users may only change their own account")
+	}
+	return user, nil
+}
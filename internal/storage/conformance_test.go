@@ -0,0 +1,117 @@
+package storage_test
+
+import (
+	"context"
+	"io"
+	"lowerthirdsapi/internal/entities"
+	"lowerthirdsapi/internal/helpers"
+	"lowerthirdsapi/internal/storage"
+	"lowerthirdsapi/internal/storage/storagetest"
+	"lowerthirdsapi/internal/testutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"gopkg.in/guregu/null.v4"
+)
+
+func TestConformance(t *testing.T) {
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	log := logrus.NewEntry(logger)
+
+	for _, backend := range []string{storage.BackendMemory, storage.BackendSQLite} {
+		t.Run(backend, func(t *testing.T) {
+			storagetest.Run(t, func(t *testing.T) storage.LowerThirdsService {
+				cfg := storage.BackendConfig{Backend: backend, SQLitePath: filepath.Join(t.TempDir(), "lowerthirds.db")}
+				db, err := storage.OpenLocal(context.Background(), cfg, log)
+				if err != nil {
+					t.Fatalf("OpenLocal failed: %v", err)
+				}
+				t.Cleanup(func() { db.Close() })
+				return storage.New(db, log)
+			})
+		})
+	}
+
+	t.Run(storage.BackendMySQL, func(t *testing.T) {
+		if backend := os.Getenv("TEST_STORAGE_BACKEND"); backend != "" && backend != storage.BackendMySQL {
+			t.Skip("TEST_STORAGE_BACKEND is ", backend)
+		}
+		storagetest.Run(t, func(t *testing.T) storage.LowerThirdsService {
+			testutil.SetupTest(t)
+			t.Cleanup(testutil.TeardownTest)
+			return storage.New(testutil.TestDB, log)
+		})
+	})
+}
+
+// SQLite has no row locks, so its transactions take the write lock as they begin. Concurrent timer changes still
+// all have to land.
+func TestSQLiteConcurrentWrites(t *testing.T) {
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	log := logrus.NewEntry(logger)
+
+	for _, backend := range []string{storage.BackendMemory, storage.BackendSQLite} {
+		t.Run(backend, func(t *testing.T) {
+			cfg := storage.BackendConfig{Backend: backend, SQLitePath: filepath.Join(t.TempDir(), "lowerthirds.db")}
+			db, err := storage.OpenLocal(context.Background(), cfg, log)
+			if err != nil {
+				t.Fatalf("OpenLocal failed: %v", err)
+			}
+			defer db.Close()
+			service := storage.New(db, log)
+
+			userID := uuid.New()
+			ctx := context.WithValue(context.Background(), helpers.SocialIDKey, "concurrent-"+userID.String())
+			err = service.CreateUser(ctx, &entities.User{UserID: userID, Email: "concurrent@example.com", SocialID: null.StringFrom("concurrent-" + userID.String())})
+			if err != nil {
+				t.Fatalf("CreateUser failed: %v", err)
+			}
+			org := &entities.Organization{OrgID: uuid.New(), Name: "Concurrent Org"}
+			if err := service.CreateOrg(ctx, org); err != nil {
+				t.Fatalf("CreateOrg failed: %v", err)
+			}
+			meeting := &entities.Meeting{MeetingID: uuid.New(), OrgID: org.OrgID, Meeting: "Concurrent Meeting", MeetingDate: time.Now()}
+			if err := service.CreateMeeting(ctx, meeting); err != nil {
+				t.Fatalf("CreateMeeting failed: %v", err)
+			}
+			item := &entities.BlankItem{BlankItemID: uuid.New(), MeetingID: meeting.MeetingID, ItemType: "blank", ItemOrder: 1, MeetingRole: "Concurrent Role"}
+			if err := service.CreateItem(ctx, item); err != nil {
+				t.Fatalf("CreateItem failed: %v", err)
+			}
+
+			const changes = 8
+			var wg sync.WaitGroup
+			errs := make(chan error, changes)
+			for i := 0; i < changes; i++ {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					_, err := service.UpdateItemTimer(ctx, item.BlankItemID, entities.TimerActionAddTime, time.Second)
+					errs <- err
+				}()
+			}
+			wg.Wait()
+			close(errs)
+			for err := range errs {
+				if err != nil {
+					t.Errorf("UpdateItemTimer failed: %v", err)
+				}
+			}
+
+			timer, err := service.GetItemTimer(ctx, item.BlankItemID)
+			if err != nil {
+				t.Fatalf("GetItemTimer failed: %v", err)
+			}
+			if timer.Version != changes || timer.AddedMS != changes*1000 {
+				t.Errorf("Expected every change to land, got version %d with %dms added", timer.Version, timer.AddedMS)
+			}
+		})
+	}
+}
@@ -0,0 +1,56 @@
+package storage
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/go-sql-driver/mysql"
+	"modernc.org/sqlite"
+	sqlite3 "modernc.org/sqlite/lib"
+)
+
+// dialect writes the SQL that differs between MySQL and SQLite. Everything else the service runs is shared.
+type dialect struct {
+	sqlite bool
+}
+
+// dialectFor picks the dialect of a database's driver
+func dialectFor(driverName string) dialect {
+	return dialect{sqlite: driverName == "sqlite"}
+}
+
+// forUpdate locks the selected rows until the transaction ends. SQLite transactions already hold the write lock.
+func (d dialect) forUpdate() string {
+	if d.sqlite {
+		return ""
+	}
+	return " FOR UPDATE"
+}
+
+// upsert ends an INSERT so that a row with the same key is updated with the inserted columns instead
+func (d dialect) upsert(key string, columns ...string) string {
+	sets := make([]string, 0, len(columns))
+	if d.sqlite {
+		for _, column := range columns {
+			sets = append(sets, column+" = excluded."+column)
+		}
+		return "ON CONFLICT (" + key + ") DO UPDATE SET " + strings.Join(sets, ", ")
+	}
+	for _, column := range columns {
+		sets = append(sets, column+" = VALUES("+column+")")
+	}
+	return "ON DUPLICATE KEY UPDATE " + strings.Join(sets, ", ")
+}
+
+// IsDuplicate reports whether err is the database refusing a row whose key is already taken
+func IsDuplicate(err error) bool {
+	var mysqlErr *mysql.MySQLError
+	if errors.As(err, &mysqlErr) {
+		return mysqlErr.Number == 1062
+	}
+	var sqliteErr *sqlite.Error
+	if errors.As(err, &sqliteErr) {
+		return sqliteErr.Code() == sqlite3.SQLITE_CONSTRAINT_PRIMARYKEY || sqliteErr.Code() == sqlite3.SQLITE_CONSTRAINT_UNIQUE
+	}
+	return false
+}
@@ -0,0 +1,34 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"github.com/google/uuid"
+	"lowerthirdsapi/internal/entities"
+	"lowerthirdsapi/internal/events"
+)
+
+// SubscribeMeetingEvents starts receiving the meeting's changes, replaying any missed since lastEventID
+func (s lowerThirdsService) SubscribeMeetingEvents(ctx context.Context, meetingID uuid.UUID, lastEventID uint64) (*events.Subscription, error) {
+	s.logger.Debug("SubscribeMeetingEvents for meetingID ", meetingID, " lastEventID ", lastEventID)
+
+	_, err := s.authorizeMeeting(ctx, meetingID, entities.RoleViewer)
+	if err != nil {
+		return nil, err
+	}
+	if s.events == nil {
+		return nil, errors.New("meeting events are not enabled")
+	}
+	return s.events.Subscribe(meetingID, lastEventID), nil
+}
+
+// publish sends a meeting event if events are enabled. Failures are logged rather than failing the change.
+func (s lowerThirdsService) publish(meetingID uuid.UUID, eventType string, data interface{}) {
+	if s.events == nil {
+		return
+	}
+	_, err := s.events.Publish(meetingID, eventType, data)
+	if err != nil {
+		s.logger.Error("publish Error", err)
+	}
+}
@@ -0,0 +1,69 @@
+package storage
+
+import (
+	"lowerthirdsapi/internal/entities"
+	"lowerthirdsapi/internal/events"
+	"lowerthirdsapi/internal/testutil"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestMeetingEvents(t *testing.T) {
+	testutil.SetupTest(t)
+	defer testutil.TeardownTest()
+
+	broker := events.NewBroker(10)
+	service := New(testutil.TestDB, testutil.TestLogger, WithEvents(broker))
+	_, _, meeting := testutil.CreateTestData(t, service)
+
+	sub, err := service.SubscribeMeetingEvents(testutil.TestCtx, meeting.MeetingID, 0)
+	if err != nil {
+		t.Fatalf("SubscribeMeetingEvents failed: %v", err)
+	}
+	defer sub.Close()
+
+	item := &entities.BlankItem{
+		BlankItemID: uuid.New(),
+		MeetingID:   meeting.MeetingID,
+		ItemType:    "blank",
+		ItemOrder:   1,
+		MeetingRole: "Test Role",
+	}
+	err = service.CreateItem(testutil.TestCtx, item)
+	if err != nil {
+		t.Fatalf("CreateItem failed: %v", err)
+	}
+	_, err = service.UpdateLiveState(testutil.TestCtx, meeting.MeetingID, entities.LiveActionNext, uuid.Nil)
+	if err != nil {
+		t.Fatalf("UpdateLiveState failed: %v", err)
+	}
+	err = service.DeleteItem(testutil.TestCtx, item.BlankItemID)
+	if err != nil {
+		t.Fatalf("DeleteItem failed: %v", err)
+	}
+
+	var lastEventID uint64
+	for _, want := range []string{events.ItemCreated, events.LiveChanged, events.ItemDeleted} {
+		select {
+		case event := <-sub.C:
+			if event.Type != want {
+				t.Errorf("Expected %s event, got %s", want, event.Type)
+			}
+			lastEventID = event.ID
+		case <-time.After(time.Second):
+			t.Fatalf("Timed out waiting for %s event", want)
+		}
+	}
+
+	// Resuming from the first event replays the rest
+	resumed, err := service.SubscribeMeetingEvents(testutil.TestCtx, meeting.MeetingID, lastEventID-2)
+	if err != nil {
+		t.Fatalf("SubscribeMeetingEvents failed: %v", err)
+	}
+	defer resumed.Close()
+	if len(resumed.Replay) != 2 {
+		t.Errorf("Expected 2 replayed events, got %d", len(resumed.Replay))
+	}
+}
@@ -0,0 +1,158 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"lowerthirdsapi/internal/apierrors"
+	"lowerthirdsapi/internal/entities"
+	"lowerthirdsapi/internal/helpers"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+func (s lowerThirdsService) GetHymn(ctx context.Context, hymnID uuid.UUID) (*entities.Hymn, error) {
+	s.logger.Debug("GetHymn for hymnID ", hymnID)
+	return s.getHymn(ctx, s.MySqlDB, hymnID)
+}
+
+// getHymn loads a hymn with its verses and translation through q, so it can read inside a transaction
+func (s lowerThirdsService) getHymn(ctx context.Context, q sqlx.QueryerContext, hymnID uuid.UUID) (*entities.Hymn, error) {
+	var hymn entities.Hymn
+	err := sqlx.GetContext(
+		ctx,
+		q,
+		&hymn,
+		`SELECT * FROM Hymns WHERE id = ? AND deleted_dt IS NULL`,
+		hymnID,
+	)
+	if err != nil {
+		s.logger.Error("GetHymn Error", err)
+		return nil, err
+	}
+
+	verses, err := s.getHymnVerses(ctx, q, hymnID)
+	if err != nil {
+		return nil, err
+	}
+	hymn.Verses = *verses
+
+	// Translations are only linked in one direction, so look both ways
+	var translation entities.Hymn
+	err = sqlx.GetContext(
+		ctx,
+		q,
+		&translation,
+		`SELECT * FROM Hymns
+		WHERE id <> ?
+		  AND (id = ? OR translation_id = ?)
+		  AND deleted_dt IS NULL
+		LIMIT 1`,
+		hymnID,
+		hymn.TranslationID,
+		hymnID,
+	)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		s.logger.Error("GetHymn Translation Error", err)
+		return nil, err
+	}
+	if err == nil {
+		hymn.Translation = &translation
+	}
+
+	return &hymn, nil
+}
+
+// GetHymns lists a page of the hymns in the request's language by page number, with the number on every page
+func (s lowerThirdsService) GetHymns(ctx context.Context) (*[]entities.Hymn, int, error) {
+	qp := helpers.GetQueryParams(ctx)
+	s.logger.Debug("GetHymns for language ", qp.Language, " search ", qp.Search)
+
+	query := `SELECT * FROM Hymns WHERE language = ? AND deleted_dt IS NULL`
+	args := []interface{}{qp.Language}
+
+	// A numeric search is a page number in the hymnal, anything else searches the title
+	search := strings.TrimSpace(qp.Search)
+	if page, err := strconv.Atoi(search); err == nil {
+		query += ` AND page = ?`
+		args = append(args, page)
+	} else if search != "" {
+		query += ` AND LOWER(name) LIKE ?`
+		args = append(args, "%"+strings.ToLower(search)+"%")
+	}
+
+	hymns := []entities.Hymn{}
+	total, err := s.selectPage(ctx, &hymns, query, "", "page, id", args...)
+	if err != nil {
+		return nil, 0, err
+	}
+	return &hymns, total, nil
+}
+
+func (s lowerThirdsService) getHymnVerses(ctx context.Context, q sqlx.QueryerContext, hymnID uuid.UUID) (*[]entities.HymnVerse, error) {
+	verses := []entities.HymnVerse{}
+	err := sqlx.SelectContext(
+		ctx,
+		q,
+		&verses,
+		`SELECT * FROM HymnVerses
+		WHERE hymn_id = ?
+		  AND deleted_dt IS NULL
+		ORDER BY verse_number`,
+		hymnID,
+	)
+	if err != nil {
+		s.logger.Error("getHymnVerses Error", err)
+		return nil, err
+	}
+	return &verses, nil
+}
+
+func (s lowerThirdsService) GetItemSlides(ctx context.Context, itemID uuid.UUID) (*[]entities.Slide, error) {
+	s.logger.Debug("GetItemSlides for itemID ", itemID)
+
+	item, err := s.GetItem(ctx, itemID)
+	if err != nil {
+		return nil, err
+	}
+	lyricsItem, ok := item.(*entities.LyricsItem)
+	if !ok {
+		return nil, apierrors.New(http.StatusBadRequest, "NOT_LYRICS", "Not a lyrics item",
+			"item %s is a %s item; only lyrics items have slides", itemID, item.GetType())
+	}
+	return s.lyricsSlides(ctx, s.MySqlDB, lyricsItem)
+}
+
+// lyricsSlides builds the slides of a lyrics item's hymn with the request's slide options, reading through q
+func (s lowerThirdsService) lyricsSlides(ctx context.Context, q sqlx.QueryerContext, lyricsItem *entities.LyricsItem) (*[]entities.Slide, error) {
+	qp := helpers.GetQueryParams(ctx)
+	s.logger.Debug("lyricsSlides for itemID ", lyricsItem.LyricsItemID, " maxLines ", qp.MaxLines, " includeOptional ", qp.IncludeOptional)
+
+	hymnID, err := uuid.Parse(lyricsItem.HymnID)
+	if err != nil {
+		return nil, apierrors.New(http.StatusBadRequest, "NO_HYMN", "No hymn",
+			"lyrics item %s doesn't have a hymn", lyricsItem.LyricsItemID)
+	}
+
+	hymn, err := s.getHymn(ctx, q, hymnID)
+	if err != nil {
+		return nil, err
+	}
+	if lyricsItem.ShowTranslation && hymn.Translation != nil {
+		verses, err := s.getHymnVerses(ctx, q, hymn.Translation.HymnID)
+		if err != nil {
+			return nil, err
+		}
+		hymn.Translation.Verses = *verses
+	}
+
+	slides := hymn.Slides(entities.SlideOptions{
+		MaxLines:        qp.MaxLines,
+		IncludeOptional: qp.IncludeOptional,
+		ShowTranslation: lyricsItem.ShowTranslation,
+	})
+	return &slides, nil
+}
@@ -0,0 +1,182 @@
+package storage
+
+import (
+	"context"
+	"lowerthirdsapi/internal/entities"
+	"lowerthirdsapi/internal/helpers"
+	"lowerthirdsapi/internal/testutil"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+// Helper function to create a hymn with verses and a translation
+func createTestHymn(t *testing.T) (uuid.UUID, uuid.UUID) {
+	hymnID := uuid.New()
+	translationID := uuid.New()
+
+	_, err := testutil.TestDB.Exec(`
+		INSERT INTO Hymns (id, language, page, name)
+		VALUES (?, 'spa', 9001, 'Test Hymn Traducido')
+	`, translationID)
+	if err != nil {
+		t.Fatalf("Failed to create translation: %v", err)
+	}
+
+	_, err = testutil.TestDB.Exec(`
+		INSERT INTO Hymns (id, language, page, name, translation_id)
+		VALUES (?, 'eng', 9001, 'Test Hymn of Praise', ?)
+	`, hymnID, translationID)
+	if err != nil {
+		t.Fatalf("Failed to create hymn: %v", err)
+	}
+
+	for i, optional := range []bool{false, false, true} {
+		_, err = testutil.TestDB.Exec(`
+			INSERT INTO HymnVerses (hymn_id, verse_number, verse_lines, optional)
+			VALUES (?, ?, ?, ?)
+		`, hymnID, i+1, "Line one\nLine two", optional)
+		if err != nil {
+			t.Fatalf("Failed to create verse: %v", err)
+		}
+		_, err = testutil.TestDB.Exec(`
+			INSERT INTO HymnVerses (hymn_id, verse_number, verse_lines, optional)
+			VALUES (?, ?, ?, ?)
+		`, translationID, i+1, "Linea uno\nLinea dos", optional)
+		if err != nil {
+			t.Fatalf("Failed to create translated verse: %v", err)
+		}
+	}
+
+	return hymnID, translationID
+}
+
+func TestGetHymn(t *testing.T) {
+	testutil.SetupTest(t)
+	defer testutil.TeardownTest()
+
+	service := New(testutil.TestDB, testutil.TestLogger)
+	hymnID, translationID := createTestHymn(t)
+
+	hymn, err := service.GetHymn(testutil.TestCtx, hymnID)
+	if err != nil {
+		t.Fatalf("GetHymn failed: %v", err)
+	}
+	if len(hymn.Verses) != 3 {
+		t.Fatalf("Expected 3 verses, got %d", len(hymn.Verses))
+	}
+	if hymn.Verses[0].VerseNumber != 1 || !hymn.Verses[2].Optional {
+		t.Errorf("Unexpected verses: %+v", hymn.Verses)
+	}
+	if hymn.Translation == nil || hymn.Translation.HymnID != translationID {
+		t.Errorf("Expected translation %v, got %+v", translationID, hymn.Translation)
+	}
+
+	// The translation links back to the original
+	translation, err := service.GetHymn(testutil.TestCtx, translationID)
+	if err != nil {
+		t.Fatalf("GetHymn failed: %v", err)
+	}
+	if translation.Translation == nil || translation.Translation.HymnID != hymnID {
+		t.Errorf("Expected translation %v, got %+v", hymnID, translation.Translation)
+	}
+
+	// Unknown hymns are an error
+	_, err = service.GetHymn(testutil.TestCtx, uuid.New())
+	if err == nil {
+		t.Error("Expected error for non-existent hymn")
+	}
+}
+
+func TestGetHymns(t *testing.T) {
+	testutil.SetupTest(t)
+	defer testutil.TeardownTest()
+
+	service := New(testutil.TestDB, testutil.TestLogger)
+	hymnID, translationID := createTestHymn(t)
+
+	tests := []struct {
+		name     string
+		language string
+		search   string
+		want     uuid.UUID
+	}{
+		{"By page", "eng", "9001", hymnID},
+		{"By title", "eng", "of praise", hymnID},
+		{"By language", "spa", "9001", translationID},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			qp := helpers.DefaultQueryParams()
+			qp.Language = tt.language
+			qp.Search = tt.search
+			ctx := context.WithValue(testutil.TestCtx, helpers.QueryParametersKey, qp)
+
+			hymns, _, err := service.GetHymns(ctx)
+			if err != nil {
+				t.Fatalf("GetHymns failed: %v", err)
+			}
+			if len(*hymns) != 1 || (*hymns)[0].HymnID != tt.want {
+				t.Errorf("Expected hymn %v, got %+v", tt.want, *hymns)
+			}
+		})
+	}
+}
+
+func TestGetItemSlides(t *testing.T) {
+	testutil.SetupTest(t)
+	defer testutil.TeardownTest()
+
+	service := New(testutil.TestDB, testutil.TestLogger)
+	_, _, meeting := testutil.CreateTestData(t, service)
+	hymnID, _ := createTestHymn(t)
+
+	lyricsItem := &entities.LyricsItem{
+		LyricsItemID:    uuid.New(),
+		MeetingID:       meeting.MeetingID,
+		ItemType:        "lyrics",
+		ItemOrder:       1,
+		MeetingRole:     "Test Role",
+		HymnID:          hymnID.String(),
+		ShowTranslation: true,
+	}
+	err := service.CreateItem(testutil.TestCtx, lyricsItem)
+	if err != nil {
+		t.Fatalf("CreateItem failed: %v", err)
+	}
+
+	qp := helpers.DefaultQueryParams()
+	qp.MaxLines = 1
+	ctx := context.WithValue(testutil.TestCtx, helpers.QueryParametersKey, qp)
+	slides, err := service.GetItemSlides(ctx, lyricsItem.LyricsItemID)
+	if err != nil {
+		t.Fatalf("GetItemSlides failed: %v", err)
+	}
+
+	// Two verses of two lines each, with the optional verse skipped
+	if len(*slides) != 4 {
+		t.Fatalf("Expected 4 slides, got %d", len(*slides))
+	}
+	first := (*slides)[0]
+	if first.Lines[0] != "Line one" || first.TranslationLines[0] != "Linea uno" {
+		t.Errorf("Unexpected first slide: %+v", first)
+	}
+
+	// Other item types don't have slides
+	blankItem := &entities.BlankItem{
+		BlankItemID: uuid.New(),
+		MeetingID:   meeting.MeetingID,
+		ItemType:    "blank",
+		ItemOrder:   2,
+		MeetingRole: "Test Role",
+	}
+	err = service.CreateItem(testutil.TestCtx, blankItem)
+	if err != nil {
+		t.Fatalf("CreateItem failed: %v", err)
+	}
+	_, err = service.GetItemSlides(ctx, blankItem.BlankItemID)
+	if err == nil {
+		t.Error("Expected error for blank item slides")
+	}
+}
@@ -0,0 +1,270 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"github.com/google/uuid"
+	"lowerthirdsapi/internal/apierrors"
+	"lowerthirdsapi/internal/entities"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// invitationNotFound creates the API error returned for an invitation that doesn't exist or was revoked
+func invitationNotFound(invitationID uuid.UUID) *apierrors.Error {
+	return apierrors.New(http.StatusNotFound, "INVITATION_NOT_FOUND", "Invitation not found",
+		"There's no open invitation %s.", invitationID)
+}
+
+// CreateInvitation saves an invitation for an email address to join the org with a role, from the calling owner.
+// The caller sets when it expires.
+func (s lowerThirdsService) CreateInvitation(ctx context.Context, orgID uuid.UUID, inv *entities.Invitation) error {
+	s.logger.Debug("CreateInvitation for orgID ", orgID)
+
+	if !inv.Role.Valid() {
+		return invalidRole(inv.Role)
+	}
+	inv.Email = strings.ToLower(strings.TrimSpace(inv.Email))
+	if !strings.Contains(inv.Email, "@") {
+		return apierrors.New(http.StatusBadRequest, "INVALID_EMAIL", "Invalid email",
+			"%q isn't an email address", inv.Email)
+	}
+	if inv.ExpiresDT.IsZero() {
+		return errors.New("invitation expiry is required")
+	}
+
+	user, err := s.authorizeOrg(ctx, orgID, entities.RoleOwner)
+	if err != nil {
+		return err
+	}
+	if inv.InvitationID == uuid.Nil {
+		inv.InvitationID = uuid.New()
+	}
+	inv.OrgID = orgID
+	inv.InvitedBy = user.UserID
+
+	tx, err := s.MySqlDB.BeginTxx(ctx, nil)
+	if err != nil {
+		s.logger.Error("CreateInvitation Begin Error", err)
+		return err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.ExecContext(
+		ctx,
+		`INSERT INTO Invitations (id, org_id, email, role, invited_by, expires_dt) VALUES (?, ?, ?, ?, ?, ?)`,
+		inv.InvitationID,
+		inv.OrgID,
+		inv.Email,
+		inv.Role,
+		inv.InvitedBy,
+		inv.ExpiresDT.UTC(),
+	)
+	if err != nil {
+		s.logger.Error("CreateInvitation Error", err)
+		return err
+	}
+
+	err = tx.GetContext(ctx, inv, `SELECT * FROM Invitations WHERE id = ?`, inv.InvitationID)
+	if err != nil {
+		s.logger.Error("CreateInvitation Select Error", err)
+		return err
+	}
+	err = s.audit(ctx, tx, user, orgID, entities.AuditInvitation, inv.InvitationID, nil, inv)
+	if err != nil {
+		return err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		s.logger.Error("CreateInvitation Commit Error", err)
+		return err
+	}
+	return nil
+}
+
+// GetInvitationsByOrg loads the org's invitations that haven't been revoked, newest first
+func (s lowerThirdsService) GetInvitationsByOrg(ctx context.Context, orgID uuid.UUID) (*[]entities.Invitation, error) {
+	s.logger.Debug("GetInvitationsByOrg for orgID ", orgID)
+
+	_, err := s.authorizeOrg(ctx, orgID, entities.RoleOwner)
+	if err != nil {
+		return nil, err
+	}
+
+	invitations := []entities.Invitation{}
+	err = s.MySqlDB.SelectContext(
+		ctx,
+		&invitations,
+		`SELECT * FROM Invitations
+		WHERE org_id = ?
+		  AND deleted_dt IS NULL
+		ORDER BY inserted_dt DESC, id`,
+		orgID,
+	)
+	if err != nil {
+		s.logger.Error("GetInvitationsByOrg Error", err)
+		return nil, err
+	}
+	return &invitations, nil
+}
+
+// DeleteInvitation revokes one of the org's invitations that hasn't been accepted
+func (s lowerThirdsService) DeleteInvitation(ctx context.Context, orgID uuid.UUID, invitationID uuid.UUID) error {
+	s.logger.Debug("DeleteInvitation for orgID ", orgID, " invitationID ", invitationID)
+
+	user, err := s.authorizeOrg(ctx, orgID, entities.RoleOwner)
+	if err != nil {
+		return err
+	}
+
+	tx, err := s.MySqlDB.BeginTxx(ctx, nil)
+	if err != nil {
+		s.logger.Error("DeleteInvitation Begin Error", err)
+		return err
+	}
+	defer tx.Rollback()
+
+	var before entities.Invitation
+	err = tx.GetContext(
+		ctx,
+		&before,
+		`SELECT * FROM Invitations
+		WHERE id = ?
+		  AND org_id = ?
+		  AND accepted_dt IS NULL
+		  AND deleted_dt IS NULL`+s.dialect.forUpdate(),
+		invitationID,
+		orgID,
+	)
+	if errors.Is(err, sql.ErrNoRows) {
+		return invitationNotFound(invitationID)
+	}
+	if err != nil {
+		s.logger.Error("DeleteInvitation Select Error", err)
+		return err
+	}
+	result, err := tx.ExecContext(ctx, `UPDATE Invitations SET deleted_dt = CURRENT_TIMESTAMP WHERE id = ?`, invitationID)
+	if err != nil {
+		s.logger.Error("DeleteInvitation error ", err)
+		return err
+	}
+	affectedRows, err := result.RowsAffected()
+	if err == nil {
+		s.logger.Info("DeleteInvitation affected rows: ", affectedRows)
+	}
+	err = s.audit(ctx, tx, user, orgID, entities.AuditInvitation, invitationID, before, nil)
+	if err != nil {
+		return err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		s.logger.Error("DeleteInvitation Commit Error", err)
+		return err
+	}
+	return nil
+}
+
+// AcceptInvitation adds the calling user to the invitation's org with its role. The invitation has to be for the
+// verified email on the caller's ID token, not just the one stored for the user, and still pending. Users who are
+// already members keep their role.
+func (s lowerThirdsService) AcceptInvitation(ctx context.Context, invitationID uuid.UUID) (*entities.Invitation, error) {
+	user, err := s.currentUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	s.logger.Debug("AcceptInvitation for userID ", user.UserID, " invitationID ", invitationID)
+	email, ok := verifiedEmail(ctx)
+	if !ok {
+		return nil, forbidden("invitations can only be accepted with a verified email address")
+	}
+
+	tx, err := s.MySqlDB.BeginTxx(ctx, nil)
+	if err != nil {
+		s.logger.Error("AcceptInvitation Begin Error", err)
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	// Lock the invitation so it's only accepted once
+	var inv entities.Invitation
+	err = tx.GetContext(
+		ctx,
+		&inv,
+		`SELECT i.*
+		FROM Invitations i
+		INNER JOIN Organization o
+		  ON o.id = i.org_id
+		  AND o.deleted_dt IS NULL
+		WHERE i.id = ?
+		  AND i.deleted_dt IS NULL`+s.dialect.forUpdate(),
+		invitationID,
+	)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, invitationNotFound(invitationID)
+	}
+	if err != nil {
+		s.logger.Error("AcceptInvitation Select Error", err)
+		return nil, err
+	}
+	if !strings.EqualFold(inv.Email, strings.TrimSpace(email)) {
+		return nil, forbidden("invitation %s is for another email address", invitationID)
+	}
+	if inv.AcceptedBy.Valid && inv.AcceptedBy.UUID == user.UserID {
+		return &inv, nil
+	}
+	if !inv.Pending(time.Now()) {
+		return nil, apierrors.New(http.StatusGone, "INVITATION_CLOSED", "Invitation closed",
+			"invitation %s has expired or was already accepted", invitationID)
+	}
+
+	var memberships int
+	err = tx.GetContext(
+		ctx,
+		&memberships,
+		`SELECT COUNT(*) FROM OrgUsers WHERE org_id = ? AND user_id = ? AND deleted_dt IS NULL`,
+		inv.OrgID,
+		user.UserID,
+	)
+	if err != nil {
+		s.logger.Error("AcceptInvitation Membership Error", err)
+		return nil, err
+	}
+	if memberships == 0 {
+		err = s.createOrgUser(ctx, tx, user, inv.OrgID, user.UserID, inv.Role)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	_, err = tx.ExecContext(
+		ctx,
+		`UPDATE Invitations SET accepted_by = ?, accepted_dt = CURRENT_TIMESTAMP WHERE id = ?`,
+		user.UserID,
+		invitationID,
+	)
+	if err != nil {
+		s.logger.Error("AcceptInvitation Update Error", err)
+		return nil, err
+	}
+	before := inv
+	err = tx.GetContext(ctx, &inv, `SELECT * FROM Invitations WHERE id = ?`, invitationID)
+	if err != nil {
+		s.logger.Error("AcceptInvitation Select Error", err)
+		return nil, err
+	}
+	err = s.audit(ctx, tx, user, inv.OrgID, entities.AuditInvitation, invitationID, before, inv)
+	if err != nil {
+		return nil, err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		s.logger.Error("AcceptInvitation Commit Error", err)
+		return nil, err
+	}
+	return &inv, nil
+}
@@ -0,0 +1,141 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"lowerthirdsapi/internal/apierrors"
+	"lowerthirdsapi/internal/auth"
+	"lowerthirdsapi/internal/entities"
+	"lowerthirdsapi/internal/helpers"
+	"lowerthirdsapi/internal/testutil"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestInvitations(t *testing.T) {
+	testutil.SetupTest(t)
+	defer testutil.TeardownTest()
+
+	service := New(testutil.TestDB, testutil.TestLogger)
+	_, org, _ := testutil.CreateTestData(t, service)
+
+	// Add the person being invited
+	inviteeID := uuid.New()
+	inviteeEmail := "invitee+" + inviteeID.String()[:8] + "@example.com"
+	inviteeSocialID := "invitee-social-id-" + inviteeID.String()
+	_, err := testutil.TestDB.Exec(`
+		INSERT INTO Users (id, email, social_id)
+		VALUES (?, ?, ?)
+	`, inviteeID, inviteeEmail, inviteeSocialID)
+	if err != nil {
+		t.Fatalf("Failed to create invitee: %v", err)
+	}
+	inviteeCtx := context.WithValue(context.Background(), helpers.SocialIDKey, inviteeSocialID)
+	unverifiedCtx := context.WithValue(inviteeCtx, helpers.AuthKey, &auth.Claims{UserID: inviteeSocialID, Email: inviteeEmail})
+	inviteeCtx = context.WithValue(inviteeCtx, helpers.AuthKey, &auth.Claims{UserID: inviteeSocialID, Email: inviteeEmail, EmailVerified: true})
+
+	invitation := &entities.Invitation{
+		Email:     " " + inviteeEmail + " ",
+		Role:      entities.RoleEditor,
+		ExpiresDT: time.Now().Add(time.Hour),
+	}
+	err = service.CreateInvitation(testutil.TestCtx, org.OrgID, invitation)
+	if err != nil {
+		t.Fatalf("CreateInvitation failed: %v", err)
+	}
+	if invitation.InvitationID == uuid.Nil || invitation.Email != inviteeEmail || invitation.InsertedDT.IsZero() {
+		t.Errorf("Unexpected invitation: %+v", invitation)
+	}
+
+	// Only owners invite, and only with real roles and addresses
+	var apiErr *apierrors.Error
+	err = service.CreateInvitation(inviteeCtx, org.OrgID, &entities.Invitation{Email: inviteeEmail, Role: entities.RoleViewer, ExpiresDT: time.Now().Add(time.Hour)})
+	if !errors.As(err, &apiErr) || apiErr.Status != http.StatusForbidden {
+		t.Errorf("Expected forbidden for a non-member, got %v", err)
+	}
+	err = service.CreateInvitation(testutil.TestCtx, org.OrgID, &entities.Invitation{Email: inviteeEmail, Role: "admin", ExpiresDT: time.Now().Add(time.Hour)})
+	if !errors.As(err, &apiErr) || apiErr.Status != http.StatusBadRequest {
+		t.Errorf("Expected bad request for an unknown role, got %v", err)
+	}
+	err = service.CreateInvitation(testutil.TestCtx, org.OrgID, &entities.Invitation{Email: "nobody", Role: entities.RoleViewer, ExpiresDT: time.Now().Add(time.Hour)})
+	if !errors.As(err, &apiErr) || apiErr.Status != http.StatusBadRequest {
+		t.Errorf("Expected bad request for a bad email, got %v", err)
+	}
+
+	invitations, err := service.GetInvitationsByOrg(testutil.TestCtx, org.OrgID)
+	if err != nil {
+		t.Fatalf("GetInvitationsByOrg failed: %v", err)
+	}
+	if len(*invitations) != 1 || (*invitations)[0].InvitationID != invitation.InvitationID {
+		t.Errorf("Expected the one invitation, got %+v", invitations)
+	}
+
+	// Someone else can't use it, and neither can the invitee until their email is verified
+	_, err = service.AcceptInvitation(testutil.TestCtx, invitation.InvitationID)
+	if !errors.As(err, &apiErr) || apiErr.Status != http.StatusForbidden {
+		t.Errorf("Expected forbidden without a verified email, got %v", err)
+	}
+	otherCtx := context.WithValue(testutil.TestCtx, helpers.AuthKey, &auth.Claims{UserID: "test-social-id", Email: "test@example.com", EmailVerified: true})
+	_, err = service.AcceptInvitation(otherCtx, invitation.InvitationID)
+	if !errors.As(err, &apiErr) || apiErr.Status != http.StatusForbidden {
+		t.Errorf("Expected forbidden for another email, got %v", err)
+	}
+	_, err = service.AcceptInvitation(unverifiedCtx, invitation.InvitationID)
+	if !errors.As(err, &apiErr) || apiErr.Status != http.StatusForbidden {
+		t.Errorf("Expected forbidden for an unverified email, got %v", err)
+	}
+
+	// The invitee joins with the invitation's role, once
+	accepted, err := service.AcceptInvitation(inviteeCtx, invitation.InvitationID)
+	if err != nil {
+		t.Fatalf("AcceptInvitation failed: %v", err)
+	}
+	if accepted.AcceptedBy.UUID != inviteeID || !accepted.AcceptedDT.Valid {
+		t.Errorf("Expected the invitation accepted by the invitee, got %+v", accepted)
+	}
+	members, err := service.GetOrgUsers(testutil.TestCtx, org.OrgID)
+	if err != nil {
+		t.Fatalf("GetOrgUsers failed: %v", err)
+	}
+	var role entities.Role
+	for _, member := range *members {
+		if member.UserID == inviteeID {
+			role = member.Role
+		}
+	}
+	if role != entities.RoleEditor {
+		t.Errorf("Expected the invitee to be an editor, got %q", role)
+	}
+	if _, err := service.AcceptInvitation(inviteeCtx, invitation.InvitationID); err != nil {
+		t.Errorf("Expected accepting again to do nothing, got %v", err)
+	}
+	err = service.DeleteInvitation(testutil.TestCtx, org.OrgID, invitation.InvitationID)
+	if !errors.As(err, &apiErr) || apiErr.Status != http.StatusNotFound {
+		t.Errorf("Expected not found revoking an accepted invitation, got %v", err)
+	}
+
+	// Revoked and expired invitations can't be accepted
+	revoked := &entities.Invitation{Email: inviteeEmail, Role: entities.RoleOwner, ExpiresDT: time.Now().Add(time.Hour)}
+	if err := service.CreateInvitation(testutil.TestCtx, org.OrgID, revoked); err != nil {
+		t.Fatalf("CreateInvitation failed: %v", err)
+	}
+	if err := service.DeleteInvitation(testutil.TestCtx, org.OrgID, revoked.InvitationID); err != nil {
+		t.Fatalf("DeleteInvitation failed: %v", err)
+	}
+	_, err = service.AcceptInvitation(inviteeCtx, revoked.InvitationID)
+	if !errors.As(err, &apiErr) || apiErr.Status != http.StatusNotFound {
+		t.Errorf("Expected not found for a revoked invitation, got %v", err)
+	}
+
+	expired := &entities.Invitation{Email: inviteeEmail, Role: entities.RoleOwner, ExpiresDT: time.Now().Add(-time.Minute)}
+	if err := service.CreateInvitation(testutil.TestCtx, org.OrgID, expired); err != nil {
+		t.Fatalf("CreateInvitation failed: %v", err)
+	}
+	_, err = service.AcceptInvitation(inviteeCtx, expired.InvitationID)
+	if !errors.As(err, &apiErr) || apiErr.Status != http.StatusGone {
+		t.Errorf("Expected gone for an expired invitation, got %v", err)
+	}
+}
@@ -4,12 +4,14 @@ import (
 	"context"
 	"database/sql"
 	"errors"
+	"lowerthirdsapi/internal/apierrors"
 	"lowerthirdsapi/internal/entities"
+	"lowerthirdsapi/internal/events"
 	"lowerthirdsapi/internal/helpers"
-	"sort"
+	"net/http"
 
 	"github.com/google/uuid"
-	"gopkg.in/guregu/null.v4"
+	"github.com/jmoiron/sqlx"
 )
 
 func (s lowerThirdsService) CreateItem(ctx context.Context, item entities.Item) error {
@@ -20,385 +22,236 @@ func (s lowerThirdsService) CreateItem(ctx context.Context, item entities.Item)
 		return errors.New("item is required")
 	}
 
-	socialID, ok := ctx.Value(helpers.SocialIDKey).(string)
-	if !ok {
-		return errors.New("socialID is required in context")
-	}
-	s.logger.Debug("[CreateItem] for socialID ", socialID)
 	s.logger.Debugf("[CreateItem] %+v", item)
 
-	// Each type of item handled separately
-	switch v := item.(type) {
-	case *entities.BlankItem:
-		if v.BlankItemID == uuid.Nil {
-			v.BlankItemID = uuid.New()
-		}
-		s.logger.Debugf("[CreateItem] createBlankItem %+v", v)
-		err := s.createBlankItem(v)
-		if err != nil {
-			s.logger.Error("error creating blankItem ", err)
-			return err
-		}
-	case *entities.LyricsItem:
-		if v.LyricsItemID == uuid.Nil {
-			v.LyricsItemID = uuid.New()
-		}
-		s.logger.Debugf("[CreateItem] createLyricsItem %+v", v)
-		err := s.createLyricsItem(v)
-		if err != nil {
-			s.logger.Error("error creating lyricsItem ", err)
-			return err
-		}
-	case *entities.MessageItem:
-		if v.MessageItemID == uuid.Nil {
-			v.MessageItemID = uuid.New()
-		}
-		s.logger.Debugf("[CreateItem] createMessageItem %+v", v)
-		err := s.createMessageItem(v)
-		if err != nil {
-			s.logger.Error("error creating messageItem ", err)
-			return err
-		}
-	case *entities.SpeakerItem:
-		if v.SpeakerItemID == uuid.Nil {
-			v.SpeakerItemID = uuid.New()
-		}
-		s.logger.Debugf("[CreateItem] createSpeakerItem %+v", v)
-		err := s.createSpeakerItem(v)
-		if err != nil {
-			s.logger.Error("error creating speakerItem ", err)
-			return err
-		}
-	case *entities.TimerItem:
-		if v.TimerItemID == uuid.Nil {
-			v.TimerItemID = uuid.New()
-		}
-		s.logger.Debugf("[CreateItem] createTimerItem %+v", v)
-		err := s.createTimerItem(v)
-		if err != nil {
-			s.logger.Error("error creating timerItem ", err)
-			return err
-		}
-	default:
-		return errors.New("unsupported item type")
+	user, err := s.authorizeMeeting(ctx, item.GetMeetingID(), entities.RoleEditor)
+	if err != nil {
+		return err
 	}
-	return nil
-}
-
-func (s lowerThirdsService) DeleteItem(ctx context.Context, itemID uuid.UUID) error {
-	socialID := ctx.Value(helpers.SocialIDKey).(string)
-	s.logger.Debug("DeleteItems for socialID ", socialID, " itemID ", itemID)
-	user, err := s.GetUserBySocialID(ctx, socialID)
+	orgID, err := s.meetingOrgID(ctx, item.GetMeetingID())
 	if err != nil {
-		s.logger.Error("User not found by socialID", err)
 		return err
 	}
-	s.logger.Debug("DeleteItems for userID ", user.UserID, " itemID ", itemID)
 
-	var totalAffectedRows int64 = 0
+	tx, err := s.MySqlDB.BeginTxx(ctx, nil)
+	if err != nil {
+		s.logger.Error("[CreateItem] Begin Error", err)
+		return err
+	}
+	defer tx.Rollback()
 
-	// Query each type of item separately
-	affectedRows, err := s.deleteBlankItem(user.UserID, itemID)
+	err = s.insertItem(ctx, tx, item)
 	if err != nil {
-		s.logger.Error("error deleting blankItem ", err)
 		return err
 	}
-	totalAffectedRows = totalAffectedRows + affectedRows
-	affectedRows, err = s.deleteMessageItem(user.UserID, itemID)
+	err = s.audit(ctx, tx, user, orgID, entities.AuditItem, item.GetID(), nil, item)
 	if err != nil {
-		s.logger.Error("error deleting messageItem ", err)
 		return err
 	}
-	totalAffectedRows = totalAffectedRows + affectedRows
-	affectedRows, err = s.deleteSpeakerItem(user.UserID, itemID)
+
+	err = tx.Commit()
 	if err != nil {
-		s.logger.Error("error deleting speakerItem ", err)
+		s.logger.Error("[CreateItem] Commit Error", err)
 		return err
 	}
-	totalAffectedRows = totalAffectedRows + affectedRows
-	affectedRows, err = s.deleteLyricsItem(user.UserID, itemID)
+
+	s.publish(item.GetMeetingID(), events.ItemCreated, item)
+	return nil
+}
+
+// insertItem gives an item an ID if it doesn't have one and inserts it with q, which may be a transaction. The
+// caller authorizes the meeting.
+func (s lowerThirdsService) insertItem(ctx context.Context, q sqlx.ExecerContext, item entities.Item) error {
+	if item.GetID() == uuid.Nil {
+		err := entities.SetItemID(item, uuid.New())
+		if err != nil {
+			return err
+		}
+	}
+	row, err := newAgendaItemRow(item)
 	if err != nil {
-		s.logger.Error("error deleting lyricsItem ", err)
+		s.logger.Error("[insertItem] error ", err)
 		return err
 	}
-	totalAffectedRows = totalAffectedRows + affectedRows
-	affectedRows, err = s.deleteTimerItem(user.UserID, itemID)
+	s.logger.Debugf("[insertItem] %+v", item)
+
+	_, err = q.ExecContext(
+		ctx,
+		`INSERT INTO AgendaItems (
+		  id,
+		  meeting_id,
+		  item_type,
+		  item_order,
+		  meeting_role,
+		  details
+		) VALUES (?, ?, ?, ?, ?, ?)`,
+		row.ID,
+		row.MeetingID,
+		row.ItemType,
+		row.ItemOrder,
+		row.MeetingRole,
+		row.Details,
+	)
 	if err != nil {
-		s.logger.Error("error deleting timerItem ", err)
+		s.logger.Error("[insertItem] Error", err)
 		return err
 	}
-	totalAffectedRows = totalAffectedRows + affectedRows
-
-	s.logger.Info("DeleteItems affectedRows rows: ", totalAffectedRows)
-
 	return nil
 }
 
-func (s lowerThirdsService) GetItem(ctx context.Context, itemID uuid.UUID) (entities.Item, error) {
-	socialID := ctx.Value(helpers.SocialIDKey).(string)
-	s.logger.Debug("GetItem for socialID ", socialID, " itemID ", itemID)
-	user, err := s.GetUserBySocialID(ctx, socialID)
+func (s lowerThirdsService) DeleteItem(ctx context.Context, itemID uuid.UUID) error {
+	s.logger.Debug("DeleteItems for itemID ", itemID)
+	user, err := s.authorizeItem(ctx, itemID, entities.RoleEditor)
 	if err != nil {
-		s.logger.Error("User not found by socialID", err)
-		return nil, err
+		return err
 	}
-	s.logger.Debug("GetItem for userID ", user.UserID, " itemID ", itemID)
+	s.logger.Debug("DeleteItems for userID ", user.UserID, " itemID ", itemID)
 
-	// Query each type of item separately
-	blankItem, err := s.getBlankItemByID(user.UserID, itemID)
+	meetingID, err := s.itemMeetingID(ctx, itemID)
 	if err != nil {
-		s.logger.Error("error querying blankItem ", err)
-		return nil, err
+		return err
 	}
-	if blankItem != nil {
-		if blankItem.ItemType != "blank" {
-			return nil, errors.New("invalid item type")
-		}
-		return blankItem, nil
+	orgID, err := s.meetingOrgID(ctx, meetingID)
+	if err != nil {
+		return err
+	}
+
+	tx, err := s.MySqlDB.BeginTxx(ctx, nil)
+	if err != nil {
+		s.logger.Error("DeleteItem Begin Error", err)
+		return err
 	}
-	messageItem, err := s.getMessageItemByID(user.UserID, itemID)
+	defer tx.Rollback()
+
+	before, err := s.lockItem(ctx, tx, itemID)
 	if err != nil {
-		s.logger.Error("error querying messageItem ", err)
-		return nil, err
+		return err
 	}
-	if messageItem != nil {
-		if messageItem.ItemType != "message" {
-			return nil, errors.New("invalid item type")
-		}
-		return messageItem, nil
+	result, err := tx.ExecContext(
+		ctx,
+		`UPDATE AgendaItems SET deleted_dt = CURRENT_TIMESTAMP WHERE id = ? AND deleted_dt IS NULL`,
+		itemID,
+	)
+	if err != nil {
+		s.logger.Error("error deleting item ", err)
+		return err
 	}
-	speakerItem, err := s.getSpeakerItemByID(user.UserID, itemID)
+	affectedRows, _ := result.RowsAffected()
+	s.logger.Info("DeleteItems affectedRows rows: ", affectedRows)
+	err = s.audit(ctx, tx, user, orgID, entities.AuditItem, itemID, before, nil)
 	if err != nil {
-		s.logger.Error("error querying speakerItem ", err)
-		return nil, err
+		return err
 	}
-	if speakerItem != nil {
-		if speakerItem.ItemType != "speaker" {
-			return nil, errors.New("invalid item type")
-		}
-		return speakerItem, nil
+
+	err = tx.Commit()
+	if err != nil {
+		s.logger.Error("DeleteItem Commit Error", err)
+		return err
 	}
-	lyricsItem, err := s.getLyricsItemByID(user.UserID, itemID)
+
+	s.publish(meetingID, events.ItemDeleted, map[string]uuid.UUID{"id": itemID})
+	return nil
+}
+
+func (s lowerThirdsService) GetItem(ctx context.Context, itemID uuid.UUID) (entities.Item, error) {
+	user, err := s.currentUser(ctx)
 	if err != nil {
-		s.logger.Error("error querying lyricsItem ", err)
 		return nil, err
 	}
-	if lyricsItem != nil {
-		if lyricsItem.ItemType != "lyrics" {
-			return nil, errors.New("invalid item type")
-		}
-		return lyricsItem, nil
+	s.logger.Debug("GetItem for userID ", user.UserID, " itemID ", itemID)
+
+	var row agendaItemRow
+	err = s.MySqlDB.GetContext(
+		ctx,
+		&row,
+		`SELECT `+agendaItemColumns+`
+        FROM OrgUsers ou
+        INNER JOIN Users u
+          ON u.id = ou.user_id
+          AND u.deleted_dt IS NULL
+        INNER JOIN Organization o
+          ON o.id = ou.org_id
+          AND o.deleted_dt IS NULL
+        INNER JOIN Meetings m
+          ON m.org_id = ou.org_id
+          AND m.deleted_dt IS NULL
+        INNER JOIN AgendaItems i
+          ON i.meeting_id = m.id
+          AND i.id = ?
+          AND i.deleted_dt IS NULL
+        WHERE ou.user_id = ?
+          AND ou.deleted_dt IS NULL`,
+		itemID,
+		user.UserID,
+	)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, apierrors.New(http.StatusNotFound, "ITEM_NOT_FOUND", "Item not found",
+			"no item %s in your organizations", itemID)
 	}
-	timerItem, err := s.getTimerItemByID(user.UserID, itemID)
 	if err != nil {
-		s.logger.Error("error querying timerItem ", err)
+		s.logger.Error("error querying item ", err)
 		return nil, err
 	}
-	if timerItem != nil {
-		if timerItem.ItemType != "timer" {
-			return nil, errors.New("invalid item type")
-		}
-		return timerItem, nil
-	}
-	return nil, errors.New("item not found")
+	return row.item()
 }
 
-func (s lowerThirdsService) getAllItemsByUser(userID uuid.UUID) ([]entities.Item, error) {
+// getAllItemsByUser lists a page of the items in the user's meetings, or in the OrgID query parameter's org when it's
+// set, in meeting date then agenda order. DateFrom and DateTo filter on the meeting date.
+func (s lowerThirdsService) getAllItemsByUser(ctx context.Context, userID uuid.UUID) ([]entities.Item, int, error) {
 	s.logger.Debug("getAllItemsByUser for userID ", userID)
 
-	query := `
-		WITH user_meetings AS (
-			SELECT m.id as meeting_id
-			FROM OrgUsers ou
-			INNER JOIN Users u ON u.id = ou.user_id AND u.deleted_dt IS NULL
-			INNER JOIN Organization o ON o.id = ou.org_id AND o.deleted_dt IS NULL
-			INNER JOIN Meetings m ON m.org_id = ou.org_id AND m.deleted_dt IS NULL
-			WHERE ou.user_id = ? AND ou.deleted_dt IS NULL
-		)
-		SELECT 
-			id, meeting_id, meeting_role, item_type, item_order,
-			NULL as primary_text, NULL as secondary_text,
-			NULL as speaker_name, NULL as title, NULL as expected_duration,
-			NULL as hymn_id, NULL as show_translation,
-			NULL as show_meeting_details,
-			'blank' as source_table
-		FROM BlankItems
-		WHERE meeting_id IN (SELECT meeting_id FROM user_meetings)
-		AND deleted_dt IS NULL
-		UNION ALL
-		SELECT 
-			id, meeting_id, meeting_role, item_type, item_order,
-			primary_text, secondary_text,
-			NULL as speaker_name, NULL as title, NULL as expected_duration,
-			NULL as hymn_id, NULL as show_translation,
-			NULL as show_meeting_details,
-			'message' as source_table
-		FROM MessageItems
-		WHERE meeting_id IN (SELECT meeting_id FROM user_meetings)
-		AND deleted_dt IS NULL
-		UNION ALL
-		SELECT 
-			id, meeting_id, meeting_role, item_type, item_order,
-			NULL as primary_text, NULL as secondary_text,
-			speaker_name, title, expected_duration,
-			NULL as hymn_id, NULL as show_translation,
-			NULL as show_meeting_details,
-			'speaker' as source_table
-		FROM SpeakerItems
-		WHERE meeting_id IN (SELECT meeting_id FROM user_meetings)
-		AND deleted_dt IS NULL
-		UNION ALL
-		SELECT 
-			id, meeting_id, meeting_role, item_type, item_order,
-			NULL as primary_text, NULL as secondary_text,
-			NULL as speaker_name, NULL as title, NULL as expected_duration,
-			hymn_id, show_translation,
-			NULL as show_meeting_details,
-			'lyrics' as source_table
-		FROM LyricsItems
-		WHERE meeting_id IN (SELECT meeting_id FROM user_meetings)
-		AND deleted_dt IS NULL
-		UNION ALL
-		SELECT 
-			id, meeting_id, meeting_role, item_type, item_order,
-			NULL as primary_text, NULL as secondary_text,
-			NULL as speaker_name, NULL as title, NULL as expected_duration,
-			NULL as hymn_id, NULL as show_translation,
-			show_meeting_details,
-			'timer' as source_table
-		FROM TimerItems
-		WHERE meeting_id IN (SELECT meeting_id FROM user_meetings)
-		AND deleted_dt IS NULL
-		ORDER BY item_order`
-
-	rows, err := s.MySqlDB.Query(query, userID)
-	if err != nil {
-		s.logger.Error("Error querying all items: ", err)
-		return nil, err
-	}
-	defer rows.Close()
-
-	var items []entities.Item
-	for rows.Next() {
-		var (
-			id                 uuid.UUID
-			meetingID          uuid.UUID
-			meetingRole        string
-			itemType           string
-			itemOrder          int
-			primaryText        sql.NullString
-			secondaryText      sql.NullString
-			speakerName        sql.NullString
-			title              sql.NullString
-			expectedDuration   sql.NullInt32
-			hymnID             sql.NullString
-			showTranslation    sql.NullBool
-			showMeetingDetails sql.NullBool
-			sourceTable        string
-		)
-
-		err := rows.Scan(
-			&id, &meetingID, &meetingRole, &itemType, &itemOrder,
-			&primaryText, &secondaryText,
-			&speakerName, &title, &expectedDuration,
-			&hymnID, &showTranslation,
-			&showMeetingDetails,
-			&sourceTable,
-		)
-		if err != nil {
-			s.logger.Error("Error scanning row: ", err)
-			return nil, err
-		}
-
-		switch sourceTable {
-		case "blank":
-			items = append(items, &entities.BlankItem{
-				BlankItemID: id,
-				MeetingID:   meetingID,
-				ItemType:    itemType,
-				ItemOrder:   itemOrder,
-				MeetingRole: meetingRole,
-			})
-		case "message":
-			items = append(items, &entities.MessageItem{
-				MessageItemID: id,
-				MeetingID:     meetingID,
-				ItemType:      itemType,
-				ItemOrder:     itemOrder,
-				MeetingRole:   meetingRole,
-				PrimaryText:   primaryText.String,
-				SecondaryText: null.StringFromPtr(&secondaryText.String),
-			})
-		case "speaker":
-			var expectedDurationPtr *int64
-			if expectedDuration.Valid {
-				duration := int64(expectedDuration.Int32)
-				expectedDurationPtr = &duration
-			}
-			items = append(items, &entities.SpeakerItem{
-				SpeakerItemID:    id,
-				MeetingID:        meetingID,
-				ItemType:         itemType,
-				ItemOrder:        itemOrder,
-				MeetingRole:      meetingRole,
-				SpeakerName:      speakerName.String,
-				Title:            null.StringFromPtr(&title.String),
-				ExpectedDuration: null.IntFromPtr(expectedDurationPtr),
-			})
-		case "lyrics":
-			items = append(items, &entities.LyricsItem{
-				LyricsItemID:    id,
-				MeetingID:       meetingID,
-				ItemType:        itemType,
-				ItemOrder:       itemOrder,
-				MeetingRole:     meetingRole,
-				HymnID:          hymnID.String,
-				ShowTranslation: showTranslation.Bool,
-			})
-		case "timer":
-			items = append(items, &entities.TimerItem{
-				TimerItemID:        id,
-				MeetingID:          meetingID,
-				ItemType:           itemType,
-				ItemOrder:          itemOrder,
-				MeetingRole:        meetingRole,
-				ShowMeetingDetails: showMeetingDetails.Bool,
-			})
-		}
+	query := `SELECT ` + agendaItemColumns + `
+        FROM OrgUsers ou
+        INNER JOIN Users u
+          ON u.id = ou.user_id
+          AND u.deleted_dt IS NULL
+        INNER JOIN Organization o
+          ON o.id = ou.org_id
+          AND o.deleted_dt IS NULL
+        INNER JOIN Meetings m
+          ON m.org_id = ou.org_id
+          AND m.deleted_dt IS NULL
+        INNER JOIN AgendaItems i
+          ON i.meeting_id = m.id
+          AND i.deleted_dt IS NULL
+        WHERE ou.user_id = ?
+          AND ou.deleted_dt IS NULL`
+	args := []interface{}{userID}
+	if orgID := helpers.GetQueryParams(ctx).OrgID; orgID != uuid.Nil {
+		query += ` AND ou.org_id = ?`
+		args = append(args, orgID)
+	}
+
+	var rows []agendaItemRow
+	total, err := s.selectPage(ctx, &rows, query, "m.meeting_date", "m.meeting_date, i.meeting_id, i.item_order, i.id", args...)
+	if err != nil {
+		return nil, 0, err
 	}
-
-	if err = rows.Err(); err != nil {
-		s.logger.Error("Error iterating rows: ", err)
-		return nil, err
+	items, err := agendaItems(rows)
+	if err != nil {
+		return nil, 0, err
 	}
-
-	return items, nil
+	return items, total, nil
 }
 
-func (s lowerThirdsService) GetItems(ctx context.Context) (*[]entities.Item, error) {
+func (s lowerThirdsService) GetItems(ctx context.Context) (*[]entities.Item, int, error) {
 	if ctx == nil {
-		return nil, errors.New("context is required")
+		return nil, 0, errors.New("context is required")
 	}
 
-	socialID, ok := ctx.Value(helpers.SocialIDKey).(string)
-	if !ok {
-		return nil, errors.New("socialID is required in context")
-	}
-	s.logger.Debug("GetItems for socialID ", socialID)
-	user, err := s.GetUserBySocialID(ctx, socialID)
+	user, err := s.currentUser(ctx)
 	if err != nil {
-		s.logger.Error("User not found by socialID", err)
-		return nil, err
+		return nil, 0, err
 	}
 	s.logger.Debug("GetItems for userID ", user.UserID)
 
-	items, err := s.getAllItemsByUser(user.UserID)
+	items, total, err := s.getAllItemsByUser(ctx, user.UserID)
 	if err != nil {
 		s.logger.Error(err)
-		return nil, err
+		return nil, 0, err
 	}
 
-	return &items, nil
+	return &items, total, nil
 }
 
 func (s lowerThirdsService) GetItemsByMeeting(ctx context.Context, meetingID uuid.UUID) (*[]entities.Item, error) {
@@ -406,130 +259,318 @@ func (s lowerThirdsService) GetItemsByMeeting(ctx context.Context, meetingID uui
 		return nil, errors.New("context is required")
 	}
 
-	socialID, ok := ctx.Value(helpers.SocialIDKey).(string)
-	if !ok {
-		return nil, errors.New("socialID is required in context")
-	}
-	s.logger.Debug("GetItem for socialID ", socialID, " meetingID ", meetingID)
-	user, err := s.GetUserBySocialID(ctx, socialID)
+	user, err := s.currentUser(ctx)
 	if err != nil {
-		s.logger.Error("User not found by socialID", err)
 		return nil, err
 	}
 	s.logger.Debug("GetItem for userID ", user.UserID, " meetingID ", meetingID)
 
-	// Query each type of item separately
-	blankItems, err := s.getBlankItemsByMeeting(user.UserID, meetingID)
+	var rows []agendaItemRow
+	err = s.MySqlDB.SelectContext(
+		ctx,
+		&rows,
+		`SELECT `+agendaItemColumns+`
+        FROM OrgUsers ou
+        INNER JOIN Users u
+          ON u.id = ou.user_id
+          AND u.deleted_dt IS NULL
+        INNER JOIN Organization o
+          ON o.id = ou.org_id
+          AND o.deleted_dt IS NULL
+        INNER JOIN Meetings m
+          ON m.org_id = ou.org_id
+          AND m.id = ?
+          AND m.deleted_dt IS NULL
+        INNER JOIN AgendaItems i
+          ON i.meeting_id = m.id
+          AND i.deleted_dt IS NULL
+        WHERE ou.user_id = ?
+          AND ou.deleted_dt IS NULL
+        ORDER BY i.item_order`,
+		meetingID,
+		user.UserID,
+	)
 	if err != nil {
 		s.logger.Error(err)
 		return nil, err
 	}
-	messageItems, err := s.getMessageItemsByMeeting(user.UserID, meetingID)
+
+	allItems, err := agendaItems(rows)
 	if err != nil {
 		s.logger.Error(err)
 		return nil, err
 	}
-	speakerItems, err := s.getSpeakerItemsByMeeting(user.UserID, meetingID)
+	return &allItems, nil
+}
+
+// GetItemsByMeetings loads the items of several meetings in one query, in agenda order and keyed by meeting. Meetings
+// the user can't see, and meetings without items, have no entry.
+func (s lowerThirdsService) GetItemsByMeetings(ctx context.Context, meetingIDs []uuid.UUID) (map[uuid.UUID][]entities.Item, error) {
+	user, err := s.currentUser(ctx)
 	if err != nil {
-		s.logger.Error(err)
 		return nil, err
 	}
-	lyricsItems, err := s.getLyricsItemsByMeeting(user.UserID, meetingID)
+	s.logger.Debug("GetItemsByMeetings for userID ", user.UserID, " meetingIDs ", meetingIDs)
+
+	byMeeting := make(map[uuid.UUID][]entities.Item, len(meetingIDs))
+	if len(meetingIDs) == 0 {
+		return byMeeting, nil
+	}
+
+	query, args, err := sqlx.In(
+		`SELECT `+agendaItemColumns+`
+        FROM OrgUsers ou
+        INNER JOIN Users u
+          ON u.id = ou.user_id
+          AND u.deleted_dt IS NULL
+        INNER JOIN Organization o
+          ON o.id = ou.org_id
+          AND o.deleted_dt IS NULL
+        INNER JOIN Meetings m
+          ON m.org_id = ou.org_id
+          AND m.id IN (?)
+          AND m.deleted_dt IS NULL
+        INNER JOIN AgendaItems i
+          ON i.meeting_id = m.id
+          AND i.deleted_dt IS NULL
+        WHERE ou.user_id = ?
+          AND ou.deleted_dt IS NULL
+        ORDER BY i.meeting_id, i.item_order`,
+		meetingIDs,
+		user.UserID,
+	)
 	if err != nil {
-		s.logger.Error(err)
 		return nil, err
 	}
-	timerItems, err := s.getTimerItemsByMeeting(user.UserID, meetingID)
+
+	var rows []agendaItemRow
+	err = s.MySqlDB.SelectContext(ctx, &rows, s.MySqlDB.Rebind(query), args...)
+	if err != nil {
+		s.logger.Error("GetItemsByMeetings Error", err)
+		return nil, err
+	}
+	items, err := agendaItems(rows)
 	if err != nil {
 		s.logger.Error(err)
 		return nil, err
 	}
+	for _, item := range items {
+		byMeeting[item.GetMeetingID()] = append(byMeeting[item.GetMeetingID()], item)
+	}
+	return byMeeting, nil
+}
+
+// ReorderItems puts a meeting's items in the order of itemIDs, which has to list every item in the meeting once.
+// The items are numbered from 1 in one transaction, and the normalized agenda is returned.
+func (s lowerThirdsService) ReorderItems(ctx context.Context, meetingID uuid.UUID, itemIDs []uuid.UUID) (*[]entities.Item, error) {
+	s.logger.Debug("ReorderItems for meetingID ", meetingID)
 
-	// Combine in an Item interface set
-	var allItems []entities.Item
-	for _, b := range blankItems {
-		allItems = append(allItems, &b)
+	user, err := s.authorizeMeeting(ctx, meetingID, entities.RoleEditor)
+	if err != nil {
+		return nil, err
 	}
-	for _, m := range messageItems {
-		allItems = append(allItems, &m)
+
+	tx, err := s.MySqlDB.BeginTxx(ctx, nil)
+	if err != nil {
+		s.logger.Error("ReorderItems Begin Error", err)
+		return nil, err
 	}
-	for _, s := range speakerItems {
-		allItems = append(allItems, &s)
+	defer tx.Rollback()
+
+	// Lock the meeting and its items so none are added, removed or moved while they're checked and renumbered
+	meeting, err := s.lockMeeting(ctx, tx, meetingID)
+	if err != nil {
+		return nil, err
 	}
-	for _, l := range lyricsItems {
-		allItems = append(allItems, &l)
+	var current []struct {
+		ID    uuid.UUID `db:"id"`
+		Order int       `db:"item_order"`
 	}
-	for _, t := range timerItems {
-		allItems = append(allItems, &t)
+	err = tx.SelectContext(
+		ctx,
+		&current,
+		`SELECT id, item_order FROM AgendaItems WHERE meeting_id = ? AND deleted_dt IS NULL`+s.dialect.forUpdate(),
+		meetingID,
+	)
+	if err != nil {
+		s.logger.Error("ReorderItems Select Error", err)
+		return nil, err
 	}
 
-	// Sort items by GetOrder
-	sort.Slice(allItems, func(i, j int) bool {
-		return allItems[i].GetOrder() < allItems[j].GetOrder()
-	})
-
-	return &allItems, nil
-}
-
-func (s lowerThirdsService) UpdateItem(ctx context.Context, itemID uuid.UUID, item entities.Item) error {
-	socialID := ctx.Value(helpers.SocialIDKey).(string)
-	s.logger.Debug("UpdateItems for socialID ", socialID, " itemID ", itemID)
-
-	// TODO: somehow check that the ID being used matches up with an existing record of the same type.
-	// Theoretically we could get duplicate ID numbers in different tables
-
-	// Each type of item handled separately
-	switch v := item.(type) {
-	case *entities.BlankItem:
-		if v.BlankItemID == uuid.Nil {
-			v.BlankItemID = itemID
-		}
-		err := s.updateBlankItem(itemID, v)
-		if err != nil {
-			s.logger.Error("error updating blankItem ", err)
-			return err
-		}
-		return nil
-	case *entities.LyricsItem:
-		if v.LyricsItemID == uuid.Nil {
-			v.LyricsItemID = itemID
+	byID := make(map[uuid.UUID]int, len(current))
+	for _, item := range current {
+		byID[item.ID] = item.Order
+	}
+	seen := make(map[uuid.UUID]bool, len(itemIDs))
+	for _, itemID := range itemIDs {
+		if _, ok := byID[itemID]; !ok {
+			return nil, invalidOrder("item %s isn't in meeting %s", itemID, meetingID)
 		}
-		err := s.updateLyricsItem(itemID, v)
-		if err != nil {
-			s.logger.Error("error updating lyricsItem ", err)
-			return err
+		if seen[itemID] {
+			return nil, invalidOrder("item %s is listed more than once", itemID)
 		}
-		return nil
-	case *entities.MessageItem:
-		if v.MessageItemID == uuid.Nil {
-			v.MessageItemID = itemID
+		seen[itemID] = true
+	}
+	if len(itemIDs) != len(byID) {
+		return nil, invalidOrder("all %d items in the meeting must be listed, got %d", len(byID), len(itemIDs))
+	}
+
+	for i, itemID := range itemIDs {
+		// Items already in place are left alone, so every update below must change a row
+		if byID[itemID] == i+1 {
+			continue
 		}
-		err := s.updateMessageItem(itemID, v)
+		result, err := tx.ExecContext(
+			ctx,
+			`UPDATE AgendaItems SET item_order = ? WHERE id = ? AND meeting_id = ? AND deleted_dt IS NULL`,
+			i+1,
+			itemID,
+			meetingID,
+		)
 		if err != nil {
-			s.logger.Error("error updating messageItem ", err)
-			return err
-		}
-		return nil
-	case *entities.SpeakerItem:
-		if v.SpeakerItemID == uuid.Nil {
-			v.SpeakerItemID = itemID
+			s.logger.Error("ReorderItems Error", err)
+			return nil, err
 		}
-		err := s.updateSpeakerItem(itemID, v)
+		affectedRows, err := result.RowsAffected()
 		if err != nil {
-			s.logger.Error("error updating speakerItem ", err)
-			return err
+			s.logger.Error("ReorderItems error getting affected rows", err)
+			return nil, err
 		}
-		return nil
-	case *entities.TimerItem:
-		if v.TimerItemID == uuid.Nil {
-			v.TimerItemID = itemID
+		if affectedRows == 0 {
+			return nil, invalidOrder("item %s isn't in meeting %s", itemID, meetingID)
 		}
-		err := s.updateTimerItem(itemID, v)
+		err = s.audit(ctx, tx, user, meeting.OrgID, entities.AuditItem, itemID,
+			map[string]int{"order": byID[itemID]}, map[string]int{"order": i + 1})
 		if err != nil {
-			s.logger.Error("error updating timerItem ", err)
-			return err
+			return nil, err
 		}
-		return nil
 	}
-	return errors.New("unsupported item type")
+
+	err = tx.Commit()
+	if err != nil {
+		s.logger.Error("ReorderItems Commit Error", err)
+		return nil, err
+	}
+
+	items, err := s.GetItemsByMeeting(ctx, meetingID)
+	if err != nil {
+		return nil, err
+	}
+	s.publish(meetingID, events.ItemsReordered, items)
+	return items, nil
+}
+
+func (s lowerThirdsService) UpdateItem(ctx context.Context, itemID uuid.UUID, item entities.Item) error {
+	s.logger.Debug("UpdateItems for itemID ", itemID)
+
+	_, err := s.authorizeItem(ctx, itemID, entities.RoleEditor)
+	if err != nil {
+		return err
+	}
+	previousMeetingID, err := s.itemMeetingID(ctx, itemID)
+	if err != nil {
+		return err
+	}
+
+	// Moving an item to another meeting also requires access to that meeting
+	user, err := s.authorizeMeeting(ctx, item.GetMeetingID(), entities.RoleEditor)
+	if err != nil {
+		return err
+	}
+	orgID, err := s.meetingOrgID(ctx, item.GetMeetingID())
+	if err != nil {
+		return err
+	}
+
+	// The item keeps its ID, but may change type
+	err = entities.SetItemID(item, itemID)
+	if err != nil {
+		return err
+	}
+	row, err := newAgendaItemRow(item)
+	if err != nil {
+		s.logger.Error("error updating item ", err)
+		return err
+	}
+
+	tx, err := s.MySqlDB.BeginTxx(ctx, nil)
+	if err != nil {
+		s.logger.Error("UpdateItem Begin Error", err)
+		return err
+	}
+	defer tx.Rollback()
+
+	before, err := s.lockItem(ctx, tx, itemID)
+	if err != nil {
+		return err
+	}
+	result, err := tx.ExecContext(
+		ctx,
+		`UPDATE AgendaItems SET
+		  meeting_id = ?,
+		  item_type = ?,
+		  item_order = ?,
+		  meeting_role = ?,
+		  details = ?
+        WHERE id = ?
+          AND deleted_dt IS NULL`,
+		row.MeetingID,
+		row.ItemType,
+		row.ItemOrder,
+		row.MeetingRole,
+		row.Details,
+		itemID,
+	)
+	if err != nil {
+		s.logger.Error("error updating item ", err)
+		return err
+	}
+	affectedRows, err := result.RowsAffected()
+	if err != nil {
+		s.logger.Error("error getting affected rows ", err)
+		return err
+	}
+	s.logger.Info("UpdateItem affected rows: ", affectedRows)
+	after, err := s.lockItem(ctx, tx, itemID)
+	if err != nil {
+		return err
+	}
+	err = s.audit(ctx, tx, user, orgID, entities.AuditItem, itemID, before, after)
+	if err != nil {
+		return err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		s.logger.Error("UpdateItem Commit Error", err)
+		return err
+	}
+
+	// Items moved to another meeting disappear from the old one
+	if previousMeetingID != item.GetMeetingID() {
+		s.publish(previousMeetingID, events.ItemDeleted, map[string]uuid.UUID{"id": itemID})
+	}
+	s.publish(item.GetMeetingID(), events.ItemUpdated, item)
+	return nil
+}
+
+// lockItem loads an item in a transaction, locking it until the transaction ends
+func (s lowerThirdsService) lockItem(ctx context.Context, tx *sqlx.Tx, itemID uuid.UUID) (entities.Item, error) {
+	var row agendaItemRow
+	err := tx.GetContext(
+		ctx,
+		&row,
+		`SELECT `+agendaItemColumns+` FROM AgendaItems i WHERE i.id = ? AND i.deleted_dt IS NULL`+s.dialect.forUpdate(),
+		itemID,
+	)
+	if err != nil {
+		s.logger.Error("lockItem Error", err)
+		return nil, err
+	}
+	return row.item()
+}
+
+// invalidOrder creates the API error returned for an item order that doesn't match the meeting's items
+func invalidOrder(detail string, args ...interface{}) *apierrors.Error {
+	return apierrors.New(http.StatusBadRequest, "INVALID_ORDER", "Invalid order", detail, args...)
 }
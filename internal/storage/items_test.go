@@ -2,9 +2,13 @@ package storage
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"lowerthirdsapi/internal/apierrors"
 	"lowerthirdsapi/internal/entities"
+	"lowerthirdsapi/internal/events"
 	"lowerthirdsapi/internal/testutil"
+	"net/http"
 	"strings"
 	"testing"
 	"time"
@@ -110,8 +114,9 @@ func TestDeleteItem(t *testing.T) {
 	if err == nil {
 		t.Fatalf("Expected error when getting deleted item, got nil")
 	}
-	if err.Error() != "item not found" {
-		t.Fatalf("Expected 'item not found' error, got: %v", err)
+	var apiErr *apierrors.Error
+	if !errors.As(err, &apiErr) || apiErr.Status != http.StatusNotFound {
+		t.Fatalf("Expected not found, got: %v", err)
 	}
 }
 
@@ -227,11 +232,7 @@ func TestGetItems(t *testing.T) {
 
 	// Clean up all relevant tables
 	cleanupStmts := []string{
-		"DELETE FROM TimerItems",
-		"DELETE FROM SpeakerItems",
-		"DELETE FROM MessageItems",
-		"DELETE FROM LyricsItems",
-		"DELETE FROM BlankItems",
+		"DELETE FROM AgendaItems",
 		"DELETE FROM Meetings",
 		"DELETE FROM OrgUsers",
 		"DELETE FROM Organization",
@@ -276,7 +277,7 @@ func TestGetItems(t *testing.T) {
 	}
 
 	// Get all items
-	items, err := service.GetItems(testutil.TestCtx)
+	items, _, err := service.GetItems(testutil.TestCtx)
 	if err != nil {
 		t.Fatalf("Failed to get items: %v", err)
 	}
@@ -344,6 +345,61 @@ func TestGetItems(t *testing.T) {
 	}
 }
 
+func TestGetItemsByMeetings(t *testing.T) {
+	testutil.SetupTest(t)
+	defer testutil.TeardownTest()
+	service := New(testutil.TestDB, testutil.TestLogger)
+	_, org, meeting := testutil.CreateTestData(t, service)
+
+	other := &entities.Meeting{
+		MeetingID:   uuid.New(),
+		OrgID:       org.OrgID,
+		Meeting:     "Test Meeting",
+		MeetingDate: time.Now(),
+	}
+	if err := service.CreateMeeting(testutil.TestCtx, other); err != nil {
+		t.Fatalf("CreateMeeting failed: %v", err)
+	}
+	empty := &entities.Meeting{
+		MeetingID:   uuid.New(),
+		OrgID:       org.OrgID,
+		Meeting:     "Test Meeting",
+		MeetingDate: time.Now(),
+	}
+	if err := service.CreateMeeting(testutil.TestCtx, empty); err != nil {
+		t.Fatalf("CreateMeeting failed: %v", err)
+	}
+
+	items := []entities.Item{
+		&entities.BlankItem{BlankItemID: uuid.New(), MeetingID: meeting.MeetingID, ItemType: "blank", ItemOrder: 2, MeetingRole: "Test Role"},
+		&entities.MessageItem{MessageItemID: uuid.New(), MeetingID: meeting.MeetingID, ItemType: "message", ItemOrder: 1, MeetingRole: "Test Role"},
+		&entities.BlankItem{BlankItemID: uuid.New(), MeetingID: other.MeetingID, ItemType: "blank", ItemOrder: 1, MeetingRole: "Test Role"},
+	}
+	for _, item := range items {
+		if err := service.CreateItem(testutil.TestCtx, item); err != nil {
+			t.Fatalf("CreateItem failed: %v", err)
+		}
+	}
+
+	byMeeting, err := service.GetItemsByMeetings(testutil.TestCtx, []uuid.UUID{meeting.MeetingID, other.MeetingID, empty.MeetingID, uuid.New()})
+	if err != nil {
+		t.Fatalf("GetItemsByMeetings failed: %v", err)
+	}
+	first := byMeeting[meeting.MeetingID]
+	if len(first) != 2 || first[0].GetID() != items[1].GetID() || first[1].GetID() != items[0].GetID() {
+		t.Errorf("Expected the first meeting's 2 items in order, got %+v", first)
+	}
+	if len(byMeeting[other.MeetingID]) != 1 || len(byMeeting) != 2 {
+		t.Errorf("Expected items for just the meetings that have them, got %+v", byMeeting)
+	}
+
+	// Nothing to load is no query at all
+	byMeeting, err = service.GetItemsByMeetings(testutil.TestCtx, nil)
+	if err != nil || len(byMeeting) != 0 {
+		t.Errorf("Expected no items, got %+v, %v", byMeeting, err)
+	}
+}
+
 func TestCreateItemErrors(t *testing.T) {
 	testutil.SetupTest(t)
 	defer testutil.TeardownTest()
@@ -410,37 +466,37 @@ func TestGetItemErrors(t *testing.T) {
 			name:          "Wrong user",
 			ctx:           context.WithValue(ctx, helpers.SocialIDKey, "wrong-social-id"),
 			itemID:        itemID,
-			expectedError: "item not found",
+			expectedError: "ITEM_NOT_FOUND",
 		},
 		{
 			name:          "Wrong organization",
 			ctx:           context.WithValue(ctx, helpers.SocialIDKey, "test-social-id"),
 			itemID:        itemID,
-			expectedError: "item not found",
+			expectedError: "ITEM_NOT_FOUND",
 		},
 		{
 			name:          "Deleted item",
 			ctx:           context.WithValue(ctx, helpers.SocialIDKey, "test-social-id"),
 			itemID:        itemID,
-			expectedError: "item not found",
+			expectedError: "ITEM_NOT_FOUND",
 		},
 		{
 			name:          "Deleted meeting",
 			ctx:           context.WithValue(ctx, helpers.SocialIDKey, "test-social-id"),
 			itemID:        itemID,
-			expectedError: "item not found",
+			expectedError: "ITEM_NOT_FOUND",
 		},
 		{
 			name:          "Deleted organization",
 			ctx:           context.WithValue(ctx, helpers.SocialIDKey, "test-social-id"),
 			itemID:        itemID,
-			expectedError: "item not found",
+			expectedError: "ITEM_NOT_FOUND",
 		},
 		{
 			name:          "Deleted user",
 			ctx:           context.WithValue(ctx, helpers.SocialIDKey, "test-social-id"),
 			itemID:        itemID,
-			expectedError: "item not found",
+			expectedError: "ITEM_NOT_FOUND",
 		},
 	}
 
@@ -451,7 +507,7 @@ func TestGetItemErrors(t *testing.T) {
 			switch tt.name {
 			case "Invalid item type":
 				// Update the item to have an invalid type
-				_, err := testutil.TestDB.Exec("UPDATE BlankItems SET item_type = 'invalid' WHERE id = ?", itemID)
+				_, err := testutil.TestDB.Exec("UPDATE AgendaItems SET item_type = 'invalid' WHERE id = ?", itemID)
 				if err != nil {
 					t.Fatalf("Failed to update item type: %v", err)
 				}
@@ -468,7 +524,7 @@ func TestGetItemErrors(t *testing.T) {
 				}
 			case "Deleted item":
 				// Delete the item
-				_, err := testutil.TestDB.Exec("UPDATE BlankItems SET deleted_dt = CURRENT_TIMESTAMP WHERE id = ?", itemID)
+				_, err := testutil.TestDB.Exec("UPDATE AgendaItems SET deleted_dt = CURRENT_TIMESTAMP WHERE id = ?", itemID)
 				if err != nil {
 					t.Fatalf("Failed to delete item: %v", err)
 				}
@@ -860,6 +916,20 @@ func TestCreateItemWithDuplicateID(t *testing.T) {
 	if err == nil {
 		t.Fatalf("Expected error when creating item with duplicate ID, got nil")
 	}
+
+	// IDs are unique across item types too
+	speakerItem := &entities.SpeakerItem{
+		SpeakerItemID: itemID,
+		MeetingID:     meeting.MeetingID,
+		ItemType:      "speaker",
+		ItemOrder:     2,
+		MeetingRole:   "Test Role",
+		SpeakerName:   "Speaker",
+	}
+	err = service.CreateItem(testutil.TestCtx, speakerItem)
+	if err == nil {
+		t.Fatalf("Expected error when creating a speaker item with a blank item's ID, got nil")
+	}
 }
 
 func TestGetItemsByMeetingWithNoItems(t *testing.T) {
@@ -887,21 +957,21 @@ func TestGetItemsErrors(t *testing.T) {
 	service := New(testutil.TestDB, testutil.TestLogger)
 
 	// Test getting items with nil context
-	_, err := service.GetItems(nil)
+	_, _, err := service.GetItems(nil)
 	if err == nil {
 		t.Fatalf("Expected error when getting items with nil context")
 	}
 
 	// Test getting items with invalid context (missing socialID)
 	invalidCtx := context.Background()
-	_, err = service.GetItems(invalidCtx)
+	_, _, err = service.GetItems(invalidCtx)
 	if err == nil {
 		t.Fatalf("Expected error when getting items with invalid context")
 	}
 
 	// Test getting items with non-existent user
 	ctxWrongUser := context.WithValue(testutil.TestCtx, helpers.SocialIDKey, "non-existent-social-id")
-	_, err = service.GetItems(ctxWrongUser)
+	_, _, err = service.GetItems(ctxWrongUser)
 	if err == nil {
 		t.Fatalf("Expected error when getting items with non-existent user")
 	}
@@ -941,3 +1011,84 @@ func TestGetItemsByMeetingErrors(t *testing.T) {
 		t.Fatalf("Expected 'sql: no rows in result set' error, got: %v", err)
 	}
 }
+
+func TestReorderItems(t *testing.T) {
+	testutil.SetupTest(t)
+	defer testutil.TeardownTest()
+
+	broker := events.NewBroker(10)
+	service := New(testutil.TestDB, testutil.TestLogger, WithEvents(broker))
+	_, _, meeting := testutil.CreateTestData(t, service)
+
+	// Orders that collide, as happens after editing items one at a time
+	blank := &entities.BlankItem{BlankItemID: uuid.New(), MeetingID: meeting.MeetingID, ItemType: "blank", ItemOrder: 1, MeetingRole: "Test Role"}
+	speaker := &entities.SpeakerItem{SpeakerItemID: uuid.New(), MeetingID: meeting.MeetingID, ItemType: "speaker", ItemOrder: 1, MeetingRole: "Test Role", SpeakerName: "Speaker"}
+	timer := &entities.TimerItem{TimerItemID: uuid.New(), MeetingID: meeting.MeetingID, ItemType: "timer", ItemOrder: 7, MeetingRole: "Test Role"}
+	for _, item := range []entities.Item{blank, speaker, timer} {
+		if err := service.CreateItem(testutil.TestCtx, item); err != nil {
+			t.Fatalf("CreateItem failed: %v", err)
+		}
+	}
+
+	sub, err := service.SubscribeMeetingEvents(testutil.TestCtx, meeting.MeetingID, 0)
+	if err != nil {
+		t.Fatalf("SubscribeMeetingEvents failed: %v", err)
+	}
+	defer sub.Close()
+
+	order := []uuid.UUID{timer.TimerItemID, blank.BlankItemID, speaker.SpeakerItemID}
+	items, err := service.ReorderItems(testutil.TestCtx, meeting.MeetingID, order)
+	if err != nil {
+		t.Fatalf("ReorderItems failed: %v", err)
+	}
+	if len(*items) != 3 {
+		t.Fatalf("Expected 3 items, got %d", len(*items))
+	}
+	for i, item := range *items {
+		if item.GetID() != order[i] || item.GetOrder() != i+1 {
+			t.Errorf("Expected item %s at %d, got %s at %d", order[i], i+1, item.GetID(), item.GetOrder())
+		}
+	}
+
+	// Subscribers are told the agenda changed
+	select {
+	case event := <-sub.C:
+		if event.Type != events.ItemsReordered {
+			t.Errorf("Expected %s event, got %s", events.ItemsReordered, event.Type)
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("Timed out waiting for %s event", events.ItemsReordered)
+	}
+
+	for _, bad := range [][]uuid.UUID{
+		{timer.TimerItemID, blank.BlankItemID},
+		{timer.TimerItemID, blank.BlankItemID, blank.BlankItemID},
+		{timer.TimerItemID, blank.BlankItemID, uuid.New()},
+	} {
+		_, err = service.ReorderItems(testutil.TestCtx, meeting.MeetingID, bad)
+		var apiErr *apierrors.Error
+		if !errors.As(err, &apiErr) || apiErr.Code != "INVALID_ORDER" {
+			t.Errorf("Expected an invalid order error for %v, got %v", bad, err)
+		}
+	}
+
+	// A deleted item can't be ordered, and items already in place are fine
+	if err := service.DeleteItem(testutil.TestCtx, blank.BlankItemID); err != nil {
+		t.Fatalf("DeleteItem failed: %v", err)
+	}
+	_, err = service.ReorderItems(testutil.TestCtx, meeting.MeetingID, order)
+	var apiErr *apierrors.Error
+	if !errors.As(err, &apiErr) || apiErr.Code != "INVALID_ORDER" {
+		t.Errorf("Expected an invalid order error with a deleted item, got %v", err)
+	}
+	order = []uuid.UUID{timer.TimerItemID, speaker.SpeakerItemID}
+	items, err = service.ReorderItems(testutil.TestCtx, meeting.MeetingID, order)
+	if err != nil {
+		t.Fatalf("ReorderItems failed: %v", err)
+	}
+	for i, item := range *items {
+		if item.GetID() != order[i] || item.GetOrder() != i+1 {
+			t.Errorf("Expected item %s at %d, got %s at %d", order[i], i+1, item.GetID(), item.GetOrder())
+		}
+	}
+}
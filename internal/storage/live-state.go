@@ -0,0 +1,159 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"lowerthirdsapi/internal/apierrors"
+	"lowerthirdsapi/internal/entities"
+	"lowerthirdsapi/internal/events"
+	"net/http"
+	"time"
+)
+
+func (s lowerThirdsService) GetLiveState(ctx context.Context, meetingID uuid.UUID) (*entities.LiveState, error) {
+	s.logger.Debug("GetLiveState for meetingID ", meetingID)
+
+	_, err := s.authorizeMeeting(ctx, meetingID, entities.RoleViewer)
+	if err != nil {
+		return nil, err
+	}
+	return s.getLiveState(ctx, meetingID)
+}
+
+// getLiveState loads the meeting's live state. GetLiveState or an overlay token authorizes the caller first.
+func (s lowerThirdsService) getLiveState(ctx context.Context, meetingID uuid.UUID) (*entities.LiveState, error) {
+	var liveState entities.LiveState
+	err := s.MySqlDB.GetContext(ctx, &liveState, `SELECT * FROM LiveStates WHERE meeting_id = ?`, meetingID)
+	if errors.Is(err, sql.ErrNoRows) {
+		// Nothing has gone live yet
+		return &entities.LiveState{MeetingID: meetingID}, nil
+	}
+	if err != nil {
+		s.logger.Error("GetLiveState Error", err)
+		return nil, err
+	}
+	return &liveState, nil
+}
+
+// UpdateLiveState applies an operator action to the meeting's live state. itemID is only used by take and cue.
+func (s lowerThirdsService) UpdateLiveState(ctx context.Context, meetingID uuid.UUID, action entities.LiveAction, itemID uuid.UUID) (*entities.LiveState, error) {
+	s.logger.Debug("UpdateLiveState for meetingID ", meetingID, " action ", action, " itemID ", itemID)
+
+	user, err := s.authorizeMeeting(ctx, meetingID, entities.RoleOperator)
+	if err != nil {
+		return nil, err
+	}
+	orgID, err := s.meetingOrgID(ctx, meetingID)
+	if err != nil {
+		return nil, err
+	}
+
+	items, err := s.GetItemsByMeeting(ctx, meetingID)
+	if err != nil {
+		return nil, err
+	}
+	agenda := make([]uuid.UUID, 0, len(*items))
+	for _, item := range *items {
+		agenda = append(agenda, item.GetID())
+	}
+
+	tx, err := s.MySqlDB.BeginTxx(ctx, nil)
+	if err != nil {
+		s.logger.Error("UpdateLiveState Begin Error", err)
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	// Lock the row so concurrent operators apply their actions one at a time
+	liveState := entities.LiveState{MeetingID: meetingID}
+	err = tx.GetContext(ctx, &liveState, `SELECT * FROM LiveStates WHERE meeting_id = ?`+s.dialect.forUpdate(), meetingID)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		s.logger.Error("UpdateLiveState Select Error", err)
+		return nil, err
+	}
+
+	previous := liveState
+	err = liveState.Apply(action, itemID, agenda, time.Now().UTC())
+	if err != nil {
+		return nil, liveStateError(err)
+	}
+	if liveState.ProgramChanged(previous) && liveState.ProgramItemID.Valid {
+		liveState.VerseCount, err = s.verseCount(ctx, tx, liveState.ProgramItemID.UUID, *items)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	_, err = tx.NamedExecContext(
+		ctx,
+		`INSERT INTO LiveStates (
+		  meeting_id, version, program_item_id, preview_item_id, visible,
+		  verse_index, verse_count,
+		  program_changed_dt, preview_changed_dt, visible_changed_dt
+		) VALUES (
+		  :meeting_id, :version, :program_item_id, :preview_item_id, :visible,
+		  :verse_index, :verse_count,
+		  :program_changed_dt, :preview_changed_dt, :visible_changed_dt
+		) `+s.dialect.upsert("meeting_id",
+			"version", "program_item_id", "preview_item_id", "visible", "verse_index", "verse_count",
+			"program_changed_dt", "preview_changed_dt", "visible_changed_dt",
+		),
+		liveState,
+	)
+	if err != nil {
+		s.logger.Error("UpdateLiveState Error", err)
+		return nil, err
+	}
+	err = s.audit(ctx, tx, user, orgID, entities.AuditLiveState, meetingID, previous, liveState)
+	if err != nil {
+		return nil, err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		s.logger.Error("UpdateLiveState Commit Error", err)
+		return nil, err
+	}
+
+	s.publish(meetingID, events.LiveChanged, liveState)
+	return &liveState, nil
+}
+
+// verseCount is the number of slides a program item steps through with next_verse, using the request's slide
+// options, reading through q. Only lyrics items with a hymn have verses.
+func (s lowerThirdsService) verseCount(ctx context.Context, q sqlx.QueryerContext, itemID uuid.UUID, items []entities.Item) (int, error) {
+	for _, item := range items {
+		if item.GetID() != itemID {
+			continue
+		}
+		lyricsItem, ok := item.(*entities.LyricsItem)
+		if !ok {
+			return 0, nil
+		}
+		if _, err := uuid.Parse(lyricsItem.HymnID); err != nil {
+			return 0, nil
+		}
+		slides, err := s.lyricsSlides(ctx, q, lyricsItem)
+		if err != nil {
+			return 0, err
+		}
+		return len(*slides), nil
+	}
+	return 0, nil
+}
+
+// liveStateError converts an error from applying a live action into an API error
+func liveStateError(err error) error {
+	switch {
+	case errors.Is(err, entities.ErrUnknownLiveAction), errors.Is(err, entities.ErrNotInAgenda):
+		return apierrors.New(http.StatusBadRequest, "INVALID_LIVE_ACTION", "Invalid live action", err.Error())
+	case errors.Is(err, entities.ErrNothingCued), errors.Is(err, entities.ErrEndOfAgenda), errors.Is(err, entities.ErrStartOfAgenda),
+		errors.Is(err, entities.ErrNoProgram), errors.Is(err, entities.ErrLastVerse), errors.Is(err, entities.ErrFirstVerse):
+		return apierrors.New(http.StatusConflict, "LIVE_STATE_CONFLICT", "Live state conflict", err.Error())
+	default:
+		return err
+	}
+}
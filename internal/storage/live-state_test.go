@@ -0,0 +1,137 @@
+package storage
+
+import (
+	"errors"
+	"lowerthirdsapi/internal/apierrors"
+	"lowerthirdsapi/internal/entities"
+	"lowerthirdsapi/internal/testutil"
+	"net/http"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestLiveState(t *testing.T) {
+	testutil.SetupTest(t)
+	defer testutil.TeardownTest()
+
+	service := New(testutil.TestDB, testutil.TestLogger)
+	_, _, meeting := testutil.CreateTestData(t, service)
+
+	var itemIDs []uuid.UUID
+	for i := 1; i <= 2; i++ {
+		item := &entities.BlankItem{
+			BlankItemID: uuid.New(),
+			MeetingID:   meeting.MeetingID,
+			ItemType:    "blank",
+			ItemOrder:   i,
+			MeetingRole: "Test Role",
+		}
+		err := service.CreateItem(testutil.TestCtx, item)
+		if err != nil {
+			t.Fatalf("CreateItem failed: %v", err)
+		}
+		itemIDs = append(itemIDs, item.BlankItemID)
+	}
+
+	// Nothing is live before the first action
+	liveState, err := service.GetLiveState(testutil.TestCtx, meeting.MeetingID)
+	if err != nil {
+		t.Fatalf("GetLiveState failed: %v", err)
+	}
+	if liveState.ProgramItemID.Valid || liveState.Visible {
+		t.Errorf("Expected empty live state, got %+v", liveState)
+	}
+
+	_, err = service.UpdateLiveState(testutil.TestCtx, meeting.MeetingID, entities.LiveActionNext, uuid.Nil)
+	if err != nil {
+		t.Fatalf("UpdateLiveState failed: %v", err)
+	}
+
+	// The state is read back from the database
+	liveState, err = service.GetLiveState(testutil.TestCtx, meeting.MeetingID)
+	if err != nil {
+		t.Fatalf("GetLiveState failed: %v", err)
+	}
+	if liveState.ProgramItemID.UUID != itemIDs[0] || liveState.PreviewItemID.UUID != itemIDs[1] || !liveState.Visible {
+		t.Errorf("Unexpected live state after next: %+v", liveState)
+	}
+	if !liveState.ProgramChangedDT.Valid {
+		t.Error("Expected program change time to be set")
+	}
+
+	liveState, err = service.UpdateLiveState(testutil.TestCtx, meeting.MeetingID, entities.LiveActionTake, uuid.Nil)
+	if err != nil {
+		t.Fatalf("UpdateLiveState failed: %v", err)
+	}
+	if liveState.ProgramItemID.UUID != itemIDs[1] || liveState.PreviewItemID.Valid {
+		t.Errorf("Unexpected live state after take: %+v", liveState)
+	}
+
+	// Running off the end of the agenda is a conflict
+	_, err = service.UpdateLiveState(testutil.TestCtx, meeting.MeetingID, entities.LiveActionNext, uuid.Nil)
+	var apiErr *apierrors.Error
+	if !errors.As(err, &apiErr) || apiErr.Status != http.StatusConflict {
+		t.Errorf("Expected conflict at the end of the agenda, got %v", err)
+	}
+
+	// Items from other meetings can't be cued
+	_, err = service.UpdateLiveState(testutil.TestCtx, meeting.MeetingID, entities.LiveActionCue, uuid.New())
+	if !errors.As(err, &apiErr) || apiErr.Status != http.StatusBadRequest {
+		t.Errorf("Expected bad request cueing an unknown item, got %v", err)
+	}
+}
+
+func TestLiveStateVerses(t *testing.T) {
+	testutil.SetupTest(t)
+	defer testutil.TeardownTest()
+
+	service := New(testutil.TestDB, testutil.TestLogger)
+	_, _, meeting := testutil.CreateTestData(t, service)
+	hymnID, _ := createTestHymn(t)
+
+	lyricsItem := &entities.LyricsItem{
+		LyricsItemID: uuid.New(),
+		MeetingID:    meeting.MeetingID,
+		ItemType:     "lyrics",
+		ItemOrder:    1,
+		MeetingRole:  "Test Role",
+		HymnID:       hymnID.String(),
+	}
+	err := service.CreateItem(testutil.TestCtx, lyricsItem)
+	if err != nil {
+		t.Fatalf("CreateItem failed: %v", err)
+	}
+
+	// Two verses fit on a slide each with the default slide options
+	liveState, err := service.UpdateLiveState(testutil.TestCtx, meeting.MeetingID, entities.LiveActionTake, lyricsItem.LyricsItemID)
+	if err != nil {
+		t.Fatalf("UpdateLiveState failed: %v", err)
+	}
+	if liveState.VerseCount != 2 || liveState.VerseIndex != 0 {
+		t.Errorf("Expected the first of 2 verses, got %+v", liveState)
+	}
+
+	liveState, err = service.UpdateLiveState(testutil.TestCtx, meeting.MeetingID, entities.LiveActionNextVerse, uuid.Nil)
+	if err != nil {
+		t.Fatalf("UpdateLiveState failed: %v", err)
+	}
+	if liveState.VerseIndex != 1 || liveState.Version != 2 {
+		t.Errorf("Expected the second verse at version 2, got %+v", liveState)
+	}
+
+	_, err = service.UpdateLiveState(testutil.TestCtx, meeting.MeetingID, entities.LiveActionNextVerse, uuid.Nil)
+	var apiErr *apierrors.Error
+	if !errors.As(err, &apiErr) || apiErr.Status != http.StatusConflict {
+		t.Errorf("Expected conflict past the last verse, got %v", err)
+	}
+
+	// The verse position is read back from the database
+	liveState, err = service.GetLiveState(testutil.TestCtx, meeting.MeetingID)
+	if err != nil {
+		t.Fatalf("GetLiveState failed: %v", err)
+	}
+	if liveState.VerseIndex != 1 || liveState.VerseCount != 2 || liveState.Version != 2 {
+		t.Errorf("Unexpected stored live state: %+v", liveState)
+	}
+}
@@ -0,0 +1,330 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"lowerthirdsapi/internal/apierrors"
+	"lowerthirdsapi/internal/entities"
+	"net/http"
+	"time"
+)
+
+// invalidTemplate creates the API error returned for a template that's missing fields or has bad item prototypes
+func invalidTemplate(err error) *apierrors.Error {
+	return apierrors.New(http.StatusBadRequest, "INVALID_TEMPLATE", "Invalid template", "%s", err.Error())
+}
+
+// templateNotFound creates the API error returned for a template that isn't in the org
+func templateNotFound(templateID uuid.UUID) *apierrors.Error {
+	return apierrors.New(http.StatusNotFound, "TEMPLATE_NOT_FOUND", "Template not found",
+		"The org has no template %s.", templateID)
+}
+
+// CreateTemplate saves a new meeting template for the org
+func (s lowerThirdsService) CreateTemplate(ctx context.Context, orgID uuid.UUID, t *entities.MeetingTemplate) error {
+	s.logger.Debug("CreateTemplate for orgID ", orgID)
+
+	user, err := s.authorizeOrg(ctx, orgID, entities.RoleEditor)
+	if err != nil {
+		return err
+	}
+	if err := t.Validate(); err != nil {
+		return invalidTemplate(err)
+	}
+	if t.TemplateID == uuid.Nil {
+		t.TemplateID = uuid.New()
+	}
+	t.OrgID = orgID
+
+	tx, err := s.MySqlDB.BeginTxx(ctx, nil)
+	if err != nil {
+		s.logger.Error("CreateTemplate Begin Error", err)
+		return err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.ExecContext(
+		ctx,
+		`INSERT INTO MeetingTemplates (
+			id, org_id, name, conference, meeting, duration, items
+		) VALUES (?, ?, ?, ?, ?, ?, ?)`,
+		t.TemplateID,
+		t.OrgID,
+		t.Name,
+		t.Conference,
+		t.Meeting,
+		t.Duration,
+		t.Items,
+	)
+	if err != nil {
+		s.logger.Error("CreateTemplate Error", err)
+		return err
+	}
+	err = s.audit(ctx, tx, user, orgID, entities.AuditTemplate, t.TemplateID, nil, t)
+	if err != nil {
+		return err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		s.logger.Error("CreateTemplate Commit Error", err)
+		return err
+	}
+	return nil
+}
+
+// DeleteTemplate removes one of the org's meeting templates. Meetings already made from it are kept.
+func (s lowerThirdsService) DeleteTemplate(ctx context.Context, orgID uuid.UUID, templateID uuid.UUID) error {
+	s.logger.Debug("DeleteTemplate for orgID ", orgID, " templateID ", templateID)
+
+	user, err := s.authorizeOrg(ctx, orgID, entities.RoleEditor)
+	if err != nil {
+		return err
+	}
+
+	tx, err := s.MySqlDB.BeginTxx(ctx, nil)
+	if err != nil {
+		s.logger.Error("DeleteTemplate Begin Error", err)
+		return err
+	}
+	defer tx.Rollback()
+
+	before, err := s.lockTemplate(ctx, tx, orgID, templateID)
+	if err != nil {
+		return err
+	}
+	result, err := tx.ExecContext(ctx, `
+		UPDATE MeetingTemplates
+		SET deleted_dt = CURRENT_TIMESTAMP
+		WHERE id = ?
+		  AND org_id = ?
+		  AND deleted_dt IS NULL`,
+		templateID,
+		orgID,
+	)
+	if err != nil {
+		s.logger.Error("DeleteTemplate error ", err)
+		return err
+	}
+	affectedRows, err := result.RowsAffected()
+	if err == nil {
+		s.logger.Info("DeleteTemplate affected rows: ", affectedRows)
+	}
+	err = s.audit(ctx, tx, user, orgID, entities.AuditTemplate, templateID, before, nil)
+	if err != nil {
+		return err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		s.logger.Error("DeleteTemplate Commit Error", err)
+		return err
+	}
+	return nil
+}
+
+// GetTemplate loads one of the org's meeting templates
+func (s lowerThirdsService) GetTemplate(ctx context.Context, orgID uuid.UUID, templateID uuid.UUID) (*entities.MeetingTemplate, error) {
+	s.logger.Debug("GetTemplate for orgID ", orgID, " templateID ", templateID)
+
+	_, err := s.authorizeOrg(ctx, orgID, entities.RoleViewer)
+	if err != nil {
+		return nil, err
+	}
+	return s.getTemplate(ctx, orgID, templateID)
+}
+
+// GetTemplatesByOrg loads the org's meeting templates by name
+func (s lowerThirdsService) GetTemplatesByOrg(ctx context.Context, orgID uuid.UUID) (*[]entities.MeetingTemplate, error) {
+	s.logger.Debug("GetTemplatesByOrg for orgID ", orgID)
+
+	_, err := s.authorizeOrg(ctx, orgID, entities.RoleViewer)
+	if err != nil {
+		return nil, err
+	}
+
+	templates := []entities.MeetingTemplate{}
+	err = s.MySqlDB.SelectContext(
+		ctx,
+		&templates,
+		`SELECT * FROM MeetingTemplates WHERE org_id = ? AND deleted_dt IS NULL ORDER BY name`,
+		orgID,
+	)
+	if err != nil {
+		s.logger.Error("GetTemplatesByOrg Error", err)
+		return nil, err
+	}
+	return &templates, nil
+}
+
+// UpdateTemplate replaces one of the org's meeting templates
+func (s lowerThirdsService) UpdateTemplate(ctx context.Context, orgID uuid.UUID, templateID uuid.UUID, t *entities.MeetingTemplate) error {
+	s.logger.Debug("UpdateTemplate for orgID ", orgID, " templateID ", templateID)
+
+	user, err := s.authorizeOrg(ctx, orgID, entities.RoleEditor)
+	if err != nil {
+		return err
+	}
+	if err := t.Validate(); err != nil {
+		return invalidTemplate(err)
+	}
+	t.TemplateID = templateID
+	t.OrgID = orgID
+
+	tx, err := s.MySqlDB.BeginTxx(ctx, nil)
+	if err != nil {
+		s.logger.Error("UpdateTemplate Begin Error", err)
+		return err
+	}
+	defer tx.Rollback()
+
+	before, err := s.lockTemplate(ctx, tx, orgID, templateID)
+	if err != nil {
+		return err
+	}
+	result, err := tx.ExecContext(
+		ctx,
+		`UPDATE MeetingTemplates SET
+		  name = ?,
+		  conference = ?,
+		  meeting = ?,
+		  duration = ?,
+		  items = ?
+		WHERE id = ?
+		  AND org_id = ?
+		  AND deleted_dt IS NULL`,
+		t.Name,
+		t.Conference,
+		t.Meeting,
+		t.Duration,
+		t.Items,
+		templateID,
+		orgID,
+	)
+	if err != nil {
+		s.logger.Error("UpdateTemplate Error", err)
+		return err
+	}
+	affectedRows, err := result.RowsAffected()
+	if err == nil {
+		s.logger.Info("UpdateTemplate affected rows: ", affectedRows)
+	}
+	after, err := s.lockTemplate(ctx, tx, orgID, templateID)
+	if err != nil {
+		return err
+	}
+	err = s.audit(ctx, tx, user, orgID, entities.AuditTemplate, templateID, before, after)
+	if err != nil {
+		return err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		s.logger.Error("UpdateTemplate Commit Error", err)
+		return err
+	}
+	return nil
+}
+
+// InstantiateTemplate makes a meeting on date from one of the org's templates. The meeting and all of its items are
+// created in one transaction, so a bad prototype leaves nothing behind.
+func (s lowerThirdsService) InstantiateTemplate(ctx context.Context, orgID uuid.UUID, templateID uuid.UUID, date time.Time) (*entities.Meeting, error) {
+	s.logger.Debug("InstantiateTemplate for orgID ", orgID, " templateID ", templateID, " date ", date)
+
+	user, err := s.authorizeOrg(ctx, orgID, entities.RoleEditor)
+	if err != nil {
+		return nil, err
+	}
+	template, err := s.getTemplate(ctx, orgID, templateID)
+	if err != nil {
+		return nil, err
+	}
+	tx, err := s.MySqlDB.BeginTxx(ctx, nil)
+	if err != nil {
+		s.logger.Error("InstantiateTemplate Begin Error", err)
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	meeting, err := s.insertTemplateMeeting(ctx, tx, template, date)
+	if err != nil {
+		return nil, err
+	}
+	err = s.audit(ctx, tx, user, orgID, entities.AuditMeeting, meeting.MeetingID, nil, meeting)
+	if err != nil {
+		return nil, err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		s.logger.Error("InstantiateTemplate Commit Error", err)
+		return nil, err
+	}
+	return meeting, nil
+}
+
+// insertTemplateMeeting creates a meeting on date from the template, with its items, as part of the caller's
+// transaction
+func (s lowerThirdsService) insertTemplateMeeting(ctx context.Context, tx *sqlx.Tx, template *entities.MeetingTemplate, date time.Time) (*entities.Meeting, error) {
+	meeting, items, err := template.Instantiate(uuid.New(), date)
+	if err != nil {
+		return nil, invalidTemplate(err)
+	}
+
+	err = s.insertMeeting(ctx, tx, &meeting)
+	if err != nil {
+		return nil, err
+	}
+	for _, item := range items {
+		err = s.insertItem(ctx, tx, item)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	meeting.AgendaItems = items
+	return &meeting, nil
+}
+
+// getTemplate loads a template without checking the caller
+func (s lowerThirdsService) getTemplate(ctx context.Context, orgID uuid.UUID, templateID uuid.UUID) (*entities.MeetingTemplate, error) {
+	var template entities.MeetingTemplate
+	err := s.MySqlDB.GetContext(
+		ctx,
+		&template,
+		`SELECT * FROM MeetingTemplates WHERE id = ? AND org_id = ? AND deleted_dt IS NULL`,
+		templateID,
+		orgID,
+	)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, templateNotFound(templateID)
+	}
+	if err != nil {
+		s.logger.Error("getTemplate Error", err)
+		return nil, err
+	}
+	return &template, nil
+}
+
+// lockTemplate loads one of the org's templates in a transaction, locking it until the transaction ends
+func (s lowerThirdsService) lockTemplate(ctx context.Context, tx *sqlx.Tx, orgID uuid.UUID, templateID uuid.UUID) (*entities.MeetingTemplate, error) {
+	var template entities.MeetingTemplate
+	err := tx.GetContext(
+		ctx,
+		&template,
+		`SELECT * FROM MeetingTemplates WHERE id = ? AND org_id = ? AND deleted_dt IS NULL`+s.dialect.forUpdate(),
+		templateID,
+		orgID,
+	)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, templateNotFound(templateID)
+	}
+	if err != nil {
+		s.logger.Error("lockTemplate Error", err)
+		return nil, err
+	}
+	return &template, nil
+}
@@ -0,0 +1,99 @@
+package storage
+
+import (
+	"encoding/json"
+	"errors"
+	"lowerthirdsapi/internal/apierrors"
+	"lowerthirdsapi/internal/entities"
+	"lowerthirdsapi/internal/testutil"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestMeetingTemplates(t *testing.T) {
+	testutil.SetupTest(t)
+	defer testutil.TeardownTest()
+
+	service := New(testutil.TestDB, testutil.TestLogger)
+	_, org, _ := testutil.CreateTestData(t, service)
+
+	template := &entities.MeetingTemplate{
+		Name:    "Sacrament Meeting",
+		Meeting: "Test Meeting",
+		Items: entities.TemplateItems{
+			json.RawMessage(`{"type": "blank", "meeting_role": "Test Role"}`),
+			json.RawMessage(`{"type": "timer", "meeting_role": "Test Role", "show_meeting_details": true}`),
+			json.RawMessage(`{"type": "speaker", "meeting_role": "Test Role", "name": "Speaker", "expected_duration": 10}`),
+		},
+	}
+	err := service.CreateTemplate(testutil.TestCtx, org.OrgID, template)
+	if err != nil {
+		t.Fatalf("CreateTemplate failed: %v", err)
+	}
+
+	templates, err := service.GetTemplatesByOrg(testutil.TestCtx, org.OrgID)
+	if err != nil {
+		t.Fatalf("GetTemplatesByOrg failed: %v", err)
+	}
+	if len(*templates) != 1 || len((*templates)[0].Items) != 3 {
+		t.Errorf("Expected the template with 3 items, got %+v", templates)
+	}
+
+	var apiErr *apierrors.Error
+	err = service.CreateTemplate(testutil.TestCtx, org.OrgID, &entities.MeetingTemplate{Name: "Bad", Meeting: "Test Meeting",
+		Items: entities.TemplateItems{json.RawMessage(`{"type": "video"}`)}})
+	if !errors.As(err, &apiErr) || apiErr.Status != http.StatusBadRequest || apiErr.Code != "INVALID_TEMPLATE" {
+		t.Errorf("Expected an invalid template error, got %v", err)
+	}
+	_, err = service.GetTemplate(testutil.TestCtx, org.OrgID, uuid.New())
+	if !errors.As(err, &apiErr) || apiErr.Status != http.StatusNotFound {
+		t.Errorf("Expected not found for an unknown template, got %v", err)
+	}
+
+	// The meeting and its items are created together
+	date := time.Date(2025, 6, 1, 9, 0, 0, 0, time.UTC)
+	meeting, err := service.InstantiateTemplate(testutil.TestCtx, org.OrgID, template.TemplateID, date)
+	if err != nil {
+		t.Fatalf("InstantiateTemplate failed: %v", err)
+	}
+	items, err := service.GetItemsByMeeting(testutil.TestCtx, meeting.MeetingID)
+	if err != nil {
+		t.Fatalf("GetItemsByMeeting failed: %v", err)
+	}
+	if len(*items) != 3 || (*items)[2].GetType() != "speaker" || (*items)[2].GetOrder() != 3 {
+		t.Errorf("Expected the template's 3 items in order, got %+v", items)
+	}
+
+	// A prototype the database rejects leaves nothing behind
+	template.Items = append(template.Items, json.RawMessage(`{"type": "blank", "meeting_role": "`+strings.Repeat("x", 60)+`"}`))
+	err = service.UpdateTemplate(testutil.TestCtx, org.OrgID, template.TemplateID, template)
+	if err != nil {
+		t.Fatalf("UpdateTemplate failed: %v", err)
+	}
+	_, err = service.InstantiateTemplate(testutil.TestCtx, org.OrgID, template.TemplateID, date.AddDate(0, 0, 7))
+	if err == nil {
+		t.Fatal("Expected InstantiateTemplate to fail")
+	}
+	meetings, _, err := service.GetMeetingsByOrg(testutil.TestCtx, org.OrgID)
+	if err != nil {
+		t.Fatalf("GetMeetingsByOrg failed: %v", err)
+	}
+	for _, m := range *meetings {
+		if m.MeetingDate.Equal(date.AddDate(0, 0, 7)) {
+			t.Errorf("Expected the failed meeting to be rolled back, got %+v", m)
+		}
+	}
+
+	err = service.DeleteTemplate(testutil.TestCtx, org.OrgID, template.TemplateID)
+	if err != nil {
+		t.Fatalf("DeleteTemplate failed: %v", err)
+	}
+	err = service.DeleteTemplate(testutil.TestCtx, org.OrgID, template.TemplateID)
+	if !errors.As(err, &apiErr) || apiErr.Status != http.StatusNotFound {
+		t.Errorf("Expected not found deleting the template twice, got %v", err)
+	}
+}
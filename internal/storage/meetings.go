@@ -3,38 +3,139 @@ package storage
 import (
 	"context"
 	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
 	"lowerthirdsapi/internal/entities"
 	"lowerthirdsapi/internal/helpers"
+	"time"
 )
 
 func (s lowerThirdsService) CreateMeeting(ctx context.Context, m *entities.Meeting) error {
 	s.logger.Debug("CreateMeeting")
 
-	// TODO: put some user-level security on this query
-	_, err := s.MySqlDB.ExecContext(
-		ctx,
-		`INSERT INTO Meetings (
-			id, org_id, conference, meeting, meeting_date, duration
-		) VALUES (?, ?, ?, ?, ?, ?)`,
-		m.MeetingID,
-		m.OrgID,
-		m.Conference,
-		m.Meeting,
-		m.MeetingDate,
-		m.Duration,
-	)
+	user, err := s.authorizeOrg(ctx, m.OrgID, entities.RoleEditor)
+	if err != nil {
+		return err
+	}
+
+	tx, err := s.MySqlDB.BeginTxx(ctx, nil)
 	if err != nil {
-		s.logger.Error("CreateMeeting Error", err)
+		s.logger.Error("CreateMeeting Begin Error", err)
+		return err
+	}
+	defer tx.Rollback()
+
+	err = s.insertMeeting(ctx, tx, m)
+	if err != nil {
+		return err
+	}
+	err = s.audit(ctx, tx, user, m.OrgID, entities.AuditMeeting, m.MeetingID, nil, m)
+	if err != nil {
+		return err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		s.logger.Error("CreateMeeting Commit Error", err)
 		return err
 	}
 	return nil
 }
 
+// CloneMeeting copies a meeting and all of its items to date, in orgID or the meeting's own org when orgID is nil.
+// The copy and its items get new IDs and keep the items' order, and are created in one transaction. Cloning needs
+// the viewer role on the meeting and the editor role in the org the copy goes to.
+func (s lowerThirdsService) CloneMeeting(ctx context.Context, meetingID uuid.UUID, date time.Time, orgID uuid.UUID) (*entities.Meeting, error) {
+	s.logger.Debug("CloneMeeting for meetingID ", meetingID, " date ", date, " orgID ", orgID)
+
+	_, err := s.authorizeMeeting(ctx, meetingID, entities.RoleViewer)
+	if err != nil {
+		return nil, err
+	}
+	var source entities.Meeting
+	err = s.MySqlDB.GetContext(ctx, &source, `SELECT * FROM Meetings WHERE id = ? AND deleted_dt IS NULL`, meetingID)
+	if err != nil {
+		s.logger.Error("CloneMeeting Error", err)
+		return nil, err
+	}
+	if orgID == uuid.Nil {
+		orgID = source.OrgID
+	}
+	user, err := s.authorizeOrg(ctx, orgID, entities.RoleEditor)
+	if err != nil {
+		return nil, err
+	}
+	items, err := s.GetItemsByMeeting(ctx, meetingID)
+	if err != nil {
+		return nil, err
+	}
+
+	clone := entities.Meeting{
+		MeetingID:   uuid.New(),
+		OrgID:       orgID,
+		Conference:  source.Conference,
+		Meeting:     source.Meeting,
+		MeetingDate: date,
+		Duration:    source.Duration,
+		AgendaItems: make([]entities.Item, 0, len(*items)),
+	}
+	for _, item := range *items {
+		copied, err := entities.CopyItem(item, clone.MeetingID, item.GetOrder())
+		if err != nil {
+			return nil, err
+		}
+		clone.AgendaItems = append(clone.AgendaItems, copied)
+	}
+
+	tx, err := s.MySqlDB.BeginTxx(ctx, nil)
+	if err != nil {
+		s.logger.Error("CloneMeeting Begin Error", err)
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	err = s.insertMeeting(ctx, tx, &clone)
+	if err != nil {
+		return nil, err
+	}
+	for _, item := range clone.AgendaItems {
+		err = s.insertItem(ctx, tx, item)
+		if err != nil {
+			return nil, err
+		}
+	}
+	err = s.audit(ctx, tx, user, orgID, entities.AuditMeeting, clone.MeetingID, nil, clone)
+	if err != nil {
+		return nil, err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		s.logger.Error("CloneMeeting Commit Error", err)
+		return nil, err
+	}
+	return &clone, nil
+}
+
 func (s lowerThirdsService) DeleteMeeting(ctx context.Context, meetingID uuid.UUID) error {
 	s.logger.Debug("DeleteMeeting for meetingID ", meetingID)
 
-	// TODO: put some user level security on this query
-	result, err := s.MySqlDB.ExecContext(ctx, `
+	user, err := s.authorizeMeeting(ctx, meetingID, entities.RoleEditor)
+	if err != nil {
+		return err
+	}
+
+	tx, err := s.MySqlDB.BeginTxx(ctx, nil)
+	if err != nil {
+		s.logger.Error("DeleteMeeting Begin Error", err)
+		return err
+	}
+	defer tx.Rollback()
+
+	before, err := s.lockMeeting(ctx, tx, meetingID)
+	if err != nil {
+		return err
+	}
+	result, err := tx.ExecContext(ctx, `
 		UPDATE Meetings 
 		SET deleted_dt = CURRENT_TIMESTAMP 
 		WHERE id = ?
@@ -49,15 +150,22 @@ func (s lowerThirdsService) DeleteMeeting(ctx context.Context, meetingID uuid.UU
 	if err == nil {
 		s.logger.Info("DeleteMeeting affected rows: ", affectedRows)
 	}
+	err = s.audit(ctx, tx, user, before.OrgID, entities.AuditMeeting, meetingID, before, nil)
+	if err != nil {
+		return err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		s.logger.Error("DeleteMeeting Commit Error", err)
+		return err
+	}
 	return nil
 }
 
 func (s lowerThirdsService) GetMeeting(ctx context.Context, meetingID uuid.UUID) (*entities.Meeting, error) {
-	socialID := ctx.Value(helpers.SocialIDKey).(string)
-	s.logger.Debug("GetMeeting for socialID ", socialID, " meetingID ", meetingID)
-	user, err := s.GetUserBySocialID(ctx, socialID)
+	user, err := s.currentUser(ctx)
 	if err != nil {
-		s.logger.Error("User not found by socialID", err)
 		return nil, err
 	}
 	s.logger.Debug("GetMeeting for userID ", user.UserID, " meetingID ", meetingID)
@@ -87,20 +195,16 @@ func (s lowerThirdsService) GetMeeting(ctx context.Context, meetingID uuid.UUID)
 	return &meeting, nil
 }
 
-func (s lowerThirdsService) GetMeetings(ctx context.Context) (*[]entities.Meeting, error) {
-	socialID := ctx.Value(helpers.SocialIDKey).(string)
-	s.logger.Debug("GetMeetings for socialID ", socialID)
-	user, err := s.GetUserBySocialID(ctx, socialID)
+// GetMeetings lists a page of the meetings in the user's orgs, or in the OrgID query parameter's org when it's set,
+// by date. DateFrom and DateTo filter on the meeting date. It also returns the number of meetings on every page.
+func (s lowerThirdsService) GetMeetings(ctx context.Context) (*[]entities.Meeting, int, error) {
+	user, err := s.currentUser(ctx)
 	if err != nil {
-		s.logger.Error("User not found by socialID", err)
-		return nil, err
+		return nil, 0, err
 	}
 	s.logger.Debug("GetMeetings for userID ", user.UserID)
 
-	var meetings []entities.Meeting
-	err = s.MySqlDB.Select(
-		&meetings,
-		`SELECT m.*
+	query := `SELECT m.*
 		FROM Users u
 		INNER JOIN OrgUsers ou
 		  ON ou.user_id = u.id
@@ -112,19 +216,47 @@ func (s lowerThirdsService) GetMeetings(ctx context.Context) (*[]entities.Meetin
 		  ON ou.org_id = m.org_id
 		  AND m.deleted_dt IS NULL
 		WHERE u.id = ?
-		  AND u.deleted_dt IS NULL`, user.UserID)
+		  AND u.deleted_dt IS NULL`
+	args := []interface{}{user.UserID}
+	if orgID := helpers.GetQueryParams(ctx).OrgID; orgID != uuid.Nil {
+		query += ` AND m.org_id = ?`
+		args = append(args, orgID)
+	}
+
+	meetings := []entities.Meeting{}
+	total, err := s.selectPage(ctx, &meetings, query, "m.meeting_date", "m.meeting_date, m.id", args...)
 	if err != nil {
-		s.logger.Error(err)
-		return nil, err
+		return nil, 0, err
 	}
-	return &meetings, nil
+	return &meetings, total, nil
 }
 
 func (s lowerThirdsService) UpdateMeeting(ctx context.Context, meetingID uuid.UUID, m *entities.Meeting) error {
 	s.logger.Debug("UpdateMeeting")
 
-	// TODO: put some user level security on this query
-	result, err := s.MySqlDB.ExecContext(
+	_, err := s.authorizeMeeting(ctx, meetingID, entities.RoleEditor)
+	if err != nil {
+		return err
+	}
+
+	// Moving a meeting to another org also requires access to that org
+	user, err := s.authorizeOrg(ctx, m.OrgID, entities.RoleEditor)
+	if err != nil {
+		return err
+	}
+
+	tx, err := s.MySqlDB.BeginTxx(ctx, nil)
+	if err != nil {
+		s.logger.Error("UpdateMeeting Begin Error", err)
+		return err
+	}
+	defer tx.Rollback()
+
+	before, err := s.lockMeeting(ctx, tx, meetingID)
+	if err != nil {
+		return err
+	}
+	result, err := tx.ExecContext(
 		ctx,
 		`UPDATE Meetings SET
 		  id = ?,
@@ -138,7 +270,7 @@ func (s lowerThirdsService) UpdateMeeting(ctx context.Context, meetingID uuid.UU
 		m.OrgID,
 		m.Conference,
 		m.Meeting,
-		m.MeetingDate,
+		m.MeetingDate.UTC(),
 		m.Duration,
 		meetingID,
 	)
@@ -150,5 +282,52 @@ func (s lowerThirdsService) UpdateMeeting(ctx context.Context, meetingID uuid.UU
 	if err == nil {
 		s.logger.Info("UpdateMeeting affected rows: ", affectedRows)
 	}
+	after, err := s.lockMeeting(ctx, tx, m.MeetingID)
+	if err != nil {
+		return err
+	}
+	err = s.audit(ctx, tx, user, m.OrgID, entities.AuditMeeting, m.MeetingID, before, after)
+	if err != nil {
+		return err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		s.logger.Error("UpdateMeeting Commit Error", err)
+		return err
+	}
+	return nil
+}
+
+// lockMeeting loads a meeting in a transaction, locking it until the transaction ends
+func (s lowerThirdsService) lockMeeting(ctx context.Context, tx *sqlx.Tx, meetingID uuid.UUID) (*entities.Meeting, error) {
+	var meeting entities.Meeting
+	err := tx.GetContext(ctx, &meeting, `SELECT * FROM Meetings WHERE id = ? AND deleted_dt IS NULL`+s.dialect.forUpdate(), meetingID)
+	if err != nil {
+		s.logger.Error("lockMeeting Error", err)
+		return nil, err
+	}
+	return &meeting, nil
+}
+
+// insertMeeting inserts a meeting with q, which may be a transaction. The caller authorizes the org.
+func (s lowerThirdsService) insertMeeting(ctx context.Context, q sqlx.ExecerContext, m *entities.Meeting) error {
+	_, err := q.ExecContext(
+		ctx,
+		`INSERT INTO Meetings (
+			id, org_id, conference, meeting, meeting_date, duration
+		) VALUES (?, ?, ?, ?, ?, ?)`,
+		m.MeetingID,
+		m.OrgID,
+		m.Conference,
+		m.Meeting,
+		// Store UTC so SQLite, which keeps times as text, compares and sorts them like MySQL
+		m.MeetingDate.UTC(),
+		m.Duration,
+	)
+	if err != nil {
+		s.logger.Error("insertMeeting Error", err)
+		return err
+	}
 	return nil
 }
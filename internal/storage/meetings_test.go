@@ -1,8 +1,11 @@
 package storage
 
 import (
+	"errors"
+	"lowerthirdsapi/internal/apierrors"
 	"lowerthirdsapi/internal/entities"
 	"lowerthirdsapi/internal/testutil"
+	"net/http"
 	"testing"
 	"time"
 
@@ -59,3 +62,74 @@ func TestCreateAndGetMeeting(t *testing.T) {
 		t.Errorf("Expected Duration %v, got %v", meeting.Duration.Int64, retrievedMeeting.Duration.Int64)
 	}
 }
+
+func TestCloneMeeting(t *testing.T) {
+	testutil.SetupTest(t)
+	defer testutil.TeardownTest()
+
+	service := New(testutil.TestDB, testutil.TestLogger)
+	_, org, meeting := testutil.CreateTestData(t, service)
+
+	source := []entities.Item{
+		&entities.SpeakerItem{MeetingID: meeting.MeetingID, ItemType: "speaker", ItemOrder: 2, MeetingRole: "Test Role",
+			SpeakerName: "Speaker", ExpectedDuration: null.IntFrom(10)},
+		&entities.BlankItem{MeetingID: meeting.MeetingID, ItemType: "blank", ItemOrder: 1, MeetingRole: "Test Role"},
+		&entities.TimerItem{MeetingID: meeting.MeetingID, ItemType: "timer", ItemOrder: 3, MeetingRole: "Test Role"},
+	}
+	for _, item := range source {
+		if err := service.CreateItem(testutil.TestCtx, item); err != nil {
+			t.Fatalf("CreateItem failed: %v", err)
+		}
+	}
+
+	date := time.Date(2025, 6, 8, 16, 0, 0, 0, time.UTC)
+	clone, err := service.CloneMeeting(testutil.TestCtx, meeting.MeetingID, date, uuid.Nil)
+	if err != nil {
+		t.Fatalf("CloneMeeting failed: %v", err)
+	}
+	if clone.MeetingID == meeting.MeetingID || clone.OrgID != org.OrgID || !clone.MeetingDate.Equal(date) {
+		t.Errorf("Expected a new meeting on the date in the same org, got %+v", clone)
+	}
+
+	items, err := service.GetItemsByMeeting(testutil.TestCtx, clone.MeetingID)
+	if err != nil {
+		t.Fatalf("GetItemsByMeeting failed: %v", err)
+	}
+	if len(*items) != 3 {
+		t.Fatalf("Expected 3 items, got %d", len(*items))
+	}
+	for i, item := range *items {
+		if item.GetOrder() != i+1 || item.GetMeetingID() != clone.MeetingID {
+			t.Errorf("Expected item %d of the clone, got %+v", i+1, item)
+		}
+		for _, original := range source {
+			if item.GetID() == original.GetID() {
+				t.Errorf("Expected item %d to get a new ID", i+1)
+			}
+		}
+	}
+	if speaker, ok := (*items)[1].(*entities.SpeakerItem); !ok || speaker.SpeakerName != "Speaker" ||
+		speaker.ExpectedDuration.Int64 != 10 {
+		t.Errorf("Expected the speaker to be copied, got %+v", (*items)[1])
+	}
+
+	// The copy can go to another org only with the editor role there
+	otherOrg := &entities.Organization{OrgID: uuid.New(), Name: "Test Org " + uuid.NewString()}
+	if err := service.CreateOrg(testutil.TestCtx, otherOrg); err != nil {
+		t.Fatalf("CreateOrg failed: %v", err)
+	}
+	clone, err = service.CloneMeeting(testutil.TestCtx, meeting.MeetingID, date, otherOrg.OrgID)
+	if err != nil || clone.OrgID != otherOrg.OrgID || len(clone.AgendaItems) != 3 {
+		t.Errorf("Expected a clone in the other org, got %+v, %v", clone, err)
+	}
+	strangerOrg := uuid.New()
+	_, err = testutil.TestDB.Exec(`INSERT INTO Organization (id, name) VALUES (?, ?)`, strangerOrg, "Test Org "+strangerOrg.String())
+	if err != nil {
+		t.Fatalf("Failed to create org: %v", err)
+	}
+	_, err = service.CloneMeeting(testutil.TestCtx, meeting.MeetingID, date, strangerOrg)
+	var apiErr *apierrors.Error
+	if !errors.As(err, &apiErr) || apiErr.Status != http.StatusForbidden {
+		t.Errorf("Expected forbidden cloning into an org without a role, got %v", err)
+	}
+}
@@ -3,32 +3,105 @@ package storage
 import (
 	"context"
 	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"lowerthirdsapi/internal/apierrors"
 	"lowerthirdsapi/internal/entities"
-	"lowerthirdsapi/internal/helpers"
+	"net/http"
 )
 
-func (s lowerThirdsService) CreateOrgUser(ctx context.Context, orgID uuid.UUID, userID uuid.UUID) error {
+func (s lowerThirdsService) CreateOrgUser(ctx context.Context, orgID uuid.UUID, userID uuid.UUID, role entities.Role) error {
 	s.logger.Debug("CreateOrgUser")
 
-	// TODO: put some user-level security on this query
-	_, err := s.MySqlDB.ExecContext(
+	if !role.Valid() {
+		return invalidRole(role)
+	}
+
+	user, err := s.authorizeOrg(ctx, orgID, entities.RoleOwner)
+	if err != nil {
+		return err
+	}
+
+	tx, err := s.MySqlDB.BeginTxx(ctx, nil)
+	if err != nil {
+		s.logger.Error("CreateOrgUser Begin Error", err)
+		return err
+	}
+	defer tx.Rollback()
+
+	err = s.createOrgUser(ctx, tx, user, orgID, userID, role)
+	if err != nil {
+		return err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		s.logger.Error("CreateOrgUser Commit Error", err)
+		return err
+	}
+	return nil
+}
+
+// createOrgUser adds a user to an org with a role in the caller's transaction, recording that actor did it
+func (s lowerThirdsService) createOrgUser(ctx context.Context, tx *sqlx.Tx, actor *entities.User, orgID uuid.UUID, userID uuid.UUID, role entities.Role) error {
+	_, err := tx.ExecContext(
 		ctx,
-		`INSERT INTO OrgUsers (org_id, user_id) VALUES (?, ?)`,
+		`INSERT INTO OrgUsers (org_id, user_id, role) VALUES (?, ?, ?)`,
 		orgID,
 		userID,
+		role,
 	)
 	if err != nil {
 		s.logger.Error("CreateOrgUser Error", err)
 		return err
 	}
-	return nil
+	member := entities.OrgUser{OrgID: orgID, UserID: userID, Role: role}
+	return s.audit(ctx, tx, actor, orgID, entities.AuditMember, userID, nil, member)
 }
 
 func (s lowerThirdsService) DeleteOrgUser(ctx context.Context, orgID uuid.UUID, userID uuid.UUID) error {
 	s.logger.Debug("DeleteOrg for orgID ", orgID, " userID ", userID)
 
-	// TODO: put some user level security on this query
-	result, err := s.MySqlDB.ExecContext(ctx, `
+	user, err := s.authorizeOrg(ctx, orgID, entities.RoleOwner)
+	if err != nil {
+		return err
+	}
+
+	tx, err := s.MySqlDB.BeginTxx(ctx, nil)
+	if err != nil {
+		s.logger.Error("DeleteOrgUser Begin Error", err)
+		return err
+	}
+	defer tx.Rollback()
+
+	err = s.rejectAPIKeyUsers(ctx, tx, []uuid.UUID{userID})
+	if err != nil {
+		return err
+	}
+	err = s.ensureAnotherOwner(ctx, tx, orgID, userID)
+	if err != nil {
+		return err
+	}
+	err = s.deleteOrgUser(ctx, tx, user, orgID, userID)
+	if err != nil {
+		return err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		s.logger.Error("DeleteOrgUser Commit Error", err)
+		return err
+	}
+	return nil
+}
+
+// deleteOrgUser removes a user from an org in the caller's transaction, recording that actor did it
+func (s lowerThirdsService) deleteOrgUser(ctx context.Context, tx *sqlx.Tx, actor *entities.User, orgID uuid.UUID, userID uuid.UUID) error {
+	member, err := s.lockOrgUser(ctx, tx, orgID, userID)
+	if err != nil {
+		return err
+	}
+
+	result, err := tx.ExecContext(ctx, `
 		UPDATE OrgUsers 
 		SET deleted_dt = CURRENT_TIMESTAMP 
 		WHERE org_id = ? 
@@ -45,37 +118,72 @@ func (s lowerThirdsService) DeleteOrgUser(ctx context.Context, orgID uuid.UUID,
 	if err == nil {
 		s.logger.Info("DeleteOrg affected rows: ", affectedRows)
 	}
-	return nil
+	if member == nil {
+		return nil
+	}
+	return s.audit(ctx, tx, actor, orgID, entities.AuditMember, userID, member, nil)
 }
 
-func (s lowerThirdsService) DeleteOrgsByUser(ctx context.Context, userID uuid.UUID) error {
-	s.logger.Debug("DeleteOrgs for userID ", userID)
-
-	// TODO: put some user level security on this query
-	result, err := s.MySqlDB.ExecContext(ctx, `
-		UPDATE OrgUsers 
-		SET deleted_dt = CURRENT_TIMESTAMP 
-		WHERE user_id = ?
-		  AND deleted_dt IS NULL`,
+// lockOrgUser loads a user's membership of an org in a transaction, locking it until the transaction ends. It's nil
+// when the user isn't a member.
+func (s lowerThirdsService) lockOrgUser(ctx context.Context, tx *sqlx.Tx, orgID uuid.UUID, userID uuid.UUID) (*entities.OrgUser, error) {
+	var members []entities.OrgUser
+	err := tx.SelectContext(
+		ctx,
+		&members,
+		`SELECT * FROM OrgUsers WHERE org_id = ? AND user_id = ? AND deleted_dt IS NULL`+s.dialect.forUpdate(),
+		orgID,
 		userID,
 	)
 	if err != nil {
-		s.logger.Error("DeleteOrg error ", err)
+		s.logger.Error("lockOrgUser error ", err)
+		return nil, err
+	}
+	if len(members) == 0 {
+		return nil, nil
+	}
+	return &members[0], nil
+}
+
+// ensureAnotherOwner prevents the last owner of an org from being removed or demoted. It locks the org's owners in
+// the caller's transaction, so owners changing each other at once can't both pass.
+func (s lowerThirdsService) ensureAnotherOwner(ctx context.Context, tx *sqlx.Tx, orgID uuid.UUID, userID uuid.UUID) error {
+	var ownerIDs []uuid.UUID
+	err := tx.SelectContext(
+		ctx,
+		&ownerIDs,
+		`SELECT ou.user_id
+		FROM OrgUsers ou
+		INNER JOIN Users u
+		  ON u.id = ou.user_id
+		  AND u.deleted_dt IS NULL
+		WHERE ou.org_id = ?
+		  AND ou.role = ?
+		  AND ou.deleted_dt IS NULL
+		  AND `+notAPIKeyUser+s.dialect.forUpdate(),
+		orgID,
+		entities.RoleOwner,
+	)
+	if err != nil {
+		s.logger.Error("ensureAnotherOwner error ", err)
 		return err
 	}
-	affectedRows, err := result.RowsAffected()
-	if err == nil {
-		s.logger.Info("DeleteOrg affected rows: ", affectedRows)
+	owners := 0
+	for _, ownerID := range ownerIDs {
+		if ownerID != userID {
+			owners++
+		}
+	}
+	if owners == 0 {
+		return apierrors.New(http.StatusConflict, "LAST_OWNER", "Last owner",
+			"organization %s must keep at least one owner", orgID)
 	}
 	return nil
 }
 
 func (s lowerThirdsService) GetOrgUsersMap(ctx context.Context) (map[uuid.UUID][]uuid.UUID, error) {
-	socialID := ctx.Value(helpers.SocialIDKey).(string)
-	s.logger.Debug("GetOrgUsersMap for socialID ", socialID)
-	user, err := s.GetUserBySocialID(ctx, socialID)
+	user, err := s.currentUser(ctx)
 	if err != nil {
-		s.logger.Error("User not found by socialID", err)
 		return nil, err
 	}
 	s.logger.Debug("GetOrgUsersMap for userID ", user.UserID)
@@ -92,6 +200,7 @@ func (s lowerThirdsService) GetOrgUsersMap(ctx context.Context) (map[uuid.UUID][
           ON o.id = ou.org_id
           AND o.deleted_dt IS NULL
         WHERE ou.deleted_dt IS NULL
+          AND `+notAPIKeyUser+`
           AND ou.org_id IN (SELECT org_id FROM OrgUsers WHERE user_id = ?)`,
 		user.UserID,
 	)
@@ -117,11 +226,46 @@ func (s lowerThirdsService) GetOrgUsersMap(ctx context.Context) (map[uuid.UUID][
 	//return &result, nil
 }
 
-func (s lowerThirdsService) GetUsersByOrg(ctx context.Context, orgID uuid.UUID) (*[]entities.User, error) {
+func (s lowerThirdsService) GetOrgUsers(ctx context.Context, orgID uuid.UUID) (*[]entities.OrgUser, error) {
+	s.logger.Debug("GetOrgUsers for orgID ", orgID)
+
+	_, err := s.authorizeOrg(ctx, orgID, entities.RoleViewer)
+	if err != nil {
+		return nil, err
+	}
+
+	var orgUsers []entities.OrgUser
+	err = s.MySqlDB.Select(
+		&orgUsers,
+		`SELECT ou.*
+		FROM OrgUsers ou
+		INNER JOIN Users u
+		  ON u.id = ou.user_id
+		  AND u.deleted_dt IS NULL
+		WHERE ou.org_id = ?
+		  AND ou.deleted_dt IS NULL
+		  AND `+notAPIKeyUser,
+		orgID)
+	if err != nil {
+		s.logger.Error(err)
+		return nil, err
+	}
+	return &orgUsers, nil
+}
+
+// GetUsersByOrg lists a page of an org's users by email, with the number on every page. DateFrom and DateTo filter on
+// when the user was made.
+func (s lowerThirdsService) GetUsersByOrg(ctx context.Context, orgID uuid.UUID) (*[]entities.User, int, error) {
 	s.logger.Debug("GetUsers for orgID ", orgID)
 
-	var users []entities.User
-	err := s.MySqlDB.Select(
+	_, err := s.authorizeOrg(ctx, orgID, entities.RoleViewer)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	users := []entities.User{}
+	total, err := s.selectPage(
+		ctx,
 		&users,
 		`SELECT u.*
 		FROM OrgUsers ou
@@ -132,13 +276,16 @@ func (s lowerThirdsService) GetUsersByOrg(ctx context.Context, orgID uuid.UUID)
 		  ON o.id = ou.org_id
 		  AND o.deleted_dt IS NULL
 		WHERE ou.org_id = ?
-		  AND ou.deleted_dt IS NULL`,
-		orgID)
+		  AND ou.deleted_dt IS NULL
+		  AND `+notAPIKeyUser,
+		"u.inserted_dt",
+		"u.email, u.id",
+		orgID,
+	)
 	if err != nil {
-		s.logger.Error(err)
-		return nil, err
+		return nil, 0, err
 	}
-	return &users, nil
+	return &users, total, nil
 }
 
 func (s lowerThirdsService) GetUserIDsByOrg(ctx context.Context, orgID uuid.UUID) (*[]uuid.UUID, error) {
@@ -156,7 +303,8 @@ func (s lowerThirdsService) GetUserIDsByOrg(ctx context.Context, orgID uuid.UUID
 		  ON o.id = ou.org_id
 		  AND o.deleted_dt IS NULL
 		WHERE ou.org_id = ?
-		  AND ou.deleted_dt IS NULL`,
+		  AND ou.deleted_dt IS NULL
+		  AND `+notAPIKeyUser,
 		orgID)
 	if err != nil {
 		s.logger.Error(err)
@@ -165,11 +313,19 @@ func (s lowerThirdsService) GetUserIDsByOrg(ctx context.Context, orgID uuid.UUID
 	return &userIDs, nil
 }
 
-func (s lowerThirdsService) GetOrgsByUser(ctx context.Context, userID uuid.UUID) (*[]entities.Organization, error) {
+// GetOrgsByUser lists a page of the calling user's orgs by name, with the number on every page. DateFrom and DateTo filter on
+// when the org was made.
+func (s lowerThirdsService) GetOrgsByUser(ctx context.Context, userID uuid.UUID) (*[]entities.Organization, int, error) {
 	s.logger.Debug("GetOrgs for userID ", userID)
 
-	var orgs []entities.Organization
-	err := s.MySqlDB.Select(
+	_, err := s.authorizeUser(ctx, userID)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	orgs := []entities.Organization{}
+	total, err := s.selectPage(
+		ctx,
 		&orgs,
 		`SELECT o.*
 		FROM OrgUsers ou
@@ -181,29 +337,241 @@ func (s lowerThirdsService) GetOrgsByUser(ctx context.Context, userID uuid.UUID)
 		  AND o.deleted_dt IS NULL
 		WHERE ou.user_id = ?
 		  AND ou.deleted_dt IS NULL`,
-		userID)
+		"o.inserted_dt",
+		"o.name, o.id",
+		userID,
+	)
 	if err != nil {
-		s.logger.Error(err)
+		return nil, 0, err
+	}
+	return &orgs, total, nil
+}
+
+// GetMembershipsByUser lists the orgs the calling user belongs to by name, with the user's role in each
+func (s lowerThirdsService) GetMembershipsByUser(ctx context.Context, userID uuid.UUID) (*[]entities.Membership, error) {
+	s.logger.Debug("GetMembershipsByUser for userID ", userID)
+
+	_, err := s.authorizeUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	memberships := []entities.Membership{}
+	err = s.MySqlDB.SelectContext(
+		ctx,
+		&memberships,
+		`SELECT o.*, ou.role
+		FROM OrgUsers ou
+		INNER JOIN Organization o
+		  ON o.id = ou.org_id
+		  AND o.deleted_dt IS NULL
+		WHERE ou.user_id = ?
+		  AND ou.deleted_dt IS NULL
+		ORDER BY o.name, o.id`,
+		userID,
+	)
+	if err != nil {
+		s.logger.Error("GetMembershipsByUser Error", err)
+		return nil, err
+	}
+	return &memberships, nil
+}
+
+// orgIDsByUser is every org the user belongs to
+func (s lowerThirdsService) orgIDsByUser(ctx context.Context, userID uuid.UUID) ([]uuid.UUID, error) {
+	var orgIDs []uuid.UUID
+	err := s.MySqlDB.SelectContext(
+		ctx,
+		&orgIDs,
+		`SELECT o.id
+		FROM OrgUsers ou
+		INNER JOIN Users u
+		  ON u.id = ou.user_id
+		  AND u.deleted_dt IS NULL
+		INNER JOIN Organization o
+		  ON o.id = ou.org_id
+		  AND o.deleted_dt IS NULL
+		WHERE ou.user_id = ?
+		  AND ou.deleted_dt IS NULL`,
+		userID,
+	)
+	if err != nil {
+		s.logger.Error("orgIDsByUser Error", err)
 		return nil, err
 	}
-	return &orgs, nil
+	return orgIDs, nil
 }
 
 func (s lowerThirdsService) SetOrgsByUser(ctx context.Context, userID uuid.UUID, orgIDs []uuid.UUID) error {
 	s.logger.Debug("SetOrgsByUser for userID ", userID, ", orgIDs ", orgIDs)
 
-	err := s.DeleteOrgsByUser(ctx, userID)
+	existingOrgIDs, err := s.orgIDsByUser(ctx, userID)
 	if err != nil {
-		s.logger.Error("SetOrgsByUser delete error", err)
+		s.logger.Error("SetOrgsByUser existing orgs error", err)
 		return err
 	}
 
+	existingOrgMap := make(map[uuid.UUID]bool)
+	for _, orgID := range existingOrgIDs {
+		existingOrgMap[orgID] = true
+	}
+	newOrgMap := make(map[uuid.UUID]bool)
 	for _, orgID := range orgIDs {
-		err := s.CreateOrgUser(ctx, orgID, userID)
+		newOrgMap[orgID] = true
+	}
+
+	// Every org the user joins must be managed by the calling user, and so must every org they leave unless they're
+	// leaving it themselves
+	var added, removed []uuid.UUID
+	for orgID := range newOrgMap {
+		if !existingOrgMap[orgID] {
+			added = append(added, orgID)
+		}
+	}
+	for orgID := range existingOrgMap {
+		if !newOrgMap[orgID] {
+			removed = append(removed, orgID)
+		}
+	}
+	user, err := s.currentUser(ctx)
+	if err != nil {
+		return err
+	}
+	for _, orgID := range added {
+		_, err = s.authorizeOrg(ctx, orgID, entities.RoleOwner)
+		if err != nil {
+			return err
+		}
+	}
+	for _, orgID := range removed {
+		required := entities.RoleOwner
+		if userID == user.UserID {
+			required = entities.RoleViewer
+		}
+		_, err = s.authorizeOrg(ctx, orgID, required)
+		if err != nil {
+			return err
+		}
+	}
+
+	tx, err := s.MySqlDB.BeginTxx(ctx, nil)
+	if err != nil {
+		s.logger.Error("SetOrgsByUser Begin Error", err)
+		return err
+	}
+	defer tx.Rollback()
+
+	if len(added) > 0 || len(removed) > 0 {
+		err = s.rejectAPIKeyUsers(ctx, tx, []uuid.UUID{userID})
+		if err != nil {
+			return err
+		}
+	}
+	for _, orgID := range removed {
+		err := s.ensureAnotherOwner(ctx, tx, orgID, userID)
+		if err != nil {
+			return err
+		}
+	}
+
+	// Existing memberships keep their roles, new ones start as viewers
+	for _, orgID := range added {
+		err := s.createOrgUser(ctx, tx, user, orgID, userID, entities.RoleViewer)
 		if err != nil {
 			s.logger.Error("SetOrgsByUser error ", err)
 			return err
 		}
 	}
+	for _, orgID := range removed {
+		err := s.deleteOrgUser(ctx, tx, user, orgID, userID)
+		if err != nil {
+			s.logger.Error("SetOrgsByUser delete error", err)
+			return err
+		}
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		s.logger.Error("SetOrgsByUser Commit Error", err)
+		return err
+	}
 	return nil
 }
+
+func (s lowerThirdsService) SetOrgUserRole(ctx context.Context, orgID uuid.UUID, userID uuid.UUID, role entities.Role) error {
+	s.logger.Debug("SetOrgUserRole for orgID ", orgID, " userID ", userID, " role ", role)
+
+	if !role.Valid() {
+		return invalidRole(role)
+	}
+
+	user, err := s.authorizeOrg(ctx, orgID, entities.RoleOwner)
+	if err != nil {
+		return err
+	}
+
+	tx, err := s.MySqlDB.BeginTxx(ctx, nil)
+	if err != nil {
+		s.logger.Error("SetOrgUserRole Begin Error", err)
+		return err
+	}
+	defer tx.Rollback()
+
+	before, err := s.lockOrgUser(ctx, tx, orgID, userID)
+	if err != nil {
+		return err
+	}
+	if before == nil {
+		return apierrors.New(http.StatusNotFound, "MEMBER_NOT_FOUND", "Member not found",
+			"user %s isn't a member of organization %s", userID, orgID)
+	}
+	err = s.rejectAPIKeyUsers(ctx, tx, []uuid.UUID{userID})
+	if err != nil {
+		return err
+	}
+	if role != entities.RoleOwner {
+		err = s.ensureAnotherOwner(ctx, tx, orgID, userID)
+		if err != nil {
+			return err
+		}
+	}
+	result, err := tx.ExecContext(ctx, `
+		UPDATE OrgUsers
+		SET role = ?
+		WHERE org_id = ?
+		  AND user_id = ?
+		  AND deleted_dt IS NULL`,
+		role,
+		orgID,
+		userID,
+	)
+	if err != nil {
+		s.logger.Error("SetOrgUserRole error ", err)
+		return err
+	}
+	affectedRows, err := result.RowsAffected()
+	if err != nil {
+		s.logger.Error("SetOrgUserRole error getting affected rows", err)
+		return err
+	}
+	s.logger.Info("SetOrgUserRole affected rows: ", affectedRows)
+	after := *before
+	after.Role = role
+	err = s.audit(ctx, tx, user, orgID, entities.AuditMember, userID, before, after)
+	if err != nil {
+		return err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		s.logger.Error("SetOrgUserRole Commit Error", err)
+		return err
+	}
+	return nil
+}
+
+// invalidRole creates the API error returned for an unknown role name
+func invalidRole(role entities.Role) *apierrors.Error {
+	return apierrors.New(http.StatusBadRequest, "INVALID_ROLE", "Invalid role",
+		"role %q must be one of owner, editor, operator or viewer", role)
+}
@@ -3,22 +3,35 @@ package storage
 import (
 	"context"
 	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"lowerthirdsapi/internal/apierrors"
 	"lowerthirdsapi/internal/entities"
-	"lowerthirdsapi/internal/helpers"
+	"net/http"
 )
 
 func (s lowerThirdsService) CreateOrg(ctx context.Context, o *entities.Organization) error {
-	socialID := ctx.Value(helpers.SocialIDKey).(string)
-	s.logger.Debug("CreateOrg for socialID ", socialID)
-	user, err := s.GetUserBySocialID(ctx, socialID)
+	user, err := s.currentUser(ctx)
 	if err != nil {
-		s.logger.Error("User not found by socialID", err)
 		return err
 	}
 	s.logger.Debug("CreateOrg for userID ", user.UserID)
+	if _, ok := callerAPIKey(ctx); ok {
+		return forbidden("API keys can't create organizations")
+	}
+
+	tx, err := s.MySqlDB.BeginTxx(ctx, nil)
+	if err != nil {
+		s.logger.Error("CreateOrg Begin Error", err)
+		return err
+	}
+	defer tx.Rollback()
+
+	err = s.rejectAPIKeyUsers(ctx, tx, o.UserIDs)
+	if err != nil {
+		return err
+	}
 
-	// TODO: put some user-level security on this query
-	_, err = s.MySqlDB.ExecContext(
+	_, err = tx.ExecContext(
 		ctx,
 		`INSERT INTO Organization (id, name) VALUES (?, ?)`,
 		o.OrgID,
@@ -28,6 +41,10 @@ func (s lowerThirdsService) CreateOrg(ctx context.Context, o *entities.Organizat
 		s.logger.Error("CreateOrg Error", err)
 		return err
 	}
+	err = s.audit(ctx, tx, user, o.OrgID, entities.AuditOrg, o.OrgID, nil, o)
+	if err != nil {
+		return err
+	}
 
 	// check if user.UserID is already in o.UserIDs
 	var nu []uuid.UUID
@@ -42,21 +59,56 @@ func (s lowerThirdsService) CreateOrg(ctx context.Context, o *entities.Organizat
 		nu = append(nu, user.UserID)
 	}
 
+	// The creating user owns the org, everyone else joins as a viewer
+	err = s.createOrgUser(ctx, tx, user, o.OrgID, user.UserID, entities.RoleOwner)
+	if err != nil {
+		s.logger.Error("CreateOrg Owner Error", err)
+		return err
+	}
+
 	// After updating the org, we need to validate the user list
-	var ex []uuid.UUID
-	affectedRows, err := s.reconcileUsers(ctx, o.OrgID, ex, nu)
-	if err == nil {
-		s.logger.Info("CreateOrg affected users: ", affectedRows)
+	ex := []uuid.UUID{user.UserID}
+	affectedRows, err := s.reconcileUsers(ctx, tx, user, o.OrgID, ex, nu)
+	if err != nil {
+		return err
 	}
+	s.logger.Info("CreateOrg affected users: ", affectedRows)
 
+	err = tx.Commit()
+	if err != nil {
+		s.logger.Error("CreateOrg Commit Error", err)
+		return err
+	}
 	return nil
 }
 
 func (s lowerThirdsService) DeleteOrg(ctx context.Context, orgID uuid.UUID) error {
 	s.logger.Debug("DeleteOrg for orgID ", orgID)
 
-	// TODO: put some user level security on this query
-	result, err := s.MySqlDB.ExecContext(ctx, `
+	user, err := s.authorizeOrg(ctx, orgID, entities.RoleOwner)
+	if err != nil {
+		return err
+	}
+
+	// delete all users. API keys stay members, but stop working with the org.
+	ex, err := s.GetUserIDsByOrg(ctx, orgID)
+	if err != nil {
+		s.logger.Error("DeleteOrg Users Error", err)
+		return err
+	}
+
+	tx, err := s.MySqlDB.BeginTxx(ctx, nil)
+	if err != nil {
+		s.logger.Error("DeleteOrg Begin Error", err)
+		return err
+	}
+	defer tx.Rollback()
+
+	before, err := s.lockOrg(ctx, tx, orgID)
+	if err != nil {
+		return err
+	}
+	result, err := tx.ExecContext(ctx, `
 		UPDATE Organization 
 		SET deleted_dt = CURRENT_TIMESTAMP 
 		WHERE id = ?
@@ -71,36 +123,39 @@ func (s lowerThirdsService) DeleteOrg(ctx context.Context, orgID uuid.UUID) erro
 	if err == nil {
 		s.logger.Info("DeleteOrg affected rows: ", affectedRows)
 	}
-
-	// delete all users
-	ex, err := s.GetUserIDsByOrg(ctx, orgID)
+	err = s.audit(ctx, tx, user, orgID, entities.AuditOrg, orgID, before, nil)
 	if err != nil {
-		s.logger.Error("DeleteOrg Users Error", err)
 		return err
 	}
 
 	// After updating the org, we need to validate the user list
 	var nu []uuid.UUID
-	affectedRows, err = s.reconcileUsers(ctx, orgID, *ex, nu)
-	if err == nil {
-		s.logger.Info("DeleteOrg affected users: ", affectedRows)
+	affectedRows, err = s.reconcileUsers(ctx, tx, user, orgID, *ex, nu)
+	if err != nil {
+		return err
 	}
+	s.logger.Info("DeleteOrg affected users: ", affectedRows)
 
+	err = tx.Commit()
+	if err != nil {
+		s.logger.Error("DeleteOrg Commit Error", err)
+		return err
+	}
 	return nil
 }
 
-func (s lowerThirdsService) GetMeetingsByOrg(ctx context.Context, orgID uuid.UUID) (*[]entities.Meeting, error) {
-	socialID := ctx.Value(helpers.SocialIDKey).(string)
-	s.logger.Debug("GetMeetings for socialID ", socialID)
-	user, err := s.GetUserBySocialID(ctx, socialID)
+// GetMeetingsByOrg lists a page of an org's meetings by date, with the number on every page. DateFrom and DateTo
+// filter on the meeting date.
+func (s lowerThirdsService) GetMeetingsByOrg(ctx context.Context, orgID uuid.UUID) (*[]entities.Meeting, int, error) {
+	user, err := s.currentUser(ctx)
 	if err != nil {
-		s.logger.Error("User not found by socialID", err)
-		return nil, err
+		return nil, 0, err
 	}
 	s.logger.Debug("GetMeetings for userID ", user.UserID)
 
-	var meetings []entities.Meeting
-	err = s.MySqlDB.Select(
+	meetings := []entities.Meeting{}
+	total, err := s.selectPage(
+		ctx,
 		&meetings,
 		`SELECT m.*
 		  FROM OrgUsers ou
@@ -115,20 +170,21 @@ func (s lowerThirdsService) GetMeetingsByOrg(ctx context.Context, orgID uuid.UUI
 		  AND m.deleted_dt IS NULL
 		WHERE ou.user_id = ?
 		  AND ou.org_id = ?
-		  AND ou.deleted_dt IS NULL`, user.UserID, orgID)
+		  AND ou.deleted_dt IS NULL`,
+		"m.meeting_date",
+		"m.meeting_date, m.id",
+		user.UserID,
+		orgID,
+	)
 	if err != nil {
-		s.logger.Error(err)
-		return nil, err
+		return nil, 0, err
 	}
-	return &meetings, nil
+	return &meetings, total, nil
 }
 
 func (s lowerThirdsService) GetOrg(ctx context.Context, orgID uuid.UUID) (*entities.Organization, error) {
-	socialID := ctx.Value(helpers.SocialIDKey).(string)
-	s.logger.Debug("GetMeeting for socialID ", socialID, " orgID ", orgID)
-	user, err := s.GetUserBySocialID(ctx, socialID)
+	user, err := s.currentUser(ctx)
 	if err != nil {
-		s.logger.Error("User not found by socialID", err)
 		return nil, err
 	}
 	s.logger.Debug("GetMeeting for userID ", user.UserID, " orgID ", orgID)
@@ -164,18 +220,18 @@ func (s lowerThirdsService) GetOrg(ctx context.Context, orgID uuid.UUID) (*entit
 	return &org, nil
 }
 
-func (s lowerThirdsService) GetOrgs(ctx context.Context) (*[]entities.Organization, error) {
-	socialID := ctx.Value(helpers.SocialIDKey).(string)
-	s.logger.Debug("GetMeeting for socialID ", socialID)
-	user, err := s.GetUserBySocialID(ctx, socialID)
+// GetOrgs lists a page of the user's orgs by name, with the number on every page. DateFrom and DateTo filter on when
+// the org was made.
+func (s lowerThirdsService) GetOrgs(ctx context.Context) (*[]entities.Organization, int, error) {
+	user, err := s.currentUser(ctx)
 	if err != nil {
-		s.logger.Error("User not found by socialID", err)
-		return nil, err
+		return nil, 0, err
 	}
 	s.logger.Debug("GetMeeting for userID ", user.UserID)
 
 	var orgs []entities.Organization
-	err = s.MySqlDB.Select(
+	total, err := s.selectPage(
+		ctx,
 		&orgs,
 		`SELECT o.*
 		FROM OrgUsers ou
@@ -187,36 +243,69 @@ func (s lowerThirdsService) GetOrgs(ctx context.Context) (*[]entities.Organizati
 		  AND o.deleted_dt IS NULL
 		WHERE ou.user_id = ?
 		  AND ou.deleted_dt IS NULL`,
-		user.UserID)
+		"o.inserted_dt",
+		"o.name, o.id",
+		user.UserID,
+	)
 	if err != nil {
-		s.logger.Error(err)
-		return nil, err
+		return nil, 0, err
 	}
 
 	// assign all users to all orgs
 	OrgUsersMap, err := s.GetOrgUsersMap(ctx)
 	if err != nil {
 		s.logger.Error("GetOrgs Users Error", err)
-		return nil, err
+		return nil, 0, err
 	}
 
-	var returnOrgs []entities.Organization
+	returnOrgs := []entities.Organization{}
 	for _, org := range orgs {
 		org.UserIDs = OrgUsersMap[org.OrgID]
 		returnOrgs = append(returnOrgs, org)
 	}
 
-	return &returnOrgs, nil
+	return &returnOrgs, total, nil
 }
 
 func (s lowerThirdsService) UpdateOrg(ctx context.Context, orgID uuid.UUID, o *entities.Organization) error {
 	s.logger.Debug("UpdateOrg")
 
-	// TODO: put some user level security on this query
-	result, err := s.MySqlDB.ExecContext(
+	// An org's ID never changes, so the payload can only repeat it
+	if o.OrgID != uuid.Nil && o.OrgID != orgID {
+		return apierrors.New(http.StatusBadRequest, "ORG_ID_MISMATCH", "Org ID mismatch",
+			"the payload's org %s doesn't match organization %s", o.OrgID, orgID)
+	}
+
+	user, err := s.authorizeOrg(ctx, orgID, entities.RoleOwner)
+	if err != nil {
+		return err
+	}
+
+	// get existing users for the org, which leaves out its API keys so they stay members
+	existingUserIDs, err := s.GetUserIDsByOrg(ctx, orgID)
+	if err != nil {
+		s.logger.Error("GetUsersByOrg Error", err)
+		return err
+	}
+
+	tx, err := s.MySqlDB.BeginTxx(ctx, nil)
+	if err != nil {
+		s.logger.Error("UpdateOrg Begin Error", err)
+		return err
+	}
+	defer tx.Rollback()
+
+	before, err := s.lockOrg(ctx, tx, orgID)
+	if err != nil {
+		return err
+	}
+	err = s.rejectAPIKeyUsers(ctx, tx, o.UserIDs)
+	if err != nil {
+		return err
+	}
+	result, err := tx.ExecContext(
 		ctx,
-		`UPDATE Organization SET id = ?, name = ? WHERE id = ?`,
-		o.OrgID,
+		`UPDATE Organization SET name = ? WHERE id = ?`,
 		o.Name,
 		orgID,
 	)
@@ -228,24 +317,52 @@ func (s lowerThirdsService) UpdateOrg(ctx context.Context, orgID uuid.UUID, o *e
 	if err == nil {
 		s.logger.Info("UpdateOrg affected rows: ", affectedRows)
 	}
-
-	// get existing users for the org
-	existingUserIDs, err := s.GetUserIDsByOrg(ctx, orgID)
+	after, err := s.lockOrg(ctx, tx, orgID)
 	if err != nil {
-		s.logger.Error("GetUsersByOrg Error", err)
 		return err
 	}
+	err = s.audit(ctx, tx, user, orgID, entities.AuditOrg, orgID, before, after)
+	if err != nil {
+		return err
+	}
+
+	// The owner making the change always stays in the org
+	nu := []uuid.UUID{user.UserID}
+	for _, userID := range o.UserIDs {
+		if userID != user.UserID {
+			nu = append(nu, userID)
+		}
+	}
 
 	// After updating the org, we need to validate the user list
-	affectedRows, err = s.reconcileUsers(ctx, orgID, *existingUserIDs, o.UserIDs)
-	if err == nil {
-		s.logger.Info("UpdateOrg affected rows: ", affectedRows)
+	affectedRows, err = s.reconcileUsers(ctx, tx, user, orgID, *existingUserIDs, nu)
+	if err != nil {
+		return err
 	}
+	s.logger.Info("UpdateOrg affected rows: ", affectedRows)
 
+	err = tx.Commit()
+	if err != nil {
+		s.logger.Error("UpdateOrg Commit Error", err)
+		return err
+	}
 	return nil
 }
 
-func (s lowerThirdsService) reconcileUsers(ctx context.Context, orgID uuid.UUID, ex []uuid.UUID, nu []uuid.UUID) (int64, error) {
+// lockOrg loads an org in a transaction, locking it until the transaction ends
+func (s lowerThirdsService) lockOrg(ctx context.Context, tx *sqlx.Tx, orgID uuid.UUID) (*entities.Organization, error) {
+	var org entities.Organization
+	err := tx.GetContext(ctx, &org, `SELECT * FROM Organization WHERE id = ? AND deleted_dt IS NULL`+s.dialect.forUpdate(), orgID)
+	if err != nil {
+		s.logger.Error("lockOrg Error", err)
+		return nil, err
+	}
+	return &org, nil
+}
+
+// reconcileUsers makes the org's members nu instead of ex in the caller's transaction, recording that actor did it.
+// New members join as viewers.
+func (s lowerThirdsService) reconcileUsers(ctx context.Context, tx *sqlx.Tx, actor *entities.User, orgID uuid.UUID, ex []uuid.UUID, nu []uuid.UUID) (int64, error) {
 	// make a map of existing users
 	existingUserMap := make(map[uuid.UUID]bool)
 	for _, userID := range ex {
@@ -260,7 +377,7 @@ func (s lowerThirdsService) reconcileUsers(ctx context.Context, orgID uuid.UUID,
 
 		// If the user is in the new map but not in the existing map, add them
 		if _, exists := existingUserMap[userID]; !exists {
-			err := s.CreateOrgUser(ctx, orgID, userID)
+			err := s.createOrgUser(ctx, tx, actor, orgID, userID, entities.RoleViewer)
 			if err != nil {
 				s.logger.Error("CreateOrgUser Error", err)
 				return 0, err
@@ -272,7 +389,7 @@ func (s lowerThirdsService) reconcileUsers(ctx context.Context, orgID uuid.UUID,
 	// If the user is in the existing map but not in the new map, remove them
 	for _, userID := range ex {
 		if _, exists := newUserMap[userID]; !exists {
-			err := s.DeleteOrgUser(ctx, orgID, userID)
+			err := s.deleteOrgUser(ctx, tx, actor, orgID, userID)
 			if err != nil {
 				s.logger.Error("DeleteOrgUser Error", err)
 				return 0, err
@@ -1,8 +1,17 @@
 package storage
 
 import (
+	"context"
+	"errors"
+	"lowerthirdsapi/internal/apierrors"
+	"lowerthirdsapi/internal/entities"
+	"lowerthirdsapi/internal/helpers"
 	"lowerthirdsapi/internal/testutil"
+	"net/http"
 	"testing"
+	"time"
+
+	"github.com/google/uuid"
 )
 
 func TestCreateAndGetOrganization(t *testing.T) {
@@ -27,4 +36,124 @@ func TestCreateAndGetOrganization(t *testing.T) {
 	if retrievedOrg.Name != org.Name {
 		t.Errorf("Expected Name %v, got %v", org.Name, retrievedOrg.Name)
 	}
+
+	// Renaming keeps the org's ID, and a payload naming another org is rejected
+	err = service.UpdateOrg(testutil.TestCtx, org.OrgID, &entities.Organization{Name: "Renamed Org", UserIDs: org.UserIDs})
+	if err != nil {
+		t.Fatalf("UpdateOrg failed: %v", err)
+	}
+	retrievedOrg, err = service.GetOrg(testutil.TestCtx, org.OrgID)
+	if err != nil {
+		t.Fatalf("GetOrg failed after rename: %v", err)
+	}
+	if retrievedOrg.Name != "Renamed Org" {
+		t.Errorf("Expected Name %v, got %v", "Renamed Org", retrievedOrg.Name)
+	}
+	err = service.UpdateOrg(testutil.TestCtx, org.OrgID, &entities.Organization{OrgID: uuid.New(), Name: "Moved Org"})
+	var apiErr *apierrors.Error
+	if !errors.As(err, &apiErr) || apiErr.Status != http.StatusBadRequest {
+		t.Errorf("Expected bad request for a mismatched org ID, got %v", err)
+	}
+}
+
+func TestOrgRoles(t *testing.T) {
+	testutil.SetupTest(t)
+	defer testutil.TeardownTest()
+
+	service := New(testutil.TestDB, testutil.TestLogger)
+
+	// Setup test data
+	user, org, _ := testutil.CreateTestData(t, service)
+
+	// The creating user owns the org
+	members, err := service.GetOrgUsers(testutil.TestCtx, org.OrgID)
+	if err != nil {
+		t.Fatalf("GetOrgUsers failed: %v", err)
+	}
+	for _, member := range *members {
+		if member.UserID == user.UserID && member.Role != entities.RoleOwner {
+			t.Errorf("Expected creator to be %v, got %v", entities.RoleOwner, member.Role)
+		}
+	}
+
+	// Add a viewer
+	viewerID := uuid.New()
+	viewerSocialID := "viewer-social-id-" + viewerID.String()
+	_, err = testutil.TestDB.Exec(`
+		INSERT INTO Users (id, email, social_id)
+		VALUES (?, ?, ?)
+	`, viewerID, "viewer+"+viewerID.String()+"@example.com", viewerSocialID)
+	if err != nil {
+		t.Fatalf("Failed to create viewer: %v", err)
+	}
+	err = service.CreateOrgUser(testutil.TestCtx, org.OrgID, viewerID, entities.RoleViewer)
+	if err != nil {
+		t.Fatalf("CreateOrgUser failed: %v", err)
+	}
+
+	// Viewers can't create meetings
+	viewerCtx := context.WithValue(context.Background(), helpers.SocialIDKey, viewerSocialID)
+	meeting := &entities.Meeting{
+		MeetingID:   uuid.New(),
+		OrgID:       org.OrgID,
+		Meeting:     "Test Meeting",
+		MeetingDate: time.Now(),
+	}
+	err = service.CreateMeeting(viewerCtx, meeting)
+	var apiErr *apierrors.Error
+	if !errors.As(err, &apiErr) || apiErr.Status != http.StatusForbidden {
+		t.Errorf("Expected forbidden error for viewer, got %v", err)
+	}
+
+	// Promote the viewer to editor
+	err = service.SetOrgUserRole(testutil.TestCtx, org.OrgID, viewerID, entities.RoleEditor)
+	if err != nil {
+		t.Fatalf("SetOrgUserRole failed: %v", err)
+	}
+	err = service.CreateMeeting(viewerCtx, meeting)
+	if err != nil {
+		t.Errorf("CreateMeeting failed for editor: %v", err)
+	}
+
+	// Only members have roles to change
+	err = service.SetOrgUserRole(testutil.TestCtx, org.OrgID, uuid.New(), entities.RoleEditor)
+	if !errors.As(err, &apiErr) || apiErr.Status != http.StatusNotFound {
+		t.Errorf("Expected not found for a non-member, got %v", err)
+	}
+
+	// The last owner can't step down
+	err = service.SetOrgUserRole(testutil.TestCtx, org.OrgID, user.UserID, entities.RoleEditor)
+	if !errors.As(err, &apiErr) || apiErr.Status != http.StatusConflict {
+		t.Errorf("Expected conflict demoting the last owner, got %v", err)
+	}
+
+	// Users only list their own orgs
+	_, _, err = service.GetOrgsByUser(viewerCtx, user.UserID)
+	if !errors.As(err, &apiErr) || apiErr.Status != http.StatusForbidden {
+		t.Errorf("Expected forbidden listing another user's orgs, got %v", err)
+	}
+	orgs, total, err := service.GetOrgsByUser(viewerCtx, viewerID)
+	if err != nil {
+		t.Fatalf("GetOrgsByUser failed: %v", err)
+	}
+	if total != 1 || len(*orgs) != 1 || (*orgs)[0].OrgID != org.OrgID {
+		t.Errorf("Expected the viewer's one org, got %d of %+v", total, orgs)
+	}
+
+	// Members can leave an org themselves, but the last owner can't
+	err = service.SetOrgsByUser(testutil.TestCtx, user.UserID, nil)
+	if !errors.As(err, &apiErr) || apiErr.Status != http.StatusConflict {
+		t.Errorf("Expected conflict when the last owner leaves, got %v", err)
+	}
+	err = service.SetOrgsByUser(viewerCtx, viewerID, nil)
+	if err != nil {
+		t.Fatalf("SetOrgsByUser failed for a member leaving: %v", err)
+	}
+	_, total, err = service.GetOrgsByUser(viewerCtx, viewerID)
+	if err != nil {
+		t.Fatalf("GetOrgsByUser failed: %v", err)
+	}
+	if total != 0 {
+		t.Errorf("Expected the viewer to have left, got %d orgs", total)
+	}
 }
@@ -0,0 +1,293 @@
+package storage
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"encoding/base64"
+	"errors"
+	"github.com/google/uuid"
+	"lowerthirdsapi/internal/apierrors"
+	"lowerthirdsapi/internal/entities"
+	"lowerthirdsapi/internal/events"
+	"net/http"
+	"time"
+)
+
+// overlayTokenBytes is the amount of randomness in an overlay token
+const overlayTokenBytes = 24
+
+func (s lowerThirdsService) CreateOverlayToken(ctx context.Context, meetingID uuid.UUID) (*entities.OverlayToken, error) {
+	s.logger.Debug("CreateOverlayToken for meetingID ", meetingID)
+
+	user, err := s.authorizeMeeting(ctx, meetingID, entities.RoleOperator)
+	if err != nil {
+		return nil, err
+	}
+	orgID, err := s.meetingOrgID(ctx, meetingID)
+	if err != nil {
+		return nil, err
+	}
+
+	raw := make([]byte, overlayTokenBytes)
+	_, err = rand.Read(raw)
+	if err != nil {
+		s.logger.Error("CreateOverlayToken Error", err)
+		return nil, err
+	}
+	token := base64.RawURLEncoding.EncodeToString(raw)
+
+	tx, err := s.MySqlDB.BeginTxx(ctx, nil)
+	if err != nil {
+		s.logger.Error("CreateOverlayToken Begin Error", err)
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.ExecContext(
+		ctx,
+		`INSERT INTO OverlayTokens (token, meeting_id) VALUES (?, ?)`,
+		token,
+		meetingID,
+	)
+	if err != nil {
+		s.logger.Error("CreateOverlayToken Error", err)
+		return nil, err
+	}
+
+	var overlayToken entities.OverlayToken
+	err = tx.GetContext(ctx, &overlayToken, `SELECT * FROM OverlayTokens WHERE token = ?`, token)
+	if err != nil {
+		s.logger.Error("CreateOverlayToken Error", err)
+		return nil, err
+	}
+	err = s.audit(ctx, tx, user, orgID, entities.AuditOverlayToken, overlayTokenRef(token), nil, auditedOverlayToken(overlayToken))
+	if err != nil {
+		return nil, err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		s.logger.Error("CreateOverlayToken Commit Error", err)
+		return nil, err
+	}
+	return &overlayToken, nil
+}
+
+func (s lowerThirdsService) GetOverlayTokens(ctx context.Context, meetingID uuid.UUID) (*[]entities.OverlayToken, error) {
+	s.logger.Debug("GetOverlayTokens for meetingID ", meetingID)
+
+	_, err := s.authorizeMeeting(ctx, meetingID, entities.RoleOperator)
+	if err != nil {
+		return nil, err
+	}
+
+	overlayTokens := []entities.OverlayToken{}
+	err = s.MySqlDB.SelectContext(
+		ctx,
+		&overlayTokens,
+		`SELECT * FROM OverlayTokens
+		WHERE meeting_id = ?
+		  AND deleted_dt IS NULL
+		ORDER BY inserted_dt`,
+		meetingID,
+	)
+	if err != nil {
+		s.logger.Error("GetOverlayTokens Error", err)
+		return nil, err
+	}
+	return &overlayTokens, nil
+}
+
+func (s lowerThirdsService) DeleteOverlayToken(ctx context.Context, meetingID uuid.UUID, token string) error {
+	s.logger.Debug("DeleteOverlayToken for meetingID ", meetingID)
+
+	user, err := s.authorizeMeeting(ctx, meetingID, entities.RoleOperator)
+	if err != nil {
+		return err
+	}
+	orgID, err := s.meetingOrgID(ctx, meetingID)
+	if err != nil {
+		return err
+	}
+
+	tx, err := s.MySqlDB.BeginTxx(ctx, nil)
+	if err != nil {
+		s.logger.Error("DeleteOverlayToken Begin Error", err)
+		return err
+	}
+	defer tx.Rollback()
+
+	var before entities.OverlayToken
+	err = tx.GetContext(
+		ctx,
+		&before,
+		`SELECT * FROM OverlayTokens WHERE token = ? AND meeting_id = ? AND deleted_dt IS NULL`+s.dialect.forUpdate(),
+		token,
+		meetingID,
+	)
+	if errors.Is(err, sql.ErrNoRows) {
+		// Nothing to revoke
+		return nil
+	}
+	if err != nil {
+		s.logger.Error("DeleteOverlayToken Select Error", err)
+		return err
+	}
+	result, err := tx.ExecContext(ctx, `
+		UPDATE OverlayTokens
+		SET deleted_dt = CURRENT_TIMESTAMP
+		WHERE token = ?
+		  AND meeting_id = ?
+		  AND deleted_dt IS NULL`,
+		token,
+		meetingID,
+	)
+	if err != nil {
+		s.logger.Error("DeleteOverlayToken Error", err)
+		return err
+	}
+	affectedRows, err := result.RowsAffected()
+	if err == nil {
+		s.logger.Info("DeleteOverlayToken affected rows: ", affectedRows)
+	}
+	err = s.audit(ctx, tx, user, orgID, entities.AuditOverlayToken, overlayTokenRef(token), auditedOverlayToken(before), nil)
+	if err != nil {
+		return err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		s.logger.Error("DeleteOverlayToken Commit Error", err)
+		return err
+	}
+	return nil
+}
+
+// overlayTokenRef is the start of an overlay token, enough to tell tokens apart in the audit log without being able
+// to use them
+func overlayTokenRef(token string) string {
+	return token[:min(len(token), 8)]
+}
+
+// auditedOverlayToken is an overlay token as it's recorded in the audit log, with only the start of the token
+func auditedOverlayToken(t entities.OverlayToken) entities.OverlayToken {
+	t.Token = overlayTokenRef(t.Token)
+	return t
+}
+
+// GetOverlay loads what the meeting of an overlay token has on air. The token is the only authorization.
+func (s lowerThirdsService) GetOverlay(ctx context.Context, token string) (*entities.Overlay, error) {
+	s.logger.Debug("GetOverlay")
+
+	meetingID, err := s.overlayMeetingID(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+
+	var overlay entities.Overlay
+	err = s.MySqlDB.GetContext(ctx, &overlay.Meeting, `SELECT * FROM Meetings WHERE id = ? AND deleted_dt IS NULL`, meetingID)
+	if err != nil {
+		s.logger.Error("GetOverlay Error", err)
+		return nil, err
+	}
+
+	liveState, err := s.getLiveState(ctx, meetingID)
+	if err != nil {
+		return nil, err
+	}
+	overlay.LiveState = *liveState
+
+	theme, err := s.meetingTheme(ctx, meetingID)
+	if err != nil {
+		return nil, err
+	}
+	overlay.Theme = *theme
+	if !liveState.ProgramItemID.Valid || !liveState.Visible {
+		return &overlay, nil
+	}
+
+	overlay.Item, err = s.getMeetingItem(ctx, meetingID, liveState.ProgramItemID.UUID)
+	if err != nil {
+		return nil, err
+	}
+	if overlay.Item == nil {
+		return &overlay, nil
+	}
+	overlay.Timer, err = s.itemTimer(ctx, s.MySqlDB, overlay.Item, &overlay.Meeting, false)
+	if err != nil {
+		return nil, err
+	}
+	overlay.Timer.Read(time.Now().UTC())
+	if lyricsItem, ok := overlay.Item.(*entities.LyricsItem); ok && lyricsItem.HymnID != "" {
+		slides, err := s.lyricsSlides(ctx, s.MySqlDB, lyricsItem)
+		if err != nil {
+			return nil, err
+		}
+		if i := liveState.VerseIndex; i >= 0 && i < len(*slides) {
+			overlay.Slide = &(*slides)[i]
+		}
+	}
+	return &overlay, nil
+}
+
+// SubscribeOverlayEvents starts receiving the changes to an overlay token's meeting
+func (s lowerThirdsService) SubscribeOverlayEvents(ctx context.Context, token string, lastEventID uint64) (*events.Subscription, error) {
+	s.logger.Debug("SubscribeOverlayEvents lastEventID ", lastEventID)
+
+	meetingID, err := s.overlayMeetingID(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+	if s.events == nil {
+		return nil, errors.New("meeting events are not enabled")
+	}
+	return s.events.Subscribe(meetingID, lastEventID), nil
+}
+
+// overlayMeetingID finds the meeting an overlay token was issued for
+func (s lowerThirdsService) overlayMeetingID(ctx context.Context, token string) (uuid.UUID, error) {
+	var meetingID uuid.UUID
+	err := s.MySqlDB.GetContext(
+		ctx,
+		&meetingID,
+		`SELECT t.meeting_id
+		FROM OverlayTokens t
+		INNER JOIN Meetings m
+		  ON m.id = t.meeting_id
+		  AND m.deleted_dt IS NULL
+		WHERE t.token = ?
+		  AND t.deleted_dt IS NULL`,
+		token,
+	)
+	if errors.Is(err, sql.ErrNoRows) {
+		return uuid.Nil, apierrors.New(http.StatusNotFound, "OVERLAY_NOT_FOUND", "Overlay not found",
+			"the overlay token is unknown or has been revoked")
+	}
+	if err != nil {
+		s.logger.Error("overlayMeetingID Error", err)
+		return uuid.Nil, err
+	}
+	return meetingID, nil
+}
+
+// getMeetingItem loads an item of any type from a meeting, or nil if it's been deleted. GetOverlay checks the
+// overlay token before this is called.
+func (s lowerThirdsService) getMeetingItem(ctx context.Context, meetingID uuid.UUID, itemID uuid.UUID) (entities.Item, error) {
+	var row agendaItemRow
+	err := s.MySqlDB.GetContext(
+		ctx,
+		&row,
+		`SELECT `+agendaItemColumns+` FROM AgendaItems i WHERE i.id = ? AND i.meeting_id = ? AND i.deleted_dt IS NULL`,
+		itemID,
+		meetingID,
+	)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		s.logger.Error("getMeetingItem Error", err)
+		return nil, err
+	}
+	return row.item()
+}
@@ -0,0 +1,82 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"lowerthirdsapi/internal/apierrors"
+	"lowerthirdsapi/internal/entities"
+	"lowerthirdsapi/internal/testutil"
+	"net/http"
+	"testing"
+
+	"github.com/google/uuid"
+	"gopkg.in/guregu/null.v4"
+)
+
+func TestOverlay(t *testing.T) {
+	testutil.SetupTest(t)
+	defer testutil.TeardownTest()
+
+	service := New(testutil.TestDB, testutil.TestLogger)
+	_, _, meeting := testutil.CreateTestData(t, service)
+
+	speakerItem := &entities.SpeakerItem{
+		SpeakerItemID: uuid.New(),
+		MeetingID:     meeting.MeetingID,
+		ItemType:      "speaker",
+		ItemOrder:     1,
+		MeetingRole:   "Test Role",
+		SpeakerName:   "Test Speaker",
+		Title:         null.StringFrom("Test Title"),
+	}
+	err := service.CreateItem(testutil.TestCtx, speakerItem)
+	if err != nil {
+		t.Fatalf("CreateItem failed: %v", err)
+	}
+
+	overlayToken, err := service.CreateOverlayToken(testutil.TestCtx, meeting.MeetingID)
+	if err != nil {
+		t.Fatalf("CreateOverlayToken failed: %v", err)
+	}
+	overlayTokens, err := service.GetOverlayTokens(testutil.TestCtx, meeting.MeetingID)
+	if err != nil {
+		t.Fatalf("GetOverlayTokens failed: %v", err)
+	}
+	if len(*overlayTokens) != 1 || (*overlayTokens)[0].Token != overlayToken.Token {
+		t.Errorf("Expected the new token to be listed, got %+v", overlayTokens)
+	}
+
+	// Overlays are read with the token alone
+	publicCtx := context.Background()
+	overlay, err := service.GetOverlay(publicCtx, overlayToken.Token)
+	if err != nil {
+		t.Fatalf("GetOverlay failed: %v", err)
+	}
+	if overlay.Item != nil || overlay.Meeting.MeetingID != meeting.MeetingID {
+		t.Errorf("Expected nothing on air, got %+v", overlay)
+	}
+
+	_, err = service.UpdateLiveState(testutil.TestCtx, meeting.MeetingID, entities.LiveActionTake, speakerItem.SpeakerItemID)
+	if err != nil {
+		t.Fatalf("UpdateLiveState failed: %v", err)
+	}
+	overlay, err = service.GetOverlay(publicCtx, overlayToken.Token)
+	if err != nil {
+		t.Fatalf("GetOverlay failed: %v", err)
+	}
+	onAir, ok := overlay.Item.(*entities.SpeakerItem)
+	if !ok || onAir.SpeakerName != "Test Speaker" {
+		t.Errorf("Expected the speaker on air, got %+v", overlay.Item)
+	}
+
+	// Revoked tokens stop working
+	err = service.DeleteOverlayToken(testutil.TestCtx, meeting.MeetingID, overlayToken.Token)
+	if err != nil {
+		t.Fatalf("DeleteOverlayToken failed: %v", err)
+	}
+	_, err = service.GetOverlay(publicCtx, overlayToken.Token)
+	var apiErr *apierrors.Error
+	if !errors.As(err, &apiErr) || apiErr.Status != http.StatusNotFound {
+		t.Errorf("Expected not found for a revoked token, got %v", err)
+	}
+}
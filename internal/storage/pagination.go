@@ -0,0 +1,44 @@
+package storage
+
+import (
+	"context"
+	"lowerthirdsapi/internal/apierrors"
+	"lowerthirdsapi/internal/helpers"
+	"net/http"
+)
+
+// selectPage runs a list query for one page of results with the request's query parameters, and returns how many
+// rows match across all pages. query is a SELECT with a WHERE clause and no ORDER BY; the date range from DateFrom
+// to DateTo, both inclusive, is applied to dateColumn unless it's empty, and the page is taken in orderBy order.
+func (s lowerThirdsService) selectPage(ctx context.Context, dest interface{}, query string, dateColumn string, orderBy string, args ...interface{}) (int, error) {
+	qp := helpers.GetQueryParams(ctx)
+	offset, ok := qp.Offset()
+	if !ok {
+		return 0, apierrors.New(http.StatusBadRequest, "INVALID_PAGE", "Invalid page",
+			"page %d of %d rows each starts past the most rows that can be skipped", qp.Page, qp.PageSize)
+	}
+
+	if dateColumn != "" && qp.DateFrom != nil {
+		query += ` AND ` + dateColumn + ` >= ?`
+		args = append(args, qp.DateFrom.UTC())
+	}
+	if dateColumn != "" && qp.DateTo != nil {
+		query += ` AND ` + dateColumn + ` < ?`
+		args = append(args, qp.DateTo.AddDate(0, 0, 1).UTC())
+	}
+
+	var total int
+	err := s.MySqlDB.GetContext(ctx, &total, `SELECT COUNT(*) FROM (`+query+`) matching`, args...)
+	if err != nil {
+		s.logger.Error("selectPage Count Error", err)
+		return 0, err
+	}
+
+	args = append(args, qp.PageSize, offset)
+	err = s.MySqlDB.SelectContext(ctx, dest, query+` ORDER BY `+orderBy+` LIMIT ? OFFSET ?`, args...)
+	if err != nil {
+		s.logger.Error("selectPage Error", err)
+		return 0, err
+	}
+	return total, nil
+}
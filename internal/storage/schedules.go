@@ -0,0 +1,445 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"lowerthirdsapi/internal/apierrors"
+	"lowerthirdsapi/internal/entities"
+	"net/http"
+	"time"
+)
+
+// invalidSchedule creates the API error returned for a schedule with missing fields or a bad rule
+func invalidSchedule(err error) *apierrors.Error {
+	return apierrors.New(http.StatusBadRequest, "INVALID_SCHEDULE", "Invalid schedule", "%s", err.Error())
+}
+
+// scheduleNotFound creates the API error returned for a schedule that isn't in the org
+func scheduleNotFound(scheduleID uuid.UUID) *apierrors.Error {
+	return apierrors.New(http.StatusNotFound, "SCHEDULE_NOT_FOUND", "Schedule not found",
+		"The org has no schedule %s.", scheduleID)
+}
+
+// CreateSchedule saves a new recurring schedule for the org. Its template has to be one of the org's.
+func (s lowerThirdsService) CreateSchedule(ctx context.Context, orgID uuid.UUID, sc *entities.Schedule) error {
+	s.logger.Debug("CreateSchedule for orgID ", orgID)
+
+	user, err := s.authorizeOrg(ctx, orgID, entities.RoleEditor)
+	if err != nil {
+		return err
+	}
+	if err := sc.Validate(); err != nil {
+		return invalidSchedule(err)
+	}
+	_, err = s.getTemplate(ctx, orgID, sc.TemplateID)
+	if err != nil {
+		return err
+	}
+	if sc.ScheduleID == uuid.Nil {
+		sc.ScheduleID = uuid.New()
+	}
+	sc.OrgID = orgID
+
+	tx, err := s.MySqlDB.BeginTxx(ctx, nil)
+	if err != nil {
+		s.logger.Error("CreateSchedule Begin Error", err)
+		return err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.ExecContext(
+		ctx,
+		`INSERT INTO Schedules (
+			id, org_id, template_id, name, rrule, time_zone, start_dt, exceptions, lead_days
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		sc.ScheduleID,
+		sc.OrgID,
+		sc.TemplateID,
+		sc.Name,
+		sc.RRule,
+		sc.TimeZone,
+		sc.StartDT,
+		sc.Exceptions,
+		sc.LeadDays,
+	)
+	if err != nil {
+		s.logger.Error("CreateSchedule Error", err)
+		return err
+	}
+	err = s.audit(ctx, tx, user, orgID, entities.AuditSchedule, sc.ScheduleID, nil, sc)
+	if err != nil {
+		return err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		s.logger.Error("CreateSchedule Commit Error", err)
+		return err
+	}
+	return nil
+}
+
+// DeleteSchedule stops one of the org's schedules. Meetings it already made are kept.
+func (s lowerThirdsService) DeleteSchedule(ctx context.Context, orgID uuid.UUID, scheduleID uuid.UUID) error {
+	s.logger.Debug("DeleteSchedule for orgID ", orgID, " scheduleID ", scheduleID)
+
+	user, err := s.authorizeOrg(ctx, orgID, entities.RoleEditor)
+	if err != nil {
+		return err
+	}
+
+	tx, err := s.MySqlDB.BeginTxx(ctx, nil)
+	if err != nil {
+		s.logger.Error("DeleteSchedule Begin Error", err)
+		return err
+	}
+	defer tx.Rollback()
+
+	before, err := s.lockSchedule(ctx, tx, orgID, scheduleID)
+	if err != nil {
+		return err
+	}
+	result, err := tx.ExecContext(ctx, `
+		UPDATE Schedules
+		SET deleted_dt = CURRENT_TIMESTAMP
+		WHERE id = ?
+		  AND org_id = ?
+		  AND deleted_dt IS NULL`,
+		scheduleID,
+		orgID,
+	)
+	if err != nil {
+		s.logger.Error("DeleteSchedule error ", err)
+		return err
+	}
+	affectedRows, err := result.RowsAffected()
+	if err == nil {
+		s.logger.Info("DeleteSchedule affected rows: ", affectedRows)
+	}
+	err = s.audit(ctx, tx, user, orgID, entities.AuditSchedule, scheduleID, before, nil)
+	if err != nil {
+		return err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		s.logger.Error("DeleteSchedule Commit Error", err)
+		return err
+	}
+	return nil
+}
+
+// GetSchedule loads one of the org's schedules
+func (s lowerThirdsService) GetSchedule(ctx context.Context, orgID uuid.UUID, scheduleID uuid.UUID) (*entities.Schedule, error) {
+	s.logger.Debug("GetSchedule for orgID ", orgID, " scheduleID ", scheduleID)
+
+	_, err := s.authorizeOrg(ctx, orgID, entities.RoleViewer)
+	if err != nil {
+		return nil, err
+	}
+	return s.getSchedule(ctx, orgID, scheduleID)
+}
+
+// GetSchedulesByOrg loads the org's schedules by name
+func (s lowerThirdsService) GetSchedulesByOrg(ctx context.Context, orgID uuid.UUID) (*[]entities.Schedule, error) {
+	s.logger.Debug("GetSchedulesByOrg for orgID ", orgID)
+
+	_, err := s.authorizeOrg(ctx, orgID, entities.RoleViewer)
+	if err != nil {
+		return nil, err
+	}
+
+	schedules := []entities.Schedule{}
+	err = s.MySqlDB.SelectContext(
+		ctx,
+		&schedules,
+		`SELECT * FROM Schedules WHERE org_id = ? AND deleted_dt IS NULL ORDER BY name`,
+		orgID,
+	)
+	if err != nil {
+		s.logger.Error("GetSchedulesByOrg Error", err)
+		return nil, err
+	}
+	return &schedules, nil
+}
+
+// UpdateSchedule replaces one of the org's schedules. Meetings already made aren't changed; later ones follow the
+// new rule.
+func (s lowerThirdsService) UpdateSchedule(ctx context.Context, orgID uuid.UUID, scheduleID uuid.UUID, sc *entities.Schedule) error {
+	s.logger.Debug("UpdateSchedule for orgID ", orgID, " scheduleID ", scheduleID)
+
+	user, err := s.authorizeOrg(ctx, orgID, entities.RoleEditor)
+	if err != nil {
+		return err
+	}
+	if err := sc.Validate(); err != nil {
+		return invalidSchedule(err)
+	}
+	sc.ScheduleID = scheduleID
+	sc.OrgID = orgID
+
+	_, err = s.getTemplate(ctx, orgID, sc.TemplateID)
+	if err != nil {
+		return err
+	}
+
+	tx, err := s.MySqlDB.BeginTxx(ctx, nil)
+	if err != nil {
+		s.logger.Error("UpdateSchedule Begin Error", err)
+		return err
+	}
+	defer tx.Rollback()
+
+	before, err := s.lockSchedule(ctx, tx, orgID, scheduleID)
+	if err != nil {
+		return err
+	}
+	result, err := tx.ExecContext(
+		ctx,
+		`UPDATE Schedules SET
+		  template_id = ?,
+		  name = ?,
+		  rrule = ?,
+		  time_zone = ?,
+		  start_dt = ?,
+		  exceptions = ?,
+		  lead_days = ?
+		WHERE id = ?
+		  AND org_id = ?
+		  AND deleted_dt IS NULL`,
+		sc.TemplateID,
+		sc.Name,
+		sc.RRule,
+		sc.TimeZone,
+		sc.StartDT,
+		sc.Exceptions,
+		sc.LeadDays,
+		scheduleID,
+		orgID,
+	)
+	if err != nil {
+		s.logger.Error("UpdateSchedule Error", err)
+		return err
+	}
+	affectedRows, err := result.RowsAffected()
+	if err == nil {
+		s.logger.Info("UpdateSchedule affected rows: ", affectedRows)
+	}
+	after, err := s.lockSchedule(ctx, tx, orgID, scheduleID)
+	if err != nil {
+		return err
+	}
+	err = s.audit(ctx, tx, user, orgID, entities.AuditSchedule, scheduleID, before, after)
+	if err != nil {
+		return err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		s.logger.Error("UpdateSchedule Commit Error", err)
+		return err
+	}
+	return nil
+}
+
+// GenerateScheduledMeetings makes the meetings every schedule has coming up between now and its lead time, and
+// returns how many it made. It runs in the background without a user, so it doesn't authorize anything.
+//
+// Each occurrence is recorded once it's handled, so a meeting that's later moved or deleted isn't made again. An
+// occurrence is also skipped when the org already has a meeting at that time, for example one made by hand.
+// A failing schedule is logged and skipped so it doesn't hold up the others.
+func (s lowerThirdsService) GenerateScheduledMeetings(ctx context.Context, now time.Time) (int, error) {
+	s.logger.Debug("GenerateScheduledMeetings at ", now)
+
+	var schedules []entities.Schedule
+	err := s.MySqlDB.SelectContext(
+		ctx,
+		&schedules,
+		`SELECT sc.*
+		FROM Schedules sc
+		INNER JOIN Organization o
+		  ON o.id = sc.org_id
+		  AND o.deleted_dt IS NULL
+		WHERE sc.deleted_dt IS NULL`,
+	)
+	if err != nil {
+		s.logger.Error("GenerateScheduledMeetings Error", err)
+		return 0, err
+	}
+
+	var created int
+	var errs []error
+	for _, schedule := range schedules {
+		n, err := s.generateScheduleMeetings(ctx, schedule, now)
+		created += n
+		if err != nil {
+			s.logger.Error("GenerateScheduledMeetings schedule ", schedule.ScheduleID, " Error ", err)
+			errs = append(errs, err)
+		}
+	}
+	s.logger.Info("GenerateScheduledMeetings created meetings: ", created)
+	return created, errors.Join(errs...)
+}
+
+// generateScheduleMeetings makes one schedule's upcoming meetings
+func (s lowerThirdsService) generateScheduleMeetings(ctx context.Context, schedule entities.Schedule, now time.Time) (int, error) {
+	occurrences, err := schedule.Occurrences(now, now.AddDate(0, 0, schedule.LeadDays))
+	if err != nil || len(occurrences) == 0 {
+		return 0, err
+	}
+
+	var handled []time.Time
+	err = s.MySqlDB.SelectContext(
+		ctx,
+		&handled,
+		`SELECT occurrence_dt FROM ScheduledMeetings WHERE schedule_id = ? AND occurrence_dt >= ?`,
+		schedule.ScheduleID,
+		occurrences[0],
+	)
+	if err != nil {
+		return 0, err
+	}
+	done := make(map[int64]bool, len(handled))
+	for _, t := range handled {
+		done[t.Unix()] = true
+	}
+
+	var template *entities.MeetingTemplate
+	var created int
+	for _, occurrence := range occurrences {
+		if done[occurrence.Unix()] {
+			continue
+		}
+
+		// A meeting the org already has at this time stands in for the scheduled one
+		var existingID uuid.UUID
+		err = s.MySqlDB.GetContext(
+			ctx,
+			&existingID,
+			`SELECT id FROM Meetings WHERE org_id = ? AND meeting_date = ? AND deleted_dt IS NULL LIMIT 1`,
+			schedule.OrgID,
+			occurrence.UTC(),
+		)
+		if err == nil {
+			err = s.recordOccurrence(ctx, s.MySqlDB, schedule.ScheduleID, occurrence, existingID)
+			if err != nil && !IsDuplicate(err) {
+				return created, err
+			}
+			continue
+		}
+		if !errors.Is(err, sql.ErrNoRows) {
+			return created, err
+		}
+
+		if template == nil {
+			template, err = s.getTemplate(ctx, schedule.OrgID, schedule.TemplateID)
+			var apiErr *apierrors.Error
+			if errors.As(err, &apiErr) && apiErr.Status == http.StatusNotFound {
+				s.logger.Warn("generateScheduleMeetings template ", schedule.TemplateID, " of schedule ",
+					schedule.ScheduleID, " was deleted")
+				return created, nil
+			}
+			if err != nil {
+				return created, err
+			}
+		}
+		ok, err := s.createScheduledMeeting(ctx, schedule, template, occurrence)
+		if err != nil {
+			return created, err
+		}
+		if ok {
+			created++
+		}
+	}
+	return created, nil
+}
+
+// createScheduledMeeting makes the meeting for one occurrence and records it in the same transaction. It reports
+// false when another server got there first.
+func (s lowerThirdsService) createScheduledMeeting(ctx context.Context, schedule entities.Schedule, template *entities.MeetingTemplate, occurrence time.Time) (bool, error) {
+	tx, err := s.MySqlDB.BeginTxx(ctx, nil)
+	if err != nil {
+		s.logger.Error("createScheduledMeeting Begin Error", err)
+		return false, err
+	}
+	defer tx.Rollback()
+
+	meeting, err := s.insertTemplateMeeting(ctx, tx, template, occurrence)
+	if err != nil {
+		return false, err
+	}
+	err = s.recordOccurrence(ctx, tx, schedule.ScheduleID, occurrence, meeting.MeetingID)
+	if IsDuplicate(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	err = s.audit(ctx, tx, nil, schedule.OrgID, entities.AuditMeeting, meeting.MeetingID, nil, meeting)
+	if err != nil {
+		return false, err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		s.logger.Error("createScheduledMeeting Commit Error", err)
+		return false, err
+	}
+	s.logger.Info("createScheduledMeeting meeting ", meeting.MeetingID, " for schedule ", schedule.ScheduleID, " at ", occurrence)
+	return true, nil
+}
+
+// recordOccurrence marks one of a schedule's occurrences as handled by the meeting
+func (s lowerThirdsService) recordOccurrence(ctx context.Context, q sqlx.ExecerContext, scheduleID uuid.UUID, occurrence time.Time, meetingID uuid.UUID) error {
+	_, err := q.ExecContext(
+		ctx,
+		`INSERT INTO ScheduledMeetings (schedule_id, occurrence_dt, meeting_id) VALUES (?, ?, ?)`,
+		scheduleID,
+		occurrence.UTC(),
+		meetingID,
+	)
+	return err
+}
+
+// getSchedule loads a schedule without checking the caller
+func (s lowerThirdsService) getSchedule(ctx context.Context, orgID uuid.UUID, scheduleID uuid.UUID) (*entities.Schedule, error) {
+	var schedule entities.Schedule
+	err := s.MySqlDB.GetContext(
+		ctx,
+		&schedule,
+		`SELECT * FROM Schedules WHERE id = ? AND org_id = ? AND deleted_dt IS NULL`,
+		scheduleID,
+		orgID,
+	)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, scheduleNotFound(scheduleID)
+	}
+	if err != nil {
+		s.logger.Error("getSchedule Error", err)
+		return nil, err
+	}
+	return &schedule, nil
+}
+
+// lockSchedule loads one of the org's schedules in a transaction, locking it until the transaction ends
+func (s lowerThirdsService) lockSchedule(ctx context.Context, tx *sqlx.Tx, orgID uuid.UUID, scheduleID uuid.UUID) (*entities.Schedule, error) {
+	var schedule entities.Schedule
+	err := tx.GetContext(
+		ctx,
+		&schedule,
+		`SELECT * FROM Schedules WHERE id = ? AND org_id = ? AND deleted_dt IS NULL`+s.dialect.forUpdate(),
+		scheduleID,
+		orgID,
+	)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, scheduleNotFound(scheduleID)
+	}
+	if err != nil {
+		s.logger.Error("lockSchedule Error", err)
+		return nil, err
+	}
+	return &schedule, nil
+}
@@ -0,0 +1,129 @@
+package storage
+
+import (
+	"errors"
+	"lowerthirdsapi/internal/apierrors"
+	"lowerthirdsapi/internal/entities"
+	"lowerthirdsapi/internal/testutil"
+	"net/http"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestSchedules(t *testing.T) {
+	testutil.SetupTest(t)
+	defer testutil.TeardownTest()
+
+	service := New(testutil.TestDB, testutil.TestLogger)
+	_, org, _ := testutil.CreateTestData(t, service)
+
+	template := &entities.MeetingTemplate{Name: "Sacrament Meeting", Meeting: "Test Meeting"}
+	err := service.CreateTemplate(testutil.TestCtx, org.OrgID, template)
+	if err != nil {
+		t.Fatalf("CreateTemplate failed: %v", err)
+	}
+
+	schedule := &entities.Schedule{
+		TemplateID: template.TemplateID,
+		Name:       "Sundays",
+		RRule:      "FREQ=WEEKLY;BYDAY=SU",
+		TimeZone:   "America/Denver",
+		StartDT:    time.Date(2030, 1, 6, 9, 0, 0, 0, time.UTC),
+		Exceptions: entities.ScheduleDates{"2030-01-27"},
+		LeadDays:   28,
+	}
+	err = service.CreateSchedule(testutil.TestCtx, org.OrgID, schedule)
+	if err != nil {
+		t.Fatalf("CreateSchedule failed: %v", err)
+	}
+
+	var apiErr *apierrors.Error
+	err = service.CreateSchedule(testutil.TestCtx, org.OrgID, &entities.Schedule{TemplateID: template.TemplateID,
+		Name: "Bad", RRule: "FREQ=SOMETIMES", StartDT: schedule.StartDT})
+	if !errors.As(err, &apiErr) || apiErr.Code != "INVALID_SCHEDULE" {
+		t.Errorf("Expected an invalid schedule error, got %v", err)
+	}
+	err = service.CreateSchedule(testutil.TestCtx, org.OrgID, &entities.Schedule{TemplateID: uuid.New(),
+		Name: "Bad", RRule: "FREQ=WEEKLY", StartDT: schedule.StartDT})
+	if !errors.As(err, &apiErr) || apiErr.Code != "TEMPLATE_NOT_FOUND" {
+		t.Errorf("Expected template not found, got %v", err)
+	}
+
+	schedules, err := service.GetSchedulesByOrg(testutil.TestCtx, org.OrgID)
+	if err != nil {
+		t.Fatalf("GetSchedulesByOrg failed: %v", err)
+	}
+	if len(*schedules) != 1 || (*schedules)[0].StartDT.Hour() != 9 || len((*schedules)[0].Exceptions) != 1 {
+		t.Errorf("Expected the schedule, got %+v", schedules)
+	}
+
+	// A meeting made by hand on the 13th stands in for that Sunday
+	handMade := &entities.Meeting{MeetingID: uuid.New(), OrgID: org.OrgID, Meeting: "Test Meeting",
+		MeetingDate: time.Date(2030, 1, 13, 16, 0, 0, 0, time.UTC)}
+	err = service.CreateMeeting(testutil.TestCtx, handMade)
+	if err != nil {
+		t.Fatalf("CreateMeeting failed: %v", err)
+	}
+
+	now := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+	created, err := service.GenerateScheduledMeetings(testutil.TestCtx, now)
+	if err != nil {
+		t.Fatalf("GenerateScheduledMeetings failed: %v", err)
+	}
+	if created != 2 {
+		t.Errorf("Expected meetings on the 6th and 20th, got %d", created)
+	}
+	generated := scheduledMeetings(t, service, org.OrgID, now)
+	if len(generated) != 3 || !generated[0].MeetingDate.Equal(time.Date(2030, 1, 6, 16, 0, 0, 0, time.UTC)) {
+		t.Errorf("Expected 3 meetings in January, got %+v", generated)
+	}
+
+	// Running again, or after a generated meeting is deleted, doesn't duplicate anything
+	err = service.DeleteMeeting(testutil.TestCtx, generated[0].MeetingID)
+	if err != nil {
+		t.Fatalf("DeleteMeeting failed: %v", err)
+	}
+	created, err = service.GenerateScheduledMeetings(testutil.TestCtx, now)
+	if err != nil || created != 0 {
+		t.Errorf("Expected no new meetings, got %d, %v", created, err)
+	}
+
+	// Schedules whose template was deleted are skipped
+	err = service.DeleteTemplate(testutil.TestCtx, org.OrgID, template.TemplateID)
+	if err != nil {
+		t.Fatalf("DeleteTemplate failed: %v", err)
+	}
+	created, err = service.GenerateScheduledMeetings(testutil.TestCtx, now.AddDate(0, 1, 0))
+	if err != nil || created != 0 {
+		t.Errorf("Expected the schedule to be skipped, got %d, %v", created, err)
+	}
+
+	err = service.DeleteSchedule(testutil.TestCtx, org.OrgID, schedule.ScheduleID)
+	if err != nil {
+		t.Fatalf("DeleteSchedule failed: %v", err)
+	}
+	_, err = service.GetSchedule(testutil.TestCtx, org.OrgID, schedule.ScheduleID)
+	if !errors.As(err, &apiErr) || apiErr.Status != http.StatusNotFound {
+		t.Errorf("Expected not found after deleting the schedule, got %v", err)
+	}
+}
+
+// scheduledMeetings lists the org's meetings after now, by date
+func scheduledMeetings(t *testing.T, service LowerThirdsService, orgID uuid.UUID, now time.Time) []entities.Meeting {
+	t.Helper()
+	meetings, _, err := service.GetMeetingsByOrg(testutil.TestCtx, orgID)
+	if err != nil {
+		t.Fatalf("GetMeetingsByOrg failed: %v", err)
+	}
+	var upcoming []entities.Meeting
+	for _, m := range *meetings {
+		if m.MeetingDate.After(now) {
+			upcoming = append(upcoming, m)
+		}
+	}
+	sort.Slice(upcoming, func(i, j int) bool { return upcoming[i].MeetingDate.Before(upcoming[j].MeetingDate) })
+	return upcoming
+}
@@ -0,0 +1,55 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"lowerthirdsapi/internal/migrations"
+	"lowerthirdsapi/internal/sqlitedb"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/sirupsen/logrus"
+)
+
+// Storage backends
+const (
+	BackendMySQL  = "mysql"
+	BackendSQLite = "sqlite"
+	BackendMemory = "memory"
+)
+
+// BackendConfig picks the database the service runs on. MySQL uses MySQLConfig; SQLite keeps everything in one
+// file, and memory keeps it in SQLite's in-memory database until the process exits. Memory is the same SQLite storage
+// on an ephemeral database, not an implementation of its own.
+type BackendConfig struct {
+	Backend    string `envconfig:"STORAGE_BACKEND" default:"mysql"`
+	SQLitePath string `envconfig:"SQLITE_PATH" default:"lowerthirds.db"`
+}
+
+// OpenLocal opens the SQLite or memory database cfg picks and migrates it to the latest schema, since nothing else
+// sets these up before the API starts
+func OpenLocal(ctx context.Context, cfg BackendConfig, log *logrus.Entry) (*sqlx.DB, error) {
+	var db *sqlx.DB
+	var err error
+	switch cfg.Backend {
+	case BackendSQLite:
+		db, err = sqlitedb.Open(cfg.SQLitePath)
+	case BackendMemory:
+		db, err = sqlitedb.OpenMemory()
+	default:
+		return nil, fmt.Errorf("unknown storage backend %q", cfg.Backend)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	m, err := migrations.New(db, log)
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+	if _, err := m.Up(ctx, 0); err != nil {
+		db.Close()
+		return nil, err
+	}
+	return db, nil
+}
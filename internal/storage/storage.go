@@ -6,6 +6,8 @@ import (
 	"github.com/jmoiron/sqlx"
 	"github.com/sirupsen/logrus"
 	"lowerthirdsapi/internal/entities"
+	"lowerthirdsapi/internal/events"
+	"time"
 )
 
 type LowerThirdsService interface {
@@ -13,50 +15,138 @@ type LowerThirdsService interface {
 	CreateMeeting(ctx context.Context, m *entities.Meeting) error
 	DeleteMeeting(ctx context.Context, meetingID uuid.UUID) error
 	GetMeeting(ctx context.Context, meetingID uuid.UUID) (*entities.Meeting, error)
-	GetMeetings(ctx context.Context) (*[]entities.Meeting, error)
+	GetMeetings(ctx context.Context) (*[]entities.Meeting, int, error)
 	UpdateMeeting(ctx context.Context, meetingID uuid.UUID, m *entities.Meeting) error
-	GetMeetingsByOrg(ctx context.Context, orgID uuid.UUID) (*[]entities.Meeting, error)
-	GetMeetingsByUser(ctx context.Context, userID uuid.UUID) (*[]entities.Meeting, error)
+	GetMeetingsByOrg(ctx context.Context, orgID uuid.UUID) (*[]entities.Meeting, int, error)
+	GetMeetingsByUser(ctx context.Context, userID uuid.UUID) (*[]entities.Meeting, int, error)
+	CloneMeeting(ctx context.Context, meetingID uuid.UUID, date time.Time, orgID uuid.UUID) (*entities.Meeting, error)
+
+	// Templates
+	CreateTemplate(ctx context.Context, orgID uuid.UUID, t *entities.MeetingTemplate) error
+	DeleteTemplate(ctx context.Context, orgID uuid.UUID, templateID uuid.UUID) error
+	GetTemplate(ctx context.Context, orgID uuid.UUID, templateID uuid.UUID) (*entities.MeetingTemplate, error)
+	GetTemplatesByOrg(ctx context.Context, orgID uuid.UUID) (*[]entities.MeetingTemplate, error)
+	UpdateTemplate(ctx context.Context, orgID uuid.UUID, templateID uuid.UUID, t *entities.MeetingTemplate) error
+	InstantiateTemplate(ctx context.Context, orgID uuid.UUID, templateID uuid.UUID, date time.Time) (*entities.Meeting, error)
+
+	// Schedules
+	CreateSchedule(ctx context.Context, orgID uuid.UUID, sc *entities.Schedule) error
+	DeleteSchedule(ctx context.Context, orgID uuid.UUID, scheduleID uuid.UUID) error
+	GetSchedule(ctx context.Context, orgID uuid.UUID, scheduleID uuid.UUID) (*entities.Schedule, error)
+	GetSchedulesByOrg(ctx context.Context, orgID uuid.UUID) (*[]entities.Schedule, error)
+	UpdateSchedule(ctx context.Context, orgID uuid.UUID, scheduleID uuid.UUID, sc *entities.Schedule) error
+	GenerateScheduledMeetings(ctx context.Context, now time.Time) (int, error)
+
+	// Events
+	SubscribeMeetingEvents(ctx context.Context, meetingID uuid.UUID, lastEventID uint64) (*events.Subscription, error)
+
+	// Live
+	GetLiveState(ctx context.Context, meetingID uuid.UUID) (*entities.LiveState, error)
+	UpdateLiveState(ctx context.Context, meetingID uuid.UUID, action entities.LiveAction, itemID uuid.UUID) (*entities.LiveState, error)
+
+	// Timers
+	GetMeetingTimers(ctx context.Context, meetingID uuid.UUID) (*[]entities.Timer, error)
+	GetItemTimer(ctx context.Context, itemID uuid.UUID) (*entities.Timer, error)
+	UpdateItemTimer(ctx context.Context, itemID uuid.UUID, action entities.TimerAction, amount time.Duration) (*entities.Timer, error)
+
+	// Overlays
+	CreateOverlayToken(ctx context.Context, meetingID uuid.UUID) (*entities.OverlayToken, error)
+	DeleteOverlayToken(ctx context.Context, meetingID uuid.UUID, token string) error
+	GetOverlayTokens(ctx context.Context, meetingID uuid.UUID) (*[]entities.OverlayToken, error)
+	GetOverlay(ctx context.Context, token string) (*entities.Overlay, error)
+	SubscribeOverlayEvents(ctx context.Context, token string, lastEventID uint64) (*events.Subscription, error)
+
+	// Themes
+	GetOrgTheme(ctx context.Context, orgID uuid.UUID) (*entities.Theme, error)
+	SetOrgTheme(ctx context.Context, orgID uuid.UUID, data []byte) (*entities.Theme, error)
+	DeleteOrgTheme(ctx context.Context, orgID uuid.UUID) error
+	GetMeetingTheme(ctx context.Context, meetingID uuid.UUID) (*entities.Theme, error)
+	SetMeetingTheme(ctx context.Context, meetingID uuid.UUID, overrides []byte) (*entities.Theme, error)
+	DeleteMeetingTheme(ctx context.Context, meetingID uuid.UUID) error
 
 	// Orgs
 	CreateOrg(ctx context.Context, o *entities.Organization) error
 	DeleteOrg(ctx context.Context, orgID uuid.UUID) error
 	GetOrg(ctx context.Context, orgID uuid.UUID) (*entities.Organization, error)
-	GetOrgs(ctx context.Context) (*[]entities.Organization, error)
+	GetOrgs(ctx context.Context) (*[]entities.Organization, int, error)
 	UpdateOrg(ctx context.Context, orgID uuid.UUID, o *entities.Organization) error
 
 	// OrgUser
-	CreateOrgUser(ctx context.Context, orgID uuid.UUID, userID uuid.UUID) error
+	CreateOrgUser(ctx context.Context, orgID uuid.UUID, userID uuid.UUID, role entities.Role) error
 	DeleteOrgUser(ctx context.Context, orgID uuid.UUID, userID uuid.UUID) error
-	GetOrgsByUser(ctx context.Context, userID uuid.UUID) (*[]entities.Organization, error)
+	GetOrgUsers(ctx context.Context, orgID uuid.UUID) (*[]entities.OrgUser, error)
+	GetOrgsByUser(ctx context.Context, userID uuid.UUID) (*[]entities.Organization, int, error)
+	GetMembershipsByUser(ctx context.Context, userID uuid.UUID) (*[]entities.Membership, error)
 	SetOrgsByUser(ctx context.Context, userID uuid.UUID, orgIDs []uuid.UUID) error
 	GetOrgUsersMap(ctx context.Context) (map[uuid.UUID][]uuid.UUID, error)
-	GetUsersByOrg(ctx context.Context, orgID uuid.UUID) (*[]entities.User, error)
+	GetUsersByOrg(ctx context.Context, orgID uuid.UUID) (*[]entities.User, int, error)
+	SetOrgUserRole(ctx context.Context, orgID uuid.UUID, userID uuid.UUID, role entities.Role) error
+
+	// Invitations
+	CreateInvitation(ctx context.Context, orgID uuid.UUID, inv *entities.Invitation) error
+	GetInvitationsByOrg(ctx context.Context, orgID uuid.UUID) (*[]entities.Invitation, error)
+	DeleteInvitation(ctx context.Context, orgID uuid.UUID, invitationID uuid.UUID) error
+	AcceptInvitation(ctx context.Context, invitationID uuid.UUID) (*entities.Invitation, error)
+
+	// API keys
+	CreateAPIKey(ctx context.Context, orgID uuid.UUID, k *entities.APIKey) (string, error)
+	GetAPIKeysByOrg(ctx context.Context, orgID uuid.UUID) (*[]entities.APIKey, error)
+	DeleteAPIKey(ctx context.Context, orgID uuid.UUID, keyID uuid.UUID) error
+	AuthenticateAPIKey(ctx context.Context, key string) (*entities.APIKey, error)
+
+	// Audit log
+	GetAuditLogByOrg(ctx context.Context, orgID uuid.UUID) (*[]entities.AuditRecord, int, error)
+
+	// Hymns
+	GetHymn(ctx context.Context, hymnID uuid.UUID) (*entities.Hymn, error)
+	GetHymns(ctx context.Context) (*[]entities.Hymn, int, error)
 
 	// Items
 	CreateItem(ctx context.Context, item entities.Item) error
 	DeleteItem(ctx context.Context, itemID uuid.UUID) error
 	GetItem(ctx context.Context, itemID uuid.UUID) (entities.Item, error)
-	GetItems(ctx context.Context) (*[]entities.Item, error)
+	GetItems(ctx context.Context) (*[]entities.Item, int, error)
 	GetItemsByMeeting(ctx context.Context, meetingID uuid.UUID) (*[]entities.Item, error)
+	GetItemsByMeetings(ctx context.Context, meetingIDs []uuid.UUID) (map[uuid.UUID][]entities.Item, error)
+	GetItemSlides(ctx context.Context, itemID uuid.UUID) (*[]entities.Slide, error)
 	UpdateItem(ctx context.Context, itemID uuid.UUID, item entities.Item) error
+	ReorderItems(ctx context.Context, meetingID uuid.UUID, itemIDs []uuid.UUID) (*[]entities.Item, error)
 
 	// Users
 	CreateUser(ctx context.Context, u *entities.User) error
 	DeleteUser(ctx context.Context, userID uuid.UUID) error
 	GetUser(ctx context.Context, userID uuid.UUID) (*entities.User, error)
-	GetUsers(ctx context.Context) (*[]entities.User, error)
+	GetUserBySocialID(ctx context.Context, socialID string) (*entities.User, error)
+	ProvisionUser(ctx context.Context, profile *entities.User, emailVerified bool, refresh bool) (*entities.User, error)
+	GetUsers(ctx context.Context) (*[]entities.User, int, error)
 	UpdateUser(ctx context.Context, userID uuid.UUID, u *entities.User) error
 }
 
 type lowerThirdsService struct {
 	MySqlDB *sqlx.DB
 	logger  *logrus.Entry
+	events  *events.Broker
+	dialect dialect
+}
+
+// Option configures optional parts of the service
+type Option func(*lowerThirdsService)
+
+// WithEvents publishes meeting changes to the broker
+func WithEvents(b *events.Broker) Option {
+	return func(s *lowerThirdsService) {
+		s.events = b
+	}
 }
 
-func New(db *sqlx.DB, l *logrus.Entry) LowerThirdsService {
-	return &lowerThirdsService{
+func New(db *sqlx.DB, l *logrus.Entry, opts ...Option) LowerThirdsService {
+	s := &lowerThirdsService{
 		MySqlDB: db,
 		logger:  l,
+		dialect: dialectFor(db.DriverName()),
+	}
+	for _, opt := range opts {
+		opt(s)
 	}
+	return s
 }
@@ -0,0 +1,861 @@
+// Package storagetest is the conformance suite every storage backend has to pass. It only goes through the
+// storage.LowerThirdsService interface, so the same cases check the MySQL and SQLite dialects of the one implementation.
+// The memory backend is SQLite on an in-memory database, which the suite also runs against.
+package storagetest
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"lowerthirdsapi/internal/apierrors"
+	"lowerthirdsapi/internal/auth"
+	"lowerthirdsapi/internal/entities"
+	"lowerthirdsapi/internal/helpers"
+	"lowerthirdsapi/internal/storage"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"gopkg.in/guregu/null.v4"
+)
+
+// Run runs the suite against the services newService makes. Each case makes its own user, org and meeting, so the
+// service may share a database with other tests. The hymn catalog comes from the migrations.
+func Run(t *testing.T, newService func(t *testing.T) storage.LowerThirdsService) {
+	cases := []struct {
+		name string
+		fn   func(t *testing.T, s storage.LowerThirdsService)
+	}{
+		{"Users", testUsers},
+		{"Provisioning", testProvisioning},
+		{"Orgs", testOrgs},
+		{"Meetings", testMeetings},
+		{"Pages", testPages},
+		{"Items", testItems},
+		{"Reorder", testReorder},
+		{"Clone", testClone},
+		{"Hymns", testHymns},
+		{"LiveState", testLiveState},
+		{"Timers", testTimers},
+		{"Themes", testThemes},
+		{"OverlayTokens", testOverlayTokens},
+		{"Templates", testTemplates},
+		{"Schedules", testSchedules},
+		{"Invitations", testInvitations},
+		{"APIKeys", testAPIKeys},
+		{"Audit", testAudit},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			c.fn(t, newService(t))
+		})
+	}
+}
+
+// fixture is a user who owns an org with one meeting, and a context logged in as them
+type fixture struct {
+	ctx     context.Context
+	user    *entities.User
+	org     *entities.Organization
+	meeting *entities.Meeting
+}
+
+func newFixture(t *testing.T, s storage.LowerThirdsService) fixture {
+	t.Helper()
+	id := uuid.New()
+	f := fixture{
+		ctx: context.WithValue(context.Background(), helpers.SocialIDKey, "conformance-"+id.String()),
+		user: &entities.User{
+			UserID:   id,
+			Email:    "conformance+" + id.String()[:8] + "@example.com",
+			SocialID: null.StringFrom("conformance-" + id.String()),
+		},
+		org: &entities.Organization{OrgID: uuid.New(), Name: "Conformance Org " + id.String()},
+	}
+	f.meeting = &entities.Meeting{
+		MeetingID:   uuid.New(),
+		OrgID:       f.org.OrgID,
+		Meeting:     "Conformance Meeting",
+		MeetingDate: time.Date(2026, time.March, 1, 17, 0, 0, 0, time.UTC),
+	}
+
+	if err := s.CreateUser(f.ctx, f.user); err != nil {
+		t.Fatalf("CreateUser failed: %v", err)
+	}
+	if err := s.CreateOrg(f.ctx, f.org); err != nil {
+		t.Fatalf("CreateOrg failed: %v", err)
+	}
+	if err := s.CreateMeeting(f.ctx, f.meeting); err != nil {
+		t.Fatalf("CreateMeeting failed: %v", err)
+	}
+	return f
+}
+
+// createItems adds blank items to the fixture's meeting in order
+func createItems(t *testing.T, s storage.LowerThirdsService, f fixture, count int) []uuid.UUID {
+	t.Helper()
+	var ids []uuid.UUID
+	for i := 1; i <= count; i++ {
+		item := &entities.BlankItem{
+			BlankItemID: uuid.New(),
+			MeetingID:   f.meeting.MeetingID,
+			ItemType:    "blank",
+			ItemOrder:   i,
+			MeetingRole: "Conformance Role",
+		}
+		if err := s.CreateItem(f.ctx, item); err != nil {
+			t.Fatalf("CreateItem failed: %v", err)
+		}
+		ids = append(ids, item.BlankItemID)
+	}
+	return ids
+}
+
+func testUsers(t *testing.T, s storage.LowerThirdsService) {
+	f := newFixture(t, s)
+
+	user, err := s.GetUser(f.ctx, f.user.UserID)
+	if err != nil {
+		t.Fatalf("GetUser failed: %v", err)
+	}
+	if user.Email != f.user.Email || user.InsertedDT.IsZero() {
+		t.Errorf("Unexpected user: %+v", user)
+	}
+
+	// Emails are unique, and every backend reports it the same way
+	err = s.CreateUser(f.ctx, &entities.User{UserID: uuid.New(), Email: f.user.Email})
+	if !storage.IsDuplicate(err) {
+		t.Errorf("Expected a duplicate error, got %v", err)
+	}
+	err = s.CreateUser(f.ctx, &entities.User{UserID: f.user.UserID, Email: "other+" + f.user.Email})
+	if !storage.IsDuplicate(err) {
+		t.Errorf("Expected a duplicate error for a reused ID, got %v", err)
+	}
+}
+
+func testProvisioning(t *testing.T, s storage.LowerThirdsService) {
+	f := newFixture(t, s)
+
+	// A first login makes a user from the account
+	id := uuid.New().String()
+	profile := &entities.User{
+		SocialID: null.StringFrom("provisioned-" + id),
+		Email:    "provisioned+" + id[:8] + "@example.com",
+		FullName: null.StringFrom("New Volunteer"),
+	}
+	ctx := context.WithValue(context.Background(), helpers.SocialIDKey, profile.SocialID.String)
+	created, err := s.ProvisionUser(ctx, profile, true, false)
+	if err != nil {
+		t.Fatalf("ProvisionUser failed: %v", err)
+	}
+	if created.UserID == uuid.Nil || created.Email != profile.Email || created.FullName != profile.FullName {
+		t.Errorf("Unexpected provisioned user: %+v", created)
+	}
+
+	// Later logins find it, and only a refresh changes it
+	profile.FullName = null.StringFrom("Renamed Volunteer")
+	again, err := s.ProvisionUser(ctx, profile, true, false)
+	if err != nil || again.UserID != created.UserID || again.FullName.String != "New Volunteer" {
+		t.Errorf("Expected the same user unchanged, got %+v, %v", again, err)
+	}
+	again, err = s.ProvisionUser(ctx, profile, true, true)
+	if err != nil || again.UserID != created.UserID || again.FullName.String != "Renamed Volunteer" {
+		t.Errorf("Expected the same user renamed, got %+v, %v", again, err)
+	}
+
+	// An unverified email doesn't change the user
+	again, err = s.ProvisionUser(ctx, &entities.User{SocialID: profile.SocialID, Email: "unverified+" + id[:8] + "@example.com"}, false, true)
+	if err != nil || again.UserID != created.UserID || again.Email != profile.Email {
+		t.Errorf("Expected the email kept, got %+v, %v", again, err)
+	}
+
+	// A user added by email before signing in is only linked to an account that verified the email
+	invited := &entities.User{UserID: uuid.New(), Email: "invited+" + id[:8] + "@example.com"}
+	if err := s.CreateUser(f.ctx, invited); err != nil {
+		t.Fatalf("CreateUser failed: %v", err)
+	}
+	var apiErr *apierrors.Error
+	_, err = s.ProvisionUser(ctx, &entities.User{SocialID: null.StringFrom("impostor-" + id), Email: invited.Email}, false, false)
+	if !errors.As(err, &apiErr) || apiErr.Status != http.StatusConflict {
+		t.Errorf("Expected a conflict for an unverified email, got %v", err)
+	}
+	if _, err := s.GetUserBySocialID(ctx, "impostor-"+id); err == nil {
+		t.Error("Expected the unverified account not to get a user")
+	}
+	linked, err := s.ProvisionUser(ctx, &entities.User{
+		SocialID: null.StringFrom("invited-" + id),
+		Email:    invited.Email,
+		PhotoURL: null.StringFrom("https://example.com/photo.png"),
+	}, true, false)
+	if err != nil {
+		t.Fatalf("ProvisionUser failed to link: %v", err)
+	}
+	if linked.UserID != invited.UserID || linked.SocialID.String != "invited-"+id || !linked.PhotoURL.Valid {
+		t.Errorf("Expected the invited user linked, got %+v", linked)
+	}
+
+	// An email that belongs to another account isn't taken over
+	_, err = s.ProvisionUser(ctx, &entities.User{SocialID: null.StringFrom("other-" + id), Email: f.user.Email}, true, false)
+	if !errors.As(err, &apiErr) || apiErr.Status != http.StatusConflict {
+		t.Errorf("Expected a conflict for another account's email, got %v", err)
+	}
+
+	// The user sees their orgs with their role
+	memberships, err := s.GetMembershipsByUser(f.ctx, f.user.UserID)
+	if err != nil {
+		t.Fatalf("GetMembershipsByUser failed: %v", err)
+	}
+	if len(*memberships) != 1 || (*memberships)[0].OrgID != f.org.OrgID || (*memberships)[0].Role != entities.RoleOwner {
+		t.Errorf("Expected ownership of the fixture org, got %+v", memberships)
+	}
+	if _, err := s.GetMembershipsByUser(ctx, f.user.UserID); err == nil {
+		t.Error("Expected another user's memberships to be hidden")
+	}
+}
+
+func testOrgs(t *testing.T, s storage.LowerThirdsService) {
+	f := newFixture(t, s)
+
+	// The creating user owns the org
+	members, err := s.GetOrgUsers(f.ctx, f.org.OrgID)
+	if err != nil {
+		t.Fatalf("GetOrgUsers failed: %v", err)
+	}
+	if len(*members) != 1 || (*members)[0].UserID != f.user.UserID || (*members)[0].Role != entities.RoleOwner {
+		t.Errorf("Expected the creator as owner, got %+v", members)
+	}
+
+	// Other users can't see it
+	other := newFixture(t, s)
+	if _, err := s.GetOrg(other.ctx, f.org.OrgID); err == nil {
+		t.Error("Expected another user's org to be hidden")
+	}
+}
+
+func testMeetings(t *testing.T, s storage.LowerThirdsService) {
+	f := newFixture(t, s)
+
+	// Dates come back as the same instant whatever zone they were given in
+	later := &entities.Meeting{
+		MeetingID:   uuid.New(),
+		OrgID:       f.org.OrgID,
+		Meeting:     "Conformance Meeting",
+		MeetingDate: time.Date(2026, time.March, 8, 10, 0, 0, 0, time.FixedZone("MST", -7*60*60)),
+	}
+	if err := s.CreateMeeting(f.ctx, later); err != nil {
+		t.Fatalf("CreateMeeting failed: %v", err)
+	}
+	meeting, err := s.GetMeeting(f.ctx, later.MeetingID)
+	if err != nil {
+		t.Fatalf("GetMeeting failed: %v", err)
+	}
+	if !meeting.MeetingDate.Equal(later.MeetingDate) {
+		t.Errorf("Expected %v, got %v", later.MeetingDate, meeting.MeetingDate)
+	}
+
+	meeting.Meeting = "Conformance Meeting Renamed"
+	if err := s.UpdateMeeting(f.ctx, meeting.MeetingID, meeting); err != nil {
+		t.Fatalf("UpdateMeeting failed: %v", err)
+	}
+	meetings, _, err := s.GetMeetingsByOrg(f.ctx, f.org.OrgID)
+	if err != nil {
+		t.Fatalf("GetMeetingsByOrg failed: %v", err)
+	}
+	if len(*meetings) != 2 {
+		t.Fatalf("Expected 2 meetings, got %+v", meetings)
+	}
+
+	// Clones copy the agenda
+	createItems(t, s, f, 2)
+	clone, err := s.CloneMeeting(f.ctx, f.meeting.MeetingID, later.MeetingDate.AddDate(0, 0, 7), uuid.Nil)
+	if err != nil {
+		t.Fatalf("CloneMeeting failed: %v", err)
+	}
+	items, err := s.GetItemsByMeeting(f.ctx, clone.MeetingID)
+	if err != nil || len(*items) != 2 {
+		t.Errorf("Expected the clone's 2 items, got %v, %v", items, err)
+	}
+
+	if err := s.DeleteMeeting(f.ctx, later.MeetingID); err != nil {
+		t.Fatalf("DeleteMeeting failed: %v", err)
+	}
+	if _, err := s.GetMeeting(f.ctx, later.MeetingID); err == nil {
+		t.Error("Expected the deleted meeting to be gone")
+	}
+}
+
+func testPages(t *testing.T, s storage.LowerThirdsService) {
+	f := newFixture(t, s)
+	for day := 2; day <= 5; day++ {
+		meeting := &entities.Meeting{
+			MeetingID:   uuid.New(),
+			OrgID:       f.org.OrgID,
+			Meeting:     "Conformance Meeting",
+			MeetingDate: time.Date(2026, time.March, day, 17, 0, 0, 0, time.UTC),
+		}
+		if err := s.CreateMeeting(f.ctx, meeting); err != nil {
+			t.Fatalf("CreateMeeting failed: %v", err)
+		}
+	}
+
+	// Pages are taken in date order, and the total counts every page
+	qp := helpers.DefaultQueryParams()
+	qp.PageSize = 2
+	qp.Page = 1
+	ctx := context.WithValue(f.ctx, helpers.QueryParametersKey, qp)
+	meetings, total, err := s.GetMeetingsByOrg(ctx, f.org.OrgID)
+	if err != nil {
+		t.Fatalf("GetMeetingsByOrg failed: %v", err)
+	}
+	if total != 5 || len(*meetings) != 2 || (*meetings)[0].MeetingDate.Day() != 3 || (*meetings)[1].MeetingDate.Day() != 4 {
+		t.Errorf("Expected the 3rd and 4th of 5 meetings, got %d of %+v", total, meetings)
+	}
+
+	// Both ends of the date range are included
+	from := time.Date(2026, time.March, 2, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2026, time.March, 3, 0, 0, 0, 0, time.UTC)
+	qp = helpers.DefaultQueryParams()
+	qp.DateFrom = &from
+	qp.DateTo = &to
+	ctx = context.WithValue(f.ctx, helpers.QueryParametersKey, qp)
+	meetings, total, err = s.GetMeetings(ctx)
+	if err != nil {
+		t.Fatalf("GetMeetings failed: %v", err)
+	}
+	if total != 2 || len(*meetings) != 2 {
+		t.Errorf("Expected the meetings on the 2nd and 3rd, got %d of %+v", total, meetings)
+	}
+
+	// Pages past the end are empty
+	qp = helpers.DefaultQueryParams()
+	qp.Page = 3
+	ctx = context.WithValue(f.ctx, helpers.QueryParametersKey, qp)
+	orgs, total, err := s.GetOrgs(ctx)
+	if err != nil {
+		t.Fatalf("GetOrgs failed: %v", err)
+	}
+	if total != 1 || len(*orgs) != 0 {
+		t.Errorf("Expected an empty page of 1 org, got %d of %+v", total, orgs)
+	}
+
+	// Pages too far out to skip to are rejected instead of overflowing
+	qp = helpers.DefaultQueryParams()
+	qp.Page = helpers.MaxOffset
+	ctx = context.WithValue(f.ctx, helpers.QueryParametersKey, qp)
+	_, _, err = s.GetOrgs(ctx)
+	var apiErr *apierrors.Error
+	if !errors.As(err, &apiErr) || apiErr.Status != http.StatusBadRequest {
+		t.Errorf("Expected bad request for a page past the end, got %v", err)
+	}
+}
+
+func testItems(t *testing.T, s storage.LowerThirdsService) {
+	f := newFixture(t, s)
+	ids := createItems(t, s, f, 3)
+
+	speaker := &entities.SpeakerItem{
+		SpeakerItemID:    uuid.New(),
+		MeetingID:        f.meeting.MeetingID,
+		ItemType:         "speaker",
+		ItemOrder:        4,
+		MeetingRole:      "Conformance Role",
+		SpeakerName:      "Conformance Speaker",
+		ExpectedDuration: null.IntFrom(5),
+	}
+	if err := s.CreateItem(f.ctx, speaker); err != nil {
+		t.Fatalf("CreateItem failed: %v", err)
+	}
+	item, err := s.GetItem(f.ctx, speaker.SpeakerItemID)
+	if err != nil {
+		t.Fatalf("GetItem failed: %v", err)
+	}
+	if got, ok := item.(*entities.SpeakerItem); !ok || got.SpeakerName != "Conformance Speaker" || got.ExpectedDuration.Int64 != 5 {
+		t.Errorf("Unexpected item: %+v", item)
+	}
+
+	// IDs are unique across item types
+	err = s.CreateItem(f.ctx, &entities.BlankItem{
+		BlankItemID: speaker.SpeakerItemID,
+		MeetingID:   f.meeting.MeetingID,
+		ItemType:    "blank",
+		ItemOrder:   5,
+		MeetingRole: "Conformance Role",
+	})
+	if !storage.IsDuplicate(err) {
+		t.Errorf("Expected a duplicate error, got %v", err)
+	}
+
+	// Values too long for a column are rejected rather than cut short
+	err = s.CreateItem(f.ctx, &entities.BlankItem{
+		BlankItemID: uuid.New(),
+		MeetingID:   f.meeting.MeetingID,
+		ItemType:    "blank",
+		ItemOrder:   5,
+		MeetingRole: "Conformance Role With A Name That Is Much Too Long To Store",
+	})
+	if err == nil {
+		t.Error("Expected an error for an overlong meeting role")
+	}
+
+	reordered, err := s.ReorderItems(f.ctx, f.meeting.MeetingID, []uuid.UUID{speaker.SpeakerItemID, ids[2], ids[1], ids[0]})
+	if err != nil {
+		t.Fatalf("ReorderItems failed: %v", err)
+	}
+	if (*reordered)[0].GetID() != speaker.SpeakerItemID || (*reordered)[3].GetID() != ids[0] {
+		t.Errorf("Unexpected order: %+v", reordered)
+	}
+}
+
+func testReorder(t *testing.T, s storage.LowerThirdsService) {
+	f := newFixture(t, s)
+	ids := createItems(t, s, f, 3)
+
+	// Every item is listed once, and deleted items aren't
+	if err := s.DeleteItem(f.ctx, ids[1]); err != nil {
+		t.Fatalf("DeleteItem failed: %v", err)
+	}
+	for _, bad := range [][]uuid.UUID{
+		{ids[2]},
+		{ids[2], ids[2]},
+		{ids[2], ids[1], ids[0]},
+		{ids[2], uuid.New()},
+	} {
+		_, err := s.ReorderItems(f.ctx, f.meeting.MeetingID, bad)
+		var apiErr *apierrors.Error
+		if !errors.As(err, &apiErr) || apiErr.Code != "INVALID_ORDER" {
+			t.Errorf("Expected an invalid order error for %v, got %v", bad, err)
+		}
+	}
+
+	// Items are numbered from 1, and one already in place stays
+	reordered, err := s.ReorderItems(f.ctx, f.meeting.MeetingID, []uuid.UUID{ids[2], ids[0]})
+	if err != nil {
+		t.Fatalf("ReorderItems failed: %v", err)
+	}
+	if len(*reordered) != 2 || (*reordered)[0].GetID() != ids[2] || (*reordered)[0].GetOrder() != 1 ||
+		(*reordered)[1].GetID() != ids[0] || (*reordered)[1].GetOrder() != 2 {
+		t.Errorf("Unexpected order: %+v", reordered)
+	}
+}
+
+func testClone(t *testing.T, s storage.LowerThirdsService) {
+	f := newFixture(t, s)
+	ids := createItems(t, s, f, 2)
+
+	date := time.Date(2026, time.March, 8, 17, 0, 0, 0, time.UTC)
+	clone, err := s.CloneMeeting(f.ctx, f.meeting.MeetingID, date, uuid.Nil)
+	if err != nil {
+		t.Fatalf("CloneMeeting failed: %v", err)
+	}
+	if clone.MeetingID == f.meeting.MeetingID || clone.OrgID != f.org.OrgID || !clone.MeetingDate.Equal(date) {
+		t.Errorf("Expected a new meeting on the date in the same org, got %+v", clone)
+	}
+
+	// The items are copied in order with new IDs
+	items, err := s.GetItemsByMeeting(f.ctx, clone.MeetingID)
+	if err != nil {
+		t.Fatalf("GetItemsByMeeting failed: %v", err)
+	}
+	if len(*items) != 2 {
+		t.Fatalf("Expected 2 items, got %+v", items)
+	}
+	for i, item := range *items {
+		if item.GetOrder() != i+1 || item.GetMeetingID() != clone.MeetingID || item.GetID() == ids[i] {
+			t.Errorf("Expected a copy of item %d, got %+v", i+1, item)
+		}
+	}
+
+	// Only into orgs the user can edit
+	_, err = s.CloneMeeting(f.ctx, f.meeting.MeetingID, date, uuid.New())
+	var apiErr *apierrors.Error
+	if !errors.As(err, &apiErr) || apiErr.Status != http.StatusForbidden {
+		t.Errorf("Expected forbidden for another org, got %v", err)
+	}
+}
+
+func testHymns(t *testing.T, s storage.LowerThirdsService) {
+	f := newFixture(t, s)
+
+	hymns, _, err := s.GetHymns(f.ctx)
+	if err != nil {
+		t.Fatalf("GetHymns failed: %v", err)
+	}
+	if len(*hymns) == 0 {
+		t.Fatal("Expected a hymn in the catalog")
+	}
+	hymn, err := s.GetHymn(f.ctx, (*hymns)[0].HymnID)
+	if err != nil {
+		t.Fatalf("GetHymn failed: %v", err)
+	}
+
+	lyrics := &entities.LyricsItem{
+		LyricsItemID: uuid.New(),
+		MeetingID:    f.meeting.MeetingID,
+		ItemType:     "lyrics",
+		ItemOrder:    1,
+		MeetingRole:  "Conformance Role",
+		HymnID:       hymn.HymnID.String(),
+	}
+	if err := s.CreateItem(f.ctx, lyrics); err != nil {
+		t.Fatalf("CreateItem failed: %v", err)
+	}
+	slides, err := s.GetItemSlides(f.ctx, lyrics.LyricsItemID)
+	if err != nil {
+		t.Fatalf("GetItemSlides failed: %v", err)
+	}
+
+	// Putting lyrics on air counts their verses inside the live state's transaction
+	liveState, err := s.UpdateLiveState(f.ctx, f.meeting.MeetingID, entities.LiveActionTake, lyrics.LyricsItemID)
+	if err != nil {
+		t.Fatalf("UpdateLiveState failed: %v", err)
+	}
+	if liveState.VerseCount != len(*slides) {
+		t.Errorf("Expected %d verses, got %+v", len(*slides), liveState)
+	}
+}
+
+func testLiveState(t *testing.T, s storage.LowerThirdsService) {
+	f := newFixture(t, s)
+	ids := createItems(t, s, f, 2)
+
+	_, err := s.UpdateLiveState(f.ctx, f.meeting.MeetingID, entities.LiveActionNext, uuid.Nil)
+	if err != nil {
+		t.Fatalf("UpdateLiveState failed: %v", err)
+	}
+	liveState, err := s.GetLiveState(f.ctx, f.meeting.MeetingID)
+	if err != nil {
+		t.Fatalf("GetLiveState failed: %v", err)
+	}
+	if liveState.ProgramItemID.UUID != ids[0] || liveState.PreviewItemID.UUID != ids[1] || !liveState.Visible {
+		t.Errorf("Unexpected live state after next: %+v", liveState)
+	}
+}
+
+func testTimers(t *testing.T, s storage.LowerThirdsService) {
+	f := newFixture(t, s)
+	ids := createItems(t, s, f, 1)
+
+	timer, err := s.UpdateItemTimer(f.ctx, ids[0], entities.TimerActionStart, 0)
+	if err != nil {
+		t.Fatalf("UpdateItemTimer failed: %v", err)
+	}
+	if !timer.Reading.Running || timer.Version != 1 {
+		t.Errorf("Expected a running timer at version 1, got %+v", timer)
+	}
+
+	timer, err = s.GetItemTimer(f.ctx, ids[0])
+	if err != nil {
+		t.Fatalf("GetItemTimer failed: %v", err)
+	}
+	if timer.Version != 1 || !timer.StartedDT.Valid {
+		t.Errorf("Expected the started timer, got %+v", timer)
+	}
+}
+
+func testThemes(t *testing.T, s storage.LowerThirdsService) {
+	f := newFixture(t, s)
+
+	// Setting a theme twice replaces it
+	for _, name := range []string{"light", "minimal"} {
+		_, err := s.SetOrgTheme(f.ctx, f.org.OrgID, []byte(`{"name": "`+name+`"}`))
+		if err != nil {
+			t.Fatalf("SetOrgTheme failed: %v", err)
+		}
+	}
+	theme, err := s.GetOrgTheme(f.ctx, f.org.OrgID)
+	if err != nil {
+		t.Fatalf("GetOrgTheme failed: %v", err)
+	}
+	if theme.Name != "minimal" {
+		t.Errorf("Expected the minimal theme, got %+v", theme)
+	}
+
+	for _, margin := range []string{"120", "140"} {
+		_, err := s.SetMeetingTheme(f.ctx, f.meeting.MeetingID, []byte(`{"margin_bottom": `+margin+`}`))
+		if err != nil {
+			t.Fatalf("SetMeetingTheme failed: %v", err)
+		}
+	}
+	theme, err = s.GetMeetingTheme(f.ctx, f.meeting.MeetingID)
+	if err != nil {
+		t.Fatalf("GetMeetingTheme failed: %v", err)
+	}
+	if theme.MarginBottom != 140 || theme.Name != "minimal" {
+		t.Errorf("Expected the org's theme with the meeting's margin, got %+v", theme)
+	}
+}
+
+func testOverlayTokens(t *testing.T, s storage.LowerThirdsService) {
+	f := newFixture(t, s)
+	createItems(t, s, f, 1)
+
+	token, err := s.CreateOverlayToken(f.ctx, f.meeting.MeetingID)
+	if err != nil {
+		t.Fatalf("CreateOverlayToken failed: %v", err)
+	}
+	tokens, err := s.GetOverlayTokens(f.ctx, f.meeting.MeetingID)
+	if err != nil {
+		t.Fatalf("GetOverlayTokens failed: %v", err)
+	}
+	if len(*tokens) != 1 || (*tokens)[0].Token != token.Token {
+		t.Errorf("Expected the new token, got %+v", tokens)
+	}
+
+	// Overlays are read with the token alone, until it's revoked
+	overlay, err := s.GetOverlay(context.Background(), token.Token)
+	if err != nil {
+		t.Fatalf("GetOverlay failed: %v", err)
+	}
+	if overlay.Meeting.MeetingID != f.meeting.MeetingID {
+		t.Errorf("Expected the fixture's meeting, got %+v", overlay.Meeting)
+	}
+	if err := s.DeleteOverlayToken(f.ctx, f.meeting.MeetingID, token.Token); err != nil {
+		t.Fatalf("DeleteOverlayToken failed: %v", err)
+	}
+	_, err = s.GetOverlay(context.Background(), token.Token)
+	var apiErr *apierrors.Error
+	if !errors.As(err, &apiErr) || apiErr.Status != http.StatusNotFound {
+		t.Errorf("Expected not found for a revoked token, got %v", err)
+	}
+}
+
+func testTemplates(t *testing.T, s storage.LowerThirdsService) {
+	f := newFixture(t, s)
+
+	template := &entities.MeetingTemplate{
+		Name:    "Conformance Template",
+		Meeting: "Conformance Meeting",
+		Items: entities.TemplateItems{
+			json.RawMessage(`{"type": "blank", "meeting_role": "Conformance Role"}`),
+			json.RawMessage(`{"type": "speaker", "meeting_role": "Conformance Role", "name": "Speaker", "expected_duration": 10}`),
+		},
+	}
+	if err := s.CreateTemplate(f.ctx, f.org.OrgID, template); err != nil {
+		t.Fatalf("CreateTemplate failed: %v", err)
+	}
+	got, err := s.GetTemplate(f.ctx, f.org.OrgID, template.TemplateID)
+	if err != nil {
+		t.Fatalf("GetTemplate failed: %v", err)
+	}
+	if got.Name != template.Name || len(got.Items) != 2 {
+		t.Errorf("Unexpected template: %+v", got)
+	}
+
+	// The meeting and its items are made together
+	date := time.Date(2026, time.March, 15, 17, 0, 0, 0, time.UTC)
+	meeting, err := s.InstantiateTemplate(f.ctx, f.org.OrgID, template.TemplateID, date)
+	if err != nil {
+		t.Fatalf("InstantiateTemplate failed: %v", err)
+	}
+	items, err := s.GetItemsByMeeting(f.ctx, meeting.MeetingID)
+	if err != nil {
+		t.Fatalf("GetItemsByMeeting failed: %v", err)
+	}
+	if len(*items) != 2 || (*items)[1].GetType() != "speaker" || (*items)[1].GetOrder() != 2 {
+		t.Errorf("Expected the template's items in order, got %+v", items)
+	}
+
+	if err := s.DeleteTemplate(f.ctx, f.org.OrgID, template.TemplateID); err != nil {
+		t.Fatalf("DeleteTemplate failed: %v", err)
+	}
+	_, err = s.GetTemplate(f.ctx, f.org.OrgID, template.TemplateID)
+	var apiErr *apierrors.Error
+	if !errors.As(err, &apiErr) || apiErr.Status != http.StatusNotFound {
+		t.Errorf("Expected not found for a deleted template, got %v", err)
+	}
+}
+
+func testSchedules(t *testing.T, s storage.LowerThirdsService) {
+	f := newFixture(t, s)
+
+	template := &entities.MeetingTemplate{Name: "Conformance Template", Meeting: "Conformance Meeting"}
+	if err := s.CreateTemplate(f.ctx, f.org.OrgID, template); err != nil {
+		t.Fatalf("CreateTemplate failed: %v", err)
+	}
+	schedule := &entities.Schedule{
+		TemplateID: template.TemplateID,
+		Name:       "Conformance Sundays",
+		RRule:      "FREQ=WEEKLY;BYDAY=SU",
+		TimeZone:   "America/Denver",
+		StartDT:    time.Date(2031, time.January, 5, 9, 0, 0, 0, time.UTC),
+		Exceptions: entities.ScheduleDates{"2031-01-12"},
+		LeadDays:   14,
+	}
+	if err := s.CreateSchedule(f.ctx, f.org.OrgID, schedule); err != nil {
+		t.Fatalf("CreateSchedule failed: %v", err)
+	}
+	got, err := s.GetSchedule(f.ctx, f.org.OrgID, schedule.ScheduleID)
+	if err != nil {
+		t.Fatalf("GetSchedule failed: %v", err)
+	}
+	if got.StartDT.Hour() != 9 || len(got.Exceptions) != 1 || got.LeadDays != 14 {
+		t.Errorf("Unexpected schedule: %+v", got)
+	}
+
+	// Meetings are made once for each date in the lead time, in the schedule's time zone, skipping exceptions
+	now := time.Date(2031, time.January, 1, 0, 0, 0, 0, time.UTC)
+	for i := 0; i < 2; i++ {
+		if _, err := s.GenerateScheduledMeetings(context.Background(), now); err != nil {
+			t.Fatalf("GenerateScheduledMeetings failed: %v", err)
+		}
+	}
+	meetings, _, err := s.GetMeetingsByOrg(f.ctx, f.org.OrgID)
+	if err != nil {
+		t.Fatalf("GetMeetingsByOrg failed: %v", err)
+	}
+	var generated []time.Time
+	for _, m := range *meetings {
+		if m.MeetingID != f.meeting.MeetingID {
+			generated = append(generated, m.MeetingDate)
+		}
+	}
+	if len(generated) != 1 || !generated[0].Equal(time.Date(2031, time.January, 5, 16, 0, 0, 0, time.UTC)) {
+		t.Errorf("Expected one meeting on the 5th, got %v", generated)
+	}
+}
+
+func testInvitations(t *testing.T, s storage.LowerThirdsService) {
+	f := newFixture(t, s)
+	invitee := newFixture(t, s)
+
+	invitation := &entities.Invitation{
+		Email:     invitee.user.Email,
+		Role:      entities.RoleEditor,
+		ExpiresDT: time.Now().Add(time.Hour),
+	}
+	if err := s.CreateInvitation(f.ctx, f.org.OrgID, invitation); err != nil {
+		t.Fatalf("CreateInvitation failed: %v", err)
+	}
+	invitations, err := s.GetInvitationsByOrg(f.ctx, f.org.OrgID)
+	if err != nil {
+		t.Fatalf("GetInvitationsByOrg failed: %v", err)
+	}
+	if len(*invitations) != 1 || (*invitations)[0].InvitationID != invitation.InvitationID {
+		t.Errorf("Expected the one invitation, got %+v", invitations)
+	}
+
+	// Only the invitee can accept, with their email verified, and joins with the invitation's role
+	claims := &auth.Claims{UserID: invitee.user.SocialID.String, Email: invitee.user.Email}
+	_, err = s.AcceptInvitation(context.WithValue(invitee.ctx, helpers.AuthKey, claims), invitation.InvitationID)
+	var apiErr *apierrors.Error
+	if !errors.As(err, &apiErr) || apiErr.Status != http.StatusForbidden {
+		t.Errorf("Expected forbidden for an unverified email, got %v", err)
+	}
+	verified := *claims
+	verified.EmailVerified = true
+	inviteeCtx := context.WithValue(invitee.ctx, helpers.AuthKey, &verified)
+	owner := &auth.Claims{UserID: f.user.SocialID.String, Email: f.user.Email, EmailVerified: true}
+	_, err = s.AcceptInvitation(context.WithValue(f.ctx, helpers.AuthKey, owner), invitation.InvitationID)
+	if !errors.As(err, &apiErr) || apiErr.Status != http.StatusForbidden {
+		t.Errorf("Expected forbidden for another user, got %v", err)
+	}
+	accepted, err := s.AcceptInvitation(inviteeCtx, invitation.InvitationID)
+	if err != nil {
+		t.Fatalf("AcceptInvitation failed: %v", err)
+	}
+	if accepted.AcceptedBy.UUID != invitee.user.UserID || !accepted.AcceptedDT.Valid {
+		t.Errorf("Expected the invitation accepted by the invitee, got %+v", accepted)
+	}
+	memberships, err := s.GetMembershipsByUser(invitee.ctx, invitee.user.UserID)
+	if err != nil {
+		t.Fatalf("GetMembershipsByUser failed: %v", err)
+	}
+	var role entities.Role
+	for _, membership := range *memberships {
+		if membership.OrgID == f.org.OrgID {
+			role = membership.Role
+		}
+	}
+	if role != entities.RoleEditor {
+		t.Errorf("Expected the invitee to be an editor, got %q", role)
+	}
+
+	// Revoked invitations can't be accepted
+	revoked := &entities.Invitation{Email: invitee.user.Email, Role: entities.RoleOwner, ExpiresDT: time.Now().Add(time.Hour)}
+	if err := s.CreateInvitation(f.ctx, f.org.OrgID, revoked); err != nil {
+		t.Fatalf("CreateInvitation failed: %v", err)
+	}
+	if err := s.DeleteInvitation(f.ctx, f.org.OrgID, revoked.InvitationID); err != nil {
+		t.Fatalf("DeleteInvitation failed: %v", err)
+	}
+	_, err = s.AcceptInvitation(inviteeCtx, revoked.InvitationID)
+	if !errors.As(err, &apiErr) || apiErr.Status != http.StatusNotFound {
+		t.Errorf("Expected not found for a revoked invitation, got %v", err)
+	}
+}
+
+func testAPIKeys(t *testing.T, s storage.LowerThirdsService) {
+	f := newFixture(t, s)
+
+	k := &entities.APIKey{Name: "Conformance Device", Scope: entities.APIKeyScopeRead}
+	key, err := s.CreateAPIKey(f.ctx, f.org.OrgID, k)
+	if err != nil {
+		t.Fatalf("CreateAPIKey failed: %v", err)
+	}
+	keys, err := s.GetAPIKeysByOrg(f.ctx, f.org.OrgID)
+	if err != nil {
+		t.Fatalf("GetAPIKeysByOrg failed: %v", err)
+	}
+	if len(*keys) != 1 || (*keys)[0].KeyID != k.KeyID {
+		t.Errorf("Expected the new key, got %+v", keys)
+	}
+
+	// The key reads as its own user, and isn't one of the org's members
+	authenticated, err := s.AuthenticateAPIKey(context.Background(), key)
+	if err != nil {
+		t.Fatalf("AuthenticateAPIKey failed: %v", err)
+	}
+	keyCtx := context.WithValue(context.Background(), helpers.SocialIDKey, authenticated.SocialID())
+	keyCtx = context.WithValue(keyCtx, helpers.APIKeyKey, authenticated)
+	if _, err := s.GetMeeting(keyCtx, f.meeting.MeetingID); err != nil {
+		t.Errorf("GetMeeting with the key failed: %v", err)
+	}
+	members, err := s.GetOrgUsers(f.ctx, f.org.OrgID)
+	if err != nil {
+		t.Fatalf("GetOrgUsers failed: %v", err)
+	}
+	if len(*members) != 1 || (*members)[0].UserID != f.user.UserID {
+		t.Errorf("Expected only the owner as a member, got %+v", members)
+	}
+
+	// Revoked keys stop working
+	if err := s.DeleteAPIKey(f.ctx, f.org.OrgID, k.KeyID); err != nil {
+		t.Fatalf("DeleteAPIKey failed: %v", err)
+	}
+	if _, err := s.AuthenticateAPIKey(context.Background(), key); err == nil {
+		t.Error("Expected a revoked key to fail")
+	}
+}
+
+func testAudit(t *testing.T, s storage.LowerThirdsService) {
+	f := newFixture(t, s)
+
+	updated := *f.meeting
+	updated.Meeting = "Conformance Meeting Renamed"
+	if err := s.UpdateMeeting(f.ctx, f.meeting.MeetingID, &updated); err != nil {
+		t.Fatalf("UpdateMeeting failed: %v", err)
+	}
+
+	// The meeting's changes are listed newest first, with what changed
+	qp := helpers.DefaultQueryParams()
+	qp.EntityType = string(entities.AuditMeeting)
+	qp.EntityID = f.meeting.MeetingID.String()
+	records, total, err := s.GetAuditLogByOrg(context.WithValue(f.ctx, helpers.QueryParametersKey, qp), f.org.OrgID)
+	if err != nil {
+		t.Fatalf("GetAuditLogByOrg failed: %v", err)
+	}
+	if total != 2 || len(*records) != 2 {
+		t.Fatalf("Expected the meeting's create and update, got %d: %+v", total, records)
+	}
+	update, created := (*records)[0], (*records)[1]
+	if update.Action != entities.AuditUpdate || created.Action != entities.AuditCreate {
+		t.Errorf("Unexpected actions %v, %v", update.Action, created.Action)
+	}
+	if update.ActorID.UUID != f.user.UserID {
+		t.Errorf("Expected the fixture's user, got %+v", update)
+	}
+	if change, ok := update.Diff["meeting"]; !ok || string(change.After) != `"Conformance Meeting Renamed"` {
+		t.Errorf("Expected the meeting's new name, got %+v", update.Diff)
+	}
+}
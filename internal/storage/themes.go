@@ -0,0 +1,361 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"lowerthirdsapi/internal/apierrors"
+	"lowerthirdsapi/internal/entities"
+	"lowerthirdsapi/internal/events"
+	"net/http"
+)
+
+// invalidTheme creates the API error returned for a theme that doesn't match the theme schema
+func invalidTheme(err error) *apierrors.Error {
+	return apierrors.New(http.StatusBadRequest, "INVALID_THEME", "Invalid theme", "%s", err.Error())
+}
+
+// GetOrgTheme loads the org's theme, or the default theme if it hasn't set one
+func (s lowerThirdsService) GetOrgTheme(ctx context.Context, orgID uuid.UUID) (*entities.Theme, error) {
+	s.logger.Debug("GetOrgTheme for orgID ", orgID)
+
+	_, err := s.authorizeOrg(ctx, orgID, entities.RoleViewer)
+	if err != nil {
+		return nil, err
+	}
+	return s.orgTheme(ctx, orgID)
+}
+
+// SetOrgTheme replaces the org's theme. Fields left out of the JSON are taken from the preset it names.
+func (s lowerThirdsService) SetOrgTheme(ctx context.Context, orgID uuid.UUID, data []byte) (*entities.Theme, error) {
+	s.logger.Debug("SetOrgTheme for orgID ", orgID)
+
+	user, err := s.authorizeOrg(ctx, orgID, entities.RoleOwner)
+	if err != nil {
+		return nil, err
+	}
+
+	theme, err := entities.ParseTheme(data)
+	if err != nil {
+		return nil, invalidTheme(err)
+	}
+	if err := theme.Validate(); err != nil {
+		return nil, invalidTheme(err)
+	}
+	raw, err := json.Marshal(theme)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := s.MySqlDB.BeginTxx(ctx, nil)
+	if err != nil {
+		s.logger.Error("SetOrgTheme Begin Error", err)
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	before, err := s.lockStoredTheme(ctx, tx, `SELECT theme FROM OrgThemes WHERE org_id = ?`, orgID)
+	if err != nil {
+		return nil, err
+	}
+	_, err = tx.ExecContext(
+		ctx,
+		`INSERT INTO OrgThemes (org_id, theme) VALUES (?, ?) `+s.dialect.upsert("org_id", "theme"),
+		orgID,
+		raw,
+	)
+	if err != nil {
+		s.logger.Error("SetOrgTheme Error", err)
+		return nil, err
+	}
+	err = s.audit(ctx, tx, user, orgID, entities.AuditOrgTheme, orgID, before, theme)
+	if err != nil {
+		return nil, err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		s.logger.Error("SetOrgTheme Commit Error", err)
+		return nil, err
+	}
+
+	s.publishOrgTheme(ctx, orgID)
+	return &theme, nil
+}
+
+// DeleteOrgTheme puts the org back on the default theme
+func (s lowerThirdsService) DeleteOrgTheme(ctx context.Context, orgID uuid.UUID) error {
+	s.logger.Debug("DeleteOrgTheme for orgID ", orgID)
+
+	user, err := s.authorizeOrg(ctx, orgID, entities.RoleOwner)
+	if err != nil {
+		return err
+	}
+
+	tx, err := s.MySqlDB.BeginTxx(ctx, nil)
+	if err != nil {
+		s.logger.Error("DeleteOrgTheme Begin Error", err)
+		return err
+	}
+	defer tx.Rollback()
+
+	before, err := s.lockStoredTheme(ctx, tx, `SELECT theme FROM OrgThemes WHERE org_id = ?`, orgID)
+	if err != nil {
+		return err
+	}
+	result, err := tx.ExecContext(ctx, `DELETE FROM OrgThemes WHERE org_id = ?`, orgID)
+	if err != nil {
+		s.logger.Error("DeleteOrgTheme Error", err)
+		return err
+	}
+	affectedRows, err := result.RowsAffected()
+	if err == nil {
+		s.logger.Info("DeleteOrgTheme affected rows: ", affectedRows)
+	}
+	if before != nil {
+		err = s.audit(ctx, tx, user, orgID, entities.AuditOrgTheme, orgID, before, nil)
+		if err != nil {
+			return err
+		}
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		s.logger.Error("DeleteOrgTheme Commit Error", err)
+		return err
+	}
+
+	s.publishOrgTheme(ctx, orgID)
+	return nil
+}
+
+// GetMeetingTheme loads the theme a meeting's graphics use: its org's theme with the meeting's overrides applied
+func (s lowerThirdsService) GetMeetingTheme(ctx context.Context, meetingID uuid.UUID) (*entities.Theme, error) {
+	s.logger.Debug("GetMeetingTheme for meetingID ", meetingID)
+
+	_, err := s.authorizeMeeting(ctx, meetingID, entities.RoleViewer)
+	if err != nil {
+		return nil, err
+	}
+	return s.meetingTheme(ctx, meetingID)
+}
+
+// SetMeetingTheme replaces the meeting's overrides of its org's theme. The JSON only needs the fields that differ.
+func (s lowerThirdsService) SetMeetingTheme(ctx context.Context, meetingID uuid.UUID, overrides []byte) (*entities.Theme, error) {
+	s.logger.Debug("SetMeetingTheme for meetingID ", meetingID)
+
+	user, err := s.authorizeMeeting(ctx, meetingID, entities.RoleEditor)
+	if err != nil {
+		return nil, err
+	}
+
+	orgID, err := s.meetingOrgID(ctx, meetingID)
+	if err != nil {
+		return nil, err
+	}
+	base, err := s.orgTheme(ctx, orgID)
+	if err != nil {
+		return nil, err
+	}
+	theme, err := base.Merge(overrides)
+	if err != nil {
+		return nil, invalidTheme(err)
+	}
+	if err := theme.Validate(); err != nil {
+		return nil, invalidTheme(err)
+	}
+
+	tx, err := s.MySqlDB.BeginTxx(ctx, nil)
+	if err != nil {
+		s.logger.Error("SetMeetingTheme Begin Error", err)
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	before, err := s.lockStoredTheme(ctx, tx, `SELECT overrides FROM MeetingThemes WHERE meeting_id = ?`, meetingID)
+	if err != nil {
+		return nil, err
+	}
+	// Store the overrides as sent, so later changes to the org's theme still show through
+	_, err = tx.ExecContext(
+		ctx,
+		`INSERT INTO MeetingThemes (meeting_id, overrides) VALUES (?, ?) `+s.dialect.upsert("meeting_id", "overrides"),
+		meetingID,
+		overrides,
+	)
+	if err != nil {
+		s.logger.Error("SetMeetingTheme Error", err)
+		return nil, err
+	}
+	err = s.audit(ctx, tx, user, orgID, entities.AuditMeetingTheme, meetingID, before, json.RawMessage(overrides))
+	if err != nil {
+		return nil, err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		s.logger.Error("SetMeetingTheme Commit Error", err)
+		return nil, err
+	}
+
+	s.publish(meetingID, events.ThemeChanged, theme)
+	return &theme, nil
+}
+
+// DeleteMeetingTheme removes the meeting's overrides, so it uses its org's theme
+func (s lowerThirdsService) DeleteMeetingTheme(ctx context.Context, meetingID uuid.UUID) error {
+	s.logger.Debug("DeleteMeetingTheme for meetingID ", meetingID)
+
+	user, err := s.authorizeMeeting(ctx, meetingID, entities.RoleEditor)
+	if err != nil {
+		return err
+	}
+	orgID, err := s.meetingOrgID(ctx, meetingID)
+	if err != nil {
+		return err
+	}
+
+	tx, err := s.MySqlDB.BeginTxx(ctx, nil)
+	if err != nil {
+		s.logger.Error("DeleteMeetingTheme Begin Error", err)
+		return err
+	}
+	defer tx.Rollback()
+
+	before, err := s.lockStoredTheme(ctx, tx, `SELECT overrides FROM MeetingThemes WHERE meeting_id = ?`, meetingID)
+	if err != nil {
+		return err
+	}
+	result, err := tx.ExecContext(ctx, `DELETE FROM MeetingThemes WHERE meeting_id = ?`, meetingID)
+	if err != nil {
+		s.logger.Error("DeleteMeetingTheme Error", err)
+		return err
+	}
+	affectedRows, err := result.RowsAffected()
+	if err == nil {
+		s.logger.Info("DeleteMeetingTheme affected rows: ", affectedRows)
+	}
+	if before != nil {
+		err = s.audit(ctx, tx, user, orgID, entities.AuditMeetingTheme, meetingID, before, nil)
+		if err != nil {
+			return err
+		}
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		s.logger.Error("DeleteMeetingTheme Commit Error", err)
+		return err
+	}
+
+	theme, err := s.meetingTheme(ctx, meetingID)
+	if err == nil {
+		s.publish(meetingID, events.ThemeChanged, theme)
+	}
+	return nil
+}
+
+// lockStoredTheme runs query in a transaction to load the JSON of an org's theme or a meeting's overrides by id,
+// locking it until the transaction ends. It returns nil, not an empty value, when there's none, so it can be passed
+// straight to audit.
+func (s lowerThirdsService) lockStoredTheme(ctx context.Context, tx *sqlx.Tx, query string, id uuid.UUID) (interface{}, error) {
+	var raw []byte
+	err := tx.GetContext(ctx, &raw, query+s.dialect.forUpdate(), id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		s.logger.Error("lockStoredTheme Error", err)
+		return nil, err
+	}
+	return json.RawMessage(raw), nil
+}
+
+// orgTheme loads an org's theme without checking the caller
+func (s lowerThirdsService) orgTheme(ctx context.Context, orgID uuid.UUID) (*entities.Theme, error) {
+	var raw []byte
+	err := s.MySqlDB.GetContext(ctx, &raw, `SELECT theme FROM OrgThemes WHERE org_id = ?`, orgID)
+	if errors.Is(err, sql.ErrNoRows) {
+		theme, _ := entities.ThemeByName("")
+		return &theme, nil
+	}
+	if err != nil {
+		s.logger.Error("orgTheme Error", err)
+		return nil, err
+	}
+
+	var theme entities.Theme
+	err = json.Unmarshal(raw, &theme)
+	if err != nil {
+		s.logger.Error("orgTheme Error", err)
+		return nil, err
+	}
+	return &theme, nil
+}
+
+// meetingTheme loads a meeting's theme without checking the caller, so overlays can use it. Overrides that no
+// longer make a valid theme with the org's current theme are ignored.
+func (s lowerThirdsService) meetingTheme(ctx context.Context, meetingID uuid.UUID) (*entities.Theme, error) {
+	orgID, err := s.meetingOrgID(ctx, meetingID)
+	if err != nil {
+		return nil, err
+	}
+	base, err := s.orgTheme(ctx, orgID)
+	if err != nil {
+		return nil, err
+	}
+
+	var overrides []byte
+	err = s.MySqlDB.GetContext(ctx, &overrides, `SELECT overrides FROM MeetingThemes WHERE meeting_id = ?`, meetingID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return base, nil
+	}
+	if err != nil {
+		s.logger.Error("meetingTheme Error", err)
+		return nil, err
+	}
+
+	theme, err := base.Merge(overrides)
+	if err == nil {
+		err = theme.Validate()
+	}
+	if err != nil {
+		s.logger.Warn("meetingTheme ignoring overrides for meetingID ", meetingID, ": ", err)
+		return base, nil
+	}
+	return &theme, nil
+}
+
+// meetingOrgID finds the org a meeting belongs to
+func (s lowerThirdsService) meetingOrgID(ctx context.Context, meetingID uuid.UUID) (uuid.UUID, error) {
+	var orgID uuid.UUID
+	err := s.MySqlDB.GetContext(ctx, &orgID, `SELECT org_id FROM Meetings WHERE id = ? AND deleted_dt IS NULL`, meetingID)
+	if err != nil {
+		s.logger.Error("meetingOrgID Error", err)
+		return uuid.Nil, err
+	}
+	return orgID, nil
+}
+
+// publishOrgTheme tells each of the org's meetings its theme has changed
+func (s lowerThirdsService) publishOrgTheme(ctx context.Context, orgID uuid.UUID) {
+	if s.events == nil {
+		return
+	}
+	var meetingIDs []uuid.UUID
+	err := s.MySqlDB.SelectContext(ctx, &meetingIDs, `SELECT id FROM Meetings WHERE org_id = ? AND deleted_dt IS NULL`, orgID)
+	if err != nil {
+		s.logger.Error("publishOrgTheme Error", err)
+		return
+	}
+	for _, meetingID := range meetingIDs {
+		theme, err := s.meetingTheme(ctx, meetingID)
+		if err != nil {
+			continue
+		}
+		s.publish(meetingID, events.ThemeChanged, theme)
+	}
+}
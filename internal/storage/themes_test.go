@@ -0,0 +1,87 @@
+package storage
+
+import (
+	"errors"
+	"lowerthirdsapi/internal/apierrors"
+	"lowerthirdsapi/internal/entities"
+	"lowerthirdsapi/internal/testutil"
+	"net/http"
+	"testing"
+)
+
+func TestThemes(t *testing.T) {
+	testutil.SetupTest(t)
+	defer testutil.TeardownTest()
+
+	service := New(testutil.TestDB, testutil.TestLogger)
+	_, org, meeting := testutil.CreateTestData(t, service)
+
+	// Orgs start on the default theme
+	theme, err := service.GetOrgTheme(testutil.TestCtx, org.OrgID)
+	if err != nil {
+		t.Fatalf("GetOrgTheme failed: %v", err)
+	}
+	if theme.Name != entities.DefaultThemeName {
+		t.Errorf("Expected the default theme, got %+v", theme)
+	}
+
+	theme, err = service.SetOrgTheme(testutil.TestCtx, org.OrgID, []byte(`{"name": "light", "accent_color": "#8B0000"}`))
+	if err != nil {
+		t.Fatalf("SetOrgTheme failed: %v", err)
+	}
+	if theme.AccentColor != "#8B0000" || theme.BackgroundColor != entities.ThemePresets["light"].BackgroundColor {
+		t.Errorf("Expected the light theme with a red accent, got %+v", theme)
+	}
+
+	var apiErr *apierrors.Error
+	_, err = service.SetOrgTheme(testutil.TestCtx, org.OrgID, []byte(`{"primary_size": 2}`))
+	if !errors.As(err, &apiErr) || apiErr.Status != http.StatusBadRequest || apiErr.Code != "INVALID_THEME" {
+		t.Errorf("Expected an invalid theme error, got %v", err)
+	}
+
+	// Meetings use their org's theme, with their own overrides on top
+	theme, err = service.GetMeetingTheme(testutil.TestCtx, meeting.MeetingID)
+	if err != nil {
+		t.Fatalf("GetMeetingTheme failed: %v", err)
+	}
+	if theme.AccentColor != "#8B0000" {
+		t.Errorf("Expected the org's theme, got %+v", theme)
+	}
+	_, err = service.SetMeetingTheme(testutil.TestCtx, meeting.MeetingID, []byte(`{"margin_bottom": 140}`))
+	if err != nil {
+		t.Fatalf("SetMeetingTheme failed: %v", err)
+	}
+	_, err = service.SetMeetingTheme(testutil.TestCtx, meeting.MeetingID, []byte(`{"colour": "#FFFFFF"}`))
+	if !errors.As(err, &apiErr) || apiErr.Code != "INVALID_THEME" {
+		t.Errorf("Expected an invalid theme error for an unknown field, got %v", err)
+	}
+
+	// Later changes to the org's theme still show through the overrides
+	_, err = service.SetOrgTheme(testutil.TestCtx, org.OrgID, []byte(`{"name": "minimal"}`))
+	if err != nil {
+		t.Fatalf("SetOrgTheme failed: %v", err)
+	}
+	theme, err = service.GetMeetingTheme(testutil.TestCtx, meeting.MeetingID)
+	if err != nil {
+		t.Fatalf("GetMeetingTheme failed: %v", err)
+	}
+	if theme.Name != "minimal" || theme.MarginBottom != 140 {
+		t.Errorf("Expected the minimal theme with the meeting's margin, got %+v", theme)
+	}
+
+	err = service.DeleteMeetingTheme(testutil.TestCtx, meeting.MeetingID)
+	if err != nil {
+		t.Fatalf("DeleteMeetingTheme failed: %v", err)
+	}
+	err = service.DeleteOrgTheme(testutil.TestCtx, org.OrgID)
+	if err != nil {
+		t.Fatalf("DeleteOrgTheme failed: %v", err)
+	}
+	theme, err = service.GetMeetingTheme(testutil.TestCtx, meeting.MeetingID)
+	if err != nil {
+		t.Fatalf("GetMeetingTheme failed: %v", err)
+	}
+	if *theme != entities.ThemePresets[entities.DefaultThemeName] {
+		t.Errorf("Expected the default theme after deleting both, got %+v", theme)
+	}
+}
@@ -0,0 +1,198 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"lowerthirdsapi/internal/apierrors"
+	"lowerthirdsapi/internal/entities"
+	"lowerthirdsapi/internal/events"
+	"net/http"
+	"time"
+)
+
+// GetMeetingTimers loads the timers of a meeting's agenda, in agenda order. Speakers with an expected duration and
+// timer items always have one, armed if nobody has used it yet; other items only once they've been used.
+func (s lowerThirdsService) GetMeetingTimers(ctx context.Context, meetingID uuid.UUID) (*[]entities.Timer, error) {
+	s.logger.Debug("GetMeetingTimers for meetingID ", meetingID)
+
+	_, err := s.authorizeMeeting(ctx, meetingID, entities.RoleViewer)
+	if err != nil {
+		return nil, err
+	}
+
+	items, err := s.GetItemsByMeeting(ctx, meetingID)
+	if err != nil {
+		return nil, err
+	}
+	var meeting entities.Meeting
+	err = s.MySqlDB.GetContext(ctx, &meeting, `SELECT * FROM Meetings WHERE id = ? AND deleted_dt IS NULL`, meetingID)
+	if err != nil {
+		s.logger.Error("GetMeetingTimers Error", err)
+		return nil, err
+	}
+
+	var stored []entities.Timer
+	err = s.MySqlDB.SelectContext(ctx, &stored, `SELECT * FROM Timers WHERE meeting_id = ?`, meetingID)
+	if err != nil {
+		s.logger.Error("GetMeetingTimers Error", err)
+		return nil, err
+	}
+	byItem := make(map[uuid.UUID]entities.Timer, len(stored))
+	for _, timer := range stored {
+		byItem[timer.ItemID] = timer
+	}
+
+	now := time.Now().UTC()
+	timers := []entities.Timer{}
+	for _, item := range *items {
+		timer, ok := byItem[item.GetID()]
+		if !ok {
+			timer = entities.ArmTimer(item, meeting)
+			if timer.Kind == entities.TimerKindStopwatch {
+				continue
+			}
+		}
+		timer.Read(now)
+		timers = append(timers, timer)
+	}
+	return &timers, nil
+}
+
+// GetItemTimer loads an item's timer, armed if nobody has used it yet
+func (s lowerThirdsService) GetItemTimer(ctx context.Context, itemID uuid.UUID) (*entities.Timer, error) {
+	s.logger.Debug("GetItemTimer for itemID ", itemID)
+
+	_, err := s.authorizeItem(ctx, itemID, entities.RoleViewer)
+	if err != nil {
+		return nil, err
+	}
+	item, meeting, err := s.timerItem(ctx, itemID)
+	if err != nil {
+		return nil, err
+	}
+
+	timer, err := s.itemTimer(ctx, s.MySqlDB, item, meeting, false)
+	if err != nil {
+		return nil, err
+	}
+	timer.Read(time.Now().UTC())
+	return timer, nil
+}
+
+// UpdateItemTimer applies an operator action to an item's timer. amount is only used by add_time.
+func (s lowerThirdsService) UpdateItemTimer(ctx context.Context, itemID uuid.UUID, action entities.TimerAction, amount time.Duration) (*entities.Timer, error) {
+	s.logger.Debug("UpdateItemTimer for itemID ", itemID, " action ", action, " amount ", amount)
+
+	user, err := s.authorizeItem(ctx, itemID, entities.RoleOperator)
+	if err != nil {
+		return nil, err
+	}
+	item, meeting, err := s.timerItem(ctx, itemID)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := s.MySqlDB.BeginTxx(ctx, nil)
+	if err != nil {
+		s.logger.Error("UpdateItemTimer Begin Error", err)
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	// Lock the row so concurrent operators apply their actions one at a time
+	timer, err := s.itemTimer(ctx, tx, item, meeting, true)
+	if err != nil {
+		return nil, err
+	}
+	previous := *timer
+	now := time.Now().UTC()
+	err = timer.Apply(action, amount, entities.ArmTimer(item, *meeting), now)
+	if err != nil {
+		return nil, timerError(err)
+	}
+
+	_, err = tx.NamedExecContext(
+		ctx,
+		`INSERT INTO Timers (
+		  item_id, meeting_id, version, kind, duration_ms, target_dt, added_ms, elapsed_ms, started_dt
+		) VALUES (
+		  :item_id, :meeting_id, :version, :kind, :duration_ms, :target_dt, :added_ms, :elapsed_ms, :started_dt
+		) `+s.dialect.upsert("item_id",
+			"version", "kind", "duration_ms", "target_dt", "added_ms", "elapsed_ms", "started_dt",
+		),
+		timer,
+	)
+	if err != nil {
+		s.logger.Error("UpdateItemTimer Error", err)
+		return nil, err
+	}
+	err = s.audit(ctx, tx, user, meeting.OrgID, entities.AuditTimer, itemID, previous, timer)
+	if err != nil {
+		return nil, err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		s.logger.Error("UpdateItemTimer Commit Error", err)
+		return nil, err
+	}
+
+	timer.Read(now)
+	s.publish(meeting.MeetingID, events.TimerChanged, timer)
+	return timer, nil
+}
+
+// timerItem loads an item and its meeting for its timer. The caller has authorized the item.
+func (s lowerThirdsService) timerItem(ctx context.Context, itemID uuid.UUID) (entities.Item, *entities.Meeting, error) {
+	meetingID, err := s.itemMeetingID(ctx, itemID)
+	if err != nil {
+		return nil, nil, err
+	}
+	var meeting entities.Meeting
+	err = s.MySqlDB.GetContext(ctx, &meeting, `SELECT * FROM Meetings WHERE id = ? AND deleted_dt IS NULL`, meetingID)
+	if err != nil {
+		s.logger.Error("timerItem Error", err)
+		return nil, nil, err
+	}
+	item, err := s.getMeetingItem(ctx, meeting.MeetingID, itemID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if item == nil {
+		return nil, nil, sql.ErrNoRows
+	}
+	return item, &meeting, nil
+}
+
+// itemTimer loads an item's stored timer, or arms a new one. forUpdate locks the stored row in a transaction.
+func (s lowerThirdsService) itemTimer(ctx context.Context, q sqlx.QueryerContext, item entities.Item, meeting *entities.Meeting, forUpdate bool) (*entities.Timer, error) {
+	query := `SELECT * FROM Timers WHERE item_id = ?`
+	if forUpdate {
+		query += s.dialect.forUpdate()
+	}
+	var timer entities.Timer
+	err := sqlx.GetContext(ctx, q, &timer, query, item.GetID())
+	if errors.Is(err, sql.ErrNoRows) {
+		timer = entities.ArmTimer(item, *meeting)
+		return &timer, nil
+	}
+	if err != nil {
+		s.logger.Error("itemTimer Error", err)
+		return nil, err
+	}
+	return &timer, nil
+}
+
+func timerError(err error) error {
+	switch {
+	case errors.Is(err, entities.ErrUnknownTimerAction):
+		return apierrors.New(http.StatusBadRequest, "INVALID_TIMER_ACTION", "Invalid timer action", err.Error())
+	case errors.Is(err, entities.ErrTimerRunning), errors.Is(err, entities.ErrTimerNotRunning), errors.Is(err, entities.ErrTimerFixed):
+		return apierrors.New(http.StatusConflict, "TIMER_CONFLICT", "Timer conflict", err.Error())
+	default:
+		return err
+	}
+}
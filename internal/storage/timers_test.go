@@ -0,0 +1,97 @@
+package storage
+
+import (
+	"errors"
+	"lowerthirdsapi/internal/apierrors"
+	"lowerthirdsapi/internal/entities"
+	"lowerthirdsapi/internal/testutil"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"gopkg.in/guregu/null.v4"
+)
+
+func TestTimers(t *testing.T) {
+	testutil.SetupTest(t)
+	defer testutil.TeardownTest()
+
+	service := New(testutil.TestDB, testutil.TestLogger)
+	_, _, meeting := testutil.CreateTestData(t, service)
+
+	speaker := &entities.SpeakerItem{
+		SpeakerItemID:    uuid.New(),
+		MeetingID:        meeting.MeetingID,
+		ItemType:         "speaker",
+		ItemOrder:        1,
+		MeetingRole:      "Test Role",
+		SpeakerName:      "Test Speaker",
+		ExpectedDuration: null.IntFrom(5),
+	}
+	blank := &entities.BlankItem{
+		BlankItemID: uuid.New(),
+		MeetingID:   meeting.MeetingID,
+		ItemType:    "blank",
+		ItemOrder:   2,
+		MeetingRole: "Test Role",
+	}
+	for _, item := range []entities.Item{speaker, blank} {
+		err := service.CreateItem(testutil.TestCtx, item)
+		if err != nil {
+			t.Fatalf("CreateItem failed: %v", err)
+		}
+	}
+
+	// Speakers with a duration are armed before anyone touches them, blank items aren't listed until they're used
+	timers, err := service.GetMeetingTimers(testutil.TestCtx, meeting.MeetingID)
+	if err != nil {
+		t.Fatalf("GetMeetingTimers failed: %v", err)
+	}
+	if len(*timers) != 1 || (*timers)[0].ItemID != speaker.SpeakerItemID || (*timers)[0].Kind != entities.TimerKindCountdown {
+		t.Fatalf("Expected the speaker's countdown, got %+v", timers)
+	}
+
+	timer, err := service.UpdateItemTimer(testutil.TestCtx, speaker.SpeakerItemID, entities.TimerActionStart, 0)
+	if err != nil {
+		t.Fatalf("UpdateItemTimer failed: %v", err)
+	}
+	if !timer.Reading.Running || timer.Version != 1 {
+		t.Errorf("Expected a running timer at version 1, got %+v", timer)
+	}
+	_, err = service.UpdateItemTimer(testutil.TestCtx, speaker.SpeakerItemID, entities.TimerActionAddTime, time.Minute)
+	if err != nil {
+		t.Fatalf("UpdateItemTimer failed: %v", err)
+	}
+
+	// The timer is read back from the database
+	timer, err = service.GetItemTimer(testutil.TestCtx, speaker.SpeakerItemID)
+	if err != nil {
+		t.Fatalf("GetItemTimer failed: %v", err)
+	}
+	if timer.Version != 2 || timer.AddedMS != 60000 || !timer.StartedDT.Valid {
+		t.Errorf("Unexpected timer: %+v", timer)
+	}
+	if remaining := timer.Reading.RemainingMS; remaining > 360000 || remaining < 350000 {
+		t.Errorf("Expected about 6 minutes left, got %dms", remaining)
+	}
+
+	var apiErr *apierrors.Error
+	_, err = service.UpdateItemTimer(testutil.TestCtx, speaker.SpeakerItemID, entities.TimerActionResume, 0)
+	if !errors.As(err, &apiErr) || apiErr.Status != http.StatusConflict {
+		t.Errorf("Expected a conflict resuming a running timer, got %v", err)
+	}
+
+	// Stopwatches show up once they've been started
+	_, err = service.UpdateItemTimer(testutil.TestCtx, blank.BlankItemID, entities.TimerActionStart, 0)
+	if err != nil {
+		t.Fatalf("UpdateItemTimer failed: %v", err)
+	}
+	timers, err = service.GetMeetingTimers(testutil.TestCtx, meeting.MeetingID)
+	if err != nil {
+		t.Fatalf("GetMeetingTimers failed: %v", err)
+	}
+	if len(*timers) != 2 || (*timers)[1].Kind != entities.TimerKindStopwatch {
+		t.Errorf("Expected the speaker's countdown and a stopwatch, got %+v", timers)
+	}
+}
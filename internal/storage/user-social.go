@@ -2,15 +2,20 @@ package storage
 
 import (
 	"context"
+	"database/sql"
+	"errors"
+	"lowerthirdsapi/internal/apierrors"
 	"lowerthirdsapi/internal/entities"
+	"net/http"
+
+	"github.com/google/uuid"
 )
 
 func (s lowerThirdsService) GetUserBySocialID(ctx context.Context, socialID string) (*entities.User, error) {
-	// TODO: setting this in ctx...
-	// userID := ctx.Value(helpers.UserIDKey).(string)
 	s.logger.Debug("GetUserBySocialID for socialID ", socialID)
 	var user entities.User
-	err := s.MySqlDB.Get(
+	err := s.MySqlDB.GetContext(
+		ctx,
 		&user,
 		`SELECT *
         FROM Users
@@ -24,3 +29,118 @@ func (s lowerThirdsService) GetUserBySocialID(ctx context.Context, socialID stri
 	}
 	return &user, nil
 }
+
+// ProvisionUser makes sure the signed-in account in profile, which has its social ID, email, name and photo, has a
+// user. The account's user is found by social ID, or else, if emailVerified says the account proved it owns the
+// email, a user with the same email and no social ID is linked to the account, or else a new user is made. With
+// refresh, the name and photo of a user the account already had are updated from profile, and the email too if it's
+// verified; a linked user always gets any name and photo it was missing. An unverified email that another user has
+// is a conflict.
+func (s lowerThirdsService) ProvisionUser(ctx context.Context, profile *entities.User, emailVerified bool, refresh bool) (*entities.User, error) {
+	if !profile.SocialID.Valid || profile.SocialID.String == "" {
+		return nil, errors.New("socialID is required to provision a user")
+	}
+	if profile.Email == "" {
+		return nil, apierrors.New(http.StatusBadRequest, "EMAIL_REQUIRED", "Email required",
+			"the account has no email address to provision a user with")
+	}
+	s.logger.Debug("ProvisionUser for socialID ", profile.SocialID.String)
+
+	tx, err := s.MySqlDB.BeginTxx(ctx, nil)
+	if err != nil {
+		s.logger.Error("ProvisionUser Begin Error", err)
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	// The user as it was, for the audit log; nil when it's created
+	var before interface{}
+	var user entities.User
+	err = tx.GetContext(
+		ctx,
+		&user,
+		`SELECT * FROM Users WHERE social_id = ? AND deleted_dt IS NULL`+s.dialect.forUpdate(),
+		profile.SocialID,
+	)
+	switch {
+	case err == nil && refresh:
+		s.logger.Info("ProvisionUser refreshing userID ", user.UserID)
+		before = user
+		email := user.Email
+		if emailVerified {
+			email = profile.Email
+		}
+		_, err = tx.ExecContext(
+			ctx,
+			`UPDATE Users SET email = ?, full_name = ?, photo_url = ? WHERE id = ?`,
+			email,
+			profile.FullName,
+			profile.PhotoURL,
+			user.UserID,
+		)
+	case err == nil:
+		return &user, nil
+	case errors.Is(err, sql.ErrNoRows):
+		// Only an account that proved it owns the email may take over the user made for it
+		if emailVerified {
+			err = tx.GetContext(
+				ctx,
+				&user,
+				`SELECT * FROM Users WHERE email = ? AND social_id IS NULL AND deleted_dt IS NULL`+s.dialect.forUpdate(),
+				profile.Email,
+			)
+		}
+		if err == nil {
+			s.logger.Info("ProvisionUser linking userID ", user.UserID)
+			before = user
+			_, err = tx.ExecContext(
+				ctx,
+				`UPDATE Users
+				SET social_id = ?,
+				  full_name = COALESCE(full_name, ?),
+				  photo_url = COALESCE(photo_url, ?)
+				WHERE id = ?`,
+				profile.SocialID,
+				profile.FullName,
+				profile.PhotoURL,
+				user.UserID,
+			)
+		} else if errors.Is(err, sql.ErrNoRows) {
+			user.UserID = uuid.New()
+			s.logger.Info("ProvisionUser creating userID ", user.UserID)
+			_, err = tx.ExecContext(
+				ctx,
+				`INSERT INTO Users (id, email, full_name, social_id, photo_url) VALUES (?, ?, ?, ?, ?)`,
+				user.UserID,
+				profile.Email,
+				profile.FullName,
+				profile.SocialID,
+				profile.PhotoURL,
+			)
+		}
+	}
+	if IsDuplicate(err) {
+		return nil, apierrors.New(http.StatusConflict, "EMAIL_TAKEN", "Email taken",
+			"email %s belongs to another account", profile.Email)
+	}
+	if err != nil {
+		s.logger.Error("ProvisionUser Error", err)
+		return nil, err
+	}
+
+	err = tx.GetContext(ctx, &user, `SELECT * FROM Users WHERE id = ?`, user.UserID)
+	if err != nil {
+		s.logger.Error("ProvisionUser Select Error", err)
+		return nil, err
+	}
+	err = s.audit(ctx, tx, &user, uuid.Nil, entities.AuditUser, user.UserID, before, user)
+	if err != nil {
+		return nil, err
+	}
+	err = tx.Commit()
+	if err != nil {
+		s.logger.Error("ProvisionUser Commit Error", err)
+		return nil, err
+	}
+	return &user, nil
+}
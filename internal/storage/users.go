@@ -3,14 +3,24 @@ package storage
 import (
 	"context"
 	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
 	"lowerthirdsapi/internal/entities"
 )
 
 func (s lowerThirdsService) CreateUser(ctx context.Context, u *entities.User) error {
 	s.logger.Debug("CreateUser")
 
-	// TODO: put some user-level security on this query
-	_, err := s.MySqlDB.ExecContext(
+	// Any logged-in user may create an account; orgs grant access through roles. The caller may not have a user yet.
+	actor, _ := s.currentUser(ctx)
+
+	tx, err := s.MySqlDB.BeginTxx(ctx, nil)
+	if err != nil {
+		s.logger.Error("CreateUser Begin Error", err)
+		return err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.ExecContext(
 		ctx,
 		`INSERT INTO Users (id, email, first_name, full_name, last_name, social_id, photo_url) 
 		VALUES (?, ?, ?, ?, ?, ?, ?)`,
@@ -26,14 +36,39 @@ func (s lowerThirdsService) CreateUser(ctx context.Context, u *entities.User) er
 		s.logger.Error("CreateUser Error", err)
 		return err
 	}
+	err = s.audit(ctx, tx, actor, uuid.Nil, entities.AuditUser, u.UserID, nil, u)
+	if err != nil {
+		return err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		s.logger.Error("CreateUser Commit Error", err)
+		return err
+	}
 	return nil
 }
 
 func (s lowerThirdsService) DeleteUser(ctx context.Context, userID uuid.UUID) error {
 	s.logger.Debug("DeleteUser for userID ", userID)
 
-	// TODO: put some user level security on this query
-	result, err := s.MySqlDB.ExecContext(ctx, `
+	user, err := s.authorizeUser(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	tx, err := s.MySqlDB.BeginTxx(ctx, nil)
+	if err != nil {
+		s.logger.Error("DeleteUser Begin Error", err)
+		return err
+	}
+	defer tx.Rollback()
+
+	before, err := s.lockUser(ctx, tx, userID)
+	if err != nil {
+		return err
+	}
+	result, err := tx.ExecContext(ctx, `
 		UPDATE Users 
 		SET deleted_dt = CURRENT_TIMESTAMP 
 		WHERE id = ?
@@ -50,14 +85,27 @@ func (s lowerThirdsService) DeleteUser(ctx context.Context, userID uuid.UUID) er
 		s.logger.Info("DeleteUser affected rows: ", affectedRows)
 
 	}
+	err = s.audit(ctx, tx, user, uuid.Nil, entities.AuditUser, userID, before, nil)
+	if err != nil {
+		return err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		s.logger.Error("DeleteUser Commit Error", err)
+		return err
+	}
 	return nil
 }
 
-func (s lowerThirdsService) GetMeetingsByUser(ctx context.Context, userID uuid.UUID) (*[]entities.Meeting, error) {
+// GetMeetingsByUser lists a page of the meetings in a user's orgs by date, with the number on every page. DateFrom
+// and DateTo filter on the meeting date.
+func (s lowerThirdsService) GetMeetingsByUser(ctx context.Context, userID uuid.UUID) (*[]entities.Meeting, int, error) {
 	s.logger.Debug("GetMeetings for userID ", userID)
 
-	var meetings []entities.Meeting
-	err := s.MySqlDB.Select(
+	meetings := []entities.Meeting{}
+	total, err := s.selectPage(
+		ctx,
 		&meetings,
 		`SELECT m.*
 		  FROM OrgUsers ou
@@ -71,12 +119,15 @@ func (s lowerThirdsService) GetMeetingsByUser(ctx context.Context, userID uuid.U
 		  ON ou.org_id = m.org_id
 		  AND m.deleted_dt IS NULL
 		WHERE ou.user_id = ?
-		  AND ou.deleted_dt IS NULL`, userID)
+		  AND ou.deleted_dt IS NULL`,
+		"m.meeting_date",
+		"m.meeting_date, m.id",
+		userID,
+	)
 	if err != nil {
-		s.logger.Error(err)
-		return nil, err
+		return nil, 0, err
 	}
-	return &meetings, nil
+	return &meetings, total, nil
 }
 
 func (s lowerThirdsService) GetUser(ctx context.Context, userID uuid.UUID) (*entities.User, error) {
@@ -90,31 +141,47 @@ func (s lowerThirdsService) GetUser(ctx context.Context, userID uuid.UUID) (*ent
 	return &user, nil
 }
 
-func (s lowerThirdsService) GetUsers(ctx context.Context) (*[]entities.User, error) {
+// GetUsers lists a page of users by email, with the number on every page. DateFrom and DateTo filter on when the
+// user was made.
+func (s lowerThirdsService) GetUsers(ctx context.Context) (*[]entities.User, int, error) {
 	s.logger.Debug("GetUsers")
 
-	var users []entities.User
-	err := s.MySqlDB.Select(&users, `SELECT * FROM Users`)
+	users := []entities.User{}
+	total, err := s.selectPage(ctx, &users, `SELECT * FROM Users WHERE 1 = 1`, "inserted_dt", "email, id")
 	if err != nil {
-		s.logger.Error(err)
-		return nil, err
+		return nil, 0, err
 	}
-	return &users, nil
+	return &users, total, nil
 }
 
 func (s lowerThirdsService) UpdateUser(ctx context.Context, userID uuid.UUID, u *entities.User) error {
 	s.logger.Debug("UpdateUser")
 
-	// TODO: put some user level security on this query
-	result, err := s.MySqlDB.ExecContext(
+	user, err := s.authorizeUser(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	tx, err := s.MySqlDB.BeginTxx(ctx, nil)
+	if err != nil {
+		s.logger.Error("UpdateUser Begin Error", err)
+		return err
+	}
+	defer tx.Rollback()
+
+	before, err := s.lockUser(ctx, tx, userID)
+	if err != nil {
+		return err
+	}
+	result, err := tx.ExecContext(
 		ctx,
 		`UPDATE Users 
         SET id = ?, 
           email = ?, 
           first_name = ?, 
           full_name = ?, 
-          last_name = ?
-          social_id = ?
+          last_name = ?,
+          social_id = ?,
           photo_url = ?
         WHERE id = ?`,
 		u.UserID,
@@ -134,5 +201,30 @@ func (s lowerThirdsService) UpdateUser(ctx context.Context, userID uuid.UUID, u
 	if err == nil {
 		s.logger.Info("UpdateUser affected rows: ", affectedRows)
 	}
+	after, err := s.lockUser(ctx, tx, u.UserID)
+	if err != nil {
+		return err
+	}
+	err = s.audit(ctx, tx, user, uuid.Nil, entities.AuditUser, u.UserID, before, after)
+	if err != nil {
+		return err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		s.logger.Error("UpdateUser Commit Error", err)
+		return err
+	}
 	return nil
 }
+
+// lockUser loads a user in a transaction, locking it until the transaction ends
+func (s lowerThirdsService) lockUser(ctx context.Context, tx *sqlx.Tx, userID uuid.UUID) (*entities.User, error) {
+	var user entities.User
+	err := tx.GetContext(ctx, &user, `SELECT * FROM Users WHERE id = ? AND deleted_dt IS NULL`+s.dialect.forUpdate(), userID)
+	if err != nil {
+		s.logger.Error("lockUser Error", err)
+		return nil, err
+	}
+	return &user, nil
+}
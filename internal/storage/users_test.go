@@ -1,6 +1,10 @@
 package storage
 
 import (
+	"context"
+	"database/sql"
+	"errors"
+	"lowerthirdsapi/internal/helpers"
 	"lowerthirdsapi/internal/testutil"
 	"testing"
 )
@@ -34,3 +38,28 @@ func TestCreateAndGetUser(t *testing.T) {
 		t.Errorf("Expected LastName %v, got %v", user.LastName.String, retrievedUser.LastName.String)
 	}
 }
+
+func TestResolvedUserInContext(t *testing.T) {
+	testutil.SetupTest(t)
+	defer testutil.TeardownTest()
+
+	service := New(testutil.TestDB, testutil.TestLogger)
+	user, org, _ := testutil.CreateTestData(t, service)
+
+	// A user resolved by the API is used as is, without looking up the social ID
+	ctx := context.WithValue(context.Background(), helpers.SocialIDKey, "unknown-social-id")
+	ctx = context.WithValue(ctx, helpers.UserIDKey, user)
+	retrievedOrg, err := service.GetOrg(ctx, org.OrgID)
+	if err != nil {
+		t.Fatalf("GetOrg with a resolved user failed: %v", err)
+	}
+	if retrievedOrg.OrgID != org.OrgID {
+		t.Errorf("Expected OrgID %v, got %v", org.OrgID, retrievedOrg.OrgID)
+	}
+
+	// Without one, the social ID has to belong to a user
+	_, err = service.GetOrg(context.WithValue(context.Background(), helpers.SocialIDKey, "unknown-social-id"), org.OrgID)
+	if !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("Expected no rows for an unknown social ID, got %v", err)
+	}
+}
@@ -2,8 +2,11 @@ package testutil
 
 import (
 	"context"
+	"io"
 	"lowerthirdsapi/internal/entities"
 	"lowerthirdsapi/internal/helpers"
+	"lowerthirdsapi/internal/migrations"
+	"lowerthirdsapi/internal/sqlitedb"
 	"os"
 	"testing"
 	"time"
@@ -71,9 +74,52 @@ func TestMain(m *testing.M) {
 	os.Exit(code)
 }
 
+// SetupTest connects TestDB and resets the test logger and context. TestDB is the lowerthirds_test MySQL database,
+// or with TEST_STORAGE_BACKEND=memory, a new in-memory SQLite database that needs no MySQL.
 func SetupTest(t *testing.T) {
-	// Setup test database
-	var err error
+	if os.Getenv("TEST_STORAGE_BACKEND") == "memory" {
+		TestDB = memoryTestDB(t)
+	} else {
+		TestDB = mysqlTestDB(t)
+	}
+
+	// Setup test tables
+	if err := setupTestTables(TestDB); err != nil {
+		t.Fatalf("Failed to setup test tables: %v", err)
+	}
+
+	// Setup test logger
+	logger := logrus.New()
+	logger.SetOutput(os.Stdout)
+	logger.SetLevel(logrus.InfoLevel)
+	TestLogger = logger.WithField("test", true)
+
+	// Setup test context
+	TestCtx = context.Background()
+	TestCtx = context.WithValue(TestCtx, helpers.SocialIDKey, "test-social-id")
+}
+
+// memoryTestDB opens a new in-memory SQLite database with the latest schema, as the memory backend does
+func memoryTestDB(t *testing.T) *sqlx.DB {
+	db, err := sqlitedb.OpenMemory()
+	if err != nil {
+		t.Fatalf("Failed to open in-memory database: %v", err)
+	}
+
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	m, err := migrations.New(db, logrus.NewEntry(logger))
+	if err != nil {
+		t.Fatalf("Failed to load migrations: %v", err)
+	}
+	if _, err := m.Up(context.Background(), 0); err != nil {
+		t.Fatalf("Failed to migrate in-memory database: %v", err)
+	}
+	return db
+}
+
+// mysqlTestDB connects to lowerthirds_test and deletes the rows earlier tests left behind
+func mysqlTestDB(t *testing.T) *sqlx.DB {
 	// First, connect without specifying a database
 	db, err := sqlx.Connect("mysql", "root:@tcp(localhost:3306)/")
 	if err != nil {
@@ -86,44 +132,39 @@ func SetupTest(t *testing.T) {
 	}
 	db.Close()
 	// Now connect to the test database
-	TestDB, err = sqlx.Connect("mysql", "root:@tcp(localhost:3306)/lowerthirds_test?parseTime=true")
+	db, err = sqlx.Connect("mysql", "root:@tcp(localhost:3306)/lowerthirds_test?parseTime=true")
 	if err != nil {
 		t.Fatalf("Failed to connect to test database: %v", err)
 	}
 
 	// Clean up any existing test data
 	cleanupStmts := []string{
-		"DELETE FROM TimerItems WHERE meeting_role = 'Test Role'",
-		"DELETE FROM SpeakerItems WHERE meeting_role = 'Test Role'",
-		"DELETE FROM MessageItems WHERE meeting_role = 'Test Role'",
-		"DELETE FROM LyricsItems WHERE meeting_role = 'Test Role'",
-		"DELETE FROM BlankItems WHERE meeting_role = 'Test Role'",
+		"DELETE FROM AuditLog WHERE org_id IN (SELECT id FROM Organization WHERE name LIKE 'Test Org%') OR actor_id IN (SELECT id FROM Users WHERE email = 'test@example.com')",
+		"DELETE FROM AgendaItems WHERE meeting_role = 'Test Role'",
+		"DELETE FROM LiveStates WHERE meeting_id IN (SELECT id FROM Meetings WHERE meeting = 'Test Meeting')",
+		"DELETE FROM OverlayTokens WHERE meeting_id IN (SELECT id FROM Meetings WHERE meeting = 'Test Meeting')",
+		"DELETE FROM Timers WHERE meeting_id IN (SELECT id FROM Meetings WHERE meeting = 'Test Meeting')",
+		"DELETE FROM MeetingThemes WHERE meeting_id IN (SELECT id FROM Meetings WHERE meeting = 'Test Meeting')",
 		"DELETE FROM Meetings WHERE meeting = 'Test Meeting'",
 		"DELETE FROM OrgUsers WHERE org_id IN (SELECT id FROM Organization WHERE name = 'Test Organization')",
+		"DELETE FROM OrgThemes WHERE org_id IN (SELECT id FROM Organization WHERE name = 'Test Organization')",
+		"DELETE FROM ScheduledMeetings WHERE schedule_id IN (SELECT id FROM Schedules WHERE org_id IN (SELECT id FROM Organization WHERE name LIKE 'Test Org%'))",
+		"DELETE FROM Schedules WHERE org_id IN (SELECT id FROM Organization WHERE name LIKE 'Test Org%')",
+		"DELETE FROM Invitations WHERE org_id IN (SELECT id FROM Organization WHERE name LIKE 'Test Org%')",
+		"DELETE FROM ApiKeys WHERE org_id IN (SELECT id FROM Organization WHERE name LIKE 'Test Org%')",
+		"DELETE FROM MeetingTemplates WHERE org_id IN (SELECT id FROM Organization WHERE name = 'Test Organization')",
 		"DELETE FROM Organization WHERE name = 'Test Organization'",
 		"DELETE FROM Users WHERE email = 'test@example.com'",
+		"DELETE FROM HymnVerses WHERE hymn_id IN (SELECT id FROM Hymns WHERE name LIKE 'Test Hymn%')",
+		"DELETE FROM Hymns WHERE name LIKE 'Test Hymn%'",
 	}
 	for _, stmt := range cleanupStmts {
-		_, err = TestDB.Exec(stmt)
+		_, err = db.Exec(stmt)
 		if err != nil {
 			t.Fatalf("Failed to clean up test data with statement '%s': %v", stmt, err)
 		}
 	}
-
-	// Setup test tables
-	if err := setupTestTables(TestDB); err != nil {
-		t.Fatalf("Failed to setup test tables: %v", err)
-	}
-
-	// Setup test logger
-	logger := logrus.New()
-	logger.SetOutput(os.Stdout)
-	logger.SetLevel(logrus.InfoLevel)
-	TestLogger = logger.WithField("test", true)
-
-	// Setup test context
-	TestCtx = context.Background()
-	TestCtx = context.WithValue(TestCtx, helpers.SocialIDKey, "test-social-id")
+	return db
 }
 
 func TeardownTest() {